@@ -1,6 +1,9 @@
 package main
 
-import "news-app-api/internal/app"
+import (
+	"news-app-api/internal/app"
+	_ "time/tzdata"
+)
 
 func main() {
 	app.Run()
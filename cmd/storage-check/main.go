@@ -0,0 +1,16 @@
+package main
+
+import (
+	"flag"
+	"news-app-api/internal/app"
+	_ "time/tzdata"
+)
+
+func main() {
+	deleteOrphans := flag.Bool("delete", false, "delete orphaned files older than the grace period")
+	graceHours := flag.Int("grace-hours", 0, "orphan grace period in hours (defaults to STORAGE_ORPHAN_GRACE_HOURS)")
+	verbose := flag.Bool("v", false, "list every inconsistent file")
+	flag.Parse()
+
+	app.CheckStorage(*deleteOrphans, *graceHours, *verbose)
+}
@@ -4,13 +4,57 @@ import (
 	"fmt"
 	"github.com/gofiber/fiber/v2/middleware/encryptcookie"
 	"os"
+	"strconv"
+	"strings"
+)
+
+const (
+	StorageDriverLocal = "local"
+	StorageDriverS3    = "s3"
 )
 
 type Config struct {
-	DBURL  string
-	Host   string
-	Port   int
-	Secret string
+	DBURL       string
+	Host        string
+	Port        int
+	Secret      string
+	PublicURL   string
+	MailFrom    string
+	MailDropDir string
+
+	ArticleURLTemplate string
+
+	VAPIDPrivateKey string
+	VAPIDSubject    string
+
+	PushAllowPrivateNetworks bool
+
+	WebhookAllowPrivateNetworks bool
+
+	AccountDeletionGraceDays int
+
+	UploadExpirationHours int
+
+	StorageDriver     string
+	StorageLocalRoot  string
+	S3Endpoint        string
+	S3Region          string
+	S3Bucket          string
+	S3AccessKeyID     string
+	S3SecretAccessKey string
+	S3PathStyle       bool
+
+	StorageOrphanGraceHours int
+	StorageDeleteOrphans    bool
+
+	AttachmentURLSecret           string
+	AttachmentURLTTLMinutes       int
+	AttachmentURLRequireSignature []string
+
+	CacheControlNews  string
+	CacheControlImage string
+	CacheControlAudio string
+	CacheControlVideo string
 }
 
 func (c *Config) Validate() (err error) {
@@ -27,6 +71,51 @@ func (c *Config) Validate() (err error) {
 		return fmt.Errorf("missing Port field")
 	} else if c.Secret == "" {
 		return fmt.Errorf("missing Secret field")
+	} else if c.PublicURL == "" {
+		return fmt.Errorf("missing PublicURL field")
+	} else if !strings.Contains(c.ArticleURLTemplate, "{id}") {
+		return fmt.Errorf("invalid ArticleURLTemplate field")
+	} else if c.MailFrom == "" {
+		return fmt.Errorf("missing MailFrom field")
+	} else if c.MailDropDir == "" {
+		return fmt.Errorf("missing MailDropDir field")
+	} else if c.AccountDeletionGraceDays < 0 {
+		return fmt.Errorf("invalid AccountDeletionGraceDays field")
+	} else if c.UploadExpirationHours < 1 {
+		return fmt.Errorf("invalid UploadExpirationHours field")
+	} else if c.StorageOrphanGraceHours < 1 {
+		return fmt.Errorf("invalid StorageOrphanGraceHours field")
+	} else if c.AttachmentURLTTLMinutes < 1 {
+		return fmt.Errorf("invalid AttachmentURLTTLMinutes field")
+	} else if len(c.AttachmentURLRequireSignature) > 0 && c.AttachmentURLSecret == "" {
+		return fmt.Errorf("missing AttachmentURLSecret field")
+	}
+
+	for _, kind := range c.AttachmentURLRequireSignature {
+		switch kind {
+		case "audio", "image", "video", "gallery", "waveform", "tracks":
+		default:
+			return fmt.Errorf("unknown attachment kind %q in AttachmentURLRequireSignature field", kind)
+		}
+	}
+
+	switch c.StorageDriver {
+	case StorageDriverLocal:
+		if c.StorageLocalRoot == "" {
+			return fmt.Errorf("missing StorageLocalRoot field")
+		}
+	case StorageDriverS3:
+		if c.S3Endpoint == "" {
+			return fmt.Errorf("missing S3Endpoint field")
+		} else if c.S3Bucket == "" {
+			return fmt.Errorf("missing S3Bucket field")
+		} else if c.S3AccessKeyID == "" {
+			return fmt.Errorf("missing S3AccessKeyID field")
+		} else if c.S3SecretAccessKey == "" {
+			return fmt.Errorf("missing S3SecretAccessKey field")
+		}
+	default:
+		return fmt.Errorf("unknown StorageDriver %q", c.StorageDriver)
 	}
 	return
 }
@@ -41,6 +130,107 @@ func Load() (*Config, error) {
 	if cfg.Secret == "" {
 		cfg.Secret = encryptcookie.GenerateKey()
 	}
+	cfg.PublicURL = os.Getenv("PUBLIC_URL")
+	if cfg.PublicURL == "" {
+		cfg.PublicURL = fmt.Sprintf("http://localhost:%d", cfg.Port)
+	}
+	cfg.ArticleURLTemplate = os.Getenv("ARTICLE_URL_TEMPLATE")
+	if cfg.ArticleURLTemplate == "" {
+		cfg.ArticleURLTemplate = strings.TrimRight(cfg.PublicURL, "/") + "/news/{id}"
+	}
+	cfg.MailFrom = os.Getenv("MAIL_FROM")
+	if cfg.MailFrom == "" {
+		cfg.MailFrom = "news@localhost"
+	}
+	cfg.MailDropDir = os.Getenv("MAIL_DROP_DIR")
+	if cfg.MailDropDir == "" {
+		cfg.MailDropDir = "mail"
+	}
+	cfg.VAPIDPrivateKey = os.Getenv("VAPID_PRIVATE_KEY")
+	cfg.VAPIDSubject = os.Getenv("VAPID_SUBJECT")
+	if cfg.VAPIDSubject == "" {
+		cfg.VAPIDSubject = "mailto:" + cfg.MailFrom
+	}
+	cfg.PushAllowPrivateNetworks = os.Getenv("PUSH_ALLOW_PRIVATE_NETWORKS") == "true"
+	cfg.WebhookAllowPrivateNetworks = os.Getenv("WEBHOOK_ALLOW_PRIVATE_NETWORKS") == "true"
+
+	cfg.AccountDeletionGraceDays = 30
+	if v := os.Getenv("ACCOUNT_DELETION_GRACE_DAYS"); v != "" {
+		days, err := strconv.Atoi(v)
+		if err != nil {
+			return nil, fmt.Errorf("Config - Load: ACCOUNT_DELETION_GRACE_DAYS: %w", err)
+		}
+		cfg.AccountDeletionGraceDays = days
+	}
+
+	cfg.UploadExpirationHours = 24
+	if v := os.Getenv("UPLOAD_EXPIRATION_HOURS"); v != "" {
+		hours, err := strconv.Atoi(v)
+		if err != nil {
+			return nil, fmt.Errorf("Config - Load: UPLOAD_EXPIRATION_HOURS: %w", err)
+		}
+		cfg.UploadExpirationHours = hours
+	}
+
+	cfg.StorageDriver = os.Getenv("STORAGE_DRIVER")
+	if cfg.StorageDriver == "" {
+		cfg.StorageDriver = StorageDriverLocal
+	}
+	cfg.StorageLocalRoot = os.Getenv("STORAGE_LOCAL_ROOT")
+	if cfg.StorageLocalRoot == "" {
+		cfg.StorageLocalRoot = "."
+	}
+	cfg.S3Endpoint = os.Getenv("S3_ENDPOINT")
+	cfg.S3Region = os.Getenv("S3_REGION")
+	if cfg.S3Region == "" {
+		cfg.S3Region = "us-east-1"
+	}
+	cfg.S3Bucket = os.Getenv("S3_BUCKET")
+	cfg.S3AccessKeyID = os.Getenv("S3_ACCESS_KEY_ID")
+	cfg.S3SecretAccessKey = os.Getenv("S3_SECRET_ACCESS_KEY")
+	cfg.S3PathStyle = os.Getenv("S3_PATH_STYLE") != "false"
+
+	cfg.StorageOrphanGraceHours = 24
+	if v := os.Getenv("STORAGE_ORPHAN_GRACE_HOURS"); v != "" {
+		hours, err := strconv.Atoi(v)
+		if err != nil {
+			return nil, fmt.Errorf("Config - Load: STORAGE_ORPHAN_GRACE_HOURS: %w", err)
+		}
+		cfg.StorageOrphanGraceHours = hours
+	}
+	cfg.StorageDeleteOrphans = os.Getenv("STORAGE_DELETE_ORPHANS") == "true"
+
+	cfg.AttachmentURLSecret = os.Getenv("ATTACHMENT_URL_SECRET")
+	cfg.AttachmentURLTTLMinutes = 60
+	if v := os.Getenv("ATTACHMENT_URL_TTL_MINUTES"); v != "" {
+		minutes, err := strconv.Atoi(v)
+		if err != nil {
+			return nil, fmt.Errorf("Config - Load: ATTACHMENT_URL_TTL_MINUTES: %w", err)
+		}
+		cfg.AttachmentURLTTLMinutes = minutes
+	}
+	for _, kind := range strings.Split(os.Getenv("ATTACHMENT_URL_REQUIRE_SIGNATURE"), ",") {
+		if kind = strings.TrimSpace(kind); kind != "" {
+			cfg.AttachmentURLRequireSignature = append(cfg.AttachmentURLRequireSignature, kind)
+		}
+	}
+
+	cfg.CacheControlNews = os.Getenv("CACHE_CONTROL_NEWS")
+	if cfg.CacheControlNews == "" {
+		cfg.CacheControlNews = "no-cache"
+	}
+	cfg.CacheControlImage = os.Getenv("CACHE_CONTROL_IMAGE")
+	if cfg.CacheControlImage == "" {
+		cfg.CacheControlImage = "public, max-age=86400"
+	}
+	cfg.CacheControlAudio = os.Getenv("CACHE_CONTROL_AUDIO")
+	if cfg.CacheControlAudio == "" {
+		cfg.CacheControlAudio = "public, max-age=86400"
+	}
+	cfg.CacheControlVideo = os.Getenv("CACHE_CONTROL_VIDEO")
+	if cfg.CacheControlVideo == "" {
+		cfg.CacheControlVideo = "public, max-age=86400"
+	}
 
 	err := cfg.Validate()
 	if err != nil {
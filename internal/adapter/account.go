@@ -0,0 +1,144 @@
+package adapter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"news-app-api/internal/dto"
+	"news-app-api/internal/entity"
+)
+
+type (
+	AccountRepository interface {
+		GetAccountExport(ctx context.Context, userID int64) (entity.AccountExport, error)
+		UpsertAccountDeletion(ctx context.Context, userID, scheduledAt int64) (entity.AccountDeletion, error)
+		GetAccountDeletion(ctx context.Context, userID int64) (entity.AccountDeletion, error)
+		DeleteAccountDeletion(ctx context.Context, userID int64) error
+		DeleteDueAccounts(ctx context.Context, limit int64) ([]int64, error)
+	}
+
+	accountRepository struct {
+		db *pgxpool.Pool
+	}
+)
+
+func NewAccountRepository(db *pgxpool.Pool) AccountRepository {
+	return &accountRepository{db}
+}
+
+func (r *accountRepository) GetAccountExport(ctx context.Context, userID int64) (e entity.AccountExport, err error) {
+	defer func() {
+		if err != nil {
+			var appErr *dto.AppError
+			if !errors.As(err, &appErr) {
+				err = fmt.Errorf("AccountRepository - GetAccountExport: %w", err)
+			}
+		}
+	}()
+	row := r.db.QueryRow(ctx, queryGetAccountExport, userID)
+	err = row.Scan(
+		&e.Profile,
+		&e.Subscriptions,
+		&e.SubscriptionHistory,
+		&e.Favorites,
+		&e.FeedReadState,
+		&e.HiddenNews,
+		&e.MutedMedia,
+		&e.Notifications,
+		&e.NotificationPreferences,
+		&e.Digest,
+		&e.PushSubscriptions,
+	)
+	return
+}
+
+func (r *accountRepository) UpsertAccountDeletion(
+	ctx context.Context,
+	userID, scheduledAt int64,
+) (d entity.AccountDeletion, err error) {
+	defer func() {
+		if err != nil {
+			var appErr *dto.AppError
+			if !errors.As(err, &appErr) {
+				err = fmt.Errorf("AccountRepository - UpsertAccountDeletion: %w", err)
+			}
+		}
+	}()
+	row := r.db.QueryRow(ctx, queryUpsertAccountDeletion, userID, scheduledAt)
+	err = row.Scan(&d.RequestedAt, &d.ScheduledAt)
+	return
+}
+
+func (r *accountRepository) GetAccountDeletion(ctx context.Context, userID int64) (d entity.AccountDeletion, err error) {
+	defer func() {
+		if err != nil {
+			var appErr *dto.AppError
+			if !errors.As(err, &appErr) {
+				err = fmt.Errorf("AccountRepository - GetAccountDeletion: %w", err)
+			}
+		}
+	}()
+	row := r.db.QueryRow(ctx, queryGetAccountDeletion, userID)
+	err = row.Scan(&d.RequestedAt, &d.ScheduledAt)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			err = &dto.AppError{
+				Message: "Удаление аккаунта не запланировано",
+				Code:    dto.ErrCodeNotFound,
+			}
+		}
+		return
+	}
+	return
+}
+
+func (r *accountRepository) DeleteAccountDeletion(ctx context.Context, userID int64) (err error) {
+	defer func() {
+		if err != nil {
+			var appErr *dto.AppError
+			if !errors.As(err, &appErr) {
+				err = fmt.Errorf("AccountRepository - DeleteAccountDeletion: %w", err)
+			}
+		}
+	}()
+	tag, err := r.db.Exec(ctx, queryDeleteAccountDeletion, userID)
+	if err != nil {
+		return
+	}
+	if tag.RowsAffected() == 0 {
+		err = &dto.AppError{
+			Message: "Удаление аккаунта не запланировано",
+			Code:    dto.ErrCodeNotFound,
+		}
+	}
+	return
+}
+
+func (r *accountRepository) DeleteDueAccounts(ctx context.Context, limit int64) (ids []int64, err error) {
+	defer func() {
+		if err != nil {
+			var appErr *dto.AppError
+			if !errors.As(err, &appErr) {
+				err = fmt.Errorf("AccountRepository - DeleteDueAccounts: %w", err)
+			}
+		}
+	}()
+	rows, err := r.db.Query(ctx, queryDeleteDueAccounts, limit)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id int64
+		err = rows.Scan(&id)
+		if err != nil {
+			return
+		}
+		ids = append(ids, id)
+	}
+	err = rows.Err()
+	return
+}
@@ -0,0 +1,147 @@
+package adapter
+
+const (
+	queryGetAccountExport = `
+SELECT (SELECT json_build_object(
+                   'id', ID_user,
+                   'login', Login,
+                   'name', FIO_user,
+                   'email', Email_user,
+                   'pendingEmail', (SELECT json_build_object(
+                                               'email', new_email,
+                                               'requestedAt', EXTRACT(EPOCH FROM created_at)::BIGINT,
+                                               'expiresAt', EXTRACT(EPOCH FROM expires_at)::BIGINT)
+                                    FROM email_change
+                                    WHERE user_id = ID_user),
+                   'deletion', (SELECT json_build_object(
+                                           'requestedAt', EXTRACT(EPOCH FROM requested_at)::BIGINT,
+                                           'scheduledAt', EXTRACT(EPOCH FROM scheduled_at)::BIGINT)
+                                FROM account_deletion
+                                WHERE user_id = ID_user))
+        FROM "user"
+        WHERE ID_user = $1),
+       (SELECT COALESCE(json_agg(json_build_object(
+                   'mediaId', media.ID_editor,
+                   'mediaName', media.Corp_name,
+                   'subscribedAt', EXTRACT(EPOCH FROM subscription.created_at)::BIGINT)
+                   ORDER BY subscription.id), '[]'::JSON)
+        FROM subscription
+        INNER JOIN media ON
+            media.ID_editor = subscription.media_id
+        WHERE subscription.user_id = $1),
+       (SELECT COALESCE(json_agg(json_build_object(
+                   'mediaId', media.ID_editor,
+                   'mediaName', media.Corp_name,
+                   'subscribedAt', EXTRACT(EPOCH FROM subscription_history.subscribed_at)::BIGINT,
+                   'unsubscribedAt', EXTRACT(EPOCH FROM subscription_history.unsubscribed_at)::BIGINT)
+                   ORDER BY subscription_history.id), '[]'::JSON)
+        FROM subscription_history
+        INNER JOIN media ON
+            media.ID_editor = subscription_history.media_id
+        WHERE subscription_history.user_id = $1),
+       (SELECT COALESCE(json_agg(json_build_object(
+                   'newsId', news.ID_news,
+                   'title', news.Title)
+                   ORDER BY news.ID_news), '[]'::JSON)
+        FROM favorite
+        INNER JOIN news ON
+            news.ID_news = favorite.news_id
+        WHERE favorite.user_id = $1),
+       (SELECT COALESCE(json_agg(json_build_object(
+                   'newsId', news.ID_news,
+                   'title', news.Title,
+                   'isRead', feed.read_at IS NOT NULL,
+                   'readAt', EXTRACT(EPOCH FROM feed.read_at)::BIGINT)
+                   ORDER BY news.ID_news), '[]'::JSON)
+        FROM feed
+        INNER JOIN news ON
+            news.ID_news = feed.ID_news
+        WHERE feed.ID_user = $1),
+       (SELECT COALESCE(json_agg(json_build_object(
+                   'newsId', news_id,
+                   'hiddenAt', EXTRACT(EPOCH FROM created_at)::BIGINT)
+                   ORDER BY created_at), '[]'::JSON)
+        FROM hidden_news
+        WHERE user_id = $1),
+       (SELECT COALESCE(json_agg(json_build_object(
+                   'mediaId', media_id,
+                   'mutedAt', EXTRACT(EPOCH FROM created_at)::BIGINT,
+                   'until', EXTRACT(EPOCH FROM until)::BIGINT)
+                   ORDER BY created_at), '[]'::JSON)
+        FROM muted_media
+        WHERE user_id = $1),
+       (SELECT COALESCE(json_agg(json_build_object(
+                   'id', id,
+                   'type', type,
+                   'newsId', news_id,
+                   'mediaId', media_id,
+                   'title', title,
+                   'body', body,
+                   'createdAt', EXTRACT(EPOCH FROM created_at)::BIGINT,
+                   'readAt', EXTRACT(EPOCH FROM read_at)::BIGINT)
+                   ORDER BY id), '[]'::JSON)
+        FROM notification
+        WHERE user_id = $1),
+       (SELECT COALESCE(json_agg(json_build_object(
+                   'type', type,
+                   'enabled', enabled)
+                   ORDER BY type), '[]'::JSON)
+        FROM notification_preference
+        WHERE user_id = $1),
+       (SELECT json_build_object(
+                   'frequency', frequency,
+                   'sendHour', send_hour,
+                   'sendWeekday', send_weekday,
+                   'timezone', timezone,
+                   'lastSentAt', EXTRACT(EPOCH FROM last_sent_at)::BIGINT)
+        FROM digest_preference
+        WHERE user_id = $1),
+       (SELECT COALESCE(json_agg(json_build_object(
+                   'id', id,
+                   'endpoint', endpoint,
+                   'createdAt', EXTRACT(EPOCH FROM created_at)::BIGINT)
+                   ORDER BY id), '[]'::JSON)
+        FROM push_subscription
+        WHERE user_id = $1)
+`
+
+	queryUpsertAccountDeletion = `
+INSERT INTO account_deletion (user_id, scheduled_at)
+VALUES ($1, TO_TIMESTAMP($2::BIGINT))
+ON CONFLICT (user_id) DO UPDATE
+    SET requested_at = NOW(),
+        scheduled_at = EXCLUDED.scheduled_at
+RETURNING EXTRACT(EPOCH FROM requested_at)::BIGINT,
+          EXTRACT(EPOCH FROM scheduled_at)::BIGINT
+`
+
+	queryGetAccountDeletion = `
+SELECT EXTRACT(EPOCH FROM requested_at)::BIGINT,
+       EXTRACT(EPOCH FROM scheduled_at)::BIGINT
+FROM account_deletion
+WHERE user_id = $1
+`
+
+	queryDeleteAccountDeletion = `
+DELETE FROM account_deletion WHERE user_id = $1
+`
+
+	queryDeleteDueAccounts = `
+WITH due AS (
+    SELECT user_id
+    FROM account_deletion
+    WHERE scheduled_at <= NOW()
+    ORDER BY scheduled_at
+    LIMIT $1
+    FOR UPDATE SKIP LOCKED
+), archived AS (
+    INSERT INTO subscription_history (user_id, media_id, subscribed_at)
+    SELECT NULL, media_id, created_at
+    FROM subscription
+    WHERE user_id IN (SELECT user_id FROM due)
+)
+DELETE FROM "user"
+WHERE ID_user IN (SELECT user_id FROM due)
+RETURNING ID_user
+`
+)
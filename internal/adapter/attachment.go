@@ -0,0 +1,431 @@
+package adapter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"gopkg.in/guregu/null.v3"
+	"news-app-api/internal/dto"
+	"news-app-api/internal/entity"
+)
+
+type (
+	AttachmentRepository interface {
+		GetAttachment(ctx context.Context, newsID int64, kind string) (entity.Attachment, error)
+		UpsertAttachment(ctx context.Context, a entity.Attachment) (entity.Attachment, []entity.StoredObject, error)
+		GetAttachmentVariants(ctx context.Context, attachmentID int64) ([]entity.AttachmentVariant, error)
+		ReplaceAttachmentVariants(ctx context.Context, attachmentID int64, variants []entity.AttachmentVariant) ([]entity.StoredObject, error)
+		UpdateAttachmentWaveform(ctx context.Context, attachmentID int64, contentHash string, w *entity.Waveform) error
+		GetAttachmentWaveform(ctx context.Context, newsID int64, kind string) (entity.Waveform, error)
+		GetGalleryImages(ctx context.Context, newsID int64) ([]entity.GalleryImage, error)
+		GetGalleryImage(ctx context.Context, newsID, imageID int64) (entity.GalleryImage, error)
+		CreateGalleryImage(ctx context.Context, img entity.GalleryImage) (entity.GalleryImage, error)
+		UpdateGalleryImage(ctx context.Context, p dto.UpdateGalleryImageParams) (entity.GalleryImage, error)
+		ReorderGalleryImages(ctx context.Context, newsID int64, imageIDs []int64) error
+		DeleteGalleryImage(ctx context.Context, newsID, imageID int64) ([]entity.StoredObject, error)
+	}
+
+	attachmentRepository struct {
+		db *pgxpool.Pool
+	}
+)
+
+func NewAttachmentRepository(db *pgxpool.Pool) AttachmentRepository {
+	return &attachmentRepository{db}
+}
+
+func (r *attachmentRepository) GetAttachment(
+	ctx context.Context,
+	newsID int64,
+	kind string,
+) (a entity.Attachment, err error) {
+	defer func() {
+		if err != nil {
+			var appErr *dto.AppError
+			if !errors.As(err, &appErr) {
+				err = fmt.Errorf("AttachmentRepository - GetAttachment: %w", err)
+			}
+		}
+	}()
+	row := r.db.QueryRow(ctx, queryGetAttachment, newsID, kind)
+	err = row.Scan(
+		&a.ID,
+		&a.NewsID,
+		&a.Kind,
+		&a.ContentType,
+		&a.Size,
+		&a.ContentHash,
+		&a.StorageKey,
+		&a.BlobHash,
+		&a.Metadata,
+		&a.CreatedAt,
+		&a.UpdatedAt,
+	)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			err = &dto.AppError{
+				Message: "Файл не найден",
+				Code:    dto.ErrCodeNotFound,
+			}
+		}
+		return
+	}
+	return
+}
+
+func (r *attachmentRepository) UpsertAttachment(
+	ctx context.Context,
+	a entity.Attachment,
+) (res entity.Attachment, previous []entity.StoredObject, err error) {
+	defer func() {
+		if err != nil {
+			var appErr *dto.AppError
+			if !errors.As(err, &appErr) {
+				err = fmt.Errorf("AttachmentRepository - UpsertAttachment: %w", err)
+			}
+		}
+	}()
+	row := r.db.QueryRow(
+		ctx,
+		queryUpsertAttachment,
+		a.NewsID,
+		a.Kind,
+		a.ContentType,
+		a.Size,
+		a.ContentHash,
+		a.StorageKey,
+		a.BlobHash,
+		a.Metadata,
+	)
+	var previousKey, previousBlob null.String
+	err = row.Scan(
+		&res.ID,
+		&res.NewsID,
+		&res.Kind,
+		&res.ContentType,
+		&res.Size,
+		&res.ContentHash,
+		&res.StorageKey,
+		&res.BlobHash,
+		&res.Metadata,
+		&res.CreatedAt,
+		&res.UpdatedAt,
+		&previousKey,
+		&previousBlob,
+	)
+	if err != nil {
+		return
+	}
+
+	if previousKey.Valid {
+		previous = append(previous, entity.StoredObject{StorageKey: previousKey.String, BlobHash: previousBlob})
+	}
+	return
+}
+
+func (r *attachmentRepository) GetAttachmentVariants(
+	ctx context.Context,
+	attachmentID int64,
+) (res []entity.AttachmentVariant, err error) {
+	defer func() {
+		if err != nil {
+			var appErr *dto.AppError
+			if !errors.As(err, &appErr) {
+				err = fmt.Errorf("AttachmentRepository - GetAttachmentVariants: %w", err)
+			}
+		}
+	}()
+	rows, err := r.db.Query(ctx, queryGetAttachmentVariants, attachmentID)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var v entity.AttachmentVariant
+		err = rows.Scan(
+			&v.Width,
+			&v.Height,
+			&v.ContentType,
+			&v.Size,
+			&v.ContentHash,
+			&v.StorageKey,
+			&v.BlobHash,
+			&v.UpdatedAt,
+		)
+		if err != nil {
+			return
+		}
+		res = append(res, v)
+	}
+	err = rows.Err()
+	return
+}
+
+func (r *attachmentRepository) ReplaceAttachmentVariants(
+	ctx context.Context,
+	attachmentID int64,
+	variants []entity.AttachmentVariant,
+) (previous []entity.StoredObject, err error) {
+	defer func() {
+		if err != nil {
+			var appErr *dto.AppError
+			if !errors.As(err, &appErr) {
+				err = fmt.Errorf("AttachmentRepository - ReplaceAttachmentVariants: %w", err)
+			}
+		}
+	}()
+
+	widths := make([]int32, len(variants))
+	heights := make([]int32, len(variants))
+	contentTypes := make([]string, len(variants))
+	sizes := make([]int64, len(variants))
+	hashes := make([]string, len(variants))
+	keys := make([]string, len(variants))
+	blobHashes := make([]*string, len(variants))
+	for i, v := range variants {
+		widths[i] = int32(v.Width)
+		heights[i] = int32(v.Height)
+		contentTypes[i] = v.ContentType
+		sizes[i] = v.Size
+		hashes[i] = v.ContentHash
+		keys[i] = v.StorageKey
+		blobHashes[i] = v.BlobHash.Ptr()
+	}
+
+	rows, err := r.db.Query(
+		ctx,
+		queryReplaceAttachmentVariants,
+		attachmentID,
+		widths,
+		heights,
+		contentTypes,
+		sizes,
+		hashes,
+		keys,
+		blobHashes,
+	)
+	if err != nil {
+		return
+	}
+	return scanStoredObjects(rows)
+}
+
+func (r *attachmentRepository) UpdateAttachmentWaveform(
+	ctx context.Context,
+	attachmentID int64,
+	contentHash string,
+	w *entity.Waveform,
+) (err error) {
+	defer func() {
+		if err != nil {
+			var appErr *dto.AppError
+			if !errors.As(err, &appErr) {
+				err = fmt.Errorf("AttachmentRepository - UpdateAttachmentWaveform: %w", err)
+			}
+		}
+	}()
+	_, err = r.db.Exec(ctx, queryUpdateAttachmentWaveform, attachmentID, contentHash, w)
+	return
+}
+
+func (r *attachmentRepository) GetAttachmentWaveform(
+	ctx context.Context,
+	newsID int64,
+	kind string,
+) (w entity.Waveform, err error) {
+	defer func() {
+		if err != nil {
+			var appErr *dto.AppError
+			if !errors.As(err, &appErr) {
+				err = fmt.Errorf("AttachmentRepository - GetAttachmentWaveform: %w", err)
+			}
+		}
+	}()
+	err = r.db.QueryRow(ctx, queryGetAttachmentWaveform, newsID, kind).Scan(&w)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			err = &dto.AppError{
+				Message: "Волновая форма не найдена",
+				Code:    dto.ErrCodeNotFound,
+			}
+		}
+		return
+	}
+	return
+}
+
+func (r *attachmentRepository) GetGalleryImages(ctx context.Context, newsID int64) (res []entity.GalleryImage, err error) {
+	defer func() {
+		if err != nil {
+			var appErr *dto.AppError
+			if !errors.As(err, &appErr) {
+				err = fmt.Errorf("AttachmentRepository - GetGalleryImages: %w", err)
+			}
+		}
+	}()
+	rows, err := r.db.Query(ctx, queryGetGalleryImages, newsID)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	res = []entity.GalleryImage{}
+	for rows.Next() {
+		var img entity.GalleryImage
+		img, err = scanGalleryImage(rows)
+		if err != nil {
+			return
+		}
+		res = append(res, img)
+	}
+	err = rows.Err()
+	return
+}
+
+func (r *attachmentRepository) GetGalleryImage(
+	ctx context.Context,
+	newsID, imageID int64,
+) (img entity.GalleryImage, err error) {
+	defer func() {
+		if err != nil {
+			var appErr *dto.AppError
+			if !errors.As(err, &appErr) {
+				err = fmt.Errorf("AttachmentRepository - GetGalleryImage: %w", err)
+			}
+		}
+	}()
+	return scanGalleryImage(r.db.QueryRow(ctx, queryGetGalleryImage, newsID, imageID))
+}
+
+func (r *attachmentRepository) CreateGalleryImage(
+	ctx context.Context,
+	img entity.GalleryImage,
+) (res entity.GalleryImage, err error) {
+	defer func() {
+		if err != nil {
+			var appErr *dto.AppError
+			if !errors.As(err, &appErr) {
+				err = fmt.Errorf("AttachmentRepository - CreateGalleryImage: %w", err)
+			}
+		}
+	}()
+	return scanGalleryImage(r.db.QueryRow(
+		ctx,
+		queryCreateGalleryImage,
+		img.NewsID,
+		img.ContentType,
+		img.Size,
+		img.ContentHash,
+		img.StorageKey,
+		img.BlobHash,
+		img.Metadata,
+		img.Caption,
+		img.AltText,
+		img.Credit,
+	))
+}
+
+func (r *attachmentRepository) UpdateGalleryImage(
+	ctx context.Context,
+	p dto.UpdateGalleryImageParams,
+) (img entity.GalleryImage, err error) {
+	defer func() {
+		if err != nil {
+			var appErr *dto.AppError
+			if !errors.As(err, &appErr) {
+				err = fmt.Errorf("AttachmentRepository - UpdateGalleryImage: %w", err)
+			}
+		}
+	}()
+	return scanGalleryImage(r.db.QueryRow(ctx, queryUpdateGalleryImage, p.NewsID, p.ImageID, p.Caption, p.AltText, p.Credit))
+}
+
+func (r *attachmentRepository) ReorderGalleryImages(ctx context.Context, newsID int64, imageIDs []int64) (err error) {
+	defer func() {
+		if err != nil {
+			var appErr *dto.AppError
+			if !errors.As(err, &appErr) {
+				err = fmt.Errorf("AttachmentRepository - ReorderGalleryImages: %w", err)
+			}
+		}
+	}()
+	_, err = r.db.Exec(ctx, queryReorderGalleryImages, newsID, imageIDs)
+	return
+}
+
+func (r *attachmentRepository) DeleteGalleryImage(
+	ctx context.Context,
+	newsID, imageID int64,
+) (deleted []entity.StoredObject, err error) {
+	defer func() {
+		if err != nil {
+			var appErr *dto.AppError
+			if !errors.As(err, &appErr) {
+				err = fmt.Errorf("AttachmentRepository - DeleteGalleryImage: %w", err)
+			}
+		}
+	}()
+	rows, err := r.db.Query(ctx, queryDeleteGalleryImage, newsID, imageID)
+	if err != nil {
+		return
+	}
+
+	deleted, err = scanStoredObjects(rows)
+	if err != nil {
+		return
+	}
+
+	if len(deleted) == 0 {
+		err = errGalleryImageNotFound()
+	}
+	return
+}
+
+func scanGalleryImage(row pgx.Row) (img entity.GalleryImage, err error) {
+	err = row.Scan(
+		&img.ID,
+		&img.NewsID,
+		&img.Kind,
+		&img.ContentType,
+		&img.Size,
+		&img.ContentHash,
+		&img.StorageKey,
+		&img.BlobHash,
+		&img.Metadata,
+		&img.CreatedAt,
+		&img.UpdatedAt,
+		&img.Caption,
+		&img.AltText,
+		&img.Credit,
+		&img.Position,
+	)
+	if err == pgx.ErrNoRows {
+		err = errGalleryImageNotFound()
+	}
+	return
+}
+
+func errGalleryImageNotFound() error {
+	return &dto.AppError{
+		Message: "Изображение не найдено",
+		Code:    dto.ErrCodeNotFound,
+	}
+}
+
+func scanStoredObjects(rows pgx.Rows) (objects []entity.StoredObject, err error) {
+	defer rows.Close()
+
+	for rows.Next() {
+		var o entity.StoredObject
+		err = rows.Scan(&o.StorageKey, &o.BlobHash)
+		if err != nil {
+			return
+		}
+		objects = append(objects, o)
+	}
+	err = rows.Err()
+	return
+}
@@ -0,0 +1,243 @@
+package adapter
+
+const (
+	queryGetAttachment = `
+SELECT id,
+       news_id,
+       kind,
+       content_type,
+       size,
+       COALESCE(content_hash, ''),
+       storage_key,
+       blob_hash,
+       metadata,
+       EXTRACT(EPOCH FROM created_at)::BIGINT,
+       EXTRACT(EPOCH FROM updated_at)::BIGINT
+FROM attachment
+WHERE news_id = $1
+  AND kind = $2
+`
+
+	queryUpsertAttachment = `
+WITH previous AS (
+    SELECT storage_key, blob_hash
+    FROM attachment
+    WHERE news_id = $1
+      AND kind = $2
+    FOR UPDATE
+)
+INSERT INTO attachment (news_id, kind, content_type, size, content_hash, storage_key, blob_hash, metadata)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8::JSONB)
+ON CONFLICT (news_id, kind) WHERE kind <> 'gallery' DO UPDATE
+    SET content_type = EXCLUDED.content_type,
+        size         = EXCLUDED.size,
+        content_hash = EXCLUDED.content_hash,
+        storage_key  = EXCLUDED.storage_key,
+        blob_hash    = EXCLUDED.blob_hash,
+        metadata     = EXCLUDED.metadata,
+        waveform     = CASE WHEN attachment.content_hash = EXCLUDED.content_hash THEN attachment.waveform END,
+        updated_at   = NOW()
+RETURNING id,
+          news_id,
+          kind,
+          content_type,
+          size,
+          COALESCE(content_hash, ''),
+          storage_key,
+          blob_hash,
+          metadata,
+          EXTRACT(EPOCH FROM created_at)::BIGINT,
+          EXTRACT(EPOCH FROM updated_at)::BIGINT,
+          (SELECT storage_key FROM previous),
+          (SELECT blob_hash FROM previous)
+`
+
+	queryGetAttachmentVariants = `
+SELECT width,
+       height,
+       content_type,
+       size,
+       COALESCE(content_hash, ''),
+       storage_key,
+       blob_hash,
+       EXTRACT(EPOCH FROM updated_at)::BIGINT
+FROM attachment_variant
+WHERE attachment_id = $1
+ORDER BY width, content_type
+`
+
+	queryReplaceAttachmentVariants = `
+WITH previous AS (
+    SELECT storage_key, blob_hash
+    FROM attachment_variant
+    WHERE attachment_id = $1
+    FOR UPDATE
+), upserted AS (
+    INSERT INTO attachment_variant (attachment_id, width, height, content_type, size, content_hash, storage_key, blob_hash)
+    SELECT $1, *
+    FROM UNNEST($2::INT[], $3::INT[], $4::VARCHAR[], $5::BIGINT[], $6::VARCHAR[], $7::VARCHAR[], $8::VARCHAR[])
+    ON CONFLICT (attachment_id, width, content_type) DO UPDATE
+        SET height       = EXCLUDED.height,
+            size         = EXCLUDED.size,
+            content_hash = EXCLUDED.content_hash,
+            storage_key  = EXCLUDED.storage_key,
+            blob_hash    = EXCLUDED.blob_hash,
+            updated_at   = NOW()
+    RETURNING width, content_type
+), deleted AS (
+    DELETE
+    FROM attachment_variant v
+    WHERE v.attachment_id = $1
+      AND (v.width, v.content_type) NOT IN (SELECT width, content_type FROM upserted)
+)
+SELECT storage_key, blob_hash
+FROM previous
+`
+
+	queryUpdateAttachmentWaveform = `
+UPDATE attachment
+SET waveform = $3::JSONB
+WHERE id = $1
+  AND content_hash = $2
+`
+
+	queryGetAttachmentWaveform = `
+SELECT waveform
+FROM attachment
+WHERE news_id = $1
+  AND kind = $2
+  AND waveform IS NOT NULL
+`
+
+	queryGetGalleryImages = `
+SELECT attachment.id,
+       attachment.news_id,
+       kind,
+       content_type,
+       size,
+       COALESCE(content_hash, ''),
+       storage_key,
+       blob_hash,
+       metadata,
+       EXTRACT(EPOCH FROM created_at)::BIGINT,
+       EXTRACT(EPOCH FROM updated_at)::BIGINT,
+       caption,
+       alt_text,
+       credit,
+       position
+FROM attachment
+INNER JOIN gallery_image ON gallery_image.attachment_id = attachment.id
+WHERE attachment.news_id = $1
+ORDER BY position, attachment.id
+`
+
+	queryGetGalleryImage = `
+SELECT attachment.id,
+       attachment.news_id,
+       kind,
+       content_type,
+       size,
+       COALESCE(content_hash, ''),
+       storage_key,
+       blob_hash,
+       metadata,
+       EXTRACT(EPOCH FROM created_at)::BIGINT,
+       EXTRACT(EPOCH FROM updated_at)::BIGINT,
+       caption,
+       alt_text,
+       credit,
+       position
+FROM attachment
+INNER JOIN gallery_image ON gallery_image.attachment_id = attachment.id
+WHERE attachment.news_id = $1
+  AND attachment.id = $2
+`
+
+	queryCreateGalleryImage = `
+WITH inserted AS (
+    INSERT INTO attachment (news_id, kind, content_type, size, content_hash, storage_key, blob_hash, metadata)
+    VALUES ($1, 'gallery', $2, $3, $4, $5, $6, $7::JSONB)
+    RETURNING *
+), image AS (
+    INSERT INTO gallery_image (attachment_id, news_id, caption, alt_text, credit, position)
+    SELECT id, news_id, $8, $9, $10, COALESCE((SELECT MAX(position) + 1 FROM gallery_image WHERE news_id = $1), 0)
+    FROM inserted
+    RETURNING *
+)
+SELECT inserted.id,
+       inserted.news_id,
+       inserted.kind,
+       inserted.content_type,
+       inserted.size,
+       COALESCE(inserted.content_hash, ''),
+       inserted.storage_key,
+       inserted.blob_hash,
+       inserted.metadata,
+       EXTRACT(EPOCH FROM inserted.created_at)::BIGINT,
+       EXTRACT(EPOCH FROM inserted.updated_at)::BIGINT,
+       image.caption,
+       image.alt_text,
+       image.credit,
+       image.position
+FROM inserted
+INNER JOIN image ON image.attachment_id = inserted.id
+`
+
+	queryUpdateGalleryImage = `
+WITH updated AS (
+    UPDATE gallery_image
+    SET caption  = COALESCE($3, caption),
+        alt_text = COALESCE($4, alt_text),
+        credit   = COALESCE($5, credit)
+    WHERE news_id = $1
+      AND attachment_id = $2
+    RETURNING *
+)
+SELECT attachment.id,
+       attachment.news_id,
+       kind,
+       content_type,
+       size,
+       COALESCE(content_hash, ''),
+       storage_key,
+       blob_hash,
+       metadata,
+       EXTRACT(EPOCH FROM created_at)::BIGINT,
+       EXTRACT(EPOCH FROM updated_at)::BIGINT,
+       updated.caption,
+       updated.alt_text,
+       updated.credit,
+       updated.position
+FROM attachment
+INNER JOIN updated ON updated.attachment_id = attachment.id
+`
+
+	queryReorderGalleryImages = `
+UPDATE gallery_image
+SET position = o.position - 1
+FROM UNNEST($2::BIGINT[]) WITH ORDINALITY AS o(attachment_id, position)
+WHERE gallery_image.news_id = $1
+  AND gallery_image.attachment_id = o.attachment_id
+`
+
+	queryDeleteGalleryImage = `
+WITH variants AS (
+    SELECT storage_key, blob_hash
+    FROM attachment_variant
+    WHERE attachment_id = $2
+), deleted AS (
+    DELETE
+    FROM attachment
+    WHERE news_id = $1
+      AND id = $2
+      AND kind = 'gallery'
+    RETURNING storage_key, blob_hash
+)
+SELECT storage_key, blob_hash
+FROM deleted
+UNION ALL
+SELECT storage_key, blob_hash
+FROM variants
+WHERE EXISTS(SELECT 1 FROM deleted)
+`
+)
@@ -0,0 +1,148 @@
+package adapter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"news-app-api/internal/dto"
+	"news-app-api/internal/entity"
+)
+
+type (
+	BlobRepository interface {
+		Transactor
+		AcquireBlob(ctx context.Context, b entity.Blob) (entity.Blob, bool, error)
+		LockUnreferencedBlobs(
+			ctx context.Context,
+			hashes []string,
+			acquiredBefore int64,
+			limit int64,
+		) ([]entity.Blob, error)
+		DeleteBlob(ctx context.Context, hash string) error
+	}
+
+	blobRepository struct {
+		db *pgxpool.Pool
+		q  Querier
+	}
+)
+
+func NewBlobRepository(db *pgxpool.Pool) BlobRepository {
+	return &blobRepository{db, db}
+}
+
+func (r *blobRepository) Begin(ctx context.Context) (err error) {
+	defer func() {
+		if err != nil {
+			var appErr *dto.AppError
+			if !errors.As(err, &appErr) {
+				err = fmt.Errorf("BlobRepository - Begin: %w", err)
+			}
+		}
+	}()
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return
+	}
+	r.q = tx
+	return
+}
+
+func (r *blobRepository) Commit(ctx context.Context) (err error) {
+	defer func() {
+		if err != nil {
+			var appErr *dto.AppError
+			if !errors.As(err, &appErr) {
+				err = fmt.Errorf("BlobRepository - Commit: %w", err)
+			}
+		}
+	}()
+	tx, ok := r.q.(pgx.Tx)
+	if !ok {
+		return ErrTxNotStarted
+	}
+	return tx.Commit(ctx)
+}
+
+func (r *blobRepository) Rollback(ctx context.Context) (err error) {
+	defer func() {
+		if err != nil {
+			var appErr *dto.AppError
+			if !errors.As(err, &appErr) {
+				err = fmt.Errorf("BlobRepository - Rollback: %w", err)
+			}
+		}
+	}()
+	tx, ok := r.q.(pgx.Tx)
+	if !ok {
+		return ErrTxNotStarted
+	}
+	return tx.Rollback(ctx)
+}
+
+func (r *blobRepository) AcquireBlob(ctx context.Context, b entity.Blob) (res entity.Blob, created bool, err error) {
+	defer func() {
+		if err != nil {
+			var appErr *dto.AppError
+			if !errors.As(err, &appErr) {
+				err = fmt.Errorf("BlobRepository - AcquireBlob: %w", err)
+			}
+		}
+	}()
+	row := r.q.QueryRow(ctx, queryAcquireBlob, b.ContentHash, b.StorageKey, b.Size)
+	err = row.Scan(
+		&res.ContentHash,
+		&res.StorageKey,
+		&res.Size,
+		&res.CreatedAt,
+		&created,
+	)
+	return
+}
+
+func (r *blobRepository) LockUnreferencedBlobs(
+	ctx context.Context,
+	hashes []string,
+	acquiredBefore int64,
+	limit int64,
+) (blobs []entity.Blob, err error) {
+	defer func() {
+		if err != nil {
+			var appErr *dto.AppError
+			if !errors.As(err, &appErr) {
+				err = fmt.Errorf("BlobRepository - LockUnreferencedBlobs: %w", err)
+			}
+		}
+	}()
+	rows, err := r.q.Query(ctx, queryLockUnreferencedBlobs, hashes, acquiredBefore, limit)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var b entity.Blob
+		err = rows.Scan(&b.ContentHash, &b.StorageKey, &b.Size, &b.CreatedAt)
+		if err != nil {
+			return
+		}
+		blobs = append(blobs, b)
+	}
+	err = rows.Err()
+	return
+}
+
+func (r *blobRepository) DeleteBlob(ctx context.Context, hash string) (err error) {
+	defer func() {
+		if err != nil {
+			var appErr *dto.AppError
+			if !errors.As(err, &appErr) {
+				err = fmt.Errorf("BlobRepository - DeleteBlob: %w", err)
+			}
+		}
+	}()
+	_, err = r.q.Exec(ctx, queryDeleteBlob, hash)
+	return
+}
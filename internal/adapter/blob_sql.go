@@ -0,0 +1,36 @@
+package adapter
+
+const (
+	queryAcquireBlob = `
+INSERT INTO blob (content_hash, storage_key, size)
+VALUES ($1, $2, $3)
+ON CONFLICT (content_hash) DO UPDATE
+    SET acquired_at = NOW()
+RETURNING content_hash,
+          storage_key,
+          size,
+          EXTRACT(EPOCH FROM created_at)::BIGINT,
+          (xmax = 0)
+`
+
+	queryLockUnreferencedBlobs = `
+SELECT content_hash,
+       storage_key,
+       size,
+       EXTRACT(EPOCH FROM created_at)::BIGINT
+FROM blob
+WHERE acquired_at < TO_TIMESTAMP($2::BIGINT)
+  AND ($1::VARCHAR[] IS NULL OR content_hash = ANY ($1))
+  AND NOT EXISTS(SELECT 1 FROM attachment WHERE blob_hash = blob.content_hash)
+  AND NOT EXISTS(SELECT 1 FROM attachment_variant WHERE blob_hash = blob.content_hash)
+ORDER BY created_at
+LIMIT $3
+FOR UPDATE SKIP LOCKED
+`
+
+	queryDeleteBlob = `
+DELETE
+FROM blob
+WHERE content_hash = $1
+`
+)
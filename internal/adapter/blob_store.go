@@ -0,0 +1,262 @@
+package adapter
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"news-app-api/internal/dto"
+	"news-app-api/internal/entity"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+type (
+	BlobStore interface {
+		Put(ctx context.Context, key string, r io.Reader) error
+		Open(ctx context.Context, key string) (entity.File, error)
+		Move(ctx context.Context, from, to string) error
+		Delete(ctx context.Context, key string) error
+		List(ctx context.Context, prefix string, fn func(entity.StoredFile) error) error
+	}
+
+	localBlobStore struct {
+		root string
+	}
+
+	localFile struct {
+		*os.File
+		size    int64
+		modTime time.Time
+	}
+)
+
+var (
+	ErrInvalidBlobKey = fmt.Errorf("invalid blob key")
+)
+
+func NewLocalBlobStore(root string) (BlobStore, error) {
+	err := os.MkdirAll(root, 0750)
+	if err != nil {
+		return nil, err
+	}
+	return &localBlobStore{root}, nil
+}
+
+func (s *localBlobStore) Put(ctx context.Context, key string, r io.Reader) (err error) {
+	defer func() {
+		if err != nil {
+			var appErr *dto.AppError
+			if !errors.As(err, &appErr) {
+				err = fmt.Errorf("LocalBlobStore - Put: %w", err)
+			}
+		}
+	}()
+
+	p, err := s.path(key)
+	if err != nil {
+		return
+	}
+
+	err = os.MkdirAll(filepath.Dir(p), 0750)
+	if err != nil {
+		return
+	}
+
+	suffix := make([]byte, 4)
+	_, err = rand.Read(suffix)
+	if err != nil {
+		return
+	}
+
+	tmp := filepath.Join(filepath.Dir(p), "."+filepath.Base(p)+"."+hex.EncodeToString(suffix))
+	f, err := os.OpenFile(tmp, os.O_CREATE|os.O_WRONLY|os.O_EXCL, 0640)
+	if err != nil {
+		return
+	}
+	defer func() {
+		if err != nil {
+			f.Close()
+			os.Remove(tmp)
+		}
+	}()
+
+	_, err = io.Copy(f, r)
+	if err != nil {
+		return
+	}
+
+	err = f.Sync()
+	if err != nil {
+		return
+	}
+
+	err = f.Close()
+	if err != nil {
+		return
+	}
+
+	return os.Rename(tmp, p)
+}
+
+func (s *localBlobStore) Open(ctx context.Context, key string) (file entity.File, err error) {
+	defer func() {
+		if err != nil {
+			var appErr *dto.AppError
+			if !errors.As(err, &appErr) {
+				err = fmt.Errorf("LocalBlobStore - Open: %w", err)
+			}
+		}
+	}()
+
+	p, err := s.path(key)
+	if err != nil {
+		return
+	}
+
+	f, err := os.Open(p)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			err = &dto.AppError{
+				Code:    dto.ErrCodeNotFound,
+				Message: "Файл не найден",
+			}
+		}
+		return
+	}
+
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return
+	}
+
+	return &localFile{f, info.Size(), info.ModTime()}, nil
+}
+
+func (s *localBlobStore) Move(ctx context.Context, from, to string) (err error) {
+	defer func() {
+		if err != nil {
+			var appErr *dto.AppError
+			if !errors.As(err, &appErr) {
+				err = fmt.Errorf("LocalBlobStore - Move: %w", err)
+			}
+		}
+	}()
+
+	src, err := s.path(from)
+	if err != nil {
+		return
+	}
+
+	dst, err := s.path(to)
+	if err != nil {
+		return
+	}
+
+	err = os.MkdirAll(filepath.Dir(dst), 0750)
+	if err != nil {
+		return
+	}
+
+	return os.Rename(src, dst)
+}
+
+func (s *localBlobStore) Delete(ctx context.Context, key string) (err error) {
+	defer func() {
+		if err != nil {
+			var appErr *dto.AppError
+			if !errors.As(err, &appErr) {
+				err = fmt.Errorf("LocalBlobStore - Delete: %w", err)
+			}
+		}
+	}()
+
+	p, err := s.path(key)
+	if err != nil {
+		return
+	}
+
+	err = os.Remove(p)
+	if errors.Is(err, os.ErrNotExist) {
+		err = nil
+	}
+	return
+}
+
+func (s *localBlobStore) List(ctx context.Context, prefix string, fn func(entity.StoredFile) error) (err error) {
+	defer func() {
+		if err != nil {
+			var appErr *dto.AppError
+			if !errors.As(err, &appErr) {
+				err = fmt.Errorf("LocalBlobStore - List: %w", err)
+			}
+		}
+	}()
+
+	dir, err := s.path(prefix)
+	if err != nil {
+		return
+	}
+
+	err = filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if strings.HasPrefix(d.Name(), ".") {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if d.IsDir() {
+			return ctx.Err()
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+
+		key, err := filepath.Rel(s.root, p)
+		if err != nil {
+			return err
+		}
+
+		return fn(entity.StoredFile{Key: filepath.ToSlash(key), Size: info.Size(), ModTime: info.ModTime()})
+	})
+	if errors.Is(err, os.ErrNotExist) {
+		err = nil
+	}
+	return
+}
+
+func (s *localBlobStore) path(key string) (string, error) {
+	key, err := cleanBlobKey(key)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(s.root, filepath.FromSlash(key)), nil
+}
+
+func (f *localFile) Size() int64 {
+	return f.size
+}
+
+func (f *localFile) ModTime() time.Time {
+	return f.modTime
+}
+
+func cleanBlobKey(key string) (string, error) {
+	cleaned := path.Clean("/" + key)[1:]
+	if cleaned == "" || cleaned != key || strings.HasPrefix(path.Base(cleaned), ".") {
+		return "", fmt.Errorf("%w: %q", ErrInvalidBlobKey, key)
+	}
+	return cleaned, nil
+}
@@ -0,0 +1,177 @@
+package adapter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"gopkg.in/guregu/null.v3"
+	"news-app-api/internal/dto"
+	"news-app-api/internal/entity"
+	"time"
+)
+
+type (
+	DigestRepository interface {
+		GetDigestPreference(ctx context.Context, userID int64) (entity.DigestPreference, error)
+		UpsertDigestPreference(
+			ctx context.Context,
+			p dto.UpdateDigestPreferenceParams,
+			unsubscribeToken string,
+			nextSendAt null.Int,
+		) (entity.DigestPreference, error)
+		ClaimDueDigests(ctx context.Context, limit int64, lease time.Duration) ([]entity.DigestRecipient, error)
+		MarkDigestSent(ctx context.Context, userID, sentAt int64, nextSendAt null.Int) error
+		UnsubscribeDigest(ctx context.Context, token string) error
+	}
+
+	digestRepository struct {
+		db *pgxpool.Pool
+	}
+)
+
+func NewDigestRepository(db *pgxpool.Pool) DigestRepository {
+	return &digestRepository{db}
+}
+
+func (r *digestRepository) GetDigestPreference(ctx context.Context, userID int64) (p entity.DigestPreference, err error) {
+	defer func() {
+		if err != nil {
+			var appErr *dto.AppError
+			if !errors.As(err, &appErr) {
+				err = fmt.Errorf("DigestRepository - GetDigestPreference: %w", err)
+			}
+		}
+	}()
+	row := r.db.QueryRow(ctx, queryGetDigestPreference, userID)
+	err = row.Scan(
+		&p.Frequency,
+		&p.SendHour,
+		&p.SendWeekday,
+		&p.Timezone,
+		&p.LastSentAt,
+		&p.NextSendAt,
+	)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			err = &dto.AppError{
+				Message: "Настройки рассылки не найдены",
+				Code:    dto.ErrCodeNotFound,
+			}
+		}
+		return
+	}
+	return
+}
+
+func (r *digestRepository) UpsertDigestPreference(
+	ctx context.Context,
+	p dto.UpdateDigestPreferenceParams,
+	unsubscribeToken string,
+	nextSendAt null.Int,
+) (pref entity.DigestPreference, err error) {
+	defer func() {
+		if err != nil {
+			var appErr *dto.AppError
+			if !errors.As(err, &appErr) {
+				err = fmt.Errorf("DigestRepository - UpsertDigestPreference: %w", err)
+			}
+		}
+	}()
+	row := r.db.QueryRow(
+		ctx,
+		queryUpsertDigestPreference,
+		p.UserID,
+		p.Frequency,
+		p.SendHour,
+		p.SendWeekday,
+		p.Timezone,
+		unsubscribeToken,
+		nextSendAt,
+	)
+	err = row.Scan(
+		&pref.Frequency,
+		&pref.SendHour,
+		&pref.SendWeekday,
+		&pref.Timezone,
+		&pref.LastSentAt,
+		&pref.NextSendAt,
+	)
+	return
+}
+
+func (r *digestRepository) ClaimDueDigests(
+	ctx context.Context,
+	limit int64,
+	lease time.Duration,
+) (list []entity.DigestRecipient, err error) {
+	defer func() {
+		if err != nil {
+			var appErr *dto.AppError
+			if !errors.As(err, &appErr) {
+				err = fmt.Errorf("DigestRepository - ClaimDueDigests: %w", err)
+			}
+		}
+	}()
+	list = make([]entity.DigestRecipient, 0, limit)
+	rows, err := r.db.Query(ctx, queryClaimDueDigests, limit, int64(lease.Seconds()))
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+	for rows.Next() {
+		item := entity.DigestRecipient{}
+		err = rows.Scan(
+			&item.UserID,
+			&item.Name,
+			&item.Email,
+			&item.UnsubscribeToken,
+			&item.Preference.Frequency,
+			&item.Preference.SendHour,
+			&item.Preference.SendWeekday,
+			&item.Preference.Timezone,
+			&item.Preference.LastSentAt,
+		)
+		if err != nil {
+			return
+		}
+		list = append(list, item)
+	}
+	return
+}
+
+func (r *digestRepository) MarkDigestSent(ctx context.Context, userID, sentAt int64, nextSendAt null.Int) (err error) {
+	defer func() {
+		if err != nil {
+			var appErr *dto.AppError
+			if !errors.As(err, &appErr) {
+				err = fmt.Errorf("DigestRepository - MarkDigestSent: %w", err)
+			}
+		}
+	}()
+	_, err = r.db.Exec(ctx, queryMarkDigestSent, userID, sentAt, nextSendAt)
+	return
+}
+
+func (r *digestRepository) UnsubscribeDigest(ctx context.Context, token string) (err error) {
+	defer func() {
+		if err != nil {
+			var appErr *dto.AppError
+			if !errors.As(err, &appErr) {
+				err = fmt.Errorf("DigestRepository - UnsubscribeDigest: %w", err)
+			}
+		}
+	}()
+	tag, err := r.db.Exec(ctx, queryUnsubscribeDigest, token)
+	if err != nil {
+		return
+	}
+	if tag.RowsAffected() == 0 {
+		err = &dto.AppError{
+			Message: "Ссылка для отписки недействительна",
+			Code:    dto.ErrCodeNotFound,
+		}
+	}
+	return
+}
@@ -0,0 +1,70 @@
+package adapter
+
+const (
+	queryGetDigestPreference = `
+SELECT frequency,
+       send_hour,
+       send_weekday,
+       timezone,
+       EXTRACT(EPOCH FROM last_sent_at)::BIGINT,
+       EXTRACT(EPOCH FROM next_send_at)::BIGINT
+FROM digest_preference
+WHERE user_id = $1
+`
+
+	queryUpsertDigestPreference = `
+INSERT INTO digest_preference (user_id, frequency, send_hour, send_weekday, timezone, unsubscribe_token, next_send_at)
+VALUES ($1, $2, $3, $4, $5, $6, TO_TIMESTAMP($7::BIGINT))
+ON CONFLICT (user_id) DO UPDATE
+    SET frequency    = EXCLUDED.frequency,
+        send_hour    = EXCLUDED.send_hour,
+        send_weekday = EXCLUDED.send_weekday,
+        timezone     = EXCLUDED.timezone,
+        next_send_at = EXCLUDED.next_send_at
+RETURNING frequency,
+          send_hour,
+          send_weekday,
+          timezone,
+          EXTRACT(EPOCH FROM last_sent_at)::BIGINT,
+          EXTRACT(EPOCH FROM next_send_at)::BIGINT
+`
+
+	queryClaimDueDigests = `
+UPDATE digest_preference
+SET next_send_at = NOW() + $2::BIGINT * INTERVAL '1 second'
+FROM "user"
+WHERE "user".ID_user = digest_preference.user_id
+  AND digest_preference.user_id IN (
+    SELECT user_id
+    FROM digest_preference
+    WHERE frequency <> 'off'
+      AND next_send_at <= NOW()
+    ORDER BY next_send_at
+    LIMIT $1
+    FOR UPDATE SKIP LOCKED
+)
+RETURNING digest_preference.user_id,
+          COALESCE("user".FIO_user, ''),
+          COALESCE("user".Email_user, ''),
+          digest_preference.unsubscribe_token,
+          digest_preference.frequency,
+          digest_preference.send_hour,
+          digest_preference.send_weekday,
+          digest_preference.timezone,
+          EXTRACT(EPOCH FROM digest_preference.last_sent_at)::BIGINT
+`
+
+	queryMarkDigestSent = `
+UPDATE digest_preference
+SET last_sent_at = TO_TIMESTAMP($2::BIGINT),
+    next_send_at = TO_TIMESTAMP($3::BIGINT)
+WHERE user_id = $1
+`
+
+	queryUnsubscribeDigest = `
+UPDATE digest_preference
+SET frequency    = 'off',
+    next_send_at = NULL
+WHERE unsubscribe_token = $1
+`
+)
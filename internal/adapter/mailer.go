@@ -0,0 +1,133 @@
+package adapter
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/textproto"
+	"news-app-api/internal/dto"
+	"news-app-api/internal/entity"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+type (
+	Mailer interface {
+		Send(ctx context.Context, m entity.Mail) error
+	}
+
+	fileDropMailer struct {
+		dir string
+	}
+)
+
+func NewFileDropMailer(dir string) (Mailer, error) {
+	err := os.MkdirAll(dir, 0750)
+	if err != nil {
+		return nil, err
+	}
+	return &fileDropMailer{dir}, nil
+}
+
+func (m *fileDropMailer) Send(ctx context.Context, mail entity.Mail) (err error) {
+	defer func() {
+		if err != nil {
+			var appErr *dto.AppError
+			if !errors.As(err, &appErr) {
+				err = fmt.Errorf("FileDropMailer - Send: %w", err)
+			}
+		}
+	}()
+
+	data, err := buildMIMEMessage(mail, time.Now())
+	if err != nil {
+		return
+	}
+
+	suffix := make([]byte, 4)
+	_, err = rand.Read(suffix)
+	if err != nil {
+		return
+	}
+
+	name := fmt.Sprintf("%d-%s.eml", time.Now().UnixNano(), hex.EncodeToString(suffix))
+	tmp := filepath.Join(m.dir, "."+name)
+
+	err = os.WriteFile(tmp, data, 0640)
+	if err != nil {
+		return
+	}
+
+	return os.Rename(tmp, filepath.Join(m.dir, name))
+}
+
+func buildMIMEMessage(mail entity.Mail, date time.Time) ([]byte, error) {
+	var buf bytes.Buffer
+	mw := multipart.NewWriter(&buf)
+
+	headers := map[string]string{
+		"From":         mail.From,
+		"To":           mail.To,
+		"Subject":      mime.QEncoding.Encode("utf-8", mail.Subject),
+		"Date":         date.Format(time.RFC1123Z),
+		"MIME-Version": "1.0",
+		"Content-Type": fmt.Sprintf("multipart/alternative; boundary=%q", mw.Boundary()),
+	}
+	for k, v := range mail.Headers {
+		headers[k] = v
+	}
+
+	keys := make([]string, 0, len(headers))
+	for k := range headers {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		fmt.Fprintf(&buf, "%s: %s\r\n", k, headers[k])
+	}
+	buf.WriteString("\r\n")
+
+	parts := []struct {
+		contentType string
+		body        string
+	}{
+		{"text/plain; charset=utf-8", mail.Text},
+		{"text/html; charset=utf-8", mail.HTML},
+	}
+	for _, part := range parts {
+		if part.body == "" {
+			continue
+		}
+		w, err := mw.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {part.contentType},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return nil, err
+		}
+		qp := quotedprintable.NewWriter(w)
+		_, err = qp.Write([]byte(part.body))
+		if err != nil {
+			return nil, err
+		}
+		err = qp.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err := mw.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
@@ -6,22 +6,35 @@ import (
 	"fmt"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
+	"gopkg.in/guregu/null.v3"
 	"news-app-api/internal/dto"
 	"news-app-api/internal/entity"
+	"strings"
 )
 
 type (
 	MediaRepository interface {
-		GetMediaByRegistrationNumber(ctx context.Context, registrationNumber int64) (entity.Media, error)
+		GetMediaByRegistrationNumber(ctx context.Context, registrationNumber string) (entity.Media, error)
 		GetMediaByName(ctx context.Context, name string) (entity.Media, error)
 		GetMediaByEmail(ctx context.Context, email string) (entity.Media, error)
 		CreateMedia(ctx context.Context, p dto.RegisterMediaParams) (entity.Media, error)
 		GetMediaByID(ctx context.Context, mediaID int64) (entity.Media, error)
 		GetMediaList(ctx context.Context, p dto.GetMediaListParams) ([]entity.MediaListItem, error)
-		CountMedia(ctx context.Context) (int64, error)
+		CountMedia(ctx context.Context, p dto.GetMediaListParams) (int64, error)
+		UpdateMediaAccount(ctx context.Context, p dto.UpdateMediaAccountParams) (entity.Media, error)
+		GetMediaProfile(ctx context.Context, mediaID, userID int64) (entity.MediaProfile, error)
+		UpdateMediaProfile(ctx context.Context, p dto.UpdateMediaProfileParams) error
+		TouchMediaImage(ctx context.Context, mediaID int64, kind string) error
 		IsSubscriptionExists(ctx context.Context, mediaID, userID int64) (bool, error)
 		CreateSubscription(ctx context.Context, mediaID, userID int64) error
 		DeleteSubscription(ctx context.Context, mediaID, userID int64) error
+		GetSubscriberList(ctx context.Context, p dto.GetSubscriberListParams) ([]entity.Subscriber, error)
+		CountSubscribers(ctx context.Context, mediaID int64) (int64, error)
+		GetSubscriptionStats(ctx context.Context, p dto.GetSubscriptionStatsParams) ([]entity.SubscriptionStatsDay, error)
+		MuteMedia(ctx context.Context, mediaID, userID, until int64) error
+		UnmuteMedia(ctx context.Context, mediaID, userID int64) error
+		GetMutedMediaList(ctx context.Context, p dto.GetMutedMediaListParams) ([]entity.MutedMedia, error)
+		CountMutedMedia(ctx context.Context, userID int64) (int64, error)
 	}
 
 	mediaRepository struct {
@@ -35,7 +48,7 @@ func NewMediaRepository(db *pgxpool.Pool) MediaRepository {
 
 func (r *mediaRepository) GetMediaByRegistrationNumber(
 	ctx context.Context,
-	registrationNumber int64,
+	registrationNumber string,
 ) (m entity.Media, err error) {
 	defer func() {
 		if err != nil {
@@ -204,7 +217,17 @@ func (r *mediaRepository) GetMediaList(
 		}
 	}()
 	list = make([]entity.MediaListItem, 0, p.Limit.Int64)
-	rows, err := r.db.Query(ctx, queryGetMediaList, p.Limit, p.Offset)
+	rows, err := r.db.Query(
+		ctx,
+		queryGetMediaList,
+		p.Query,
+		mediaNamePattern(p.Query),
+		p.ActiveWithinDays,
+		p.UserID,
+		p.Sort.String,
+		p.Limit,
+		p.Offset,
+	)
 	if err != nil {
 		return
 	}
@@ -219,6 +242,7 @@ func (r *mediaRepository) GetMediaList(
 			&item.Editor.LastName,
 			&item.Editor.FirstName,
 			&item.SubscriptionCount,
+			&item.IsSubscribed,
 		)
 		if err != nil {
 			return
@@ -228,7 +252,7 @@ func (r *mediaRepository) GetMediaList(
 	return
 }
 
-func (r *mediaRepository) CountMedia(ctx context.Context) (v int64, err error) {
+func (r *mediaRepository) CountMedia(ctx context.Context, p dto.GetMediaListParams) (v int64, err error) {
 	defer func() {
 		if err != nil {
 			var appErr *dto.AppError
@@ -237,11 +261,127 @@ func (r *mediaRepository) CountMedia(ctx context.Context) (v int64, err error) {
 			}
 		}
 	}()
-	row := r.db.QueryRow(ctx, queryCountMedia)
+	row := r.db.QueryRow(ctx, queryCountMedia, p.Query, mediaNamePattern(p.Query), p.ActiveWithinDays)
 	err = row.Scan(&v)
 	return
 }
 
+func (r *mediaRepository) UpdateMediaAccount(
+	ctx context.Context,
+	p dto.UpdateMediaAccountParams,
+) (m entity.Media, err error) {
+	defer func() {
+		if err != nil {
+			var appErr *dto.AppError
+			if !errors.As(err, &appErr) {
+				err = fmt.Errorf("MediaRepository - UpdateMediaAccount: %w", err)
+			}
+		}
+	}()
+	row := r.db.QueryRow(
+		ctx,
+		queryUpdateMediaAccount,
+		p.MediaID,
+		p.Name,
+		p.Email,
+		p.Editor.LastName,
+		p.Editor.FirstName,
+	)
+	err = row.Scan(
+		&m.ID,
+		&m.RegistrationNumber,
+		&m.Name,
+		&m.Email,
+		&m.Editor.LastName,
+		&m.Editor.FirstName,
+		&m.Password,
+	)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			err = &dto.AppError{
+				Message: "СМИ не найдено",
+				Code:    dto.ErrCodeNotFound,
+			}
+		}
+		return
+	}
+	return
+}
+
+func (r *mediaRepository) GetMediaProfile(
+	ctx context.Context,
+	mediaID, userID int64,
+) (m entity.MediaProfile, err error) {
+	defer func() {
+		if err != nil {
+			var appErr *dto.AppError
+			if !errors.As(err, &appErr) {
+				err = fmt.Errorf("MediaRepository - GetMediaProfile: %w", err)
+			}
+		}
+	}()
+	row := r.db.QueryRow(ctx, queryGetMediaProfile, mediaID, userID)
+	err = row.Scan(
+		&m.ID,
+		&m.RegistrationNumber,
+		&m.Name,
+		&m.Email,
+		&m.Editor.LastName,
+		&m.Editor.FirstName,
+		&m.Description,
+		&m.Website,
+		&m.SocialLinks,
+		&m.LogoUpdatedAt,
+		&m.CoverUpdatedAt,
+		&m.IsSubscribed,
+		&m.CreatedAt,
+		&m.Stats.SubscriptionCount,
+		&m.Stats.NewsCount,
+		&m.Stats.FavoriteCount,
+		&m.Stats.LastPublishedAt,
+	)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			err = &dto.AppError{
+				Message: "СМИ не найдено",
+				Code:    dto.ErrCodeNotFound,
+			}
+		}
+		return
+	}
+	return
+}
+
+func (r *mediaRepository) UpdateMediaProfile(ctx context.Context, p dto.UpdateMediaProfileParams) (err error) {
+	defer func() {
+		if err != nil {
+			var appErr *dto.AppError
+			if !errors.As(err, &appErr) {
+				err = fmt.Errorf("MediaRepository - UpdateMediaProfile: %w", err)
+			}
+		}
+	}()
+	_, err = r.db.Exec(ctx, queryUpdateMediaProfile, p.MediaID, p.Description, p.Website, p.SocialLinks)
+	return
+}
+
+func (r *mediaRepository) TouchMediaImage(ctx context.Context, mediaID int64, kind string) (err error) {
+	defer func() {
+		if err != nil {
+			var appErr *dto.AppError
+			if !errors.As(err, &appErr) {
+				err = fmt.Errorf("MediaRepository - TouchMediaImage: %w", err)
+			}
+		}
+	}()
+	query := queryTouchMediaLogo
+	if kind == dto.MediaImageCover {
+		query = queryTouchMediaCover
+	}
+	_, err = r.db.Exec(ctx, query, mediaID)
+	return
+}
+
 func (r *mediaRepository) IsSubscriptionExists(ctx context.Context, mediaID, userID int64) (v bool, err error) {
 	defer func() {
 		if err != nil {
@@ -281,3 +421,170 @@ func (r *mediaRepository) DeleteSubscription(ctx context.Context, mediaID, userI
 	_, err = r.db.Exec(ctx, queryDeleteSubscription, mediaID, userID)
 	return
 }
+
+func (r *mediaRepository) GetSubscriberList(
+	ctx context.Context,
+	p dto.GetSubscriberListParams,
+) (list []entity.Subscriber, err error) {
+	defer func() {
+		if err != nil {
+			var appErr *dto.AppError
+			if !errors.As(err, &appErr) {
+				err = fmt.Errorf("MediaRepository - GetSubscriberList: %w", err)
+			}
+		}
+	}()
+	list = make([]entity.Subscriber, 0, p.Limit.Int64)
+	rows, err := r.db.Query(ctx, queryGetSubscriberList, p.MediaID, p.Limit, p.Offset)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+	for rows.Next() {
+		item := entity.Subscriber{}
+		err = rows.Scan(
+			&item.UserID,
+			&item.Name,
+			&item.SubscribedAt,
+		)
+		if err != nil {
+			return
+		}
+		list = append(list, item)
+	}
+	return
+}
+
+func (r *mediaRepository) CountSubscribers(ctx context.Context, mediaID int64) (v int64, err error) {
+	defer func() {
+		if err != nil {
+			var appErr *dto.AppError
+			if !errors.As(err, &appErr) {
+				err = fmt.Errorf("MediaRepository - CountSubscribers: %w", err)
+			}
+		}
+	}()
+	row := r.db.QueryRow(ctx, queryCountSubscribers, mediaID)
+	err = row.Scan(&v)
+	return
+}
+
+func (r *mediaRepository) GetSubscriptionStats(
+	ctx context.Context,
+	p dto.GetSubscriptionStatsParams,
+) (list []entity.SubscriptionStatsDay, err error) {
+	defer func() {
+		if err != nil {
+			var appErr *dto.AppError
+			if !errors.As(err, &appErr) {
+				err = fmt.Errorf("MediaRepository - GetSubscriptionStats: %w", err)
+			}
+		}
+	}()
+	list = make([]entity.SubscriptionStatsDay, 0)
+	rows, err := r.db.Query(ctx, queryGetSubscriptionStats, p.MediaID, p.From.String, p.To.String, p.Timezone.String)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+	for rows.Next() {
+		item := entity.SubscriptionStatsDay{}
+		err = rows.Scan(
+			&item.Date,
+			&item.Subscribed,
+			&item.Unsubscribed,
+		)
+		if err != nil {
+			return
+		}
+		item.Net = item.Subscribed - item.Unsubscribed
+		list = append(list, item)
+	}
+	return
+}
+
+func (r *mediaRepository) MuteMedia(ctx context.Context, mediaID, userID, until int64) (err error) {
+	defer func() {
+		if err != nil {
+			var appErr *dto.AppError
+			if !errors.As(err, &appErr) {
+				err = fmt.Errorf("MediaRepository - MuteMedia: %w", err)
+			}
+		}
+	}()
+	_, err = r.db.Exec(ctx, queryMuteMedia, userID, mediaID, until)
+	return
+}
+
+func (r *mediaRepository) UnmuteMedia(ctx context.Context, mediaID, userID int64) (err error) {
+	defer func() {
+		if err != nil {
+			var appErr *dto.AppError
+			if !errors.As(err, &appErr) {
+				err = fmt.Errorf("MediaRepository - UnmuteMedia: %w", err)
+			}
+		}
+	}()
+	_, err = r.db.Exec(ctx, queryUnmuteMedia, userID, mediaID)
+	return
+}
+
+func (r *mediaRepository) GetMutedMediaList(
+	ctx context.Context,
+	p dto.GetMutedMediaListParams,
+) (list []entity.MutedMedia, err error) {
+	defer func() {
+		if err != nil {
+			var appErr *dto.AppError
+			if !errors.As(err, &appErr) {
+				err = fmt.Errorf("MediaRepository - GetMutedMediaList: %w", err)
+			}
+		}
+	}()
+	list = make([]entity.MutedMedia, 0, p.Limit.Int64)
+	rows, err := r.db.Query(ctx, queryGetMutedMediaList, p.UserID, p.Limit, p.Offset)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+	for rows.Next() {
+		item := entity.MutedMedia{}
+		err = rows.Scan(
+			&item.Media.ID,
+			&item.Media.RegistrationNumber,
+			&item.Media.Name,
+			&item.Media.Email,
+			&item.Media.Editor.LastName,
+			&item.Media.Editor.FirstName,
+			&item.Media.SubscriptionCount,
+			&item.Until,
+		)
+		if err != nil {
+			return
+		}
+		list = append(list, item)
+	}
+	return
+}
+
+func (r *mediaRepository) CountMutedMedia(ctx context.Context, userID int64) (v int64, err error) {
+	defer func() {
+		if err != nil {
+			var appErr *dto.AppError
+			if !errors.As(err, &appErr) {
+				err = fmt.Errorf("MediaRepository - CountMutedMedia: %w", err)
+			}
+		}
+	}()
+	row := r.db.QueryRow(ctx, queryCountMutedMedia, userID)
+	err = row.Scan(&v)
+	return
+}
+
+func mediaNamePattern(q null.String) null.String {
+	if !q.Valid {
+		return q
+	}
+	escaped := strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(q.String)
+	return null.StringFrom("%" + escaped + "%")
+}
@@ -0,0 +1,512 @@
+package adapter
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"image"
+	"io"
+	"news-app-api/internal/dto"
+	"news-app-api/internal/entity"
+)
+
+const (
+	oggTailSize    = 64 << 10
+	mp3ScanSize    = 64 << 10
+	mp4MaxBoxDepth = 8
+	wavMaxFmtSize  = 40
+)
+
+var (
+	errMalformedMedia = errors.New("malformed media file")
+
+	mp3Bitrates = map[bool][16]int{
+		true:  {0, 32, 40, 48, 56, 64, 80, 96, 112, 128, 160, 192, 224, 256, 320, 0},
+		false: {0, 8, 16, 24, 32, 40, 48, 56, 64, 80, 96, 112, 128, 144, 160, 0},
+	}
+	mp3SampleRates = [4][3]int{
+		{11025, 12000, 8000},
+		{0, 0, 0},
+		{22050, 24000, 16000},
+		{44100, 48000, 32000},
+	}
+)
+
+type (
+	MetadataExtractor interface {
+		Extract(contentType string, r io.ReaderAt, size int64) (entity.AttachmentMetadata, error)
+	}
+
+	metadataExtractor struct{}
+
+	wavFormat struct {
+		format        uint16
+		channels      int
+		sampleRate    int
+		byteRate      int
+		bitsPerSample int
+	}
+
+	mp4Box struct {
+		typ    string
+		offset int64
+		size   int64
+	}
+)
+
+func NewMetadataExtractor() MetadataExtractor {
+	return &metadataExtractor{}
+}
+
+func (e *metadataExtractor) Extract(
+	contentType string,
+	r io.ReaderAt,
+	size int64,
+) (m entity.AttachmentMetadata, err error) {
+	defer func() {
+		if err != nil {
+			err = fmt.Errorf("MetadataExtractor - Extract: %w", err)
+		}
+	}()
+
+	switch contentType {
+	case "image/jpeg", "image/png", "image/webp", "image/gif":
+		var cfg image.Config
+		cfg, _, err = image.DecodeConfig(io.NewSectionReader(r, 0, size))
+		m.Width, m.Height = cfg.Width, cfg.Height
+	case "audio/wav":
+		m, err = wavMetadata(r, size)
+	case "audio/mpeg":
+		m, err = mp3Metadata(r, size)
+	case "audio/ogg":
+		m, err = oggMetadata(r, size)
+	case "audio/mp4", "video/mp4":
+		m, err = mp4Metadata(r, size)
+	}
+	if errors.Is(err, errMalformedMedia) || errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return entity.AttachmentMetadata{}, &dto.AppError{
+			Message: "Файл повреждён или имеет неверный формат",
+			Code:    dto.ErrCodeBadRequest,
+		}
+	} else if err != nil {
+		return entity.AttachmentMetadata{}, err
+	}
+
+	if m.Bitrate == 0 && m.Duration > 0 {
+		m.Bitrate = int64(float64(size*8) / m.Duration)
+	}
+	return
+}
+
+func wavMetadata(r io.ReaderAt, size int64) (m entity.AttachmentMetadata, err error) {
+	sr := io.NewSectionReader(r, 0, size)
+	f, length, err := readWAVHeader(sr)
+	if err != nil {
+		return
+	}
+
+	offset, err := sr.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return
+	}
+	if offset+length > size {
+		length = size - offset
+	}
+
+	m.Channels = f.channels
+	m.SampleRate = f.sampleRate
+	m.Bitrate = int64(f.byteRate) * 8
+	m.Duration = float64(length) / float64(f.byteRate)
+	m.Codec = "pcm"
+	if f.format == 3 {
+		m.Codec = "pcm_float"
+	}
+	return
+}
+
+// readWAVHeader walks the RIFF chunks up to the data chunk and leaves r at its first byte.
+func readWAVHeader(r io.Reader) (f wavFormat, dataSize int64, err error) {
+	header := make([]byte, 12)
+	_, err = io.ReadFull(r, header)
+	if err != nil {
+		return
+	}
+	if !bytes.Equal(header[:4], []byte("RIFF")) || !bytes.Equal(header[8:], []byte("WAVE")) {
+		return f, 0, fmt.Errorf("%w: invalid WAV header", errMalformedMedia)
+	}
+
+	chunk := make([]byte, 8)
+	for {
+		_, err = io.ReadFull(r, chunk)
+		if err == io.EOF {
+			return f, 0, fmt.Errorf("%w: WAV data chunk not found", errMalformedMedia)
+		} else if err != nil {
+			return
+		}
+		id, length := string(chunk[:4]), int64(binary.LittleEndian.Uint32(chunk[4:]))
+
+		switch id {
+		case "fmt ":
+			if length < 16 {
+				return f, 0, fmt.Errorf("%w: invalid WAV fmt chunk", errMalformedMedia)
+			}
+			body := make([]byte, wavMaxFmtSize)
+			if length < wavMaxFmtSize {
+				body = body[:length]
+			}
+			_, err = io.ReadFull(r, body)
+			if err != nil {
+				return
+			}
+			length -= int64(len(body))
+
+			f.format = binary.LittleEndian.Uint16(body)
+			f.channels = int(binary.LittleEndian.Uint16(body[2:]))
+			f.sampleRate = int(binary.LittleEndian.Uint32(body[4:]))
+			f.byteRate = int(binary.LittleEndian.Uint32(body[8:]))
+			f.bitsPerSample = int(binary.LittleEndian.Uint16(body[14:]))
+			if f.format == 0xfffe && len(body) >= 26 {
+				f.format = binary.LittleEndian.Uint16(body[24:])
+			}
+			if f.channels < 1 || f.sampleRate < 1 || f.byteRate < 1 {
+				return f, 0, fmt.Errorf("%w: invalid WAV fmt chunk", errMalformedMedia)
+			}
+		case "data":
+			if f.channels == 0 {
+				return f, 0, fmt.Errorf("%w: WAV data chunk precedes fmt chunk", errMalformedMedia)
+			}
+			return f, length, nil
+		}
+
+		err = skipBytes(r, length+length%2)
+		if err != nil {
+			return
+		}
+	}
+}
+
+func skipBytes(r io.Reader, n int64) error {
+	if s, ok := r.(io.Seeker); ok {
+		_, err := s.Seek(n, io.SeekCurrent)
+		return err
+	}
+	_, err := io.CopyN(io.Discard, r, n)
+	return err
+}
+
+func mp3Metadata(r io.ReaderAt, size int64) (m entity.AttachmentMetadata, err error) {
+	start := int64(0)
+	id3 := make([]byte, 10)
+	if _, err = r.ReadAt(id3, 0); err == nil && bytes.Equal(id3[:3], []byte("ID3")) {
+		start = 10 + (int64(id3[6])<<21 | int64(id3[7])<<14 | int64(id3[8])<<7 | int64(id3[9]))
+		if id3[5]&0x10 != 0 {
+			start += 10
+		}
+	}
+
+	buf := make([]byte, mp3ScanSize)
+	n, err := r.ReadAt(buf, start)
+	if err != nil && err != io.EOF {
+		return
+	}
+	buf = buf[:n]
+
+	for i := 0; i+4 <= len(buf); i++ {
+		if buf[i] != 0xff || buf[i+1]&0xe0 != 0xe0 {
+			continue
+		}
+
+		version := int(buf[i+1] >> 3 & 0x03)
+		layer := int(buf[i+1] >> 1 & 0x03)
+		bitrateIndex := int(buf[i+2] >> 4)
+		sampleRateIndex := int(buf[i+2] >> 2 & 0x03)
+		if version == 1 || layer != 1 || bitrateIndex == 0 || bitrateIndex == 15 || sampleRateIndex == 3 {
+			continue
+		}
+
+		mpeg1 := version == 3
+		mono := buf[i+3]>>6 == 3
+		m.SampleRate = mp3SampleRates[version][sampleRateIndex]
+		m.Bitrate = int64(mp3Bitrates[mpeg1][bitrateIndex]) * 1000
+		m.Channels = 2
+		if mono {
+			m.Channels = 1
+		}
+		m.Codec = "mp3"
+
+		samplesPerFrame := 576
+		sideInfo := 17
+		if mpeg1 {
+			samplesPerFrame = 1152
+			sideInfo = 32
+		}
+		if mono {
+			sideInfo = 9
+			if mpeg1 {
+				sideInfo = 17
+			}
+		}
+
+		audioSize := size - start - int64(i)
+		frames := mp3FrameCount(buf[i:], 4+sideInfo)
+		if frames > 0 {
+			m.Duration = float64(frames) * float64(samplesPerFrame) / float64(m.SampleRate)
+			m.Bitrate = int64(float64(audioSize*8) / m.Duration)
+		} else {
+			m.Duration = float64(audioSize*8) / float64(m.Bitrate)
+		}
+		return m, nil
+	}
+
+	return m, fmt.Errorf("%w: MP3 frame not found", errMalformedMedia)
+}
+
+func mp3FrameCount(frame []byte, xingOffset int) int64 {
+	if len(frame) >= xingOffset+12 {
+		tag := string(frame[xingOffset : xingOffset+4])
+		flags := binary.BigEndian.Uint32(frame[xingOffset+4:])
+		if (tag == "Xing" || tag == "Info") && flags&0x01 != 0 {
+			return int64(binary.BigEndian.Uint32(frame[xingOffset+8:]))
+		}
+	}
+	if len(frame) >= 36+18 && string(frame[36:40]) == "VBRI" {
+		return int64(binary.BigEndian.Uint32(frame[36+14:]))
+	}
+	return 0
+}
+
+func oggMetadata(r io.ReaderAt, size int64) (m entity.AttachmentMetadata, err error) {
+	head := make([]byte, 27+255+64)
+	n, err := r.ReadAt(head, 0)
+	if err != nil && err != io.EOF {
+		return
+	}
+	head = head[:n]
+	if len(head) < 28 || !bytes.Equal(head[:4], []byte("OggS")) {
+		return m, fmt.Errorf("%w: invalid Ogg header", errMalformedMedia)
+	}
+
+	segments := 27 + int(head[26])
+	if segments > len(head) {
+		return m, fmt.Errorf("%w: truncated Ogg page", errMalformedMedia)
+	}
+	packet := head[segments:]
+	preSkip := int64(0)
+	switch {
+	case len(packet) >= 30 && bytes.HasPrefix(packet, []byte("\x01vorbis")):
+		m.Codec = "vorbis"
+		m.Channels = int(packet[11])
+		m.SampleRate = int(binary.LittleEndian.Uint32(packet[12:]))
+	case len(packet) >= 19 && bytes.HasPrefix(packet, []byte("OpusHead")):
+		m.Codec = "opus"
+		m.Channels = int(packet[9])
+		m.SampleRate = 48000
+		preSkip = int64(binary.LittleEndian.Uint16(packet[10:]))
+	default:
+		return m, errors.New("unsupported Ogg codec")
+	}
+	if m.SampleRate == 0 {
+		return m, fmt.Errorf("%w: invalid Ogg sample rate", errMalformedMedia)
+	}
+
+	tailSize := int64(oggTailSize)
+	if tailSize > size {
+		tailSize = size
+	}
+	tail := make([]byte, tailSize)
+	_, err = r.ReadAt(tail, size-tailSize)
+	if err != nil && err != io.EOF {
+		return
+	}
+	err = nil
+
+	i := bytes.LastIndex(tail, []byte("OggS\x00"))
+	if i < 0 || i+14 > len(tail) {
+		return m, nil
+	}
+	granule := int64(binary.LittleEndian.Uint64(tail[i+6:]))
+	if granule > preSkip {
+		m.Duration = float64(granule-preSkip) / float64(m.SampleRate)
+	}
+	return
+}
+
+func mp4Metadata(r io.ReaderAt, size int64) (m entity.AttachmentMetadata, err error) {
+	moov, ok, err := findMP4Box(r, 0, size, "moov")
+	if err != nil {
+		return
+	}
+	if !ok {
+		return m, fmt.Errorf("%w: MP4 moov box not found", errMalformedMedia)
+	}
+
+	mvhd, ok, err := findMP4Box(r, moov.offset, moov.size, "mvhd")
+	if err != nil {
+		return
+	}
+	if ok {
+		m.Duration, err = mp4Duration(r, mvhd)
+		if err != nil {
+			return
+		}
+	}
+
+	boxes, err := readMP4Boxes(r, moov.offset, moov.size)
+	if err != nil {
+		return
+	}
+	for _, trak := range boxes {
+		if trak.typ != "trak" {
+			continue
+		}
+		err = mp4Track(r, trak, &m)
+		if err != nil {
+			return
+		}
+	}
+	return
+}
+
+func mp4Track(r io.ReaderAt, trak mp4Box, m *entity.AttachmentMetadata) error {
+	var handler string
+	hdlr, ok, err := findMP4Box(r, trak.offset, trak.size, "mdia", "hdlr")
+	if err != nil {
+		return err
+	}
+	if ok {
+		b := make([]byte, 12)
+		_, err = r.ReadAt(b, hdlr.offset)
+		if err != nil {
+			return err
+		}
+		handler = string(b[8:12])
+	}
+
+	stsd, ok, err := findMP4Box(r, trak.offset, trak.size, "mdia", "minf", "stbl", "stsd")
+	if err != nil || !ok {
+		return err
+	}
+	entry := make([]byte, 36)
+	n, err := r.ReadAt(entry, stsd.offset+8)
+	if err != nil && err != io.EOF {
+		return err
+	}
+	entry = entry[:n]
+	if len(entry) < 8 {
+		return nil
+	}
+	codec := string(entry[4:8])
+
+	switch handler {
+	case "vide":
+		tkhd, ok, err := findMP4Box(r, trak.offset, trak.size, "tkhd")
+		if err != nil || !ok {
+			return err
+		}
+		if tkhd.size < 8 {
+			return fmt.Errorf("%w: invalid MP4 tkhd box", errMalformedMedia)
+		}
+		b := make([]byte, 8)
+		_, err = r.ReadAt(b, tkhd.offset+tkhd.size-8)
+		if err != nil {
+			return err
+		}
+		if m.Width == 0 {
+			m.Width = int(binary.BigEndian.Uint32(b) >> 16)
+			m.Height = int(binary.BigEndian.Uint32(b[4:]) >> 16)
+			m.Codec = codec
+		}
+	case "soun":
+		if len(entry) >= 36 {
+			m.Channels = int(binary.BigEndian.Uint16(entry[24:]))
+			m.SampleRate = int(binary.BigEndian.Uint32(entry[32:]) >> 16)
+		}
+		if m.Codec == "" {
+			m.Codec = codec
+		}
+	}
+	return nil
+}
+
+func mp4Duration(r io.ReaderAt, mvhd mp4Box) (float64, error) {
+	b := make([]byte, 32)
+	_, err := r.ReadAt(b[:4], mvhd.offset)
+	if err != nil {
+		return 0, err
+	}
+
+	var timescale, duration uint64
+	if b[0] == 1 {
+		_, err = r.ReadAt(b[:32], mvhd.offset)
+		if err != nil {
+			return 0, err
+		}
+		timescale = uint64(binary.BigEndian.Uint32(b[20:]))
+		duration = binary.BigEndian.Uint64(b[24:])
+	} else {
+		_, err = r.ReadAt(b[:20], mvhd.offset)
+		if err != nil {
+			return 0, err
+		}
+		timescale = uint64(binary.BigEndian.Uint32(b[12:]))
+		duration = uint64(binary.BigEndian.Uint32(b[16:]))
+	}
+
+	if timescale == 0 || duration == 0xffffffff || duration == 0xffffffffffffffff {
+		return 0, nil
+	}
+	return float64(duration) / float64(timescale), nil
+}
+
+func findMP4Box(r io.ReaderAt, offset, size int64, path ...string) (mp4Box, bool, error) {
+	if len(path) > mp4MaxBoxDepth {
+		return mp4Box{}, false, fmt.Errorf("%w: MP4 box path is too deep", errMalformedMedia)
+	}
+
+	boxes, err := readMP4Boxes(r, offset, size)
+	if err != nil {
+		return mp4Box{}, false, err
+	}
+	for _, box := range boxes {
+		if box.typ != path[0] {
+			continue
+		}
+		if len(path) == 1 {
+			return box, true, nil
+		}
+		return findMP4Box(r, box.offset, box.size, path[1:]...)
+	}
+	return mp4Box{}, false, nil
+}
+
+func readMP4Boxes(r io.ReaderAt, offset, size int64) (boxes []mp4Box, err error) {
+	header := make([]byte, 16)
+	end := offset + size
+	for offset+8 <= end {
+		_, err = r.ReadAt(header[:8], offset)
+		if err != nil {
+			return
+		}
+
+		boxSize, headerSize := int64(binary.BigEndian.Uint32(header)), int64(8)
+		typ := string(header[4:8])
+		switch boxSize {
+		case 0:
+			boxSize = end - offset
+		case 1:
+			_, err = r.ReadAt(header[8:16], offset+8)
+			if err != nil {
+				return
+			}
+			boxSize, headerSize = int64(binary.BigEndian.Uint64(header[8:])), 16
+		}
+		if boxSize < headerSize || boxSize > end-offset {
+			return boxes, fmt.Errorf("%w: invalid MP4 box %q", errMalformedMedia, typ)
+		}
+
+		boxes = append(boxes, mp4Box{typ, offset + headerSize, boxSize - headerSize})
+		offset += boxSize
+	}
+	return
+}
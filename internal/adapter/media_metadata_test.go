@@ -0,0 +1,233 @@
+package adapter
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"math"
+	"news-app-api/internal/dto"
+	"news-app-api/internal/entity"
+	"testing"
+)
+
+func TestMetadataExtractor(t *testing.T) {
+	tests := []struct {
+		name        string
+		contentType string
+		data        []byte
+		want        entity.AttachmentMetadata
+	}{
+		{
+			name:        "wav",
+			contentType: "audio/wav",
+			data:        testWAV(2, 8000, 16, 32000),
+			want:        entity.AttachmentMetadata{Duration: 1, Bitrate: 256000, SampleRate: 8000, Channels: 2, Codec: "pcm"},
+		},
+		{
+			name:        "mp3 cbr",
+			contentType: "audio/mpeg",
+			data:        testMP3(10, 0),
+			want:        entity.AttachmentMetadata{Duration: 0.260625, Bitrate: 128000, SampleRate: 44100, Channels: 2, Codec: "mp3"},
+		},
+		{
+			name:        "mp3 xing",
+			contentType: "audio/mpeg",
+			data:        testMP3(2, 100),
+			want:        entity.AttachmentMetadata{Duration: 100 * 1152 / 44100.0, Bitrate: 2554, SampleRate: 44100, Channels: 2, Codec: "mp3"},
+		},
+		{
+			name:        "ogg opus",
+			contentType: "audio/ogg",
+			data:        testOggOpus(2*48000 + 312),
+			want:        entity.AttachmentMetadata{Duration: 2, Bitrate: 572, SampleRate: 48000, Channels: 2, Codec: "opus"},
+		},
+		{
+			name:        "mp4 video",
+			contentType: "video/mp4",
+			data:        testMP4(),
+			want:        entity.AttachmentMetadata{Duration: 2.5, Bitrate: 1235, Width: 640, Height: 360, Codec: "avc1"},
+		},
+	}
+
+	e := NewMetadataExtractor()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := e.Extract(tt.contentType, bytes.NewReader(tt.data), int64(len(tt.data)))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if math.Abs(got.Duration-tt.want.Duration) > 1e-6 {
+				t.Errorf("duration = %v, want %v", got.Duration, tt.want.Duration)
+			}
+			got.Duration = tt.want.Duration
+			if got != tt.want {
+				t.Errorf("metadata = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMetadataExtractorMalformed(t *testing.T) {
+	oggHeader := append([]byte("OggS"), make([]byte, 26)...)
+	oggHeader[26] = 255
+
+	tests := []struct {
+		name        string
+		contentType string
+		data        []byte
+	}{
+		{"ogg segment table past end", "audio/ogg", oggHeader},
+		{"ogg bad magic", "audio/ogg", bytes.Repeat([]byte{0}, 64)},
+		{"wav truncated header", "audio/wav", testWAV(1, 8000, 8, 100)[:10]},
+		{"wav without data", "audio/wav", testWAV(1, 8000, 8, 100)[:36]},
+		{"wav short fmt chunk", "audio/wav", shortFmtWAV()},
+		{"mp3 without frames", "audio/mpeg", bytes.Repeat([]byte{0}, 1024)},
+		{"mp4 truncated box", "video/mp4", testMP4()[:40]},
+		{"mp4 without moov", "video/mp4", testMP4Box("ftyp", []byte("isom"))},
+		{"mp4 oversized box", "video/mp4", oversizedMP4Box()},
+	}
+
+	e := NewMetadataExtractor()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := e.Extract(tt.contentType, bytes.NewReader(tt.data), int64(len(tt.data)))
+			var appErr *dto.AppError
+			if !errors.As(err, &appErr) || appErr.Code != dto.ErrCodeBadRequest {
+				t.Fatalf("error = %v, want bad request AppError", err)
+			}
+		})
+	}
+}
+
+func TestMetadataExtractorTruncated(t *testing.T) {
+	inputs := map[string][]byte{
+		"audio/wav":  testWAV(2, 8000, 16, 400),
+		"audio/mpeg": testMP3(2, 100),
+		"audio/ogg":  testOggOpus(48000),
+		"video/mp4":  testMP4(),
+	}
+
+	e := NewMetadataExtractor()
+	for contentType, data := range inputs {
+		for n := 0; n < len(data); n++ {
+			func() {
+				defer func() {
+					if r := recover(); r != nil {
+						t.Fatalf("%s truncated to %d bytes panicked: %v", contentType, n, r)
+					}
+				}()
+				_, _ = e.Extract(contentType, bytes.NewReader(data[:n]), int64(n))
+			}()
+		}
+	}
+}
+
+func testWAV(channels, sampleRate, bits, dataSize int) []byte {
+	var b bytes.Buffer
+	b.WriteString("RIFF")
+	writeLE(&b, uint32(36+dataSize))
+	b.WriteString("WAVEfmt ")
+	writeLE(&b, uint32(16))
+	writeLE(&b, uint16(1))
+	writeLE(&b, uint16(channels))
+	writeLE(&b, uint32(sampleRate))
+	writeLE(&b, uint32(sampleRate*channels*bits/8))
+	writeLE(&b, uint16(channels*bits/8))
+	writeLE(&b, uint16(bits))
+	b.WriteString("data")
+	writeLE(&b, uint32(dataSize))
+	b.Write(make([]byte, dataSize))
+	return b.Bytes()
+}
+
+func shortFmtWAV() []byte {
+	var b bytes.Buffer
+	b.WriteString("RIFF")
+	writeLE(&b, uint32(20))
+	b.WriteString("WAVEfmt ")
+	writeLE(&b, uint32(4))
+	b.Write(make([]byte, 4))
+	return b.Bytes()
+}
+
+func testMP3(frames int, xingFrames uint32) []byte {
+	const frameSize = 417
+	var b bytes.Buffer
+	for i := 0; i < frames; i++ {
+		frame := make([]byte, frameSize)
+		copy(frame, []byte{0xff, 0xfb, 0x90, 0x00})
+		if i == 0 && xingFrames > 0 {
+			copy(frame[36:], "Xing")
+			binary.BigEndian.PutUint32(frame[40:], 1)
+			binary.BigEndian.PutUint32(frame[44:], xingFrames)
+		}
+		b.Write(frame)
+	}
+	return b.Bytes()
+}
+
+func testOggOpus(granule uint64) []byte {
+	head := []byte("OpusHead\x01\x02")
+	head = binary.LittleEndian.AppendUint16(head, 312)
+	head = binary.LittleEndian.AppendUint32(head, 48000)
+	head = append(head, 0, 0, 0)
+
+	var b bytes.Buffer
+	b.Write(testOggPage(2, 0, head))
+	b.Write(testOggPage(0, 0, make([]byte, 20)))
+	b.Write(testOggPage(4, granule, make([]byte, 20)))
+	return b.Bytes()
+}
+
+func testOggPage(headerType byte, granule uint64, packet []byte) []byte {
+	var b bytes.Buffer
+	b.WriteString("OggS")
+	b.WriteByte(0)
+	b.WriteByte(headerType)
+	writeLE(&b, granule)
+	b.Write(make([]byte, 12))
+	b.WriteByte(1)
+	b.WriteByte(byte(len(packet)))
+	b.Write(packet)
+	return b.Bytes()
+}
+
+func testMP4() []byte {
+	mvhd := make([]byte, 100)
+	binary.BigEndian.PutUint32(mvhd[12:], 1000)
+	binary.BigEndian.PutUint32(mvhd[16:], 2500)
+
+	tkhd := make([]byte, 84)
+	binary.BigEndian.PutUint32(tkhd[76:], 640<<16)
+	binary.BigEndian.PutUint32(tkhd[80:], 360<<16)
+
+	hdlr := make([]byte, 24)
+	copy(hdlr[8:], "vide")
+
+	entry := make([]byte, 86)
+	binary.BigEndian.PutUint32(entry, uint32(len(entry)))
+	copy(entry[4:], "avc1")
+	stsd := append([]byte{0, 0, 0, 0, 0, 0, 0, 1}, entry...)
+
+	stbl := testMP4Box("stbl", testMP4Box("stsd", stsd))
+	mdia := testMP4Box("mdia", append(testMP4Box("hdlr", hdlr), testMP4Box("minf", stbl)...))
+	trak := testMP4Box("trak", append(testMP4Box("tkhd", tkhd), mdia...))
+	moov := testMP4Box("moov", append(testMP4Box("mvhd", mvhd), trak...))
+	return append(testMP4Box("ftyp", []byte("isom")), moov...)
+}
+
+func testMP4Box(typ string, body []byte) []byte {
+	b := binary.BigEndian.AppendUint32(nil, uint32(8+len(body)))
+	b = append(b, typ...)
+	return append(b, body...)
+}
+
+func oversizedMP4Box() []byte {
+	b := binary.BigEndian.AppendUint32(nil, 1)
+	b = append(b, "moov"...)
+	return binary.BigEndian.AppendUint64(b, math.MaxInt64)
+}
+
+func writeLE(b *bytes.Buffer, v any) {
+	_ = binary.Write(b, binary.LittleEndian, v)
+}
@@ -55,21 +55,117 @@ FROM media
 WHERE ID_editor = $1
 `
 
-	queryGetMediaList = `
+	queryUpdateMediaAccount = `
+UPDATE media
+SET Corp_name      = $2,
+    Email_red      = $3,
+    Editor_surname = $4,
+    Editor_name    = $5
+WHERE ID_editor = $1
+RETURNING ID_editor,
+          Num_reg_media_r,
+          Corp_name,
+          Email_red,
+          Editor_surname,
+          Editor_name,
+          Password
+`
+
+	queryGetMediaProfile = `
 SELECT ID_editor,
        Num_reg_media_r,
        Corp_name,
        Email_red,
        Editor_surname,
        Editor_name,
-       (SELECT COUNT(*) FROM subscription WHERE media_id = ID_editor)
+       description,
+       website,
+       social_links,
+       EXTRACT(EPOCH FROM logo_updated_at)::BIGINT,
+       EXTRACT(EPOCH FROM cover_updated_at)::BIGINT,
+       EXISTS(SELECT 1 FROM subscription WHERE media_id = ID_editor AND user_id = $2),
+       EXTRACT(EPOCH FROM created_at)::BIGINT,
+       (SELECT COUNT(*) FROM subscription WHERE media_id = ID_editor),
+       (SELECT COUNT(*) FROM news WHERE Num_reg_media_news = Num_reg_media_r),
+       (SELECT COUNT(*)
+        FROM favorite
+        INNER JOIN news ON
+            news.ID_news = favorite.news_id
+        WHERE news.Num_reg_media_news = Num_reg_media_r),
+       (SELECT EXTRACT(EPOCH FROM MAX(release))::BIGINT FROM news WHERE Num_reg_media_news = Num_reg_media_r)
 FROM media
-LIMIT $1 OFFSET $2
+WHERE ID_editor = $1
+`
+
+	queryUpdateMediaProfile = `
+UPDATE media
+SET description  = $2,
+    website      = $3,
+    social_links = $4
+WHERE ID_editor = $1
+`
+
+	queryTouchMediaLogo = `
+UPDATE media SET logo_updated_at = NOW() WHERE ID_editor = $1
+`
+
+	queryTouchMediaCover = `
+UPDATE media SET cover_updated_at = NOW() WHERE ID_editor = $1
+`
+
+	queryGetMediaList = `
+SELECT ID_editor,
+       Num_reg_media_r,
+       Corp_name,
+       Email_red,
+       Editor_surname,
+       Editor_name,
+       subscription_count,
+       is_subscribed
+FROM (
+    SELECT media.*,
+           (SELECT COUNT(*) FROM subscription WHERE media_id = ID_editor) AS subscription_count,
+           EXISTS(SELECT 1 FROM subscription WHERE media_id = ID_editor AND user_id = $4) AS is_subscribed,
+           CASE
+               WHEN $5 = 'active' THEN
+                   (SELECT COUNT(*)
+                    FROM news
+                    WHERE Num_reg_media_news = Num_reg_media_r
+                      AND release >= NOW() - INTERVAL '30 days')
+               ELSE 0
+           END AS recent_news_count,
+           CASE
+               WHEN $1::TEXT IS NULL THEN 0
+               ELSE word_similarity($1, COALESCE(Corp_name, ''))
+           END AS relevance
+    FROM media
+    WHERE ($1::TEXT IS NULL OR Corp_name ILIKE $2 OR Corp_name % $1 OR $1 <% Corp_name)
+      AND ($3::BIGINT IS NULL OR EXISTS(
+        SELECT 1
+        FROM news
+        WHERE Num_reg_media_news = Num_reg_media_r
+          AND release >= NOW() - $3::BIGINT * INTERVAL '1 day'
+      ))
+) media
+ORDER BY CASE WHEN $5 = 'name' THEN LOWER(Corp_name) END,
+         CASE WHEN $5 = 'subscribers' THEN subscription_count END DESC,
+         CASE WHEN $5 = 'newest' THEN created_at END DESC,
+         CASE WHEN $5 = 'active' THEN recent_news_count END DESC,
+         CASE WHEN $5 = 'relevance' THEN relevance END DESC,
+         ID_editor
+LIMIT $6 OFFSET $7
 `
 
 	queryCountMedia = `
 SELECT COUNT(*)
 FROM media
+WHERE ($1::TEXT IS NULL OR Corp_name ILIKE $2 OR Corp_name % $1 OR $1 <% Corp_name)
+  AND ($3::BIGINT IS NULL OR EXISTS(
+    SELECT 1
+    FROM news
+    WHERE Num_reg_media_news = Num_reg_media_r
+      AND release >= NOW() - $3::BIGINT * INTERVAL '1 day'
+  ))
 `
 
 	queryIsSubscriptionExists = `
@@ -82,6 +178,104 @@ VALUES ($1, $2)
 `
 
 	queryDeleteSubscription = `
-DELETE FROM subscription WHERE media_id = $1 AND user_id = $2
+WITH deleted AS (
+    DELETE FROM subscription WHERE media_id = $1 AND user_id = $2
+    RETURNING user_id, media_id, created_at
+)
+INSERT INTO subscription_history (user_id, media_id, subscribed_at)
+SELECT user_id, media_id, created_at FROM deleted
+`
+
+	queryGetSubscriberList = `
+SELECT "user".ID_user,
+       COALESCE("user".FIO_user, ''),
+       EXTRACT(EPOCH FROM subscription.created_at)::BIGINT
+FROM subscription
+INNER JOIN "user" ON
+    "user".ID_user = subscription.user_id
+WHERE subscription.media_id = $1
+ORDER BY subscription.created_at DESC NULLS LAST, subscription.id DESC
+LIMIT $2 OFFSET $3
+`
+
+	queryCountSubscribers = `
+SELECT COUNT(*) FROM subscription WHERE media_id = $1
+`
+
+	queryGetSubscriptionStats = `
+WITH bounds AS (
+    SELECT $2::DATE::TIMESTAMP AT TIME ZONE $4 AS start_at,
+           ($3::DATE + 1)::TIMESTAMP AT TIME ZONE $4 AS end_at
+),
+subscribed AS (
+    SELECT (s.subscribed_at AT TIME ZONE $4)::DATE AS day, COUNT(*) AS n
+    FROM (
+        SELECT created_at AS subscribed_at
+        FROM subscription
+        WHERE media_id = $1
+        UNION ALL
+        SELECT subscribed_at
+        FROM subscription_history
+        WHERE media_id = $1
+    ) s, bounds
+    WHERE s.subscribed_at >= bounds.start_at
+      AND s.subscribed_at < bounds.end_at
+    GROUP BY 1
+),
+unsubscribed AS (
+    SELECT (unsubscribed_at AT TIME ZONE $4)::DATE AS day, COUNT(*) AS n
+    FROM subscription_history, bounds
+    WHERE media_id = $1
+      AND unsubscribed_at >= bounds.start_at
+      AND unsubscribed_at < bounds.end_at
+    GROUP BY 1
+)
+SELECT TO_CHAR(days.day, 'YYYY-MM-DD'),
+       COALESCE(subscribed.n, 0),
+       COALESCE(unsubscribed.n, 0)
+FROM (
+    SELECT d::DATE AS day
+    FROM GENERATE_SERIES($2::DATE, $3::DATE, INTERVAL '1 day') d
+) days
+LEFT JOIN subscribed ON
+    subscribed.day = days.day
+LEFT JOIN unsubscribed ON
+    unsubscribed.day = days.day
+ORDER BY days.day
+`
+
+	queryMuteMedia = `
+INSERT INTO muted_media (user_id, media_id, until)
+VALUES ($1, $2, TO_TIMESTAMP($3))
+ON CONFLICT (user_id, media_id) DO UPDATE SET until = EXCLUDED.until
+`
+
+	queryUnmuteMedia = `
+DELETE FROM muted_media WHERE user_id = $1 AND media_id = $2
+`
+
+	queryGetMutedMediaList = `
+SELECT ID_editor,
+       Num_reg_media_r,
+       Corp_name,
+       Email_red,
+       Editor_surname,
+       Editor_name,
+       (SELECT COUNT(*) FROM subscription WHERE media_id = ID_editor),
+       EXTRACT(EPOCH FROM muted_media.until)::BIGINT
+FROM muted_media
+INNER JOIN media ON
+    media.id_editor = muted_media.media_id
+WHERE muted_media.user_id = $1
+  AND muted_media.until > NOW()
+ORDER BY muted_media.until DESC
+LIMIT $2 OFFSET $3
+`
+
+	queryCountMutedMedia = `
+SELECT COUNT(*)
+FROM muted_media
+WHERE user_id = $1
+  AND until > NOW()
 `
 )
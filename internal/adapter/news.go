@@ -9,6 +9,7 @@ import (
 	"gopkg.in/guregu/null.v3"
 	"news-app-api/internal/dto"
 	"news-app-api/internal/entity"
+	"time"
 )
 
 type (
@@ -19,6 +20,11 @@ type (
 		GetNews(ctx context.Context, newsID int64) (entity.NewsListItem, error)
 		GetFeedNewsList(ctx context.Context, p dto.GetFeedParams) ([]entity.NewsListItem, error)
 		CountFeedNews(ctx context.Context, userID int64, since null.Int) (int64, error)
+		GetFeedSessionTail(ctx context.Context, p dto.GetFeedParams) ([]entity.NewsListItem, error)
+		CountFeedSessionTail(ctx context.Context, userID int64, since null.Int, token string) (int64, error)
+		CreateFeedSession(ctx context.Context, s entity.FeedSession, ttl time.Duration) error
+		GetFeedSession(ctx context.Context, userID int64, token string, ttl time.Duration) (entity.FeedSession, bool, error)
+		DeleteExpiredFeedSessions(ctx context.Context) error
 		IsFavorite(ctx context.Context, userID, newsID int64) (bool, error)
 		AddToFavorite(ctx context.Context, userID, newsID int64) error
 		RemoveFromFavorite(ctx context.Context, userID, newsID int64) error
@@ -26,6 +32,16 @@ type (
 		CountFavorites(ctx context.Context, userID int64) (int64, error)
 		GetNewsList(ctx context.Context, p dto.GetNewsListParams) ([]entity.NewsListItem, error)
 		CountNews(ctx context.Context, mediaID int64) (int64, error)
+		HideNews(ctx context.Context, userID, newsID int64) error
+		UnhideNews(ctx context.Context, userID, newsID int64) error
+		GetHiddenNewsList(ctx context.Context, p dto.GetHiddenNewsListParams) ([]entity.NewsListItem, error)
+		CountHiddenNews(ctx context.Context, userID int64) (int64, error)
+		GetFeedCandidates(ctx context.Context, userID int64, since null.Int, limit int64) ([]entity.FeedCandidate, error)
+		GetFeedNewsListByIDs(ctx context.Context, userID int64, ids []int64) ([]entity.NewsListItem, error)
+		MarkFeedNewsRead(ctx context.Context, userID, newsID int64) error
+		GetDigestNewsList(ctx context.Context, userID, since, limit int64) ([]entity.NewsListItem, error)
+		SearchNews(ctx context.Context, p dto.SearchNewsParams) ([]entity.NewsListItem, error)
+		CountSearchNews(ctx context.Context, query string) (int64, error)
 	}
 
 	newsRepository struct {
@@ -96,12 +112,13 @@ func (r *newsRepository) CreateNews(ctx context.Context, p dto.CreateNewsParams)
 			}
 		}
 	}()
-	row := r.q.QueryRow(ctx, queryCreateNews, p.MediaID, p.Title, p.Text)
+	row := r.q.QueryRow(ctx, queryCreateNews, p.MediaID, p.Title, p.Text, p.IsBreaking)
 	err = row.Scan(
 		&n.ID,
 		&n.MediaRegistrationNumber,
 		&n.Title,
 		&n.Text,
+		&n.IsBreaking,
 		&n.CreatedAt,
 	)
 	return
@@ -142,6 +159,7 @@ func (r *newsRepository) GetNews(ctx context.Context, newsID int64) (n entity.Ne
 		&n.Title,
 		&n.Text,
 		&n.CreatedAt,
+		&n.Attachments,
 	)
 	if err != nil {
 		if err == pgx.ErrNoRows {
@@ -167,8 +185,31 @@ func (r *newsRepository) GetFeedNewsList(
 			}
 		}
 	}()
+	return r.getFeedNewsList(ctx, p, null.String{})
+}
+
+func (r *newsRepository) GetFeedSessionTail(
+	ctx context.Context,
+	p dto.GetFeedParams,
+) (list []entity.NewsListItem, err error) {
+	defer func() {
+		if err != nil {
+			var appErr *dto.AppError
+			if !errors.As(err, &appErr) {
+				err = fmt.Errorf("NewsRepository - GetFeedSessionTail: %w", err)
+			}
+		}
+	}()
+	return r.getFeedNewsList(ctx, p, null.StringFrom(p.Session.String))
+}
+
+func (r *newsRepository) getFeedNewsList(
+	ctx context.Context,
+	p dto.GetFeedParams,
+	session null.String,
+) (list []entity.NewsListItem, err error) {
 	list = make([]entity.NewsListItem, 0, p.Limit.Int64)
-	rows, err := r.q.Query(ctx, queryGetFeedNewsList, p.UserID, p.Since, p.Limit, p.Offset)
+	rows, err := r.q.Query(ctx, queryGetFeedNewsList, p.UserID, p.Since, p.Limit, p.Offset, session)
 	if err != nil {
 		return
 	}
@@ -188,6 +229,7 @@ func (r *newsRepository) GetFeedNewsList(
 			&item.Text,
 			&item.IsFavorite,
 			&item.CreatedAt,
+			&item.Attachments,
 		)
 		if err != nil {
 			return
@@ -206,11 +248,86 @@ func (r *newsRepository) CountFeedNews(ctx context.Context, userID int64, since
 			}
 		}
 	}()
-	row := r.q.QueryRow(ctx, queryCountFeedNews, userID, since)
+	row := r.q.QueryRow(ctx, queryCountFeedNews, userID, since, null.String{})
 	err = row.Scan(&v)
 	return
 }
 
+func (r *newsRepository) CountFeedSessionTail(
+	ctx context.Context,
+	userID int64,
+	since null.Int,
+	token string,
+) (v int64, err error) {
+	defer func() {
+		if err != nil {
+			var appErr *dto.AppError
+			if !errors.As(err, &appErr) {
+				err = fmt.Errorf("NewsRepository - CountFeedSessionTail: %w", err)
+			}
+		}
+	}()
+	row := r.q.QueryRow(ctx, queryCountFeedNews, userID, since, token)
+	err = row.Scan(&v)
+	return
+}
+
+func (r *newsRepository) CreateFeedSession(ctx context.Context, s entity.FeedSession, ttl time.Duration) (err error) {
+	defer func() {
+		if err != nil {
+			var appErr *dto.AppError
+			if !errors.As(err, &appErr) {
+				err = fmt.Errorf("NewsRepository - CreateFeedSession: %w", err)
+			}
+		}
+	}()
+	_, err = r.q.Exec(
+		ctx,
+		queryCreateFeedSession,
+		s.Token,
+		s.UserID,
+		s.NewsIDs,
+		s.CutoffNewsID,
+		int64(ttl.Seconds()),
+	)
+	return
+}
+
+func (r *newsRepository) GetFeedSession(
+	ctx context.Context,
+	userID int64,
+	token string,
+	ttl time.Duration,
+) (s entity.FeedSession, found bool, err error) {
+	defer func() {
+		if err != nil {
+			var appErr *dto.AppError
+			if !errors.As(err, &appErr) {
+				err = fmt.Errorf("NewsRepository - GetFeedSession: %w", err)
+			}
+		}
+	}()
+	row := r.q.QueryRow(ctx, queryGetFeedSession, token, userID, int64(ttl.Seconds()))
+	err = row.Scan(&s.Token, &s.UserID, &s.NewsIDs, &s.CutoffNewsID)
+	if err == pgx.ErrNoRows {
+		return s, false, nil
+	}
+	return s, err == nil, err
+}
+
+func (r *newsRepository) DeleteExpiredFeedSessions(ctx context.Context) (err error) {
+	defer func() {
+		if err != nil {
+			var appErr *dto.AppError
+			if !errors.As(err, &appErr) {
+				err = fmt.Errorf("NewsRepository - DeleteExpiredFeedSessions: %w", err)
+			}
+		}
+	}()
+	_, err = r.q.Exec(ctx, queryDeleteExpiredFeedSessions)
+	return
+}
+
 func (r *newsRepository) IsFavorite(ctx context.Context, userID, newsID int64) (v bool, err error) {
 	defer func() {
 		if err != nil {
@@ -284,6 +401,7 @@ func (r *newsRepository) GetFavoriteList(
 			&item.Text,
 			&item.IsFavorite,
 			&item.CreatedAt,
+			&item.Attachments,
 		)
 		if err != nil {
 			return
@@ -340,6 +458,7 @@ func (r *newsRepository) GetNewsList(
 			&item.Text,
 			&item.IsFavorite,
 			&item.CreatedAt,
+			&item.Attachments,
 		)
 		if err != nil {
 			return
@@ -362,3 +481,291 @@ func (r *newsRepository) CountNews(ctx context.Context, mediaID int64) (v int64,
 	err = row.Scan(&v)
 	return
 }
+
+func (r *newsRepository) HideNews(ctx context.Context, userID, newsID int64) (err error) {
+	defer func() {
+		if err != nil {
+			var appErr *dto.AppError
+			if !errors.As(err, &appErr) {
+				err = fmt.Errorf("NewsRepository - HideNews: %w", err)
+			}
+		}
+	}()
+	_, err = r.q.Exec(ctx, queryHideNews, userID, newsID)
+	return
+}
+
+func (r *newsRepository) UnhideNews(ctx context.Context, userID, newsID int64) (err error) {
+	defer func() {
+		if err != nil {
+			var appErr *dto.AppError
+			if !errors.As(err, &appErr) {
+				err = fmt.Errorf("NewsRepository - UnhideNews: %w", err)
+			}
+		}
+	}()
+	_, err = r.q.Exec(ctx, queryUnhideNews, userID, newsID)
+	return
+}
+
+func (r *newsRepository) GetHiddenNewsList(
+	ctx context.Context,
+	p dto.GetHiddenNewsListParams,
+) (list []entity.NewsListItem, err error) {
+	defer func() {
+		if err != nil {
+			var appErr *dto.AppError
+			if !errors.As(err, &appErr) {
+				err = fmt.Errorf("NewsRepository - GetHiddenNewsList: %w", err)
+			}
+		}
+	}()
+	list = make([]entity.NewsListItem, 0, p.Limit.Int64)
+	rows, err := r.q.Query(ctx, queryGetHiddenNewsList, p.UserID, p.Limit, p.Offset)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+	for rows.Next() {
+		item := entity.NewsListItem{}
+		err = rows.Scan(
+			&item.ID,
+			&item.Media.ID,
+			&item.Media.RegistrationNumber,
+			&item.Media.Name,
+			&item.Media.Email,
+			&item.Media.Editor.FirstName,
+			&item.Media.Editor.LastName,
+			&item.Media.SubscriptionCount,
+			&item.Title,
+			&item.Text,
+			&item.IsFavorite,
+			&item.CreatedAt,
+			&item.Attachments,
+		)
+		if err != nil {
+			return
+		}
+		list = append(list, item)
+	}
+	return
+}
+
+func (r *newsRepository) CountHiddenNews(ctx context.Context, userID int64) (v int64, err error) {
+	defer func() {
+		if err != nil {
+			var appErr *dto.AppError
+			if !errors.As(err, &appErr) {
+				err = fmt.Errorf("NewsRepository - CountHiddenNews: %w", err)
+			}
+		}
+	}()
+	row := r.q.QueryRow(ctx, queryCountHiddenNews, userID)
+	err = row.Scan(&v)
+	return
+}
+
+func (r *newsRepository) GetFeedCandidates(
+	ctx context.Context,
+	userID int64,
+	since null.Int,
+	limit int64,
+) (list []entity.FeedCandidate, err error) {
+	defer func() {
+		if err != nil {
+			var appErr *dto.AppError
+			if !errors.As(err, &appErr) {
+				err = fmt.Errorf("NewsRepository - GetFeedCandidates: %w", err)
+			}
+		}
+	}()
+	list = make([]entity.FeedCandidate, 0, limit)
+	rows, err := r.q.Query(ctx, queryGetFeedCandidates, userID, since, limit)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+	for rows.Next() {
+		item := entity.FeedCandidate{}
+		err = rows.Scan(
+			&item.NewsID,
+			&item.MediaID,
+			&item.CreatedAt,
+			&item.IsRead,
+			&item.FavoriteCount,
+			&item.MediaAffinity,
+		)
+		if err != nil {
+			return
+		}
+		list = append(list, item)
+	}
+	return
+}
+
+func (r *newsRepository) GetFeedNewsListByIDs(
+	ctx context.Context,
+	userID int64,
+	ids []int64,
+) (list []entity.NewsListItem, err error) {
+	defer func() {
+		if err != nil {
+			var appErr *dto.AppError
+			if !errors.As(err, &appErr) {
+				err = fmt.Errorf("NewsRepository - GetFeedNewsListByIDs: %w", err)
+			}
+		}
+	}()
+	list = make([]entity.NewsListItem, 0, len(ids))
+	rows, err := r.q.Query(ctx, queryGetFeedNewsListByIDs, userID, ids)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+	byID := make(map[int64]entity.NewsListItem, len(ids))
+	for rows.Next() {
+		item := entity.NewsListItem{}
+		err = rows.Scan(
+			&item.ID,
+			&item.Media.ID,
+			&item.Media.RegistrationNumber,
+			&item.Media.Name,
+			&item.Media.Email,
+			&item.Media.Editor.FirstName,
+			&item.Media.Editor.LastName,
+			&item.Media.SubscriptionCount,
+			&item.Title,
+			&item.Text,
+			&item.IsFavorite,
+			&item.CreatedAt,
+			&item.Attachments,
+		)
+		if err != nil {
+			return
+		}
+		byID[item.ID] = item
+	}
+	err = rows.Err()
+	if err != nil {
+		return
+	}
+	for _, id := range ids {
+		if item, ok := byID[id]; ok {
+			list = append(list, item)
+		}
+	}
+	return
+}
+
+func (r *newsRepository) MarkFeedNewsRead(ctx context.Context, userID, newsID int64) (err error) {
+	defer func() {
+		if err != nil {
+			var appErr *dto.AppError
+			if !errors.As(err, &appErr) {
+				err = fmt.Errorf("NewsRepository - MarkFeedNewsRead: %w", err)
+			}
+		}
+	}()
+	_, err = r.q.Exec(ctx, queryMarkFeedNewsRead, userID, newsID)
+	return
+}
+
+func (r *newsRepository) GetDigestNewsList(
+	ctx context.Context,
+	userID, since, limit int64,
+) (list []entity.NewsListItem, err error) {
+	defer func() {
+		if err != nil {
+			var appErr *dto.AppError
+			if !errors.As(err, &appErr) {
+				err = fmt.Errorf("NewsRepository - GetDigestNewsList: %w", err)
+			}
+		}
+	}()
+	list = make([]entity.NewsListItem, 0, limit)
+	rows, err := r.q.Query(ctx, queryGetDigestNewsList, userID, since, limit)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+	for rows.Next() {
+		item := entity.NewsListItem{}
+		err = rows.Scan(
+			&item.ID,
+			&item.Media.ID,
+			&item.Media.RegistrationNumber,
+			&item.Media.Name,
+			&item.Media.Email,
+			&item.Media.Editor.FirstName,
+			&item.Media.Editor.LastName,
+			&item.Media.SubscriptionCount,
+			&item.Title,
+			&item.Text,
+			&item.IsFavorite,
+			&item.CreatedAt,
+			&item.Attachments,
+		)
+		if err != nil {
+			return
+		}
+		list = append(list, item)
+	}
+	return
+}
+
+func (r *newsRepository) SearchNews(
+	ctx context.Context,
+	p dto.SearchNewsParams,
+) (list []entity.NewsListItem, err error) {
+	defer func() {
+		if err != nil {
+			var appErr *dto.AppError
+			if !errors.As(err, &appErr) {
+				err = fmt.Errorf("NewsRepository - SearchNews: %w", err)
+			}
+		}
+	}()
+	list = make([]entity.NewsListItem, 0, p.Limit.Int64)
+	rows, err := r.q.Query(ctx, querySearchNews, p.Query, p.UserID, p.Limit, p.Offset)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+	for rows.Next() {
+		item := entity.NewsListItem{}
+		err = rows.Scan(
+			&item.ID,
+			&item.Media.ID,
+			&item.Media.RegistrationNumber,
+			&item.Media.Name,
+			&item.Media.Email,
+			&item.Media.Editor.FirstName,
+			&item.Media.Editor.LastName,
+			&item.Media.SubscriptionCount,
+			&item.Title,
+			&item.Text,
+			&item.IsFavorite,
+			&item.CreatedAt,
+			&item.Attachments,
+		)
+		if err != nil {
+			return
+		}
+		list = append(list, item)
+	}
+	return
+}
+
+func (r *newsRepository) CountSearchNews(ctx context.Context, query string) (v int64, err error) {
+	defer func() {
+		if err != nil {
+			var appErr *dto.AppError
+			if !errors.As(err, &appErr) {
+				err = fmt.Errorf("NewsRepository - CountSearchNews: %w", err)
+			}
+		}
+	}()
+	row := r.q.QueryRow(ctx, queryCountSearchNews, query)
+	err = row.Scan(&v)
+	return
+}
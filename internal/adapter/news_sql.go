@@ -1,10 +1,36 @@
 package adapter
 
 const (
+	// newsAttachmentsColumn selects the attachments of news.id_news as a JSON array.
+	newsAttachmentsColumn = `(SELECT COALESCE(JSON_AGG(JSON_BUILD_OBJECT(
+                   'kind', a.kind,
+                   'contentType', a.content_type,
+                   'size', a.size,
+                   'version', a.version,
+                   'url', FORMAT('/api/news/%s/%s?v=%s', a.news_id, a.path, a.version),
+                   'metadata', a.metadata,
+                   'updatedAt', EXTRACT(EPOCH FROM a.updated_at)::BIGINT,
+                   'gallery', a.gallery
+               ) ORDER BY a.kind, a.position, a.id), '[]')
+        FROM (SELECT attachment.*,
+                     COALESCE(LEFT(content_hash, 16), EXTRACT(EPOCH FROM updated_at)::BIGINT::TEXT) AS version,
+                     CASE WHEN kind = 'gallery' THEN 'gallery/' || attachment.id ELSE kind END AS path,
+                     CASE WHEN kind = 'gallery' THEN JSON_BUILD_OBJECT(
+                         'id', attachment.id,
+                         'caption', gallery_image.caption,
+                         'altText', gallery_image.alt_text,
+                         'credit', gallery_image.credit,
+                         'position', gallery_image.position
+                     ) END AS gallery,
+                     gallery_image.position
+              FROM attachment
+              LEFT JOIN gallery_image ON gallery_image.attachment_id = attachment.id
+              WHERE attachment.news_id = news.id_news) a)`
+
 	queryCreateNews = `
-INSERT INTO news (Num_reg_media_news, title, text_content, release)
-SELECT Num_reg_media_r, $2, $3, NOW() FROM media WHERE ID_editor = $1
-RETURNING ID_news, Num_reg_media_news, title, text_content, EXTRACT(EPOCH FROM release)::BIGINT
+INSERT INTO news (Num_reg_media_news, title, text_content, is_breaking, release)
+SELECT Num_reg_media_r, $2, $3, $4, NOW() FROM media WHERE ID_editor = $1
+RETURNING ID_news, Num_reg_media_news, title, text_content, is_breaking, EXTRACT(EPOCH FROM release)::BIGINT
 `
 
 	queryAddNewsToFeed = `
@@ -26,7 +52,8 @@ SELECT id_news,
        (SELECT COUNT(*) FROM subscription WHERE media_id = media.id_editor),
        title,
        text_content,
-       EXTRACT(EPOCH FROM release)::BIGINT
+       EXTRACT(EPOCH FROM release)::BIGINT,
+       ` + newsAttachmentsColumn + `
 FROM news
 INNER JOIN media ON
     news.num_reg_media_news = media.num_reg_media_r
@@ -45,7 +72,8 @@ SELECT news.id_news,
        news.title,
        news.text_content,
        EXISTS(SELECT 1 FROM favorite WHERE user_id = $1 AND news_id = news.id_news),
-       EXTRACT(EPOCH FROM news.release)::BIGINT
+       EXTRACT(EPOCH FROM news.release)::BIGINT,
+       ` + newsAttachmentsColumn + `
 FROM feed
 INNER JOIN news ON
     feed.id_news = news.id_news
@@ -53,7 +81,12 @@ INNER JOIN media ON
     media.num_reg_media_r = news.num_reg_media_news
 WHERE id_user = $1
   AND ($2::BIGINT IS NULL OR EXTRACT(EPOCH FROM news.release)::BIGINT >= $2::BIGINT)
-ORDER BY news.release DESC
+  AND NOT EXISTS(SELECT 1 FROM hidden_news WHERE user_id = $1 AND news_id = news.id_news)
+  AND NOT EXISTS(SELECT 1 FROM muted_media WHERE user_id = $1 AND media_id = media.id_editor AND until > NOW())
+  AND ($5::VARCHAR IS NULL OR (news.release, news.id_news) < (
+    SELECT cutoff_release, cutoff_news_id FROM feed_session WHERE token = $5 AND user_id = $1
+  ))
+ORDER BY news.release DESC, news.id_news DESC
 LIMIT $3 OFFSET $4
 `
 
@@ -62,8 +95,15 @@ SELECT COUNT(*)
 FROM feed
 INNER JOIN news ON
     news.id_news = feed.id_news
+INNER JOIN media ON
+    media.num_reg_media_r = news.num_reg_media_news
 WHERE id_user = $1
   AND ($2::BIGINT IS NULL OR EXTRACT(EPOCH FROM news.release)::BIGINT >= $2::BIGINT)
+  AND NOT EXISTS(SELECT 1 FROM hidden_news WHERE user_id = $1 AND news_id = news.id_news)
+  AND NOT EXISTS(SELECT 1 FROM muted_media WHERE user_id = $1 AND media_id = media.id_editor AND until > NOW())
+  AND ($3::VARCHAR IS NULL OR (news.release, news.id_news) < (
+    SELECT cutoff_release, cutoff_news_id FROM feed_session WHERE token = $3 AND user_id = $1
+  ))
 `
 
 	queryIsFavorite = `
@@ -91,7 +131,8 @@ SELECT news.id_news,
        news.title,
        news.text_content,
        EXISTS(SELECT 1 FROM favorite WHERE user_id = $1 AND news_id = news.id_news),
-       EXTRACT(EPOCH FROM news.release)::BIGINT
+       EXTRACT(EPOCH FROM news.release)::BIGINT,
+       ` + newsAttachmentsColumn + `
 FROM favorite
 INNER JOIN news ON
     favorite.news_id = news.id_news
@@ -119,7 +160,8 @@ SELECT news.id_news,
        news.title,
        news.text_content,
        EXISTS(SELECT 1 FROM favorite WHERE user_id = $2 AND news_id = news.id_news),
-       EXTRACT(EPOCH FROM news.release)::BIGINT
+       EXTRACT(EPOCH FROM news.release)::BIGINT,
+       ` + newsAttachmentsColumn + `
 FROM news
 INNER JOIN media ON
     media.num_reg_media_r = news.num_reg_media_news
@@ -134,5 +176,191 @@ FROM news
 INNER JOIN media ON 
     news.num_reg_media_news = media.num_reg_media_r
 WHERE media.id_editor = $1
+`
+
+	queryHideNews = `
+INSERT INTO hidden_news (user_id, news_id)
+VALUES ($1, $2)
+ON CONFLICT (user_id, news_id) DO NOTHING
+`
+
+	queryUnhideNews = `
+DELETE FROM hidden_news WHERE user_id = $1 AND news_id = $2
+`
+
+	queryGetHiddenNewsList = `
+SELECT news.id_news,
+       media.id_editor,
+       media.num_reg_media_r,
+       media.corp_name,
+       media.email_red,
+       media.editor_name,
+       media.editor_surname,
+       (SELECT COUNT(*) FROM subscription WHERE media_id = media.id_editor),
+       news.title,
+       news.text_content,
+       EXISTS(SELECT 1 FROM favorite WHERE user_id = $1 AND news_id = news.id_news),
+       EXTRACT(EPOCH FROM news.release)::BIGINT,
+       ` + newsAttachmentsColumn + `
+FROM hidden_news
+INNER JOIN news ON
+    hidden_news.news_id = news.id_news
+INNER JOIN media ON
+    media.num_reg_media_r = news.num_reg_media_news
+WHERE hidden_news.user_id = $1
+ORDER BY hidden_news.created_at DESC
+LIMIT $2 OFFSET $3
+`
+
+	queryCountHiddenNews = `
+SELECT COUNT(*)
+FROM hidden_news
+WHERE user_id = $1
+`
+
+	queryGetFeedCandidates = `
+SELECT news.id_news,
+       media.id_editor,
+       EXTRACT(EPOCH FROM news.release)::BIGINT,
+       feed.read_at IS NOT NULL,
+       (SELECT COUNT(*) FROM favorite WHERE news_id = news.id_news),
+       (SELECT COUNT(*)
+        FROM favorite
+        INNER JOIN news AS favorite_news ON
+            favorite_news.id_news = favorite.news_id
+        WHERE favorite.user_id = $1
+          AND favorite_news.num_reg_media_news = media.num_reg_media_r)
+FROM feed
+INNER JOIN news ON
+    feed.id_news = news.id_news
+INNER JOIN media ON
+    media.num_reg_media_r = news.num_reg_media_news
+WHERE id_user = $1
+  AND ($2::BIGINT IS NULL OR EXTRACT(EPOCH FROM news.release)::BIGINT >= $2::BIGINT)
+  AND NOT EXISTS(SELECT 1 FROM hidden_news WHERE user_id = $1 AND news_id = news.id_news)
+  AND NOT EXISTS(SELECT 1 FROM muted_media WHERE user_id = $1 AND media_id = media.id_editor AND until > NOW())
+ORDER BY news.release DESC, news.id_news DESC
+LIMIT $3
+`
+
+	queryGetFeedNewsListByIDs = `
+SELECT news.id_news,
+       media.id_editor,
+       media.num_reg_media_r,
+       media.corp_name,
+       media.email_red,
+       media.editor_name,
+       media.editor_surname,
+       (SELECT COUNT(*) FROM subscription WHERE media_id = media.id_editor),
+       news.title,
+       news.text_content,
+       EXISTS(SELECT 1 FROM favorite WHERE user_id = $1 AND news_id = news.id_news),
+       EXTRACT(EPOCH FROM news.release)::BIGINT,
+       ` + newsAttachmentsColumn + `
+FROM news
+INNER JOIN media ON
+    media.num_reg_media_r = news.num_reg_media_news
+WHERE news.id_news = ANY($2::BIGINT[])
+  AND NOT EXISTS(SELECT 1 FROM hidden_news WHERE user_id = $1 AND news_id = news.id_news)
+  AND NOT EXISTS(SELECT 1 FROM muted_media WHERE user_id = $1 AND media_id = media.id_editor AND until > NOW())
+`
+
+	queryCreateFeedSession = `
+INSERT INTO feed_session (token, user_id, news_ids, cutoff_release, cutoff_news_id, expires_at)
+SELECT $1, $2, $3, (SELECT release FROM news WHERE id_news = $4), $4, NOW() + $5::BIGINT * INTERVAL '1 second'
+`
+
+	queryGetFeedSession = `
+UPDATE feed_session
+SET expires_at = NOW() + $3::BIGINT * INTERVAL '1 second'
+WHERE token = $1
+  AND user_id = $2
+  AND expires_at > NOW()
+RETURNING token, user_id, news_ids, cutoff_news_id
+`
+
+	queryDeleteExpiredFeedSessions = `
+DELETE FROM feed_session WHERE expires_at <= NOW()
+`
+
+	queryMarkFeedNewsRead = `
+UPDATE feed
+SET read_at = NOW()
+WHERE id_user = $1
+  AND id_news = $2
+  AND read_at IS NULL
+`
+
+	queryGetDigestNewsList = `
+SELECT news.id_news,
+       media.id_editor,
+       media.num_reg_media_r,
+       media.corp_name,
+       media.email_red,
+       media.editor_name,
+       media.editor_surname,
+       (SELECT COUNT(*) FROM subscription WHERE media_id = media.id_editor),
+       news.title,
+       news.text_content,
+       EXISTS(SELECT 1 FROM favorite WHERE user_id = $1 AND news_id = news.id_news),
+       EXTRACT(EPOCH FROM news.release)::BIGINT,
+       ` + newsAttachmentsColumn + `
+FROM feed
+INNER JOIN news ON
+    feed.id_news = news.id_news
+INNER JOIN media ON
+    media.num_reg_media_r = news.num_reg_media_news
+WHERE id_user = $1
+  AND news.release > TO_TIMESTAMP($2::BIGINT)
+  AND NOT EXISTS(SELECT 1 FROM hidden_news WHERE user_id = $1 AND news_id = news.id_news)
+  AND NOT EXISTS(SELECT 1 FROM muted_media WHERE user_id = $1 AND media_id = media.id_editor AND until > NOW())
+ORDER BY news.release DESC
+LIMIT $3
+`
+
+	querySearchNews = `
+WITH q AS (
+    SELECT websearch_to_tsquery('russian', $1) AS query
+)
+SELECT news.id_news,
+       media.id_editor,
+       media.num_reg_media_r,
+       media.corp_name,
+       media.email_red,
+       media.editor_name,
+       media.editor_surname,
+       (SELECT COUNT(*) FROM subscription WHERE media_id = media.id_editor),
+       news.title,
+       news.text_content,
+       EXISTS(SELECT 1 FROM favorite WHERE user_id = $2 AND news_id = news.id_news),
+       EXTRACT(EPOCH FROM news.release)::BIGINT,
+       ` + newsAttachmentsColumn + `
+FROM news
+INNER JOIN media ON
+    media.num_reg_media_r = news.num_reg_media_news
+CROSS JOIN q
+LEFT JOIN LATERAL (
+    SELECT MAX(ts_rank(t.search_vector, websearch_to_tsquery(t.search_config, $1))) AS rank
+    FROM text_track t
+    WHERE t.news_id = news.id_news
+      AND t.search_vector @@ websearch_to_tsquery(t.search_config, $1)
+) transcript ON TRUE
+WHERE news.search_vector @@ q.query
+   OR transcript.rank IS NOT NULL
+ORDER BY ts_rank(news.search_vector, q.query) + COALESCE(transcript.rank, 0) / 2 DESC,
+         news.release DESC
+LIMIT $3 OFFSET $4
+`
+
+	queryCountSearchNews = `
+SELECT COUNT(*)
+FROM news
+WHERE news.search_vector @@ websearch_to_tsquery('russian', $1)
+   OR EXISTS(
+       SELECT 1
+       FROM text_track t
+       WHERE t.news_id = news.id_news
+         AND t.search_vector @@ websearch_to_tsquery(t.search_config, $1)
+   )
 `
 )
@@ -0,0 +1,221 @@
+package adapter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"news-app-api/internal/dto"
+	"news-app-api/internal/entity"
+)
+
+type (
+	NotificationRepository interface {
+		CreateMediaSubscriberNotifications(
+			ctx context.Context,
+			mediaID int64,
+			n entity.Notification,
+			enabledByDefault bool,
+		) error
+		GetNotificationList(ctx context.Context, p dto.GetNotificationListParams) ([]entity.Notification, error)
+		CountNotifications(ctx context.Context, userID int64, unreadOnly bool) (int64, error)
+		IsNotificationExists(ctx context.Context, notificationID, userID int64) (bool, error)
+		MarkNotificationRead(ctx context.Context, notificationID, userID int64) error
+		MarkAllNotificationsRead(ctx context.Context, userID int64) error
+		DeleteUnreadMediaNotifications(ctx context.Context, userID, mediaID int64) error
+		GetNotificationPreferenceList(ctx context.Context, userID int64) ([]entity.NotificationPreference, error)
+		UpsertNotificationPreference(ctx context.Context, userID int64, p entity.NotificationPreference) error
+	}
+
+	notificationRepository struct {
+		db *pgxpool.Pool
+	}
+)
+
+func NewNotificationRepository(db *pgxpool.Pool) NotificationRepository {
+	return &notificationRepository{db}
+}
+
+func (r *notificationRepository) CreateMediaSubscriberNotifications(
+	ctx context.Context,
+	mediaID int64,
+	n entity.Notification,
+	enabledByDefault bool,
+) (err error) {
+	defer func() {
+		if err != nil {
+			var appErr *dto.AppError
+			if !errors.As(err, &appErr) {
+				err = fmt.Errorf("NotificationRepository - CreateMediaSubscriberNotifications: %w", err)
+			}
+		}
+	}()
+	_, err = r.db.Exec(
+		ctx,
+		queryCreateMediaSubscriberNotifications,
+		mediaID,
+		n.Type,
+		n.NewsID,
+		n.Title,
+		n.Body,
+		enabledByDefault,
+	)
+	return
+}
+
+func (r *notificationRepository) GetNotificationList(
+	ctx context.Context,
+	p dto.GetNotificationListParams,
+) (list []entity.Notification, err error) {
+	defer func() {
+		if err != nil {
+			var appErr *dto.AppError
+			if !errors.As(err, &appErr) {
+				err = fmt.Errorf("NotificationRepository - GetNotificationList: %w", err)
+			}
+		}
+	}()
+	list = make([]entity.Notification, 0, p.Limit.Int64)
+	rows, err := r.db.Query(ctx, queryGetNotificationList, p.UserID, p.UnreadOnly.Bool, p.Limit, p.Offset)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+	for rows.Next() {
+		item := entity.Notification{}
+		err = rows.Scan(
+			&item.ID,
+			&item.Type,
+			&item.NewsID,
+			&item.MediaID,
+			&item.Title,
+			&item.Body,
+			&item.IsRead,
+			&item.CreatedAt,
+		)
+		if err != nil {
+			return
+		}
+		list = append(list, item)
+	}
+	return
+}
+
+func (r *notificationRepository) CountNotifications(
+	ctx context.Context,
+	userID int64,
+	unreadOnly bool,
+) (v int64, err error) {
+	defer func() {
+		if err != nil {
+			var appErr *dto.AppError
+			if !errors.As(err, &appErr) {
+				err = fmt.Errorf("NotificationRepository - CountNotifications: %w", err)
+			}
+		}
+	}()
+	row := r.db.QueryRow(ctx, queryCountNotifications, userID, unreadOnly)
+	err = row.Scan(&v)
+	return
+}
+
+func (r *notificationRepository) IsNotificationExists(
+	ctx context.Context,
+	notificationID, userID int64,
+) (v bool, err error) {
+	defer func() {
+		if err != nil {
+			var appErr *dto.AppError
+			if !errors.As(err, &appErr) {
+				err = fmt.Errorf("NotificationRepository - IsNotificationExists: %w", err)
+			}
+		}
+	}()
+	row := r.db.QueryRow(ctx, queryIsNotificationExists, notificationID, userID)
+	err = row.Scan(&v)
+	return
+}
+
+func (r *notificationRepository) MarkNotificationRead(ctx context.Context, notificationID, userID int64) (err error) {
+	defer func() {
+		if err != nil {
+			var appErr *dto.AppError
+			if !errors.As(err, &appErr) {
+				err = fmt.Errorf("NotificationRepository - MarkNotificationRead: %w", err)
+			}
+		}
+	}()
+	_, err = r.db.Exec(ctx, queryMarkNotificationRead, notificationID, userID)
+	return
+}
+
+func (r *notificationRepository) MarkAllNotificationsRead(ctx context.Context, userID int64) (err error) {
+	defer func() {
+		if err != nil {
+			var appErr *dto.AppError
+			if !errors.As(err, &appErr) {
+				err = fmt.Errorf("NotificationRepository - MarkAllNotificationsRead: %w", err)
+			}
+		}
+	}()
+	_, err = r.db.Exec(ctx, queryMarkAllNotificationsRead, userID)
+	return
+}
+
+func (r *notificationRepository) DeleteUnreadMediaNotifications(ctx context.Context, userID, mediaID int64) (err error) {
+	defer func() {
+		if err != nil {
+			var appErr *dto.AppError
+			if !errors.As(err, &appErr) {
+				err = fmt.Errorf("NotificationRepository - DeleteUnreadMediaNotifications: %w", err)
+			}
+		}
+	}()
+	_, err = r.db.Exec(ctx, queryDeleteUnreadMediaNotifications, userID, mediaID)
+	return
+}
+
+func (r *notificationRepository) GetNotificationPreferenceList(
+	ctx context.Context,
+	userID int64,
+) (list []entity.NotificationPreference, err error) {
+	defer func() {
+		if err != nil {
+			var appErr *dto.AppError
+			if !errors.As(err, &appErr) {
+				err = fmt.Errorf("NotificationRepository - GetNotificationPreferenceList: %w", err)
+			}
+		}
+	}()
+	rows, err := r.db.Query(ctx, queryGetNotificationPreferenceList, userID)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+	for rows.Next() {
+		item := entity.NotificationPreference{}
+		err = rows.Scan(&item.Type, &item.Enabled)
+		if err != nil {
+			return
+		}
+		list = append(list, item)
+	}
+	return
+}
+
+func (r *notificationRepository) UpsertNotificationPreference(
+	ctx context.Context,
+	userID int64,
+	p entity.NotificationPreference,
+) (err error) {
+	defer func() {
+		if err != nil {
+			var appErr *dto.AppError
+			if !errors.As(err, &appErr) {
+				err = fmt.Errorf("NotificationRepository - UpsertNotificationPreference: %w", err)
+			}
+		}
+	}()
+	_, err = r.db.Exec(ctx, queryUpsertNotificationPreference, userID, p.Type, p.Enabled)
+	return
+}
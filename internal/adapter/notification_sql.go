@@ -0,0 +1,85 @@
+package adapter
+
+const (
+	queryCreateMediaSubscriberNotifications = `
+INSERT INTO notification (user_id, type, news_id, media_id, title, body)
+SELECT subscription.user_id, $2, $3, subscription.media_id, $4, $5
+FROM subscription
+WHERE subscription.media_id = $1
+  AND COALESCE(
+    (SELECT enabled
+     FROM notification_preference
+     WHERE notification_preference.user_id = subscription.user_id
+       AND notification_preference.type = $2),
+    $6
+  )
+  AND NOT EXISTS(
+    SELECT 1
+    FROM muted_media
+    WHERE muted_media.user_id = subscription.user_id
+      AND muted_media.media_id = subscription.media_id
+      AND muted_media.until > NOW()
+  )
+`
+
+	queryGetNotificationList = `
+SELECT id,
+       type,
+       news_id,
+       media_id,
+       title,
+       body,
+       read_at IS NOT NULL,
+       EXTRACT(EPOCH FROM created_at)::BIGINT
+FROM notification
+WHERE user_id = $1
+  AND (NOT $2::BOOLEAN OR read_at IS NULL)
+ORDER BY created_at DESC, id DESC
+LIMIT $3 OFFSET $4
+`
+
+	queryCountNotifications = `
+SELECT COUNT(*)
+FROM notification
+WHERE user_id = $1
+  AND (NOT $2::BOOLEAN OR read_at IS NULL)
+`
+
+	queryMarkNotificationRead = `
+UPDATE notification
+SET read_at = NOW()
+WHERE id = $1
+  AND user_id = $2
+  AND read_at IS NULL
+`
+
+	queryIsNotificationExists = `
+SELECT EXISTS(SELECT 1 FROM notification WHERE id = $1 AND user_id = $2)
+`
+
+	queryMarkAllNotificationsRead = `
+UPDATE notification
+SET read_at = NOW()
+WHERE user_id = $1
+  AND read_at IS NULL
+`
+
+	queryDeleteUnreadMediaNotifications = `
+DELETE FROM notification
+WHERE user_id = $1
+  AND media_id = $2
+  AND read_at IS NULL
+`
+
+	queryGetNotificationPreferenceList = `
+SELECT type, enabled
+FROM notification_preference
+WHERE user_id = $1
+`
+
+	queryUpsertNotificationPreference = `
+INSERT INTO notification_preference (user_id, type, enabled)
+VALUES ($1, $2, $3)
+ON CONFLICT (user_id, type) DO UPDATE SET enabled = EXCLUDED.enabled
+`
+)
@@ -0,0 +1,170 @@
+package adapter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"news-app-api/internal/dto"
+	"news-app-api/internal/entity"
+	"time"
+)
+
+type (
+	PushSubscriptionRepository interface {
+		UpsertPushSubscription(ctx context.Context, p dto.RegisterPushSubscriptionParams) (entity.PushSubscription, error)
+		DeletePushSubscription(ctx context.Context, userID int64, endpoint string) error
+		DeletePushSubscriptionByEndpoint(ctx context.Context, endpoint string) error
+		EnqueuePushDeliveries(ctx context.Context, mediaID int64, notificationType, payload string) error
+		ClaimDuePushDeliveries(ctx context.Context, limit int64, lease time.Duration) ([]entity.PushDeliveryJob, error)
+		RetryPushDelivery(ctx context.Context, deliveryID int64, nextAttemptAt int64) error
+		DeletePushDelivery(ctx context.Context, deliveryID int64) error
+	}
+
+	pushSubscriptionRepository struct {
+		db *pgxpool.Pool
+	}
+)
+
+func NewPushSubscriptionRepository(db *pgxpool.Pool) PushSubscriptionRepository {
+	return &pushSubscriptionRepository{db}
+}
+
+func (r *pushSubscriptionRepository) UpsertPushSubscription(
+	ctx context.Context,
+	p dto.RegisterPushSubscriptionParams,
+) (s entity.PushSubscription, err error) {
+	defer func() {
+		if err != nil {
+			var appErr *dto.AppError
+			if !errors.As(err, &appErr) {
+				err = fmt.Errorf("PushSubscriptionRepository - UpsertPushSubscription: %w", err)
+			}
+		}
+	}()
+	row := r.db.QueryRow(ctx, queryUpsertPushSubscription, p.UserID, p.Endpoint, p.Keys.P256dh, p.Keys.Auth)
+	err = row.Scan(
+		&s.ID,
+		&s.UserID,
+		&s.Endpoint,
+		&s.P256dh,
+		&s.Auth,
+	)
+	return
+}
+
+func (r *pushSubscriptionRepository) DeletePushSubscription(
+	ctx context.Context,
+	userID int64,
+	endpoint string,
+) (err error) {
+	defer func() {
+		if err != nil {
+			var appErr *dto.AppError
+			if !errors.As(err, &appErr) {
+				err = fmt.Errorf("PushSubscriptionRepository - DeletePushSubscription: %w", err)
+			}
+		}
+	}()
+	_, err = r.db.Exec(ctx, queryDeletePushSubscription, userID, endpoint)
+	return
+}
+
+func (r *pushSubscriptionRepository) DeletePushSubscriptionByEndpoint(ctx context.Context, endpoint string) (err error) {
+	defer func() {
+		if err != nil {
+			var appErr *dto.AppError
+			if !errors.As(err, &appErr) {
+				err = fmt.Errorf("PushSubscriptionRepository - DeletePushSubscriptionByEndpoint: %w", err)
+			}
+		}
+	}()
+	_, err = r.db.Exec(ctx, queryDeletePushSubscriptionByEndpoint, endpoint)
+	return
+}
+
+func (r *pushSubscriptionRepository) EnqueuePushDeliveries(
+	ctx context.Context,
+	mediaID int64,
+	notificationType, payload string,
+) (err error) {
+	defer func() {
+		if err != nil {
+			var appErr *dto.AppError
+			if !errors.As(err, &appErr) {
+				err = fmt.Errorf("PushSubscriptionRepository - EnqueuePushDeliveries: %w", err)
+			}
+		}
+	}()
+	_, err = r.db.Exec(ctx, queryEnqueuePushDeliveries, mediaID, notificationType, payload)
+	return
+}
+
+func (r *pushSubscriptionRepository) ClaimDuePushDeliveries(
+	ctx context.Context,
+	limit int64,
+	lease time.Duration,
+) (list []entity.PushDeliveryJob, err error) {
+	defer func() {
+		if err != nil {
+			var appErr *dto.AppError
+			if !errors.As(err, &appErr) {
+				err = fmt.Errorf("PushSubscriptionRepository - ClaimDuePushDeliveries: %w", err)
+			}
+		}
+	}()
+	list = make([]entity.PushDeliveryJob, 0, limit)
+	rows, err := r.db.Query(ctx, queryClaimDuePushDeliveries, limit, int64(lease.Seconds()))
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+	for rows.Next() {
+		item := entity.PushDeliveryJob{}
+		err = rows.Scan(
+			&item.DeliveryID,
+			&item.Payload,
+			&item.Attempts,
+			&item.Subscription.ID,
+			&item.Subscription.UserID,
+			&item.Subscription.Endpoint,
+			&item.Subscription.P256dh,
+			&item.Subscription.Auth,
+		)
+		if err != nil {
+			return
+		}
+		list = append(list, item)
+	}
+	return
+}
+
+func (r *pushSubscriptionRepository) RetryPushDelivery(
+	ctx context.Context,
+	deliveryID int64,
+	nextAttemptAt int64,
+) (err error) {
+	defer func() {
+		if err != nil {
+			var appErr *dto.AppError
+			if !errors.As(err, &appErr) {
+				err = fmt.Errorf("PushSubscriptionRepository - RetryPushDelivery: %w", err)
+			}
+		}
+	}()
+	_, err = r.db.Exec(ctx, queryRetryPushDelivery, deliveryID, nextAttemptAt)
+	return
+}
+
+func (r *pushSubscriptionRepository) DeletePushDelivery(ctx context.Context, deliveryID int64) (err error) {
+	defer func() {
+		if err != nil {
+			var appErr *dto.AppError
+			if !errors.As(err, &appErr) {
+				err = fmt.Errorf("PushSubscriptionRepository - DeletePushDelivery: %w", err)
+			}
+		}
+	}()
+	_, err = r.db.Exec(ctx, queryDeletePushDelivery, deliveryID)
+	return
+}
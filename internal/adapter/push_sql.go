@@ -0,0 +1,78 @@
+package adapter
+
+const (
+	queryUpsertPushSubscription = `
+INSERT INTO push_subscription (user_id, endpoint, p256dh, auth)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (endpoint) DO UPDATE
+    SET user_id = EXCLUDED.user_id,
+        p256dh  = EXCLUDED.p256dh,
+        auth    = EXCLUDED.auth
+RETURNING id, user_id, endpoint, p256dh, auth
+`
+
+	queryDeletePushSubscription = `
+DELETE FROM push_subscription WHERE user_id = $1 AND endpoint = $2
+`
+
+	queryDeletePushSubscriptionByEndpoint = `
+DELETE FROM push_subscription WHERE endpoint = $1
+`
+
+	queryEnqueuePushDeliveries = `
+INSERT INTO push_delivery (subscription_id, payload)
+SELECT push_subscription.id, $3
+FROM push_subscription
+INNER JOIN subscription ON
+    subscription.user_id = push_subscription.user_id
+WHERE subscription.media_id = $1
+  AND COALESCE(
+    (SELECT enabled
+     FROM notification_preference
+     WHERE notification_preference.user_id = subscription.user_id
+       AND notification_preference.type = $2),
+    TRUE
+  )
+  AND NOT EXISTS(
+    SELECT 1
+    FROM muted_media
+    WHERE muted_media.user_id = subscription.user_id
+      AND muted_media.media_id = subscription.media_id
+      AND muted_media.until > NOW()
+  )
+`
+
+	queryClaimDuePushDeliveries = `
+UPDATE push_delivery
+SET next_attempt_at = NOW() + $2::BIGINT * INTERVAL '1 second'
+FROM push_subscription
+WHERE push_subscription.id = push_delivery.subscription_id
+  AND push_delivery.id IN (
+    SELECT id
+    FROM push_delivery
+    WHERE next_attempt_at <= NOW()
+    ORDER BY next_attempt_at
+    LIMIT $1
+    FOR UPDATE SKIP LOCKED
+)
+RETURNING push_delivery.id,
+          push_delivery.payload,
+          push_delivery.attempts,
+          push_subscription.id,
+          push_subscription.user_id,
+          push_subscription.endpoint,
+          push_subscription.p256dh,
+          push_subscription.auth
+`
+
+	queryRetryPushDelivery = `
+UPDATE push_delivery
+SET attempts        = attempts + 1,
+    next_attempt_at = TO_TIMESTAMP($2::BIGINT)
+WHERE id = $1
+`
+
+	queryDeletePushDelivery = `
+DELETE FROM push_delivery WHERE id = $1
+`
+)
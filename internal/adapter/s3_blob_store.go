@@ -0,0 +1,631 @@
+package adapter
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"news-app-api/internal/dto"
+	"news-app-api/internal/entity"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	s3PartSize        = 8 << 20
+	s3EmptyBodySHA256 = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+)
+
+type (
+	S3Config struct {
+		Endpoint        string
+		Region          string
+		Bucket          string
+		AccessKeyID     string
+		SecretAccessKey string
+		PathStyle       bool
+	}
+
+	s3BlobStore struct {
+		client   *http.Client
+		cfg      S3Config
+		endpoint *url.URL
+	}
+
+	s3Object struct {
+		ctx     context.Context
+		store   *s3BlobStore
+		key     string
+		size    int64
+		modTime time.Time
+
+		mu      sync.Mutex
+		pos     int64
+		body    io.ReadCloser
+		bodyOff int64
+	}
+
+	s3Error struct {
+		Status  int
+		Code    string `xml:"Code"`
+		Message string `xml:"Message"`
+	}
+
+	s3InitiateMultipartUploadResult struct {
+		UploadID string `xml:"UploadId"`
+	}
+
+	s3CompletedPart struct {
+		PartNumber int    `xml:"PartNumber"`
+		ETag       string `xml:"ETag"`
+	}
+
+	s3ListBucketResult struct {
+		Contents []struct {
+			Key          string    `xml:"Key"`
+			Size         int64     `xml:"Size"`
+			LastModified time.Time `xml:"LastModified"`
+		} `xml:"Contents"`
+		IsTruncated           bool   `xml:"IsTruncated"`
+		NextContinuationToken string `xml:"NextContinuationToken"`
+	}
+
+	s3CompleteMultipartUpload struct {
+		XMLName xml.Name          `xml:"CompleteMultipartUpload"`
+		Parts   []s3CompletedPart `xml:"Part"`
+	}
+)
+
+func NewS3BlobStore(ctx context.Context, client *http.Client, cfg S3Config) (BlobStore, error) {
+	endpoint, err := url.Parse(strings.TrimRight(cfg.Endpoint, "/"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid S3 endpoint: %w", err)
+	}
+	if endpoint.Scheme != "http" && endpoint.Scheme != "https" || endpoint.Host == "" {
+		return nil, fmt.Errorf("invalid S3 endpoint %q", cfg.Endpoint)
+	}
+	if cfg.Region == "" {
+		cfg.Region = "us-east-1"
+	}
+
+	s := &s3BlobStore{client, cfg, endpoint}
+
+	res, err := s.do(ctx, http.MethodHead, "", nil, nil, nil)
+	if err != nil {
+		return nil, fmt.Errorf("S3 bucket %q is not accessible: %w", cfg.Bucket, err)
+	}
+	res.Body.Close()
+
+	return s, nil
+}
+
+func (s *s3BlobStore) Put(ctx context.Context, key string, r io.Reader) (err error) {
+	defer func() {
+		if err != nil {
+			var appErr *dto.AppError
+			if !errors.As(err, &appErr) {
+				err = fmt.Errorf("S3BlobStore - Put: %w", err)
+			}
+		}
+	}()
+
+	key, err = cleanBlobKey(key)
+	if err != nil {
+		return
+	}
+
+	buf := make([]byte, s3PartSize)
+	n, err := io.ReadFull(r, buf)
+	if err == io.EOF || err == io.ErrUnexpectedEOF {
+		var res *http.Response
+		res, err = s.do(ctx, http.MethodPut, key, nil, nil, buf[:n])
+		if err != nil {
+			return
+		}
+		return res.Body.Close()
+	} else if err != nil {
+		return
+	}
+
+	return s.putMultipart(ctx, key, r, buf)
+}
+
+func (s *s3BlobStore) putMultipart(ctx context.Context, key string, r io.Reader, buf []byte) (err error) {
+	res, err := s.do(ctx, http.MethodPost, key, url.Values{"uploads": {""}}, nil, nil)
+	if err != nil {
+		return
+	}
+	var upload s3InitiateMultipartUploadResult
+	err = decodeS3XML(res, &upload)
+	if err != nil {
+		return
+	}
+
+	defer func() {
+		if err != nil {
+			res, abortErr := s.do(
+				context.Background(),
+				http.MethodDelete,
+				key,
+				url.Values{"uploadId": {upload.UploadID}},
+				nil,
+				nil,
+			)
+			if abortErr == nil {
+				res.Body.Close()
+			}
+		}
+	}()
+
+	var complete s3CompleteMultipartUpload
+	n := len(buf)
+	for partNumber := 1; n > 0; partNumber++ {
+		res, err = s.do(ctx, http.MethodPut, key, url.Values{
+			"partNumber": {strconv.Itoa(partNumber)},
+			"uploadId":   {upload.UploadID},
+		}, nil, buf[:n])
+		if err != nil {
+			return
+		}
+		res.Body.Close()
+
+		complete.Parts = append(complete.Parts, s3CompletedPart{partNumber, res.Header.Get("ETag")})
+
+		n, err = io.ReadFull(r, buf)
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			err = nil
+		} else if err != nil {
+			return
+		}
+	}
+
+	body, err := xml.Marshal(complete)
+	if err != nil {
+		return
+	}
+
+	res, err = s.do(ctx, http.MethodPost, key, url.Values{"uploadId": {upload.UploadID}}, nil, body)
+	if err != nil {
+		return
+	}
+
+	var result struct {
+		XMLName xml.Name
+		s3Error
+	}
+	err = decodeS3XML(res, &result)
+	if err != nil {
+		return
+	}
+	if result.XMLName.Local == "Error" {
+		result.Status = res.StatusCode
+		return &result.s3Error
+	}
+
+	return
+}
+
+func (s *s3BlobStore) Open(ctx context.Context, key string) (file entity.File, err error) {
+	defer func() {
+		if err != nil {
+			var appErr *dto.AppError
+			if !errors.As(err, &appErr) {
+				err = fmt.Errorf("S3BlobStore - Open: %w", err)
+			}
+		}
+	}()
+
+	key, err = cleanBlobKey(key)
+	if err != nil {
+		return
+	}
+
+	res, err := s.do(ctx, http.MethodHead, key, nil, nil, nil)
+	if err != nil {
+		var s3Err *s3Error
+		if errors.As(err, &s3Err) && s3Err.Status == http.StatusNotFound {
+			err = &dto.AppError{
+				Code:    dto.ErrCodeNotFound,
+				Message: "Файл не найден",
+			}
+		}
+		return
+	}
+	res.Body.Close()
+
+	modTime, _ := http.ParseTime(res.Header.Get("Last-Modified"))
+
+	return &s3Object{
+		ctx:     ctx,
+		store:   s,
+		key:     key,
+		size:    res.ContentLength,
+		modTime: modTime,
+	}, nil
+}
+
+func (s *s3BlobStore) Move(ctx context.Context, from, to string) (err error) {
+	defer func() {
+		if err != nil {
+			var appErr *dto.AppError
+			if !errors.As(err, &appErr) {
+				err = fmt.Errorf("S3BlobStore - Move: %w", err)
+			}
+		}
+	}()
+
+	from, err = cleanBlobKey(from)
+	if err != nil {
+		return
+	}
+
+	to, err = cleanBlobKey(to)
+	if err != nil {
+		return
+	}
+
+	res, err := s.do(ctx, http.MethodPut, to, nil, http.Header{
+		"X-Amz-Copy-Source": {s3EscapePath("/" + s.cfg.Bucket + "/" + from)},
+	}, nil)
+	if err != nil {
+		return
+	}
+
+	var result struct {
+		XMLName xml.Name
+		s3Error
+	}
+	err = decodeS3XML(res, &result)
+	if err != nil {
+		return
+	}
+	if result.XMLName.Local == "Error" {
+		result.Status = res.StatusCode
+		return &result.s3Error
+	}
+
+	res, err = s.do(ctx, http.MethodDelete, from, nil, nil, nil)
+	if err != nil {
+		return
+	}
+	return res.Body.Close()
+}
+
+func (s *s3BlobStore) Delete(ctx context.Context, key string) (err error) {
+	defer func() {
+		if err != nil {
+			var appErr *dto.AppError
+			if !errors.As(err, &appErr) {
+				err = fmt.Errorf("S3BlobStore - Delete: %w", err)
+			}
+		}
+	}()
+
+	key, err = cleanBlobKey(key)
+	if err != nil {
+		return
+	}
+
+	res, err := s.do(ctx, http.MethodDelete, key, nil, nil, nil)
+	if err != nil {
+		return
+	}
+	return res.Body.Close()
+}
+
+func (s *s3BlobStore) List(ctx context.Context, prefix string, fn func(entity.StoredFile) error) (err error) {
+	defer func() {
+		if err != nil {
+			var appErr *dto.AppError
+			if !errors.As(err, &appErr) {
+				err = fmt.Errorf("S3BlobStore - List: %w", err)
+			}
+		}
+	}()
+
+	prefix, err = cleanBlobKey(prefix)
+	if err != nil {
+		return
+	}
+
+	query := url.Values{"list-type": {"2"}, "prefix": {prefix + "/"}}
+	for {
+		var res *http.Response
+		res, err = s.do(ctx, http.MethodGet, "", query, nil, nil)
+		if err != nil {
+			return
+		}
+
+		var result s3ListBucketResult
+		err = decodeS3XML(res, &result)
+		if err != nil {
+			return
+		}
+
+		for _, o := range result.Contents {
+			err = fn(entity.StoredFile{Key: o.Key, Size: o.Size, ModTime: o.LastModified})
+			if err != nil {
+				return
+			}
+		}
+
+		if !result.IsTruncated || result.NextContinuationToken == "" {
+			return
+		}
+		query.Set("continuation-token", result.NextContinuationToken)
+	}
+}
+
+func (s *s3BlobStore) do(
+	ctx context.Context,
+	method, key string,
+	query url.Values,
+	header http.Header,
+	body []byte,
+) (*http.Response, error) {
+	u := *s.endpoint
+	if s.cfg.PathStyle {
+		u.Path += "/" + s.cfg.Bucket
+	} else {
+		u.Host = s.cfg.Bucket + "." + u.Host
+	}
+	if key != "" || !s.cfg.PathStyle {
+		u.Path += "/" + key
+	}
+	u.RawPath = s3EscapePath(u.Path)
+	u.RawQuery = s3CanonicalQuery(query)
+
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), reader)
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	if body != nil {
+		req.ContentLength = int64(len(body))
+	}
+
+	s.sign(req, body, time.Now().UTC())
+
+	res, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if res.StatusCode >= 300 {
+		defer res.Body.Close()
+		s3Err := &s3Error{Status: res.StatusCode}
+		data, _ := io.ReadAll(io.LimitReader(res.Body, 64<<10))
+		_ = xml.Unmarshal(data, s3Err)
+		return nil, s3Err
+	}
+
+	return res, nil
+}
+
+func (s *s3BlobStore) sign(req *http.Request, body []byte, now time.Time) {
+	payloadHash := s3EmptyBodySHA256
+	if len(body) > 0 {
+		sum := sha256.Sum256(body)
+		payloadHash = hex.EncodeToString(sum[:])
+	}
+
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	headers := map[string]string{"host": req.URL.Host}
+	for k, v := range req.Header {
+		headers[strings.ToLower(k)] = strings.TrimSpace(strings.Join(v, ","))
+	}
+	names := make([]string, 0, len(headers))
+	for k := range headers {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+
+	var canonicalHeaders strings.Builder
+	for _, k := range names {
+		canonicalHeaders.WriteString(k + ":" + headers[k] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		req.URL.RawQuery,
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := date + "/" + s.cfg.Region + "/s3/aws4_request"
+	hash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(hash[:])
+
+	key := hmacSHA256([]byte("AWS4"+s.cfg.SecretAccessKey), date)
+	key = hmacSHA256(key, s.cfg.Region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf(
+		"AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s.cfg.AccessKeyID,
+		scope,
+		signedHeaders,
+		signature,
+	))
+}
+
+func (o *s3Object) Size() int64 {
+	return o.size
+}
+
+func (o *s3Object) ModTime() time.Time {
+	return o.modTime
+}
+
+func (o *s3Object) Read(p []byte) (int, error) {
+	o.mu.Lock()
+	pos := o.pos
+	o.mu.Unlock()
+
+	n, err := o.ReadAt(p, pos)
+
+	o.mu.Lock()
+	o.pos += int64(n)
+	o.mu.Unlock()
+
+	return n, err
+}
+
+func (o *s3Object) Seek(offset int64, whence int) (int64, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	switch whence {
+	case io.SeekStart:
+	case io.SeekCurrent:
+		offset += o.pos
+	case io.SeekEnd:
+		offset += o.size
+	default:
+		return 0, errors.New("S3Object - Seek: invalid whence")
+	}
+	if offset < 0 {
+		return 0, errors.New("S3Object - Seek: negative position")
+	}
+
+	o.pos = offset
+	return offset, nil
+}
+
+func (o *s3Object) ReadAt(p []byte, off int64) (n int, err error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if off >= o.size {
+		return 0, io.EOF
+	}
+
+	want := p
+	if remaining := o.size - off; int64(len(want)) > remaining {
+		want = want[:remaining]
+	}
+
+	if o.body == nil || o.bodyOff != off {
+		o.closeBody()
+
+		var res *http.Response
+		res, err = o.store.do(o.ctx, http.MethodGet, o.key, nil, http.Header{
+			"Range": {fmt.Sprintf("bytes=%d-", off)},
+		}, nil)
+		if err != nil {
+			return 0, fmt.Errorf("S3Object - ReadAt: %w", err)
+		}
+		o.body = res.Body
+		o.bodyOff = off
+	}
+
+	n, err = io.ReadFull(o.body, want)
+	o.bodyOff += int64(n)
+	if err != nil {
+		o.closeBody()
+		return n, fmt.Errorf("S3Object - ReadAt: %w", err)
+	}
+
+	if n < len(p) {
+		return n, io.EOF
+	}
+	return n, nil
+}
+
+func (o *s3Object) Close() error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	o.closeBody()
+	return nil
+}
+
+func (o *s3Object) closeBody() {
+	if o.body != nil {
+		o.body.Close()
+		o.body = nil
+	}
+}
+
+func (e *s3Error) Error() string {
+	if e.Code == "" {
+		return fmt.Sprintf("s3: unexpected status %d", e.Status)
+	}
+	return fmt.Sprintf("s3: %s: %s (status %d)", e.Code, e.Message, e.Status)
+}
+
+func decodeS3XML(res *http.Response, v any) error {
+	defer res.Body.Close()
+	return xml.NewDecoder(io.LimitReader(res.Body, 1<<20)).Decode(v)
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+func s3EscapePath(p string) string {
+	segments := strings.Split(p, "/")
+	for i, segment := range segments {
+		segments[i] = s3Escape(segment)
+	}
+	return strings.Join(segments, "/")
+}
+
+func s3CanonicalQuery(query url.Values) string {
+	keys := make([]string, 0, len(query))
+	for k := range query {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	pairs := make([]string, 0, len(keys))
+	for _, k := range keys {
+		for _, v := range query[k] {
+			pairs = append(pairs, s3Escape(k)+"="+s3Escape(v))
+		}
+	}
+	return strings.Join(pairs, "&")
+}
+
+func s3Escape(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if 'A' <= c && c <= 'Z' || 'a' <= c && c <= 'z' || '0' <= c && c <= '9' ||
+			c == '-' || c == '_' || c == '.' || c == '~' {
+			b.WriteByte(c)
+		} else {
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
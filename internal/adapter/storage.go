@@ -0,0 +1,65 @@
+package adapter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"news-app-api/internal/dto"
+	"news-app-api/internal/entity"
+)
+
+type (
+	StorageRepository interface {
+		GetStorageReferences(ctx context.Context) ([]entity.StorageReference, error)
+		CountUnreferencedBlobs(ctx context.Context, acquiredBefore int64) (int64, error)
+	}
+
+	storageRepository struct {
+		db *pgxpool.Pool
+	}
+)
+
+func NewStorageRepository(db *pgxpool.Pool) StorageRepository {
+	return &storageRepository{db}
+}
+
+func (r *storageRepository) GetStorageReferences(ctx context.Context) (refs []entity.StorageReference, err error) {
+	defer func() {
+		if err != nil {
+			var appErr *dto.AppError
+			if !errors.As(err, &appErr) {
+				err = fmt.Errorf("StorageRepository - GetStorageReferences: %w", err)
+			}
+		}
+	}()
+	rows, err := r.db.Query(ctx, queryGetStorageReferences)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var ref entity.StorageReference
+		err = rows.Scan(&ref.Key, &ref.Size, &ref.Optional)
+		if err != nil {
+			return
+		}
+		refs = append(refs, ref)
+	}
+	err = rows.Err()
+	return
+}
+
+func (r *storageRepository) CountUnreferencedBlobs(ctx context.Context, acquiredBefore int64) (n int64, err error) {
+	defer func() {
+		if err != nil {
+			var appErr *dto.AppError
+			if !errors.As(err, &appErr) {
+				err = fmt.Errorf("StorageRepository - CountUnreferencedBlobs: %w", err)
+			}
+		}
+	}()
+	err = r.db.QueryRow(ctx, queryCountUnreferencedBlobs, acquiredBefore).Scan(&n)
+	return
+}
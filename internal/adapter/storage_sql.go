@@ -0,0 +1,45 @@
+package adapter
+
+const (
+	queryGetStorageReferences = `
+SELECT storage_key, size, FALSE
+FROM attachment
+WHERE blob_hash IS NULL
+UNION ALL
+SELECT storage_key, size, FALSE
+FROM attachment_variant
+WHERE blob_hash IS NULL
+UNION ALL
+SELECT storage_key, size, FALSE
+FROM blob
+UNION ALL
+SELECT storage_key, size, FALSE
+FROM text_track
+UNION ALL
+SELECT storage_key, size, FALSE
+FROM upload_chunk
+UNION ALL
+SELECT 'image/media-' || id_editor || '-logo', NULL, FALSE
+FROM media
+WHERE logo_updated_at IS NOT NULL
+UNION ALL
+SELECT 'image/media-' || id_editor || '-cover', NULL, FALSE
+FROM media
+WHERE cover_updated_at IS NOT NULL
+UNION ALL
+SELECT legacy.prefix || n.ID_news || legacy.ext, NULL, TRUE
+FROM news n
+         CROSS JOIN (VALUES ('audio', 'audio/', '.wav'),
+                            ('image', 'image/', '.png'),
+                            ('video', 'video/', '.mp4')) AS legacy (kind, prefix, ext)
+WHERE NOT EXISTS(SELECT 1 FROM attachment a WHERE a.news_id = n.ID_news AND a.kind = legacy.kind)
+`
+
+	queryCountUnreferencedBlobs = `
+SELECT COUNT(*)
+FROM blob
+WHERE acquired_at < TO_TIMESTAMP($1::BIGINT)
+  AND NOT EXISTS(SELECT 1 FROM attachment WHERE blob_hash = blob.content_hash)
+  AND NOT EXISTS(SELECT 1 FROM attachment_variant WHERE blob_hash = blob.content_hash)
+`
+)
@@ -0,0 +1,157 @@
+package adapter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"gopkg.in/guregu/null.v3"
+	"news-app-api/internal/dto"
+	"news-app-api/internal/entity"
+)
+
+type (
+	TextTrackRepository interface {
+		UpsertTextTrack(ctx context.Context, t entity.TextTrack, searchConfig, searchText null.String) (entity.TextTrack, error)
+		GetTextTracks(ctx context.Context, newsID int64, target string) ([]entity.TextTrack, error)
+		GetTextTrack(ctx context.Context, newsID int64, target, kind, language string) (entity.TextTrack, error)
+		DeleteTextTrack(ctx context.Context, newsID int64, target, kind, language string) (string, error)
+	}
+
+	textTrackRepository struct {
+		db *pgxpool.Pool
+	}
+)
+
+func NewTextTrackRepository(db *pgxpool.Pool) TextTrackRepository {
+	return &textTrackRepository{db}
+}
+
+func (r *textTrackRepository) UpsertTextTrack(
+	ctx context.Context,
+	t entity.TextTrack,
+	searchConfig, searchText null.String,
+) (res entity.TextTrack, err error) {
+	defer func() {
+		if err != nil {
+			var appErr *dto.AppError
+			if !errors.As(err, &appErr) {
+				err = fmt.Errorf("TextTrackRepository - UpsertTextTrack: %w", err)
+			}
+		}
+	}()
+	row := r.db.QueryRow(
+		ctx,
+		queryUpsertTextTrack,
+		t.NewsID,
+		t.Target,
+		t.Kind,
+		t.Language,
+		t.Label,
+		t.ContentType,
+		t.Size,
+		t.ContentHash,
+		t.StorageKey,
+		searchConfig,
+		searchText,
+	)
+	return scanTextTrack(row)
+}
+
+func (r *textTrackRepository) GetTextTracks(
+	ctx context.Context,
+	newsID int64,
+	target string,
+) (res []entity.TextTrack, err error) {
+	defer func() {
+		if err != nil {
+			var appErr *dto.AppError
+			if !errors.As(err, &appErr) {
+				err = fmt.Errorf("TextTrackRepository - GetTextTracks: %w", err)
+			}
+		}
+	}()
+	rows, err := r.db.Query(ctx, queryGetTextTracks, newsID, target)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	res = []entity.TextTrack{}
+	for rows.Next() {
+		var t entity.TextTrack
+		t, err = scanTextTrack(rows)
+		if err != nil {
+			return
+		}
+		res = append(res, t)
+	}
+	err = rows.Err()
+	return
+}
+
+func (r *textTrackRepository) GetTextTrack(
+	ctx context.Context,
+	newsID int64,
+	target, kind, language string,
+) (t entity.TextTrack, err error) {
+	defer func() {
+		if err != nil {
+			var appErr *dto.AppError
+			if !errors.As(err, &appErr) {
+				err = fmt.Errorf("TextTrackRepository - GetTextTrack: %w", err)
+			}
+		}
+	}()
+	t, err = scanTextTrack(r.db.QueryRow(ctx, queryGetTextTrack, newsID, target, kind, language))
+	if err == pgx.ErrNoRows {
+		err = errTextTrackNotFound()
+	}
+	return
+}
+
+func (r *textTrackRepository) DeleteTextTrack(
+	ctx context.Context,
+	newsID int64,
+	target, kind, language string,
+) (key string, err error) {
+	defer func() {
+		if err != nil {
+			var appErr *dto.AppError
+			if !errors.As(err, &appErr) {
+				err = fmt.Errorf("TextTrackRepository - DeleteTextTrack: %w", err)
+			}
+		}
+	}()
+	err = r.db.QueryRow(ctx, queryDeleteTextTrack, newsID, target, kind, language).Scan(&key)
+	if err == pgx.ErrNoRows {
+		err = errTextTrackNotFound()
+	}
+	return
+}
+
+func scanTextTrack(row pgx.Row) (t entity.TextTrack, err error) {
+	err = row.Scan(
+		&t.ID,
+		&t.NewsID,
+		&t.Target,
+		&t.Kind,
+		&t.Language,
+		&t.Label,
+		&t.ContentType,
+		&t.Size,
+		&t.ContentHash,
+		&t.StorageKey,
+		&t.CreatedAt,
+		&t.UpdatedAt,
+	)
+	return
+}
+
+func errTextTrackNotFound() error {
+	return &dto.AppError{
+		Message: "Дорожка не найдена",
+		Code:    dto.ErrCodeNotFound,
+	}
+}
@@ -0,0 +1,79 @@
+package adapter
+
+const (
+	queryUpsertTextTrack = `
+INSERT INTO text_track (news_id, target, kind, language, label, content_type, size, content_hash, storage_key,
+                        search_config, search_vector)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10::TEXT::REGCONFIG, to_tsvector($10::TEXT::REGCONFIG, $11::TEXT))
+ON CONFLICT (news_id, target, kind, language) DO UPDATE
+    SET label         = EXCLUDED.label,
+        content_type  = EXCLUDED.content_type,
+        size          = EXCLUDED.size,
+        content_hash  = EXCLUDED.content_hash,
+        storage_key   = EXCLUDED.storage_key,
+        search_config = EXCLUDED.search_config,
+        search_vector = EXCLUDED.search_vector,
+        updated_at    = NOW()
+RETURNING id,
+          news_id,
+          target,
+          kind,
+          language,
+          label,
+          content_type,
+          size,
+          content_hash,
+          storage_key,
+          EXTRACT(EPOCH FROM created_at)::BIGINT,
+          EXTRACT(EPOCH FROM updated_at)::BIGINT
+`
+
+	queryGetTextTracks = `
+SELECT id,
+       news_id,
+       target,
+       kind,
+       language,
+       label,
+       content_type,
+       size,
+       content_hash,
+       storage_key,
+       EXTRACT(EPOCH FROM created_at)::BIGINT,
+       EXTRACT(EPOCH FROM updated_at)::BIGINT
+FROM text_track
+WHERE news_id = $1
+  AND target = $2
+ORDER BY kind, language
+`
+
+	queryGetTextTrack = `
+SELECT id,
+       news_id,
+       target,
+       kind,
+       language,
+       label,
+       content_type,
+       size,
+       content_hash,
+       storage_key,
+       EXTRACT(EPOCH FROM created_at)::BIGINT,
+       EXTRACT(EPOCH FROM updated_at)::BIGINT
+FROM text_track
+WHERE news_id = $1
+  AND target = $2
+  AND kind = $3
+  AND language = $4
+`
+
+	queryDeleteTextTrack = `
+DELETE
+FROM text_track
+WHERE news_id = $1
+  AND target = $2
+  AND kind = $3
+  AND language = $4
+RETURNING storage_key
+`
+)
@@ -0,0 +1,117 @@
+package adapter
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp"
+	"image"
+	"image/color"
+	_ "image/gif"
+	"image/jpeg"
+	_ "image/png"
+	"news-app-api/internal/dto"
+	"news-app-api/internal/entity"
+	"sort"
+)
+
+const (
+	thumbnailMaxPixels   = 40_000_000
+	thumbnailJPEGQuality = 82
+)
+
+type (
+	Thumbnailer interface {
+		Generate(data []byte, widths []int) ([]entity.Thumbnail, error)
+	}
+
+	thumbnailer struct{}
+)
+
+func NewThumbnailer() Thumbnailer {
+	return &thumbnailer{}
+}
+
+func (t *thumbnailer) Generate(data []byte, widths []int) (res []entity.Thumbnail, err error) {
+	defer func() {
+		if err != nil {
+			var appErr *dto.AppError
+			if !errors.As(err, &appErr) {
+				err = fmt.Errorf("Thumbnailer - Generate: %w", err)
+			}
+		}
+	}()
+
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, &dto.AppError{
+			Message: "Не удалось прочитать изображение",
+			Code:    dto.ErrCodeBadRequest,
+		}
+	}
+	if cfg.Width < 1 || cfg.Height < 1 || cfg.Width*cfg.Height > thumbnailMaxPixels {
+		return nil, &dto.AppError{
+			Message: "Максимальное разрешение изображения - 40 мегапикселей",
+			Code:    dto.ErrCodeBadRequest,
+		}
+	}
+
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, &dto.AppError{
+			Message: "Не удалось прочитать изображение",
+			Code:    dto.ErrCodeBadRequest,
+		}
+	}
+	bounds := src.Bounds()
+
+	for _, width := range thumbnailWidths(widths, bounds.Dx()) {
+		height := (bounds.Dy()*width + bounds.Dx()/2) / bounds.Dx()
+		if height < 1 {
+			height = 1
+		}
+
+		scaled := image.NewNRGBA(image.Rect(0, 0, width, height))
+		draw.CatmullRom.Scale(scaled, scaled.Rect, src, bounds, draw.Src, nil)
+
+		if width <= vp8lMaxDimension && height <= vp8lMaxDimension {
+			var buf bytes.Buffer
+			err = encodeWebPLossless(&buf, scaled)
+			if err != nil {
+				return
+			}
+			res = append(res, entity.Thumbnail{Width: width, Height: height, ContentType: "image/webp", Data: buf.Bytes()})
+		}
+
+		flat := image.NewRGBA(scaled.Rect)
+		draw.Draw(flat, flat.Rect, image.NewUniform(color.White), image.Point{}, draw.Src)
+		draw.Draw(flat, flat.Rect, scaled, image.Point{}, draw.Over)
+
+		var buf bytes.Buffer
+		err = jpeg.Encode(&buf, flat, &jpeg.Options{Quality: thumbnailJPEGQuality})
+		if err != nil {
+			return
+		}
+		res = append(res, entity.Thumbnail{Width: width, Height: height, ContentType: "image/jpeg", Data: buf.Bytes()})
+	}
+
+	return
+}
+
+func thumbnailWidths(widths []int, max int) []int {
+	seen := make(map[int]bool, len(widths))
+	res := make([]int, 0, len(widths))
+	for _, w := range widths {
+		if w > max {
+			w = max
+		}
+		if w < 1 || seen[w] {
+			continue
+		}
+		seen[w] = true
+		res = append(res, w)
+	}
+	sort.Ints(res)
+	return res
+}
@@ -0,0 +1,215 @@
+package adapter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"gopkg.in/guregu/null.v3"
+	"news-app-api/internal/dto"
+	"news-app-api/internal/entity"
+	"time"
+)
+
+type (
+	UploadRepository interface {
+		CreateUpload(ctx context.Context, u entity.Upload) (entity.Upload, error)
+		GetUpload(ctx context.Context, id string) (entity.Upload, error)
+		AddUploadChunk(ctx context.Context, id string, c entity.UploadChunk, expiresAt int64) (entity.Upload, error)
+		GetUploadChunks(ctx context.Context, id string) ([]entity.UploadChunk, error)
+		ClaimProcessingUpload(ctx context.Context, lease time.Duration) (entity.Upload, bool, error)
+		FinishUpload(ctx context.Context, id, status string, message null.String) ([]string, error)
+		DeleteUpload(ctx context.Context, id string) ([]string, error)
+		DeleteExpiredUploads(ctx context.Context) ([]string, error)
+	}
+
+	uploadRepository struct {
+		db *pgxpool.Pool
+	}
+)
+
+func NewUploadRepository(db *pgxpool.Pool) UploadRepository {
+	return &uploadRepository{db}
+}
+
+func (r *uploadRepository) CreateUpload(ctx context.Context, u entity.Upload) (res entity.Upload, err error) {
+	defer func() {
+		if err != nil {
+			var appErr *dto.AppError
+			if !errors.As(err, &appErr) {
+				err = fmt.Errorf("UploadRepository - CreateUpload: %w", err)
+			}
+		}
+	}()
+	row := r.db.QueryRow(ctx, queryCreateUpload, u.ID, u.MediaID, u.NewsID, u.Kind, u.Length, u.ExpiresAt)
+	res, err = scanUpload(row)
+	return
+}
+
+func (r *uploadRepository) GetUpload(ctx context.Context, id string) (res entity.Upload, err error) {
+	defer func() {
+		if err != nil {
+			var appErr *dto.AppError
+			if !errors.As(err, &appErr) {
+				err = fmt.Errorf("UploadRepository - GetUpload: %w", err)
+			}
+		}
+	}()
+	row := r.db.QueryRow(ctx, queryGetUpload, id)
+	res, err = scanUpload(row)
+	if err == pgx.ErrNoRows {
+		err = &dto.AppError{
+			Message: "Загрузка не найдена",
+			Code:    dto.ErrCodeNotFound,
+		}
+	}
+	return
+}
+
+func (r *uploadRepository) AddUploadChunk(
+	ctx context.Context,
+	id string,
+	c entity.UploadChunk,
+	expiresAt int64,
+) (res entity.Upload, err error) {
+	defer func() {
+		if err != nil {
+			var appErr *dto.AppError
+			if !errors.As(err, &appErr) {
+				err = fmt.Errorf("UploadRepository - AddUploadChunk: %w", err)
+			}
+		}
+	}()
+	row := r.db.QueryRow(ctx, queryAddUploadChunk, id, c.Offset, c.Size, c.StorageKey, expiresAt)
+	res, err = scanUpload(row)
+	if err == pgx.ErrNoRows {
+		err = &dto.AppError{
+			Message: "Смещение загрузки не совпадает с текущим",
+			Code:    dto.ErrCodeConflict,
+		}
+	}
+	return
+}
+
+func (r *uploadRepository) GetUploadChunks(ctx context.Context, id string) (res []entity.UploadChunk, err error) {
+	defer func() {
+		if err != nil {
+			var appErr *dto.AppError
+			if !errors.As(err, &appErr) {
+				err = fmt.Errorf("UploadRepository - GetUploadChunks: %w", err)
+			}
+		}
+	}()
+	rows, err := r.db.Query(ctx, queryGetUploadChunks, id)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var c entity.UploadChunk
+		err = rows.Scan(&c.Offset, &c.Size, &c.StorageKey)
+		if err != nil {
+			return
+		}
+		res = append(res, c)
+	}
+	err = rows.Err()
+	return
+}
+
+func (r *uploadRepository) ClaimProcessingUpload(
+	ctx context.Context,
+	lease time.Duration,
+) (res entity.Upload, ok bool, err error) {
+	defer func() {
+		if err != nil {
+			var appErr *dto.AppError
+			if !errors.As(err, &appErr) {
+				err = fmt.Errorf("UploadRepository - ClaimProcessingUpload: %w", err)
+			}
+		}
+	}()
+	row := r.db.QueryRow(ctx, queryClaimProcessingUpload, int64(lease.Seconds()))
+	res, err = scanUpload(row)
+	if err == pgx.ErrNoRows {
+		return res, false, nil
+	}
+	return res, err == nil, err
+}
+
+func (r *uploadRepository) FinishUpload(
+	ctx context.Context,
+	id, status string,
+	message null.String,
+) (keys []string, err error) {
+	defer func() {
+		if err != nil {
+			var appErr *dto.AppError
+			if !errors.As(err, &appErr) {
+				err = fmt.Errorf("UploadRepository - FinishUpload: %w", err)
+			}
+		}
+	}()
+	return r.queryStorageKeys(ctx, queryFinishUpload, id, status, message)
+}
+
+func (r *uploadRepository) DeleteUpload(ctx context.Context, id string) (keys []string, err error) {
+	defer func() {
+		if err != nil {
+			var appErr *dto.AppError
+			if !errors.As(err, &appErr) {
+				err = fmt.Errorf("UploadRepository - DeleteUpload: %w", err)
+			}
+		}
+	}()
+	return r.queryStorageKeys(ctx, queryDeleteUpload, id)
+}
+
+func (r *uploadRepository) DeleteExpiredUploads(ctx context.Context) (keys []string, err error) {
+	defer func() {
+		if err != nil {
+			var appErr *dto.AppError
+			if !errors.As(err, &appErr) {
+				err = fmt.Errorf("UploadRepository - DeleteExpiredUploads: %w", err)
+			}
+		}
+	}()
+	return r.queryStorageKeys(ctx, queryDeleteExpiredUploads)
+}
+
+func (r *uploadRepository) queryStorageKeys(ctx context.Context, query string, args ...any) (keys []string, err error) {
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var key string
+		err = rows.Scan(&key)
+		if err != nil {
+			return
+		}
+		keys = append(keys, key)
+	}
+	err = rows.Err()
+	return
+}
+
+func scanUpload(row pgx.Row) (u entity.Upload, err error) {
+	err = row.Scan(
+		&u.ID,
+		&u.MediaID,
+		&u.NewsID,
+		&u.Kind,
+		&u.Length,
+		&u.Offset,
+		&u.Status,
+		&u.Error,
+		&u.CreatedAt,
+		&u.ExpiresAt,
+	)
+	return
+}
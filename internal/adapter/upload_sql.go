@@ -0,0 +1,146 @@
+package adapter
+
+const (
+	queryCreateUpload = `
+INSERT INTO upload (id, media_id, news_id, kind, length, expires_at)
+VALUES ($1, $2, $3, $4, $5, TO_TIMESTAMP($6::BIGINT))
+RETURNING id,
+          media_id,
+          news_id,
+          kind,
+          length,
+          upload_offset,
+          status,
+          error,
+          EXTRACT(EPOCH FROM created_at)::BIGINT,
+          EXTRACT(EPOCH FROM expires_at)::BIGINT
+`
+
+	queryGetUpload = `
+SELECT id,
+       media_id,
+       news_id,
+       kind,
+       length,
+       upload_offset,
+       status,
+       error,
+       EXTRACT(EPOCH FROM created_at)::BIGINT,
+       EXTRACT(EPOCH FROM expires_at)::BIGINT
+FROM upload
+WHERE id = $1
+  AND media_id IS NOT NULL
+  AND news_id IS NOT NULL
+  AND expires_at > NOW()
+`
+
+	queryAddUploadChunk = `
+WITH updated AS (
+    UPDATE upload
+    SET upload_offset = upload_offset + $3,
+        expires_at    = TO_TIMESTAMP($5::BIGINT),
+        status        = CASE WHEN upload_offset + $3 = length THEN 'processing' ELSE status END,
+        process_after = CASE WHEN upload_offset + $3 = length THEN NOW() END
+    WHERE id = $1
+      AND upload_offset = $2
+      AND status = 'uploading'
+      AND media_id IS NOT NULL
+      AND news_id IS NOT NULL
+      AND expires_at > NOW()
+    RETURNING *
+), chunk AS (
+    INSERT INTO upload_chunk (upload_id, upload_offset, size, storage_key)
+    SELECT id, $2, $3, $4
+    FROM updated
+)
+SELECT id,
+       media_id,
+       news_id,
+       kind,
+       length,
+       upload_offset,
+       status,
+       error,
+       EXTRACT(EPOCH FROM created_at)::BIGINT,
+       EXTRACT(EPOCH FROM expires_at)::BIGINT
+FROM updated
+`
+
+	queryGetUploadChunks = `
+SELECT upload_offset,
+       size,
+       storage_key
+FROM upload_chunk
+WHERE upload_id = $1
+ORDER BY upload_offset
+`
+
+	queryClaimProcessingUpload = `
+UPDATE upload
+SET process_after = NOW() + $1::BIGINT * INTERVAL '1 second'
+WHERE id = (
+    SELECT id
+    FROM upload
+    WHERE status = 'processing'
+      AND process_after <= NOW()
+      AND media_id IS NOT NULL
+      AND news_id IS NOT NULL
+      AND expires_at > NOW()
+    ORDER BY process_after
+    LIMIT 1
+    FOR UPDATE SKIP LOCKED
+)
+RETURNING id,
+          media_id,
+          news_id,
+          kind,
+          length,
+          upload_offset,
+          status,
+          error,
+          EXTRACT(EPOCH FROM created_at)::BIGINT,
+          EXTRACT(EPOCH FROM expires_at)::BIGINT
+`
+
+	queryFinishUpload = `
+WITH updated AS (
+    UPDATE upload
+    SET status        = $2,
+        error         = $3,
+        process_after = NULL
+    WHERE id = $1
+      AND status = 'processing'
+    RETURNING id
+)
+DELETE
+FROM upload_chunk
+WHERE upload_id IN (SELECT id FROM updated)
+RETURNING storage_key
+`
+
+	queryDeleteUpload = `
+WITH deleted AS (
+    DELETE
+    FROM upload
+    WHERE id = $1
+    RETURNING id
+)
+SELECT storage_key
+FROM upload_chunk
+WHERE upload_id IN (SELECT id FROM deleted)
+`
+
+	queryDeleteExpiredUploads = `
+WITH deleted AS (
+    DELETE
+    FROM upload
+    WHERE expires_at <= NOW()
+       OR media_id IS NULL
+       OR news_id IS NULL
+    RETURNING id
+)
+SELECT storage_key
+FROM upload_chunk
+WHERE upload_id IN (SELECT id FROM deleted)
+`
+)
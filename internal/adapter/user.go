@@ -18,6 +18,11 @@ type (
 		CreateUser(ctx context.Context, p dto.RegisterUserParams) (entity.User, error)
 		GetSubscriptionList(ctx context.Context, p dto.GetSubscriptionListParams) ([]entity.MediaListItem, error)
 		CountSubscriptions(ctx context.Context, userID int64) (int64, error)
+		UpdateUserName(ctx context.Context, userID int64, name string) (entity.User, error)
+		UpdateUserPassword(ctx context.Context, userID int64, password string) error
+		UpsertEmailChange(ctx context.Context, userID int64, email, token string, expiresAt int64) error
+		GetEmailChangeByToken(ctx context.Context, token string) (int64, string, error)
+		ConfirmEmailChange(ctx context.Context, token string) (entity.User, error)
 	}
 
 	userRepository struct {
@@ -186,3 +191,118 @@ func (r *userRepository) CountSubscriptions(ctx context.Context, userID int64) (
 	err = row.Scan(&v)
 	return
 }
+
+func (r *userRepository) UpdateUserName(ctx context.Context, userID int64, name string) (u entity.User, err error) {
+	defer func() {
+		if err != nil {
+			var appErr *dto.AppError
+			if !errors.As(err, &appErr) {
+				err = fmt.Errorf("UserRepository - UpdateUserName: %w", err)
+			}
+		}
+	}()
+	row := r.db.QueryRow(ctx, queryUpdateUserName, userID, name)
+	err = row.Scan(
+		&u.ID,
+		&u.Login,
+		&u.Password,
+		&u.Name,
+		&u.Email,
+	)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			err = &dto.AppError{
+				Message: "Пользователь не найден",
+				Code:    dto.ErrCodeNotFound,
+			}
+		}
+		return
+	}
+	return
+}
+
+func (r *userRepository) UpdateUserPassword(ctx context.Context, userID int64, password string) (err error) {
+	defer func() {
+		if err != nil {
+			var appErr *dto.AppError
+			if !errors.As(err, &appErr) {
+				err = fmt.Errorf("UserRepository - UpdateUserPassword: %w", err)
+			}
+		}
+	}()
+	_, err = r.db.Exec(ctx, queryUpdateUserPassword, userID, password)
+	return
+}
+
+func (r *userRepository) UpsertEmailChange(
+	ctx context.Context,
+	userID int64,
+	email, token string,
+	expiresAt int64,
+) (err error) {
+	defer func() {
+		if err != nil {
+			var appErr *dto.AppError
+			if !errors.As(err, &appErr) {
+				err = fmt.Errorf("UserRepository - UpsertEmailChange: %w", err)
+			}
+		}
+	}()
+	_, err = r.db.Exec(ctx, queryUpsertEmailChange, userID, email, token, expiresAt)
+	return
+}
+
+func (r *userRepository) GetEmailChangeByToken(
+	ctx context.Context,
+	token string,
+) (userID int64, email string, err error) {
+	defer func() {
+		if err != nil {
+			var appErr *dto.AppError
+			if !errors.As(err, &appErr) {
+				err = fmt.Errorf("UserRepository - GetEmailChangeByToken: %w", err)
+			}
+		}
+	}()
+	row := r.db.QueryRow(ctx, queryGetEmailChangeByToken, token)
+	err = row.Scan(&userID, &email)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			err = &dto.AppError{
+				Message: "Ссылка для подтверждения недействительна или устарела",
+				Code:    dto.ErrCodeNotFound,
+			}
+		}
+		return
+	}
+	return
+}
+
+func (r *userRepository) ConfirmEmailChange(ctx context.Context, token string) (u entity.User, err error) {
+	defer func() {
+		if err != nil {
+			var appErr *dto.AppError
+			if !errors.As(err, &appErr) {
+				err = fmt.Errorf("UserRepository - ConfirmEmailChange: %w", err)
+			}
+		}
+	}()
+	row := r.db.QueryRow(ctx, queryConfirmEmailChange, token)
+	err = row.Scan(
+		&u.ID,
+		&u.Login,
+		&u.Password,
+		&u.Name,
+		&u.Email,
+	)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			err = &dto.AppError{
+				Message: "Ссылка для подтверждения недействительна или устарела",
+				Code:    dto.ErrCodeNotFound,
+			}
+		}
+		return
+	}
+	return
+}
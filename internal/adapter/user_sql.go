@@ -54,5 +54,47 @@ LIMIT $2 OFFSET $3
 SELECT COUNT(*)
 FROM media
 WHERE id_editor IN (SELECT media_id FROM subscription WHERE user_id = $1)
+`
+
+	queryUpdateUserName = `
+UPDATE "user"
+SET FIO_user = $2
+WHERE ID_user = $1
+RETURNING ID_user, Login, Password, FIO_user, Email_user
+`
+
+	queryUpdateUserPassword = `
+UPDATE "user" SET Password = $2 WHERE ID_user = $1
+`
+
+	queryUpsertEmailChange = `
+INSERT INTO email_change (user_id, new_email, token, expires_at)
+VALUES ($1, $2, $3, TO_TIMESTAMP($4::BIGINT))
+ON CONFLICT (user_id) DO UPDATE
+    SET new_email  = EXCLUDED.new_email,
+        token      = EXCLUDED.token,
+        expires_at = EXCLUDED.expires_at,
+        created_at = NOW()
+`
+
+	queryGetEmailChangeByToken = `
+SELECT user_id, new_email
+FROM email_change
+WHERE token = $1
+  AND expires_at > NOW()
+`
+
+	queryConfirmEmailChange = `
+WITH confirmed AS (
+    DELETE FROM email_change
+    WHERE token = $1
+      AND expires_at > NOW()
+    RETURNING user_id, new_email
+)
+UPDATE "user"
+SET Email_user = confirmed.new_email
+FROM confirmed
+WHERE "user".ID_user = confirmed.user_id
+RETURNING ID_user, Login, Password, FIO_user, Email_user
 `
 )
@@ -0,0 +1,1247 @@
+package adapter
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"math/bits"
+	"math/cmplx"
+	"sort"
+)
+
+const (
+	oggMaxPacketSize     = 1 << 20
+	vorbisMaxSetupValues = 1 << 20
+)
+
+var (
+	vorbisFloor1Ranges = [4]int{256, 128, 86, 64}
+
+	vorbisInverseDB = func() (t [256]float64) {
+		for i := range t {
+			t[i] = math.Exp(math.Log(1.0649863e-07) * float64(255-i) / 255)
+		}
+		return
+	}()
+)
+
+type (
+	oggPacketReader struct {
+		r        io.Reader
+		header   [27]byte
+		lacing   [255]byte
+		segments []byte
+		serial   uint32
+		started  bool
+		packet   []byte
+	}
+
+	vorbisBitReader struct {
+		data []byte
+		pos  int
+		eop  bool
+	}
+
+	vorbisCodebook struct {
+		dimensions int
+		entries    int
+		tree       [][2]int32
+		values     []float32
+	}
+
+	vorbisFloor struct {
+		partitionClasses []int
+		classDimensions  []int
+		classSubclasses  []int
+		classMasterbooks []int
+		subclassBooks    [][]int
+		multiplier       int
+		xList            []int
+		order            []int
+		lowNeighbor      []int
+		highNeighbor     []int
+		finalY           []int
+		step2            []bool
+	}
+
+	vorbisResidue struct {
+		typ             int
+		begin           int
+		end             int
+		partitionSize   int
+		classifications int
+		classbook       int
+		books           [][8]int
+	}
+
+	vorbisMapping struct {
+		magnitude     []int
+		angle         []int
+		mux           []int
+		submapFloor   []int
+		submapResidue []int
+	}
+
+	vorbisMode struct {
+		long    bool
+		mapping int
+	}
+
+	vorbisIMDCT struct {
+		n       int
+		twiddle []complex128
+		roots   []complex128
+		rev     []int
+		buf     []complex128
+		u       []float64
+	}
+
+	// vorbisDecoder decodes the first Vorbis I stream of an Ogg file into PCM.
+	vorbisDecoder struct {
+		packets    *oggPacketReader
+		channels   int
+		sampleRate int
+		blocksize  [2]int
+		codebooks  []vorbisCodebook
+		floors     []vorbisFloor
+		residues   []vorbisResidue
+		mappings   []vorbisMapping
+		modes      []vorbisMode
+		imdct      [2]*vorbisIMDCT
+		slopes     [2][]float64
+
+		floorY      [][]int
+		floorUsed   []bool
+		skip        []bool
+		spectrum    [][]float64
+		curve       []float64
+		block       [][]float64
+		prev        [][]float64
+		prevN       int
+		started     bool
+		out         [][]float64
+		interleaved []float64
+		classes     [][]int
+		vectors     [][]float64
+		vectorSkip  []bool
+	}
+)
+
+func newOggPacketReader(r io.Reader) *oggPacketReader {
+	return &oggPacketReader{r: r}
+}
+
+// next returns the next packet of the first logical stream, or io.EOF once the stream or file ends.
+func (o *oggPacketReader) next() ([]byte, error) {
+	o.packet = o.packet[:0]
+	for {
+		for len(o.segments) > 0 {
+			n := int(o.segments[0])
+			o.segments = o.segments[1:]
+			if len(o.packet)+n > oggMaxPacketSize {
+				return nil, fmt.Errorf("%w: Ogg packet is too large", errMalformedMedia)
+			}
+
+			start := len(o.packet)
+			o.packet = append(o.packet, make([]byte, n)...)
+			_, err := io.ReadFull(o.r, o.packet[start:])
+			if err == io.ErrUnexpectedEOF {
+				return nil, io.EOF
+			} else if err != nil {
+				return nil, err
+			}
+			if n < 255 {
+				return o.packet, nil
+			}
+		}
+
+		err := o.readPage()
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+func (o *oggPacketReader) readPage() error {
+	for {
+		_, err := io.ReadFull(o.r, o.header[:])
+		if err == io.ErrUnexpectedEOF {
+			return io.EOF
+		} else if err != nil {
+			return err
+		}
+		if !bytes.Equal(o.header[:4], []byte("OggS")) || o.header[4] != 0 {
+			return fmt.Errorf("%w: invalid Ogg page", errMalformedMedia)
+		}
+
+		lacing := o.lacing[:o.header[26]]
+		_, err = io.ReadFull(o.r, lacing)
+		if err == io.ErrUnexpectedEOF {
+			return io.EOF
+		} else if err != nil {
+			return err
+		}
+
+		serial := binary.LittleEndian.Uint32(o.header[14:])
+		if !o.started {
+			o.serial, o.started = serial, true
+		}
+		if serial == o.serial {
+			o.segments = lacing
+			return nil
+		}
+
+		var size int64
+		for _, n := range lacing {
+			size += int64(n)
+		}
+		_, err = io.CopyN(io.Discard, o.r, size)
+		if err == io.ErrUnexpectedEOF {
+			return io.EOF
+		} else if err != nil {
+			return err
+		}
+	}
+}
+
+func (b *vorbisBitReader) read(n int) uint32 {
+	if b.pos+n > len(b.data)*8 {
+		b.pos = len(b.data) * 8
+		b.eop = true
+		return 0
+	}
+
+	var v uint32
+	for shift := 0; shift < n; {
+		bit := b.pos & 7
+		take := 8 - bit
+		if take > n-shift {
+			take = n - shift
+		}
+		v |= uint32(b.data[b.pos>>3]>>bit) & (1<<take - 1) << shift
+		shift += take
+		b.pos += take
+	}
+	return v
+}
+
+func (b *vorbisBitReader) readBit() int32 {
+	if b.pos >= len(b.data)*8 {
+		b.eop = true
+		return 0
+	}
+	v := b.data[b.pos>>3] >> (b.pos & 7) & 1
+	b.pos++
+	return int32(v)
+}
+
+func vorbisIlog(x int) int {
+	if x <= 0 {
+		return 0
+	}
+	return bits.Len(uint(x))
+}
+
+func vorbisFloat32(x uint32) float64 {
+	mantissa := float64(x & 0x1fffff)
+	if x&0x80000000 != 0 {
+		mantissa = -mantissa
+	}
+	return math.Ldexp(mantissa, int(x&0x7fe00000>>21)-788)
+}
+
+func vorbisLookup1Values(entries, dimensions int) int {
+	pow := func(base int) int {
+		v := 1
+		for i := 0; i < dimensions && v <= entries; i++ {
+			v *= base
+		}
+		return v
+	}
+
+	r := int(math.Pow(float64(entries), 1/float64(dimensions)))
+	for pow(r+1) <= entries {
+		r++
+	}
+	for r > 0 && pow(r) > entries {
+		r--
+	}
+	return r
+}
+
+func readVorbisCodebook(b *vorbisBitReader, budget *int) (c vorbisCodebook, err error) {
+	if b.read(24) != 0x564342 {
+		return c, fmt.Errorf("%w: invalid Vorbis codebook", errMalformedMedia)
+	}
+	c.dimensions = int(b.read(16))
+	c.entries = int(b.read(24))
+	*budget -= c.entries
+	if *budget < 0 {
+		return c, fmt.Errorf("%w: Vorbis codebooks are too large", errMalformedMedia)
+	}
+
+	lengths := make([]int, c.entries)
+	if b.read(1) == 1 {
+		length := int(b.read(5)) + 1
+		for i := 0; i < c.entries; length++ {
+			n := int(b.read(vorbisIlog(c.entries - i)))
+			if b.eop || length > 32 || n > c.entries-i {
+				return c, fmt.Errorf("%w: invalid Vorbis codebook lengths", errMalformedMedia)
+			}
+			for ; n > 0; n-- {
+				lengths[i] = length
+				i++
+			}
+		}
+	} else {
+		sparse := b.read(1) == 1
+		for i := range lengths {
+			if !sparse || b.read(1) == 1 {
+				lengths[i] = int(b.read(5)) + 1
+			}
+		}
+	}
+
+	err = c.buildTree(lengths)
+	if err != nil {
+		return
+	}
+
+	lookup := b.read(4)
+	switch lookup {
+	case 0:
+	case 1, 2:
+		minimum, delta := vorbisFloat32(b.read(32)), vorbisFloat32(b.read(32))
+		valueBits := int(b.read(4)) + 1
+		sequence := b.read(1) == 1
+		if c.dimensions == 0 {
+			return c, fmt.Errorf("%w: invalid Vorbis codebook dimensions", errMalformedMedia)
+		}
+
+		n := c.entries * c.dimensions
+		if lookup == 1 {
+			n = vorbisLookup1Values(c.entries, c.dimensions)
+		}
+		*budget -= n + c.entries*c.dimensions
+		if *budget < 0 {
+			return c, fmt.Errorf("%w: Vorbis codebooks are too large", errMalformedMedia)
+		}
+
+		multiplicands := make([]uint32, n)
+		for i := range multiplicands {
+			multiplicands[i] = b.read(valueBits)
+		}
+
+		c.values = make([]float32, c.entries*c.dimensions)
+		for e := 0; e < c.entries; e++ {
+			last, divisor := 0.0, 1
+			for i := 0; i < c.dimensions; i++ {
+				offset := e*c.dimensions + i
+				if lookup == 1 {
+					offset = e / divisor % n
+					divisor *= n
+				}
+				v := float64(multiplicands[offset])*delta + minimum + last
+				if sequence {
+					last = v
+				}
+				c.values[e*c.dimensions+i] = float32(v)
+			}
+		}
+	default:
+		return c, fmt.Errorf("%w: invalid Vorbis codebook lookup type %d", errMalformedMedia, lookup)
+	}
+
+	if b.eop {
+		return c, fmt.Errorf("%w: truncated Vorbis codebook", errMalformedMedia)
+	}
+	return
+}
+
+// buildTree assigns each used entry the lowest free codeword of its length, in entry order.
+func (c *vorbisCodebook) buildTree(lengths []int) error {
+	c.tree = make([][2]int32, 1)
+
+	var available [33]uint32
+	first := true
+	for entry, length := range lengths {
+		if length == 0 {
+			continue
+		}
+		if first {
+			first = false
+			for i := 1; i <= length; i++ {
+				available[i] = 1 << (32 - i)
+			}
+			if !c.insert(0, length, entry) {
+				return fmt.Errorf("%w: invalid Vorbis codebook tree", errMalformedMedia)
+			}
+			continue
+		}
+
+		z := length
+		for z > 0 && available[z] == 0 {
+			z--
+		}
+		if z == 0 {
+			return fmt.Errorf("%w: overspecified Vorbis codebook", errMalformedMedia)
+		}
+		code := available[z]
+		available[z] = 0
+		for y := length; y > z; y-- {
+			available[y] = code + 1<<(32-y)
+		}
+		if !c.insert(code, length, entry) {
+			return fmt.Errorf("%w: invalid Vorbis codebook tree", errMalformedMedia)
+		}
+	}
+	return nil
+}
+
+func (c *vorbisCodebook) insert(code uint32, length, entry int) bool {
+	node := 0
+	for i := 0; i < length; i++ {
+		bit := code >> (31 - i) & 1
+		child := c.tree[node][bit]
+		if i == length-1 {
+			if child != 0 {
+				return false
+			}
+			c.tree[node][bit] = int32(^entry)
+			return true
+		}
+
+		if child < 0 {
+			return false
+		}
+		if child == 0 {
+			c.tree = append(c.tree, [2]int32{})
+			child = int32(len(c.tree) - 1)
+			c.tree[node][bit] = child
+		}
+		node = int(child)
+	}
+	return false
+}
+
+// decode returns the next entry number, or -1 at the end of the packet or on an invalid codeword.
+func (c *vorbisCodebook) decode(b *vorbisBitReader) int {
+	node := int32(0)
+	for {
+		node = c.tree[node][b.readBit()]
+		if b.eop || node == 0 {
+			return -1
+		}
+		if node < 0 {
+			return int(^node)
+		}
+	}
+}
+
+func (c *vorbisCodebook) vector(entry int) []float32 {
+	return c.values[entry*c.dimensions : (entry+1)*c.dimensions]
+}
+
+func readVorbisFloor(b *vorbisBitReader, books []vorbisCodebook) (f vorbisFloor, err error) {
+	if typ := b.read(16); typ != 1 {
+		return f, fmt.Errorf("unsupported Vorbis floor type %d", typ)
+	}
+
+	f.partitionClasses = make([]int, b.read(5))
+	classes := 0
+	for i := range f.partitionClasses {
+		f.partitionClasses[i] = int(b.read(4))
+		if f.partitionClasses[i] >= classes {
+			classes = f.partitionClasses[i] + 1
+		}
+	}
+
+	f.classDimensions = make([]int, classes)
+	f.classSubclasses = make([]int, classes)
+	f.classMasterbooks = make([]int, classes)
+	f.subclassBooks = make([][]int, classes)
+	for i := 0; i < classes; i++ {
+		f.classDimensions[i] = int(b.read(3)) + 1
+		f.classSubclasses[i] = int(b.read(2))
+		if f.classSubclasses[i] != 0 {
+			f.classMasterbooks[i] = int(b.read(8))
+			if f.classMasterbooks[i] >= len(books) {
+				return f, fmt.Errorf("%w: invalid Vorbis floor codebook", errMalformedMedia)
+			}
+		}
+		f.subclassBooks[i] = make([]int, 1<<f.classSubclasses[i])
+		for j := range f.subclassBooks[i] {
+			f.subclassBooks[i][j] = int(b.read(8)) - 1
+			if f.subclassBooks[i][j] >= len(books) {
+				return f, fmt.Errorf("%w: invalid Vorbis floor codebook", errMalformedMedia)
+			}
+		}
+	}
+
+	f.multiplier = int(b.read(2)) + 1
+	rangeBits := int(b.read(4))
+	f.xList = []int{0, 1 << rangeBits}
+	for _, class := range f.partitionClasses {
+		for j := 0; j < f.classDimensions[class]; j++ {
+			f.xList = append(f.xList, int(b.read(rangeBits)))
+		}
+	}
+
+	f.order = make([]int, len(f.xList))
+	for i := range f.order {
+		f.order[i] = i
+	}
+	sort.Slice(f.order, func(i, j int) bool { return f.xList[f.order[i]] < f.xList[f.order[j]] })
+	for i := 1; i < len(f.order); i++ {
+		if f.xList[f.order[i]] == f.xList[f.order[i-1]] {
+			return f, fmt.Errorf("%w: duplicate Vorbis floor point", errMalformedMedia)
+		}
+	}
+
+	f.lowNeighbor = make([]int, len(f.xList))
+	f.highNeighbor = make([]int, len(f.xList))
+	for i := 2; i < len(f.xList); i++ {
+		low, high := 0, 1
+		for j := 0; j < i; j++ {
+			if f.xList[j] < f.xList[i] && f.xList[j] > f.xList[low] {
+				low = j
+			}
+			if f.xList[j] > f.xList[i] && f.xList[j] < f.xList[high] {
+				high = j
+			}
+		}
+		f.lowNeighbor[i], f.highNeighbor[i] = low, high
+	}
+
+	f.finalY = make([]int, len(f.xList))
+	f.step2 = make([]bool, len(f.xList))
+	return
+}
+
+// decode reads the floor's Y values into y and reports whether the channel is used in this packet.
+func (f *vorbisFloor) decode(b *vorbisBitReader, books []vorbisCodebook, y []int) bool {
+	if b.read(1) == 0 {
+		return false
+	}
+
+	yBits := vorbisIlog(vorbisFloor1Ranges[f.multiplier-1] - 1)
+	y[0], y[1] = int(b.read(yBits)), int(b.read(yBits))
+	offset := 2
+	for _, class := range f.partitionClasses {
+		cbits := f.classSubclasses[class]
+		csub := 1<<cbits - 1
+		cval := 0
+		if cbits > 0 {
+			cval = books[f.classMasterbooks[class]].decode(b)
+			if cval < 0 {
+				return false
+			}
+		}
+		for j := 0; j < f.classDimensions[class]; j++ {
+			y[offset+j] = 0
+			if book := f.subclassBooks[class][cval&csub]; book >= 0 {
+				y[offset+j] = books[book].decode(b)
+				if y[offset+j] < 0 {
+					return false
+				}
+			}
+			cval >>= cbits
+		}
+		offset += f.classDimensions[class]
+	}
+	return !b.eop
+}
+
+// render turns decoded Y values into the floor curve over out.
+func (f *vorbisFloor) render(y []int, out []float64) {
+	rng := vorbisFloor1Ranges[f.multiplier-1]
+	x, final := f.xList, f.finalY
+
+	final[0], final[1] = y[0], y[1]
+	f.step2[0], f.step2[1] = true, true
+	for i := 2; i < len(x); i++ {
+		low, high := f.lowNeighbor[i], f.highNeighbor[i]
+		predicted := vorbisRenderPoint(x[low], final[low], x[high], final[high], x[i])
+		highRoom, lowRoom := rng-predicted, predicted
+		room := lowRoom * 2
+		if highRoom < lowRoom {
+			room = highRoom * 2
+		}
+
+		switch val := y[i]; {
+		case val == 0:
+			f.step2[i] = false
+			final[i] = predicted
+		case val >= room:
+			f.step2[low], f.step2[high], f.step2[i] = true, true, true
+			if highRoom > lowRoom {
+				final[i] = val - lowRoom + predicted
+			} else {
+				final[i] = predicted - val + highRoom - 1
+			}
+		case val%2 == 1:
+			f.step2[low], f.step2[high], f.step2[i] = true, true, true
+			final[i] = predicted - (val+1)/2
+		default:
+			f.step2[low], f.step2[high], f.step2[i] = true, true, true
+			final[i] = predicted + val/2
+		}
+	}
+
+	lx, ly := 0, final[0]*f.multiplier
+	hx, hy := 0, 0
+	for _, i := range f.order[1:] {
+		if !f.step2[i] {
+			continue
+		}
+		hx, hy = x[i], final[i]*f.multiplier
+		vorbisRenderLine(lx, ly, hx, hy, out)
+		lx, ly = hx, hy
+	}
+	if hx < len(out) {
+		vorbisRenderLine(hx, hy, len(out), hy, out)
+	}
+}
+
+func vorbisRenderPoint(x0, y0, x1, y1, x int) int {
+	dy := y1 - y0
+	off := vorbisAbs(dy) * (x - x0) / (x1 - x0)
+	if dy < 0 {
+		return y0 - off
+	}
+	return y0 + off
+}
+
+func vorbisRenderLine(x0, y0, x1, y1 int, out []float64) {
+	dy, adx := y1-y0, x1-x0
+	base := dy / adx
+	sy := base + 1
+	if dy < 0 {
+		sy = base - 1
+	}
+	ady := vorbisAbs(dy) - vorbisAbs(base)*adx
+
+	y, err := y0, 0
+	if x0 < len(out) {
+		out[x0] = vorbisAmplitude(y)
+	}
+	for x := x0 + 1; x < x1 && x < len(out); x++ {
+		err += ady
+		if err >= adx {
+			err -= adx
+			y += sy
+		} else {
+			y += base
+		}
+		out[x] = vorbisAmplitude(y)
+	}
+}
+
+func vorbisAmplitude(y int) float64 {
+	if y < 0 {
+		y = 0
+	} else if y > 255 {
+		y = 255
+	}
+	return vorbisInverseDB[y]
+}
+
+func vorbisAbs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
+
+func readVorbisResidue(b *vorbisBitReader, books []vorbisCodebook) (r vorbisResidue, err error) {
+	r.typ = int(b.read(16))
+	if r.typ > 2 {
+		return r, fmt.Errorf("%w: invalid Vorbis residue type %d", errMalformedMedia, r.typ)
+	}
+	r.begin = int(b.read(24))
+	r.end = int(b.read(24))
+	r.partitionSize = int(b.read(24)) + 1
+	r.classifications = int(b.read(6)) + 1
+	r.classbook = int(b.read(8))
+	if r.classbook >= len(books) || books[r.classbook].dimensions == 0 {
+		return r, fmt.Errorf("%w: invalid Vorbis residue classbook", errMalformedMedia)
+	}
+
+	cascade := make([]uint32, r.classifications)
+	for i := range cascade {
+		cascade[i] = b.read(3)
+		if b.read(1) == 1 {
+			cascade[i] |= b.read(5) << 3
+		}
+	}
+
+	r.books = make([][8]int, r.classifications)
+	for i := range r.books {
+		for j := range r.books[i] {
+			r.books[i][j] = -1
+			if cascade[i]&(1<<j) == 0 {
+				continue
+			}
+			book := int(b.read(8))
+			if book >= len(books) || books[book].values == nil {
+				return r, fmt.Errorf("%w: invalid Vorbis residue codebook", errMalformedMedia)
+			}
+			r.books[i][j] = book
+		}
+	}
+	return
+}
+
+func readVorbisMapping(b *vorbisBitReader, channels, floors, residues int) (m vorbisMapping, err error) {
+	if typ := b.read(16); typ != 0 {
+		return m, fmt.Errorf("%w: invalid Vorbis mapping type %d", errMalformedMedia, typ)
+	}
+
+	submaps := 1
+	if b.read(1) == 1 {
+		submaps = int(b.read(4)) + 1
+	}
+
+	if b.read(1) == 1 {
+		steps := int(b.read(8)) + 1
+		channelBits := vorbisIlog(channels - 1)
+		for i := 0; i < steps; i++ {
+			magnitude, angle := int(b.read(channelBits)), int(b.read(channelBits))
+			if magnitude == angle || magnitude >= channels || angle >= channels {
+				return m, fmt.Errorf("%w: invalid Vorbis channel coupling", errMalformedMedia)
+			}
+			m.magnitude = append(m.magnitude, magnitude)
+			m.angle = append(m.angle, angle)
+		}
+	}
+
+	if b.read(2) != 0 {
+		return m, fmt.Errorf("%w: invalid Vorbis mapping", errMalformedMedia)
+	}
+
+	m.mux = make([]int, channels)
+	if submaps > 1 {
+		for i := range m.mux {
+			m.mux[i] = int(b.read(4))
+			if m.mux[i] >= submaps {
+				return m, fmt.Errorf("%w: invalid Vorbis mapping mux", errMalformedMedia)
+			}
+		}
+	}
+
+	for i := 0; i < submaps; i++ {
+		b.read(8)
+		floor, residue := int(b.read(8)), int(b.read(8))
+		if floor >= floors || residue >= residues {
+			return m, fmt.Errorf("%w: invalid Vorbis submap", errMalformedMedia)
+		}
+		m.submapFloor = append(m.submapFloor, floor)
+		m.submapResidue = append(m.submapResidue, residue)
+	}
+	return
+}
+
+func newVorbisIMDCT(n int) *vorbisIMDCT {
+	m, q := n/2, n/4
+	t := &vorbisIMDCT{
+		n:       n,
+		twiddle: make([]complex128, q),
+		roots:   make([]complex128, q/2),
+		rev:     make([]int, q),
+		buf:     make([]complex128, q),
+		u:       make([]float64, m),
+	}
+	for k := range t.twiddle {
+		t.twiddle[k] = cmplx.Exp(complex(0, -math.Pi*(float64(k)+0.125)/float64(m)))
+	}
+	for k := range t.roots {
+		t.roots[k] = cmplx.Exp(complex(0, -2*math.Pi*float64(k)/float64(q)))
+	}
+	shift := bits.UintSize - bits.Len(uint(q)) + 1
+	for i := range t.rev {
+		t.rev[i] = int(bits.Reverse(uint(i)) >> shift)
+	}
+	return t
+}
+
+// transform computes y[i] = sum(x[k] * cos(2π/n * (i + 1/2 + n/4) * (k + 1/2))) through an n/4-point FFT.
+func (t *vorbisIMDCT) transform(x, y []float64) {
+	m, q := t.n/2, t.n/4
+
+	for k := 0; k < q; k++ {
+		t.buf[t.rev[k]] = complex(x[2*k], x[m-1-2*k]) * t.twiddle[k]
+	}
+	for size := 2; size <= q; size <<= 1 {
+		half, step := size/2, q/size
+		for start := 0; start < q; start += size {
+			for k := 0; k < half; k++ {
+				w := t.roots[k*step] * t.buf[start+k+half]
+				a := t.buf[start+k]
+				t.buf[start+k] = a + w
+				t.buf[start+k+half] = a - w
+			}
+		}
+	}
+	for j := 0; j < q; j++ {
+		z := t.buf[j] * t.twiddle[j]
+		t.u[2*j] = real(z)
+		t.u[m-1-2*j] = -imag(z)
+	}
+
+	for i := range y[:t.n] {
+		switch k := i + m/2; {
+		case k < m:
+			y[i] = t.u[k]
+		case k < 2*m:
+			y[i] = -t.u[2*m-1-k]
+		default:
+			y[i] = -t.u[k-2*m]
+		}
+	}
+}
+
+func newVorbisDecoder(r io.Reader) (d *vorbisDecoder, err error) {
+	d = &vorbisDecoder{packets: newOggPacketReader(r)}
+
+	packet, err := d.packets.next()
+	if err != nil {
+		return
+	}
+	if !bytes.HasPrefix(packet, []byte("\x01vorbis")) {
+		return nil, errors.New("unsupported Ogg codec")
+	}
+	err = d.readIdentification(packet)
+	if err != nil {
+		return
+	}
+
+	packet, err = d.packets.next()
+	if err != nil {
+		return
+	}
+	if !bytes.HasPrefix(packet, []byte("\x03vorbis")) {
+		return nil, fmt.Errorf("%w: missing Vorbis comment header", errMalformedMedia)
+	}
+
+	packet, err = d.packets.next()
+	if err != nil {
+		return
+	}
+	if !bytes.HasPrefix(packet, []byte("\x05vorbis")) {
+		return nil, fmt.Errorf("%w: missing Vorbis setup header", errMalformedMedia)
+	}
+	err = d.readSetup(&vorbisBitReader{data: packet[7:]})
+	if err != nil {
+		return
+	}
+
+	d.allocate()
+	return
+}
+
+func (d *vorbisDecoder) readIdentification(packet []byte) error {
+	if len(packet) < 30 || binary.LittleEndian.Uint32(packet[7:]) != 0 || packet[29]&1 == 0 {
+		return fmt.Errorf("%w: invalid Vorbis identification header", errMalformedMedia)
+	}
+
+	d.channels = int(packet[11])
+	d.sampleRate = int(binary.LittleEndian.Uint32(packet[12:]))
+	b0, b1 := int(packet[28]&0x0f), int(packet[28]>>4)
+	if d.channels == 0 || d.sampleRate == 0 || b0 < 6 || b1 > 13 || b0 > b1 {
+		return fmt.Errorf("%w: invalid Vorbis identification header", errMalformedMedia)
+	}
+	d.blocksize = [2]int{1 << b0, 1 << b1}
+	return nil
+}
+
+func (d *vorbisDecoder) readSetup(b *vorbisBitReader) (err error) {
+	budget := vorbisMaxSetupValues
+
+	d.codebooks = make([]vorbisCodebook, b.read(8)+1)
+	for i := range d.codebooks {
+		d.codebooks[i], err = readVorbisCodebook(b, &budget)
+		if err != nil {
+			return
+		}
+	}
+
+	for i := b.read(6) + 1; i > 0; i-- {
+		if b.read(16) != 0 {
+			return fmt.Errorf("%w: invalid Vorbis time domain transform", errMalformedMedia)
+		}
+	}
+
+	d.floors = make([]vorbisFloor, b.read(6)+1)
+	for i := range d.floors {
+		d.floors[i], err = readVorbisFloor(b, d.codebooks)
+		if err != nil {
+			return
+		}
+	}
+
+	d.residues = make([]vorbisResidue, b.read(6)+1)
+	for i := range d.residues {
+		d.residues[i], err = readVorbisResidue(b, d.codebooks)
+		if err != nil {
+			return
+		}
+	}
+
+	d.mappings = make([]vorbisMapping, b.read(6)+1)
+	for i := range d.mappings {
+		d.mappings[i], err = readVorbisMapping(b, d.channels, len(d.floors), len(d.residues))
+		if err != nil {
+			return
+		}
+	}
+
+	d.modes = make([]vorbisMode, b.read(6)+1)
+	for i := range d.modes {
+		d.modes[i].long = b.read(1) == 1
+		windowType, transformType := b.read(16), b.read(16)
+		d.modes[i].mapping = int(b.read(8))
+		if windowType != 0 || transformType != 0 || d.modes[i].mapping >= len(d.mappings) {
+			return fmt.Errorf("%w: invalid Vorbis mode", errMalformedMedia)
+		}
+	}
+
+	if b.read(1) != 1 || b.eop {
+		return fmt.Errorf("%w: invalid Vorbis setup header", errMalformedMedia)
+	}
+	return nil
+}
+
+func (d *vorbisDecoder) allocate() {
+	values := 0
+	for _, f := range d.floors {
+		if len(f.xList) > values {
+			values = len(f.xList)
+		}
+	}
+
+	long := d.blocksize[1]
+	d.floorY = make([][]int, d.channels)
+	d.floorUsed = make([]bool, d.channels)
+	d.skip = make([]bool, d.channels)
+	d.spectrum = make([][]float64, d.channels)
+	d.block = make([][]float64, d.channels)
+	d.prev = make([][]float64, d.channels)
+	d.out = make([][]float64, d.channels)
+	for ch := 0; ch < d.channels; ch++ {
+		d.floorY[ch] = make([]int, values)
+		d.spectrum[ch] = make([]float64, long/2)
+		d.block[ch] = make([]float64, long)
+		d.prev[ch] = make([]float64, long)
+		d.out[ch] = make([]float64, long/2)
+	}
+	d.curve = make([]float64, long/2)
+	d.interleaved = make([]float64, d.channels*long/2)
+
+	for i, n := range d.blocksize {
+		d.imdct[i] = newVorbisIMDCT(n)
+		d.slopes[i] = make([]float64, n/2)
+		for j := range d.slopes[i] {
+			s := math.Sin((float64(j) + 0.5) / float64(n/2) * math.Pi / 2)
+			d.slopes[i][j] = math.Sin(math.Pi / 2 * s * s)
+		}
+	}
+}
+
+// decode returns the next run of PCM samples per channel, or io.EOF at the end of the stream.
+func (d *vorbisDecoder) decode() ([][]float64, error) {
+	for {
+		packet, err := d.packets.next()
+		if err != nil {
+			return nil, err
+		}
+		pcm := d.decodePacket(packet)
+		if len(pcm) > 0 && len(pcm[0]) > 0 {
+			return pcm, nil
+		}
+	}
+}
+
+// decodePacket decodes one audio packet. Packets that can't be decoded are skipped, as the spec allows.
+func (d *vorbisDecoder) decodePacket(packet []byte) [][]float64 {
+	b := &vorbisBitReader{data: packet}
+	if b.read(1) != 0 {
+		return nil
+	}
+	modeNumber := int(b.read(vorbisIlog(len(d.modes) - 1)))
+	if b.eop || modeNumber >= len(d.modes) {
+		return nil
+	}
+	mode := d.modes[modeNumber]
+	m := &d.mappings[mode.mapping]
+
+	blockflag, prevLong, nextLong := 0, false, false
+	if mode.long {
+		blockflag = 1
+		prevLong, nextLong = b.read(1) == 1, b.read(1) == 1
+	}
+	if b.eop {
+		return nil
+	}
+	n := d.blocksize[blockflag]
+	n2 := n / 2
+
+	for ch := 0; ch < d.channels; ch++ {
+		floor := &d.floors[m.submapFloor[m.mux[ch]]]
+		d.floorUsed[ch] = floor.decode(b, d.codebooks, d.floorY[ch])
+		d.skip[ch] = !d.floorUsed[ch]
+		for i := range d.spectrum[ch][:n2] {
+			d.spectrum[ch][i] = 0
+		}
+	}
+	for i := range m.magnitude {
+		if !d.skip[m.magnitude[i]] || !d.skip[m.angle[i]] {
+			d.skip[m.magnitude[i]], d.skip[m.angle[i]] = false, false
+		}
+	}
+
+	for s, residue := range m.submapResidue {
+		d.vectors, d.vectorSkip = d.vectors[:0], d.vectorSkip[:0]
+		for ch, mux := range m.mux {
+			if mux == s {
+				d.vectors = append(d.vectors, d.spectrum[ch][:n2])
+				d.vectorSkip = append(d.vectorSkip, d.skip[ch])
+			}
+		}
+		d.decodeResidue(b, &d.residues[residue], d.vectors, d.vectorSkip)
+	}
+
+	for i := len(m.magnitude) - 1; i >= 0; i-- {
+		magnitude, angle := d.spectrum[m.magnitude[i]][:n2], d.spectrum[m.angle[i]][:n2]
+		for j, mv := range magnitude {
+			av := angle[j]
+			switch {
+			case mv > 0 && av > 0:
+				angle[j] = mv - av
+			case mv > 0:
+				angle[j], magnitude[j] = mv, mv+av
+			case av > 0:
+				angle[j] = mv + av
+			default:
+				angle[j], magnitude[j] = mv, mv-av
+			}
+		}
+	}
+
+	for ch := 0; ch < d.channels; ch++ {
+		block := d.block[ch][:n]
+		if !d.floorUsed[ch] {
+			for i := range block {
+				block[i] = 0
+			}
+			continue
+		}
+
+		curve, spectrum := d.curve[:n2], d.spectrum[ch][:n2]
+		d.floors[m.submapFloor[m.mux[ch]]].render(d.floorY[ch], curve)
+		for i := range spectrum {
+			spectrum[i] *= curve[i]
+		}
+		d.imdct[blockflag].transform(spectrum, block)
+		d.applyWindow(block, mode.long, prevLong, nextLong)
+	}
+
+	if !d.started {
+		d.started = true
+		d.prev, d.block, d.prevN = d.block, d.prev, n
+		return nil
+	}
+
+	// Output runs from the centre of the previous window to the centre of this one.
+	count := d.prevN/4 + n/4
+	offset := n/4 - d.prevN/4
+	for ch := 0; ch < d.channels; ch++ {
+		out, prev, block := d.out[ch][:count], d.prev[ch][:d.prevN], d.block[ch][:n]
+		for j := range out {
+			v := 0.0
+			if t := d.prevN/2 + j; t < d.prevN {
+				v = prev[t]
+			}
+			if k := j + offset; k >= 0 && k < n {
+				v += block[k]
+			}
+			out[j] = v
+		}
+	}
+	d.prev, d.block, d.prevN = d.block, d.prev, n
+
+	pcm := make([][]float64, d.channels)
+	for ch := range pcm {
+		pcm[ch] = d.out[ch][:count]
+	}
+	return pcm
+}
+
+func (d *vorbisDecoder) applyWindow(y []float64, long, prevLong, nextLong bool) {
+	n := len(y)
+	short := d.blocksize[0]
+
+	leftStart, left := 0, d.slopes[0]
+	if long {
+		left = d.slopes[1]
+		if !prevLong {
+			leftStart, left = n/4-short/4, d.slopes[0]
+		}
+	}
+	rightStart, right := n/2, d.slopes[0]
+	if long {
+		right = d.slopes[1]
+		if !nextLong {
+			rightStart, right = n*3/4-short/4, d.slopes[0]
+		}
+	}
+
+	for i := 0; i < leftStart; i++ {
+		y[i] = 0
+	}
+	for i, w := range left {
+		y[leftStart+i] *= w
+	}
+	for i := range right {
+		y[rightStart+i] *= right[len(right)-1-i]
+	}
+	for i := rightStart + len(right); i < n; i++ {
+		y[i] = 0
+	}
+}
+
+func (d *vorbisDecoder) decodeResidue(b *vorbisBitReader, r *vorbisResidue, vectors [][]float64, skip []bool) {
+	if len(vectors) == 0 {
+		return
+	}
+	if r.typ != 2 {
+		d.decodeResiduePartitions(b, r, vectors, skip, r.typ)
+		return
+	}
+
+	decode := false
+	for _, s := range skip {
+		decode = decode || !s
+	}
+	if !decode {
+		return
+	}
+
+	n2 := len(vectors[0])
+	v := d.interleaved[:n2*len(vectors)]
+	for i := range v {
+		v[i] = 0
+	}
+	d.decodeResiduePartitions(b, r, [][]float64{v}, []bool{false}, 1)
+	for i := 0; i < n2; i++ {
+		for j, vector := range vectors {
+			vector[i] = v[i*len(vectors)+j]
+		}
+	}
+}
+
+func (d *vorbisDecoder) decodeResiduePartitions(
+	b *vorbisBitReader,
+	r *vorbisResidue,
+	vectors [][]float64,
+	skip []bool,
+	format int,
+) {
+	size := len(vectors[0])
+	begin, end := r.begin, r.end
+	if begin > size {
+		begin = size
+	}
+	if end > size {
+		end = size
+	}
+	if end <= begin {
+		return
+	}
+
+	classbook := &d.codebooks[r.classbook]
+	perWord := classbook.dimensions
+	partitions := (end - begin) / r.partitionSize
+	if partitions == 0 {
+		return
+	}
+
+	for len(d.classes) < len(vectors) {
+		d.classes = append(d.classes, nil)
+	}
+	for j := range vectors {
+		if cap(d.classes[j]) < partitions+perWord {
+			d.classes[j] = make([]int, partitions+perWord)
+		}
+		d.classes[j] = d.classes[j][:partitions+perWord]
+	}
+
+	for pass := 0; pass < 8; pass++ {
+		for p := 0; p < partitions; {
+			if pass == 0 {
+				for j := range vectors {
+					if skip[j] {
+						continue
+					}
+					word := classbook.decode(b)
+					if word < 0 {
+						return
+					}
+					for i := perWord - 1; i >= 0; i-- {
+						d.classes[j][p+i] = word % r.classifications
+						word /= r.classifications
+					}
+				}
+			}
+
+			for i := 0; i < perWord && p < partitions; i, p = i+1, p+1 {
+				for j, vector := range vectors {
+					if skip[j] {
+						continue
+					}
+					book := r.books[d.classes[j][p]][pass]
+					if book < 0 {
+						continue
+					}
+					offset := begin + p*r.partitionSize
+					if !decodeVorbisPartition(b, &d.codebooks[book], vector[offset:offset+r.partitionSize], format) {
+						return
+					}
+				}
+			}
+		}
+	}
+}
+
+func decodeVorbisPartition(b *vorbisBitReader, c *vorbisCodebook, v []float64, format int) bool {
+	if format == 0 {
+		step := len(v) / c.dimensions
+		for i := 0; i < step; i++ {
+			entry := c.decode(b)
+			if entry < 0 {
+				return false
+			}
+			for j, x := range c.vector(entry) {
+				v[i+j*step] += float64(x)
+			}
+		}
+		return true
+	}
+
+	for i := 0; i < len(v); {
+		entry := c.decode(b)
+		if entry < 0 {
+			return false
+		}
+		for _, x := range c.vector(entry) {
+			if i == len(v) {
+				break
+			}
+			v[i] += float64(x)
+			i++
+		}
+	}
+	return true
+}
@@ -0,0 +1,207 @@
+package adapter
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"github.com/hajimehoshi/go-mp3"
+	"io"
+	"math"
+	"news-app-api/internal/entity"
+)
+
+const waveformBucketsPerSecond = 100
+
+var waveformResolutions = []int{100, 500, 2000}
+
+type (
+	WaveformGenerator interface {
+		Generate(contentType string, r io.Reader) (entity.Waveform, error)
+	}
+
+	waveformGenerator struct{}
+
+	peakAccumulator struct {
+		bucketSize int
+		count      int
+		peak       float64
+		peaks      []float64
+	}
+)
+
+func NewWaveformGenerator() WaveformGenerator {
+	return &waveformGenerator{}
+}
+
+func (g *waveformGenerator) Generate(contentType string, r io.Reader) (w entity.Waveform, err error) {
+	defer func() {
+		if err != nil {
+			err = fmt.Errorf("WaveformGenerator - Generate: %w", err)
+		}
+	}()
+
+	var peaks []float64
+	switch contentType {
+	case "audio/wav":
+		peaks, err = wavPeaks(bufio.NewReader(r))
+	case "audio/mpeg":
+		peaks, err = mp3Peaks(r)
+	case "audio/ogg":
+		peaks, err = oggPeaks(r)
+	default:
+		return w, fmt.Errorf("waveform isn't supported for %s", contentType)
+	}
+	if err != nil {
+		return
+	}
+	if len(peaks) == 0 {
+		return w, errors.New("audio has no samples")
+	}
+
+	w.Duration = float64(len(peaks)) / float64(waveformBucketsPerSecond)
+	for _, resolution := range waveformResolutions {
+		w.Resolutions = append(w.Resolutions, entity.WaveformResolution{
+			Resolution: resolution,
+			Peaks:      downsamplePeaks(peaks, resolution),
+		})
+	}
+	return
+}
+
+func newPeakAccumulator(sampleRate int) *peakAccumulator {
+	bucketSize := sampleRate / waveformBucketsPerSecond
+	if bucketSize < 1 {
+		bucketSize = 1
+	}
+	return &peakAccumulator{bucketSize: bucketSize}
+}
+
+func (a *peakAccumulator) add(v float64) {
+	v = math.Abs(v)
+	if v > a.peak {
+		a.peak = v
+	}
+}
+
+func (a *peakAccumulator) endFrame() {
+	a.count++
+	if a.count == a.bucketSize {
+		a.flush()
+	}
+}
+
+func (a *peakAccumulator) flush() []float64 {
+	if a.count > 0 {
+		a.peaks = append(a.peaks, math.Min(a.peak, 1))
+		a.count, a.peak = 0, 0
+	}
+	return a.peaks
+}
+
+func downsamplePeaks(peaks []float64, points int) []float64 {
+	if points > len(peaks) {
+		points = len(peaks)
+	}
+
+	res := make([]float64, points)
+	for i := range res {
+		from, to := i*len(peaks)/points, (i+1)*len(peaks)/points
+		peak := 0.0
+		for _, v := range peaks[from:to] {
+			peak = math.Max(peak, v)
+		}
+		res[i] = math.Round(peak*1000) / 1000
+	}
+	return res
+}
+
+func wavPeaks(r io.Reader) ([]float64, error) {
+	f, size, err := readWAVHeader(r)
+	if err != nil {
+		return nil, err
+	}
+	r = io.LimitReader(r, size)
+
+	var sample func([]byte) float64
+	switch {
+	case f.format == 1 && f.bitsPerSample == 8:
+		sample = func(b []byte) float64 { return (float64(b[0]) - 128) / 128 }
+	case f.format == 1 && f.bitsPerSample == 16:
+		sample = func(b []byte) float64 { return float64(int16(binary.LittleEndian.Uint16(b))) / (1 << 15) }
+	case f.format == 1 && f.bitsPerSample == 24:
+		sample = func(b []byte) float64 {
+			return float64(int32(uint32(b[0])<<8|uint32(b[1])<<16|uint32(b[2])<<24)>>8) / (1 << 23)
+		}
+	case f.format == 1 && f.bitsPerSample == 32:
+		sample = func(b []byte) float64 { return float64(int32(binary.LittleEndian.Uint32(b))) / (1 << 31) }
+	case f.format == 3 && f.bitsPerSample == 32:
+		sample = func(b []byte) float64 { return float64(math.Float32frombits(binary.LittleEndian.Uint32(b))) }
+	default:
+		return nil, fmt.Errorf("unsupported WAV sample format %d/%d", f.format, f.bitsPerSample)
+	}
+
+	bytesPerSample := f.bitsPerSample / 8
+	frame := make([]byte, bytesPerSample*f.channels)
+	acc := newPeakAccumulator(f.sampleRate)
+	for {
+		_, err = io.ReadFull(r, frame)
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			return acc.flush(), nil
+		} else if err != nil {
+			return nil, err
+		}
+
+		for c := 0; c < f.channels; c++ {
+			acc.add(sample(frame[c*bytesPerSample:]))
+		}
+		acc.endFrame()
+	}
+}
+
+func mp3Peaks(r io.Reader) ([]float64, error) {
+	d, err := mp3.NewDecoder(struct{ io.Reader }{r})
+	if err != nil {
+		return nil, err
+	}
+
+	acc := newPeakAccumulator(d.SampleRate())
+	buf := make([]byte, 4*4096)
+	for {
+		n, err := io.ReadFull(d, buf)
+		for i := 0; i+4 <= n; i += 4 {
+			acc.add(float64(int16(binary.LittleEndian.Uint16(buf[i:]))) / (1 << 15))
+			acc.add(float64(int16(binary.LittleEndian.Uint16(buf[i+2:]))) / (1 << 15))
+			acc.endFrame()
+		}
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			return acc.flush(), nil
+		} else if err != nil {
+			return nil, err
+		}
+	}
+}
+
+func oggPeaks(r io.Reader) ([]float64, error) {
+	d, err := newVorbisDecoder(r)
+	if err != nil {
+		return nil, err
+	}
+
+	acc := newPeakAccumulator(d.sampleRate)
+	for {
+		pcm, err := d.decode()
+		if err == io.EOF {
+			return acc.flush(), nil
+		} else if err != nil {
+			return nil, err
+		}
+
+		for i := range pcm[0] {
+			for _, samples := range pcm {
+				acc.add(samples[i])
+			}
+			acc.endFrame()
+		}
+	}
+}
@@ -0,0 +1,265 @@
+package adapter
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestVorbisIMDCT(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for _, n := range []int{64, 256, 2048} {
+		x := make([]float64, n/2)
+		for i := range x {
+			x[i] = rnd.Float64()*2 - 1
+		}
+
+		got := make([]float64, n)
+		newVorbisIMDCT(n).transform(x, got)
+
+		for i := range got {
+			want := 0.0
+			for k, v := range x {
+				want += v * math.Cos(2*math.Pi/float64(n)*(float64(i)+0.5+float64(n)/4)*(float64(k)+0.5))
+			}
+			if math.Abs(got[i]-want) > 1e-9 {
+				t.Fatalf("n=%d: y[%d] = %v, want %v", n, i, got[i], want)
+			}
+		}
+	}
+}
+
+func TestWaveformGenerator(t *testing.T) {
+	const rate = 8000
+	sine := make([]float64, rate)
+	for i := range sine {
+		sine[i] = 0.5 * math.Sin(2*math.Pi*440*float64(i)/rate)
+	}
+
+	tests := []struct {
+		name        string
+		contentType string
+		data        []byte
+	}{
+		{"wav", "audio/wav", testPCMWAV(sine, rate)},
+		{"ogg vorbis", "audio/ogg", testOggVorbis(sine, rate)},
+	}
+
+	g := NewWaveformGenerator()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w, err := g.Generate(tt.contentType, bytes.NewReader(tt.data))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if math.Abs(w.Duration-1) > 0.02 {
+				t.Errorf("duration = %v, want 1", w.Duration)
+			}
+			if len(w.Resolutions) != len(waveformResolutions) {
+				t.Fatalf("resolutions = %d, want %d", len(w.Resolutions), len(waveformResolutions))
+			}
+
+			peaks := w.Resolutions[0].Peaks
+			if len(peaks) != 100 {
+				t.Fatalf("peaks = %d, want 100", len(peaks))
+			}
+			for i, p := range peaks[1:99] {
+				if math.Abs(p-0.5) > 0.03 {
+					t.Errorf("peak %d = %v, want 0.5", i+1, p)
+				}
+			}
+		})
+	}
+}
+
+func TestWaveformGeneratorMalformed(t *testing.T) {
+	oversizedFmt := testPCMWAV(make([]float64, 100), 8000)
+	binary.LittleEndian.PutUint32(oversizedFmt[16:], math.MaxUint32)
+
+	vorbis := testOggVorbis(make([]float64, 1000), 8000)
+
+	tests := []struct {
+		name        string
+		contentType string
+		data        []byte
+	}{
+		{"wav oversized fmt chunk", "audio/wav", oversizedFmt},
+		{"wav truncated", "audio/wav", testPCMWAV(nil, 8000)[:30]},
+		{"ogg opus", "audio/ogg", testOggOpus(48000)},
+		{"ogg truncated setup", "audio/ogg", vorbis[:200]},
+	}
+
+	g := NewWaveformGenerator()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := g.Generate(tt.contentType, bytes.NewReader(tt.data))
+			if err == nil {
+				t.Fatal("expected an error")
+			}
+		})
+	}
+
+	for n := 0; n < len(vorbis); n++ {
+		func() {
+			defer func() {
+				if r := recover(); r != nil {
+					t.Fatalf("ogg truncated to %d bytes panicked: %v", n, r)
+				}
+			}()
+			_, _ = g.Generate("audio/ogg", bytes.NewReader(vorbis[:n]))
+		}()
+	}
+}
+
+func testPCMWAV(samples []float64, rate int) []byte {
+	data := make([]byte, 2*len(samples))
+	for i, s := range samples {
+		binary.LittleEndian.PutUint16(data[2*i:], uint16(int16(math.Round(s*math.MaxInt16))))
+	}
+
+	b := bytes.NewBuffer(testWAV(1, rate, 16, 0))
+	b.Truncate(b.Len() - 4)
+	writeLE(b, uint32(len(data)))
+	b.Write(data)
+	return b.Bytes()
+}
+
+type testBitWriter struct {
+	buf []byte
+	n   int
+}
+
+func (w *testBitWriter) write(v uint32, bits int) {
+	for i := 0; i < bits; i++ {
+		if w.n%8 == 0 {
+			w.buf = append(w.buf, 0)
+		}
+		w.buf[len(w.buf)-1] |= byte(v>>i&1) << (w.n % 8)
+		w.n++
+	}
+}
+
+func (w *testBitWriter) writeCode(code uint32, length int) {
+	for i := length - 1; i >= 0; i-- {
+		w.write(code>>i&1, 1)
+	}
+}
+
+// testOggVorbis encodes mono audio as Vorbis with 256-sample blocks, a flat floor and 8-bit residue values.
+func testOggVorbis(samples []float64, rate int) []byte {
+	const (
+		n      = 256
+		floorY = 171
+	)
+
+	id := []byte("\x01vorbis")
+	id = binary.LittleEndian.AppendUint32(id, 0)
+	id = append(id, 1)
+	id = binary.LittleEndian.AppendUint32(id, uint32(rate))
+	id = append(id, make([]byte, 12)...)
+	id = append(id, 8|8<<4, 1)
+
+	comment := append([]byte("\x03vorbis"), make([]byte, 8)...)
+	comment = append(comment, 1)
+
+	setup := &testBitWriter{buf: []byte("\x05vorbis"), n: 56}
+	setup.write(1, 8)
+	setup.write(0x564342, 24)
+	setup.write(1, 16)
+	setup.write(2, 24)
+	setup.write(0, 2)
+	setup.write(0, 10)
+	setup.write(0, 4)
+	setup.write(0x564342, 24)
+	setup.write(1, 16)
+	setup.write(256, 24)
+	setup.write(0, 2)
+	for i := 0; i < 256; i++ {
+		setup.write(7, 5)
+	}
+	setup.write(1, 4)
+	setup.write(0x80000000|788<<21|128, 32)
+	setup.write(788<<21|1, 32)
+	setup.write(7, 4)
+	setup.write(0, 1)
+	for i := 0; i < 256; i++ {
+		setup.write(uint32(i), 8)
+	}
+	setup.write(0, 6+16)
+	setup.write(0, 6)
+	setup.write(1, 16)
+	setup.write(0, 5+2)
+	setup.write(7, 4)
+	setup.write(0, 6)
+	setup.write(1, 16)
+	setup.write(0, 24)
+	setup.write(n/2, 24)
+	setup.write(31, 24)
+	setup.write(0, 6+8)
+	setup.write(1, 3+1)
+	setup.write(1, 8)
+	setup.write(0, 6+16+1+1+2+8+8+8)
+	setup.write(0, 6+1+16+16+8)
+	setup.write(1, 1)
+
+	packets := [][]byte{id, comment, setup.buf}
+
+	window := make([]float64, n)
+	for i := 0; i < n/2; i++ {
+		s := math.Sin((float64(i) + 0.5) / (n / 2) * math.Pi / 2)
+		window[i] = math.Sin(math.Pi / 2 * s * s)
+		window[n-1-i] = window[i]
+	}
+	amplitude := vorbisInverseDB[floorY]
+	blocks := (len(samples)+n/2-1)/(n/2) + 1
+	for block := 0; block < blocks; block++ {
+		frame := make([]float64, n)
+		for i := range frame {
+			if t := (block-1)*n/2 + i; t >= 0 && t < len(samples) {
+				frame[i] = samples[t] * window[i]
+			}
+		}
+
+		w := &testBitWriter{}
+		w.write(0, 1)
+		w.write(1, 1)
+		w.write(floorY, 8)
+		w.write(floorY, 8)
+		for k := 0; k < n/2; k++ {
+			if k%32 == 0 {
+				w.writeCode(0, 1)
+			}
+			x := 0.0
+			for i, v := range frame {
+				x += v * math.Cos(2*math.Pi/n*(float64(i)+0.5+n/4)*(float64(k)+0.5))
+			}
+			q := math.Max(-128, math.Min(127, math.Round(x*4/n/amplitude)))
+			w.writeCode(uint32(q+128), 8)
+		}
+		packets = append(packets, w.buf)
+	}
+
+	var b bytes.Buffer
+	for i, packet := range packets {
+		headerType := byte(0)
+		if i == 0 {
+			headerType = 2
+		}
+		lacing := bytes.Repeat([]byte{255}, len(packet)/255)
+		lacing = append(lacing, byte(len(packet)%255))
+
+		b.WriteString("OggS")
+		b.WriteByte(0)
+		b.WriteByte(headerType)
+		writeLE(&b, uint64(0))
+		writeLE(&b, uint32(1))
+		writeLE(&b, uint32(i))
+		writeLE(&b, uint32(0))
+		b.WriteByte(byte(len(lacing)))
+		b.Write(lacing)
+		b.Write(packet)
+	}
+	return b.Bytes()
+}
@@ -0,0 +1,364 @@
+package adapter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"gopkg.in/guregu/null.v3"
+	"news-app-api/internal/dto"
+	"news-app-api/internal/entity"
+	"time"
+)
+
+type (
+	WebhookRepository interface {
+		CreateWebhook(ctx context.Context, p dto.CreateWebhookParams, secret string) (entity.Webhook, error)
+		GetWebhookList(ctx context.Context, mediaID int64) ([]entity.Webhook, error)
+		GetWebhook(ctx context.Context, webhookID, mediaID int64) (entity.Webhook, error)
+		UpdateWebhook(ctx context.Context, p dto.UpdateWebhookParams) (entity.Webhook, error)
+		RotateWebhookSecret(ctx context.Context, webhookID, mediaID int64, secret string) (entity.Webhook, error)
+		DeleteWebhook(ctx context.Context, webhookID, mediaID int64) error
+		EnqueueWebhookDeliveries(ctx context.Context, mediaID int64, eventType, payload string) error
+		GetWebhookDeliveryList(ctx context.Context, p dto.GetWebhookDeliveryListParams) ([]entity.WebhookDelivery, error)
+		CountWebhookDeliveries(ctx context.Context, p dto.GetWebhookDeliveryListParams) (int64, error)
+		GetWebhookDelivery(ctx context.Context, p dto.WebhookDeliveryParams) (entity.WebhookDelivery, error)
+		ReplayWebhookDelivery(ctx context.Context, deliveryID int64) (entity.WebhookDelivery, error)
+		ClaimDueWebhookDeliveries(
+			ctx context.Context,
+			limit int64,
+			lease time.Duration,
+		) ([]entity.WebhookDeliveryJob, error)
+		MarkWebhookDeliveryAttempt(
+			ctx context.Context,
+			deliveryID int64,
+			status string,
+			responseStatus null.Int,
+			responseBody, deliveryErr null.String,
+			nextAttemptAt null.Int,
+		) error
+	}
+
+	webhookRepository struct {
+		db *pgxpool.Pool
+	}
+)
+
+func NewWebhookRepository(db *pgxpool.Pool) WebhookRepository {
+	return &webhookRepository{db}
+}
+
+func (r *webhookRepository) CreateWebhook(
+	ctx context.Context,
+	p dto.CreateWebhookParams,
+	secret string,
+) (w entity.Webhook, err error) {
+	defer func() {
+		if err != nil {
+			var appErr *dto.AppError
+			if !errors.As(err, &appErr) {
+				err = fmt.Errorf("WebhookRepository - CreateWebhook: %w", err)
+			}
+		}
+	}()
+	row := r.db.QueryRow(ctx, queryCreateWebhook, p.MediaID, p.URL, secret, p.EventTypes)
+	return scanWebhook(row)
+}
+
+func (r *webhookRepository) GetWebhookList(ctx context.Context, mediaID int64) (list []entity.Webhook, err error) {
+	defer func() {
+		if err != nil {
+			var appErr *dto.AppError
+			if !errors.As(err, &appErr) {
+				err = fmt.Errorf("WebhookRepository - GetWebhookList: %w", err)
+			}
+		}
+	}()
+	list = make([]entity.Webhook, 0)
+	rows, err := r.db.Query(ctx, queryGetWebhookList, mediaID)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var item entity.Webhook
+		item, err = scanWebhook(rows)
+		if err != nil {
+			return
+		}
+		list = append(list, item)
+	}
+	return
+}
+
+func (r *webhookRepository) GetWebhook(ctx context.Context, webhookID, mediaID int64) (w entity.Webhook, err error) {
+	defer func() {
+		if err != nil {
+			var appErr *dto.AppError
+			if !errors.As(err, &appErr) {
+				err = fmt.Errorf("WebhookRepository - GetWebhook: %w", err)
+			}
+		}
+	}()
+	row := r.db.QueryRow(ctx, queryGetWebhook, webhookID, mediaID)
+	return scanWebhook(row)
+}
+
+func (r *webhookRepository) UpdateWebhook(ctx context.Context, p dto.UpdateWebhookParams) (w entity.Webhook, err error) {
+	defer func() {
+		if err != nil {
+			var appErr *dto.AppError
+			if !errors.As(err, &appErr) {
+				err = fmt.Errorf("WebhookRepository - UpdateWebhook: %w", err)
+			}
+		}
+	}()
+	row := r.db.QueryRow(ctx, queryUpdateWebhook, p.WebhookID, p.MediaID, p.URL, p.EventTypes, p.IsActive)
+	return scanWebhook(row)
+}
+
+func (r *webhookRepository) RotateWebhookSecret(
+	ctx context.Context,
+	webhookID, mediaID int64,
+	secret string,
+) (w entity.Webhook, err error) {
+	defer func() {
+		if err != nil {
+			var appErr *dto.AppError
+			if !errors.As(err, &appErr) {
+				err = fmt.Errorf("WebhookRepository - RotateWebhookSecret: %w", err)
+			}
+		}
+	}()
+	row := r.db.QueryRow(ctx, queryRotateWebhookSecret, webhookID, mediaID, secret)
+	return scanWebhook(row)
+}
+
+func (r *webhookRepository) DeleteWebhook(ctx context.Context, webhookID, mediaID int64) (err error) {
+	defer func() {
+		if err != nil {
+			var appErr *dto.AppError
+			if !errors.As(err, &appErr) {
+				err = fmt.Errorf("WebhookRepository - DeleteWebhook: %w", err)
+			}
+		}
+	}()
+	tag, err := r.db.Exec(ctx, queryDeleteWebhook, webhookID, mediaID)
+	if err != nil {
+		return
+	}
+	if tag.RowsAffected() == 0 {
+		err = &dto.AppError{
+			Message: "Вебхук не найден",
+			Code:    dto.ErrCodeNotFound,
+		}
+	}
+	return
+}
+
+func (r *webhookRepository) EnqueueWebhookDeliveries(
+	ctx context.Context,
+	mediaID int64,
+	eventType, payload string,
+) (err error) {
+	defer func() {
+		if err != nil {
+			var appErr *dto.AppError
+			if !errors.As(err, &appErr) {
+				err = fmt.Errorf("WebhookRepository - EnqueueWebhookDeliveries: %w", err)
+			}
+		}
+	}()
+	_, err = r.db.Exec(ctx, queryEnqueueWebhookDeliveries, mediaID, eventType, payload)
+	return
+}
+
+func (r *webhookRepository) GetWebhookDeliveryList(
+	ctx context.Context,
+	p dto.GetWebhookDeliveryListParams,
+) (list []entity.WebhookDelivery, err error) {
+	defer func() {
+		if err != nil {
+			var appErr *dto.AppError
+			if !errors.As(err, &appErr) {
+				err = fmt.Errorf("WebhookRepository - GetWebhookDeliveryList: %w", err)
+			}
+		}
+	}()
+	list = make([]entity.WebhookDelivery, 0, p.Limit.Int64)
+	rows, err := r.db.Query(ctx, queryGetWebhookDeliveryList, p.WebhookID, p.MediaID, p.Status, p.Limit, p.Offset)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var item entity.WebhookDelivery
+		item, err = scanWebhookDelivery(rows)
+		if err != nil {
+			return
+		}
+		list = append(list, item)
+	}
+	return
+}
+
+func (r *webhookRepository) CountWebhookDeliveries(
+	ctx context.Context,
+	p dto.GetWebhookDeliveryListParams,
+) (count int64, err error) {
+	defer func() {
+		if err != nil {
+			var appErr *dto.AppError
+			if !errors.As(err, &appErr) {
+				err = fmt.Errorf("WebhookRepository - CountWebhookDeliveries: %w", err)
+			}
+		}
+	}()
+	row := r.db.QueryRow(ctx, queryCountWebhookDeliveries, p.WebhookID, p.MediaID, p.Status)
+	err = row.Scan(&count)
+	return
+}
+
+func (r *webhookRepository) GetWebhookDelivery(
+	ctx context.Context,
+	p dto.WebhookDeliveryParams,
+) (d entity.WebhookDelivery, err error) {
+	defer func() {
+		if err != nil {
+			var appErr *dto.AppError
+			if !errors.As(err, &appErr) {
+				err = fmt.Errorf("WebhookRepository - GetWebhookDelivery: %w", err)
+			}
+		}
+	}()
+	row := r.db.QueryRow(ctx, queryGetWebhookDelivery, p.DeliveryID, p.WebhookID, p.MediaID)
+	d, err = scanWebhookDelivery(row)
+	if err == pgx.ErrNoRows {
+		err = &dto.AppError{
+			Message: "Доставка не найдена",
+			Code:    dto.ErrCodeNotFound,
+		}
+	}
+	return
+}
+
+func (r *webhookRepository) ReplayWebhookDelivery(
+	ctx context.Context,
+	deliveryID int64,
+) (d entity.WebhookDelivery, err error) {
+	defer func() {
+		if err != nil {
+			var appErr *dto.AppError
+			if !errors.As(err, &appErr) {
+				err = fmt.Errorf("WebhookRepository - ReplayWebhookDelivery: %w", err)
+			}
+		}
+	}()
+	row := r.db.QueryRow(ctx, queryReplayWebhookDelivery, deliveryID)
+	return scanWebhookDelivery(row)
+}
+
+func (r *webhookRepository) ClaimDueWebhookDeliveries(
+	ctx context.Context,
+	limit int64,
+	lease time.Duration,
+) (list []entity.WebhookDeliveryJob, err error) {
+	defer func() {
+		if err != nil {
+			var appErr *dto.AppError
+			if !errors.As(err, &appErr) {
+				err = fmt.Errorf("WebhookRepository - ClaimDueWebhookDeliveries: %w", err)
+			}
+		}
+	}()
+	list = make([]entity.WebhookDeliveryJob, 0, limit)
+	rows, err := r.db.Query(ctx, queryClaimDueWebhookDeliveries, limit, int64(lease.Seconds()))
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+	for rows.Next() {
+		item := entity.WebhookDeliveryJob{}
+		err = rows.Scan(
+			&item.DeliveryID,
+			&item.EventType,
+			&item.Payload,
+			&item.Attempts,
+			&item.URL,
+			&item.Secret,
+		)
+		if err != nil {
+			return
+		}
+		list = append(list, item)
+	}
+	return
+}
+
+func (r *webhookRepository) MarkWebhookDeliveryAttempt(
+	ctx context.Context,
+	deliveryID int64,
+	status string,
+	responseStatus null.Int,
+	responseBody, deliveryErr null.String,
+	nextAttemptAt null.Int,
+) (err error) {
+	defer func() {
+		if err != nil {
+			var appErr *dto.AppError
+			if !errors.As(err, &appErr) {
+				err = fmt.Errorf("WebhookRepository - MarkWebhookDeliveryAttempt: %w", err)
+			}
+		}
+	}()
+	_, err = r.db.Exec(
+		ctx,
+		queryMarkWebhookDeliveryAttempt,
+		deliveryID,
+		status,
+		responseStatus,
+		responseBody,
+		deliveryErr,
+		nextAttemptAt,
+	)
+	return
+}
+
+func scanWebhook(row pgx.Row) (w entity.Webhook, err error) {
+	err = row.Scan(
+		&w.ID,
+		&w.MediaID,
+		&w.URL,
+		&w.Secret,
+		&w.EventTypes,
+		&w.IsActive,
+		&w.CreatedAt,
+	)
+	if err == pgx.ErrNoRows {
+		err = &dto.AppError{
+			Message: "Вебхук не найден",
+			Code:    dto.ErrCodeNotFound,
+		}
+	}
+	return
+}
+
+func scanWebhookDelivery(row pgx.Row) (d entity.WebhookDelivery, err error) {
+	err = row.Scan(
+		&d.ID,
+		&d.WebhookID,
+		&d.EventType,
+		&d.Payload,
+		&d.Status,
+		&d.Attempts,
+		&d.ResponseStatus,
+		&d.ResponseBody,
+		&d.Error,
+		&d.ReplayOf,
+		&d.CreatedAt,
+		&d.LastAttemptAt,
+		&d.NextAttemptAt,
+	)
+	return
+}
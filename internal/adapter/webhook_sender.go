@@ -0,0 +1,81 @@
+package adapter
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"news-app-api/internal/dto"
+	"news-app-api/internal/entity"
+	"time"
+)
+
+const webhookResponseBodyLimit = 1024
+
+type (
+	WebhookSender interface {
+		Send(ctx context.Context, job entity.WebhookDeliveryJob, now time.Time) (int, string, error)
+	}
+
+	httpWebhookSender struct {
+		client *http.Client
+	}
+)
+
+func NewHTTPWebhookSender(timeout time.Duration, allowPrivateNetworks bool) WebhookSender {
+	return &httpWebhookSender{newOutboundHTTPClient(timeout, allowPrivateNetworks)}
+}
+
+func (s *httpWebhookSender) Send(
+	ctx context.Context,
+	job entity.WebhookDeliveryJob,
+	now time.Time,
+) (status int, body string, err error) {
+	defer func() {
+		if err != nil {
+			var appErr *dto.AppError
+			if !errors.As(err, &appErr) {
+				err = fmt.Errorf("WebhookSender - Send: %w", err)
+			}
+		}
+	}()
+
+	timestamp := fmt.Sprint(now.Unix())
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, job.URL, bytes.NewReader([]byte(job.Payload)))
+	if err != nil {
+		return
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "news-app-webhooks/1.0")
+	req.Header.Set("X-Webhook-Id", fmt.Sprint(job.DeliveryID))
+	req.Header.Set("X-Webhook-Event", job.EventType)
+	req.Header.Set("X-Webhook-Timestamp", timestamp)
+	req.Header.Set("X-Webhook-Signature", "sha256="+SignWebhookPayload(job.Secret, timestamp, job.Payload))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, webhookResponseBodyLimit))
+	if err != nil {
+		return resp.StatusCode, "", nil
+	}
+
+	return resp.StatusCode, string(data), nil
+}
+
+func SignWebhookPayload(secret, timestamp, payload string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write([]byte(payload))
+	return hex.EncodeToString(mac.Sum(nil))
+}
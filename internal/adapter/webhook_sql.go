@@ -0,0 +1,197 @@
+package adapter
+
+const (
+	queryCreateWebhook = `
+INSERT INTO webhook (media_id, url, secret, event_types)
+VALUES ($1, $2, $3, $4)
+RETURNING webhook.id,
+          webhook.media_id,
+          webhook.url,
+          webhook.secret,
+          webhook.event_types,
+          webhook.is_active,
+          EXTRACT(EPOCH FROM webhook.created_at)::BIGINT
+`
+
+	queryGetWebhookList = `
+SELECT webhook.id,
+       webhook.media_id,
+       webhook.url,
+       webhook.secret,
+       webhook.event_types,
+       webhook.is_active,
+       EXTRACT(EPOCH FROM webhook.created_at)::BIGINT
+FROM webhook
+WHERE media_id = $1
+ORDER BY id
+`
+
+	queryGetWebhook = `
+SELECT webhook.id,
+       webhook.media_id,
+       webhook.url,
+       webhook.secret,
+       webhook.event_types,
+       webhook.is_active,
+       EXTRACT(EPOCH FROM webhook.created_at)::BIGINT
+FROM webhook
+WHERE id = $1
+  AND media_id = $2
+`
+
+	queryUpdateWebhook = `
+UPDATE webhook
+SET url         = $3,
+    event_types = $4,
+    is_active   = $5
+WHERE id = $1
+  AND media_id = $2
+RETURNING webhook.id,
+          webhook.media_id,
+          webhook.url,
+          webhook.secret,
+          webhook.event_types,
+          webhook.is_active,
+          EXTRACT(EPOCH FROM webhook.created_at)::BIGINT
+`
+
+	queryRotateWebhookSecret = `
+UPDATE webhook
+SET secret = $3
+WHERE id = $1
+  AND media_id = $2
+RETURNING webhook.id,
+          webhook.media_id,
+          webhook.url,
+          webhook.secret,
+          webhook.event_types,
+          webhook.is_active,
+          EXTRACT(EPOCH FROM webhook.created_at)::BIGINT
+`
+
+	queryDeleteWebhook = `
+DELETE FROM webhook WHERE id = $1 AND media_id = $2
+`
+
+	queryEnqueueWebhookDeliveries = `
+INSERT INTO webhook_delivery (webhook_id, event_type, payload, next_attempt_at)
+SELECT id, $2, $3, NOW()
+FROM webhook
+WHERE media_id = $1
+  AND is_active
+  AND $2 = ANY (event_types)
+`
+
+	queryGetWebhookDeliveryList = `
+SELECT webhook_delivery.id,
+       webhook_delivery.webhook_id,
+       webhook_delivery.event_type,
+       webhook_delivery.payload,
+       webhook_delivery.status,
+       webhook_delivery.attempts,
+       webhook_delivery.response_status,
+       webhook_delivery.response_body,
+       webhook_delivery.error,
+       webhook_delivery.replay_of,
+       EXTRACT(EPOCH FROM webhook_delivery.created_at)::BIGINT,
+       EXTRACT(EPOCH FROM webhook_delivery.last_attempt_at)::BIGINT,
+       EXTRACT(EPOCH FROM webhook_delivery.next_attempt_at)::BIGINT
+FROM webhook_delivery
+INNER JOIN webhook ON
+    webhook.id = webhook_delivery.webhook_id
+WHERE webhook.id = $1
+  AND webhook.media_id = $2
+  AND ($3::VARCHAR IS NULL OR webhook_delivery.status = $3)
+ORDER BY webhook_delivery.created_at DESC, webhook_delivery.id DESC
+LIMIT $4 OFFSET $5
+`
+
+	queryCountWebhookDeliveries = `
+SELECT COUNT(*)
+FROM webhook_delivery
+INNER JOIN webhook ON
+    webhook.id = webhook_delivery.webhook_id
+WHERE webhook.id = $1
+  AND webhook.media_id = $2
+  AND ($3::VARCHAR IS NULL OR webhook_delivery.status = $3)
+`
+
+	queryGetWebhookDelivery = `
+SELECT webhook_delivery.id,
+       webhook_delivery.webhook_id,
+       webhook_delivery.event_type,
+       webhook_delivery.payload,
+       webhook_delivery.status,
+       webhook_delivery.attempts,
+       webhook_delivery.response_status,
+       webhook_delivery.response_body,
+       webhook_delivery.error,
+       webhook_delivery.replay_of,
+       EXTRACT(EPOCH FROM webhook_delivery.created_at)::BIGINT,
+       EXTRACT(EPOCH FROM webhook_delivery.last_attempt_at)::BIGINT,
+       EXTRACT(EPOCH FROM webhook_delivery.next_attempt_at)::BIGINT
+FROM webhook_delivery
+INNER JOIN webhook ON
+    webhook.id = webhook_delivery.webhook_id
+WHERE webhook_delivery.id = $1
+  AND webhook.id = $2
+  AND webhook.media_id = $3
+`
+
+	queryReplayWebhookDelivery = `
+INSERT INTO webhook_delivery (webhook_id, event_type, payload, replay_of, next_attempt_at)
+SELECT webhook_id, event_type, payload, id, NOW()
+FROM webhook_delivery
+WHERE id = $1
+RETURNING webhook_delivery.id,
+          webhook_delivery.webhook_id,
+          webhook_delivery.event_type,
+          webhook_delivery.payload,
+          webhook_delivery.status,
+          webhook_delivery.attempts,
+          webhook_delivery.response_status,
+          webhook_delivery.response_body,
+          webhook_delivery.error,
+          webhook_delivery.replay_of,
+          EXTRACT(EPOCH FROM webhook_delivery.created_at)::BIGINT,
+          EXTRACT(EPOCH FROM webhook_delivery.last_attempt_at)::BIGINT,
+          EXTRACT(EPOCH FROM webhook_delivery.next_attempt_at)::BIGINT
+`
+
+	queryClaimDueWebhookDeliveries = `
+UPDATE webhook_delivery
+SET next_attempt_at = NOW() + $2::BIGINT * INTERVAL '1 second'
+FROM webhook
+WHERE webhook.id = webhook_delivery.webhook_id
+  AND webhook_delivery.id IN (
+    SELECT webhook_delivery.id
+    FROM webhook_delivery
+    INNER JOIN webhook ON
+        webhook.id = webhook_delivery.webhook_id
+    WHERE webhook_delivery.status = 'pending'
+      AND webhook_delivery.next_attempt_at <= NOW()
+      AND webhook.is_active
+    ORDER BY webhook_delivery.next_attempt_at
+    LIMIT $1
+    FOR UPDATE OF webhook_delivery SKIP LOCKED
+)
+RETURNING webhook_delivery.id,
+          webhook_delivery.event_type,
+          webhook_delivery.payload,
+          webhook_delivery.attempts,
+          webhook.url,
+          webhook.secret
+`
+
+	queryMarkWebhookDeliveryAttempt = `
+UPDATE webhook_delivery
+SET status          = $2,
+    attempts        = attempts + 1,
+    response_status = $3,
+    response_body   = $4,
+    error           = $5,
+    last_attempt_at = NOW(),
+    next_attempt_at = TO_TIMESTAMP($6::BIGINT)
+WHERE id = $1
+`
+)
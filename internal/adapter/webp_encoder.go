@@ -0,0 +1,566 @@
+package adapter
+
+import (
+	"encoding/binary"
+	"fmt"
+	"image"
+	"io"
+	"math/bits"
+)
+
+const (
+	vp8lMaxDimension      = 16384
+	vp8lPredictorBits     = 4
+	vp8lMaxCodeLength     = 15
+	vp8lMaxCodeLenLength  = 7
+	vp8lMaxBackwardLength = 4096
+	vp8lMinBackwardLength = 3
+	vp8lNumLiterals       = 256
+	vp8lNumLengthCodes    = 24
+	vp8lNumDistanceCodes  = 40
+	vp8lDistanceAbove     = 1
+	vp8lDistanceLeft      = 2
+)
+
+var (
+	vp8lCodeLengthOrder = [19]int{17, 18, 0, 1, 2, 3, 4, 5, 16, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15}
+	vp8lPredictorModes  = []uint32{1, 2, 11, 12}
+)
+
+type (
+	vp8lBitWriter struct {
+		buf   []byte
+		acc   uint64
+		nbits uint
+	}
+
+	vp8lPrefixCode struct {
+		lengths []uint8
+		codes   []uint32
+	}
+
+	vp8lToken struct {
+		argb     uint32
+		length   int
+		distance int
+	}
+)
+
+func encodeWebPLossless(w io.Writer, img *image.NRGBA) error {
+	width, height := img.Rect.Dx(), img.Rect.Dy()
+	if width < 1 || height < 1 || width > vp8lMaxDimension || height > vp8lMaxDimension {
+		return fmt.Errorf("webp: unsupported image size %dx%d", width, height)
+	}
+
+	argb := make([]uint32, width*height)
+	hasAlpha := false
+	for y := 0; y < height; y++ {
+		row := img.Pix[y*img.Stride:]
+		for x := 0; x < width; x++ {
+			r, g, b, a := uint32(row[x*4]), uint32(row[x*4+1]), uint32(row[x*4+2]), uint32(row[x*4+3])
+			if a != 0xff {
+				hasAlpha = true
+			}
+			argb[y*width+x] = a<<24 | ((r-g)&0xff)<<16 | g<<8 | ((b - g) & 0xff)
+		}
+	}
+
+	modes, residuals := vp8lPredict(argb, width, height)
+
+	bw := &vp8lBitWriter{}
+	bw.writeBits(0x2f, 8)
+	bw.writeBits(uint32(width-1), 14)
+	bw.writeBits(uint32(height-1), 14)
+	if hasAlpha {
+		bw.writeBits(1, 1)
+	} else {
+		bw.writeBits(0, 1)
+	}
+	bw.writeBits(0, 3)
+
+	bw.writeBits(1, 1)
+	bw.writeBits(2, 2)
+
+	bw.writeBits(1, 1)
+	bw.writeBits(0, 2)
+	bw.writeBits(vp8lPredictorBits-2, 3)
+	bw.writeEntropyImage(modes, vp8lSubSampleSize(width), false)
+
+	bw.writeBits(0, 1)
+	bw.writeEntropyImage(residuals, width, true)
+
+	data := bw.bytes()
+	chunkSize := len(data)
+	if len(data)%2 == 1 {
+		data = append(data, 0)
+	}
+
+	header := make([]byte, 20)
+	copy(header[0:4], "RIFF")
+	binary.LittleEndian.PutUint32(header[4:8], uint32(12+len(data)))
+	copy(header[8:12], "WEBP")
+	copy(header[12:16], "VP8L")
+	binary.LittleEndian.PutUint32(header[16:20], uint32(chunkSize))
+
+	_, err := w.Write(header)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(data)
+	return err
+}
+
+func vp8lSubSampleSize(size int) int {
+	return (size + 1<<vp8lPredictorBits - 1) >> vp8lPredictorBits
+}
+
+func vp8lPredict(argb []uint32, width, height int) ([]uint32, []uint32) {
+	tilesX, tilesY := vp8lSubSampleSize(width), vp8lSubSampleSize(height)
+	modes := make([]uint32, tilesX*tilesY)
+	residuals := make([]uint32, len(argb))
+
+	for ty := 0; ty < tilesY; ty++ {
+		for tx := 0; tx < tilesX; tx++ {
+			x0, y0 := tx<<vp8lPredictorBits, ty<<vp8lPredictorBits
+			x1, y1 := minInt(x0+1<<vp8lPredictorBits, width), minInt(y0+1<<vp8lPredictorBits, height)
+
+			bestMode, bestCost := vp8lPredictorModes[0], -1
+			for _, mode := range vp8lPredictorModes {
+				cost := 0
+				for y := y0; y < y1; y++ {
+					for x := x0; x < x1; x++ {
+						cost += vp8lResidualCost(vp8lSubPixels(argb[y*width+x], vp8lPredictPixel(argb, width, x, y, mode)))
+					}
+				}
+				if bestCost < 0 || cost < bestCost {
+					bestMode, bestCost = mode, cost
+				}
+			}
+
+			modes[ty*tilesX+tx] = 0xff000000 | bestMode<<8
+			for y := y0; y < y1; y++ {
+				for x := x0; x < x1; x++ {
+					i := y*width + x
+					residuals[i] = vp8lSubPixels(argb[i], vp8lPredictPixel(argb, width, x, y, bestMode))
+				}
+			}
+		}
+	}
+
+	return modes, residuals
+}
+
+func vp8lPredictPixel(argb []uint32, width, x, y int, mode uint32) uint32 {
+	i := y*width + x
+	switch {
+	case x == 0 && y == 0:
+		return 0xff000000
+	case y == 0:
+		return argb[i-1]
+	case x == 0:
+		return argb[i-width]
+	}
+
+	l, t, tl := argb[i-1], argb[i-width], argb[i-width-1]
+	switch mode {
+	case 1:
+		return l
+	case 2:
+		return t
+	case 11:
+		return vp8lSelect(l, t, tl)
+	case 12:
+		return vp8lClampAddSubtractFull(l, t, tl)
+	}
+	return 0xff000000
+}
+
+func vp8lSelect(l, t, tl uint32) uint32 {
+	pl, pt := 0, 0
+	for shift := 0; shift < 32; shift += 8 {
+		cl, ct, ctl := int(l>>shift&0xff), int(t>>shift&0xff), int(tl>>shift&0xff)
+		p := cl + ct - ctl
+		pl += absInt(p - cl)
+		pt += absInt(p - ct)
+	}
+	if pl < pt {
+		return l
+	}
+	return t
+}
+
+func vp8lClampAddSubtractFull(a, b, c uint32) uint32 {
+	var res uint32
+	for shift := 0; shift < 32; shift += 8 {
+		v := int(a>>shift&0xff) + int(b>>shift&0xff) - int(c>>shift&0xff)
+		if v < 0 {
+			v = 0
+		} else if v > 0xff {
+			v = 0xff
+		}
+		res |= uint32(v) << shift
+	}
+	return res
+}
+
+func vp8lSubPixels(a, b uint32) uint32 {
+	alphaGreen := 0x00ff00ff + (a & 0xff00ff00) - (b & 0xff00ff00)
+	redBlue := 0xff00ff00 + (a & 0x00ff00ff) - (b & 0x00ff00ff)
+	return alphaGreen&0xff00ff00 | redBlue&0x00ff00ff
+}
+
+func vp8lResidualCost(p uint32) int {
+	cost := 0
+	for shift := 0; shift < 32; shift += 8 {
+		v := int(p >> shift & 0xff)
+		cost += minInt(v, 0x100-v)
+	}
+	return cost
+}
+
+func (w *vp8lBitWriter) writeEntropyImage(argb []uint32, width int, isMain bool) {
+	w.writeBits(0, 1)
+	if isMain {
+		w.writeBits(0, 1)
+	}
+
+	tokens := vp8lTokenize(argb, width)
+
+	green := make([]uint32, vp8lNumLiterals+vp8lNumLengthCodes)
+	red := make([]uint32, vp8lNumLiterals)
+	blue := make([]uint32, vp8lNumLiterals)
+	alpha := make([]uint32, vp8lNumLiterals)
+	distance := make([]uint32, vp8lNumDistanceCodes)
+	for _, t := range tokens {
+		if t.length == 0 {
+			green[t.argb>>8&0xff]++
+			red[t.argb>>16&0xff]++
+			blue[t.argb&0xff]++
+			alpha[t.argb>>24]++
+			continue
+		}
+		lengthCode, _, _ := vp8lPrefixEncode(t.length)
+		distanceCode, _, _ := vp8lPrefixEncode(t.distance)
+		green[vp8lNumLiterals+lengthCode]++
+		distance[distanceCode]++
+	}
+
+	greenCode := w.writePrefixCode(green)
+	redCode := w.writePrefixCode(red)
+	blueCode := w.writePrefixCode(blue)
+	alphaCode := w.writePrefixCode(alpha)
+	distanceCode := w.writePrefixCode(distance)
+
+	for _, t := range tokens {
+		if t.length == 0 {
+			greenCode.write(w, int(t.argb>>8&0xff))
+			redCode.write(w, int(t.argb>>16&0xff))
+			blueCode.write(w, int(t.argb&0xff))
+			alphaCode.write(w, int(t.argb>>24))
+			continue
+		}
+
+		code, extraBits, extra := vp8lPrefixEncode(t.length)
+		greenCode.write(w, vp8lNumLiterals+code)
+		w.writeBits(extra, extraBits)
+
+		code, extraBits, extra = vp8lPrefixEncode(t.distance)
+		distanceCode.write(w, code)
+		w.writeBits(extra, extraBits)
+	}
+}
+
+func vp8lTokenize(argb []uint32, width int) []vp8lToken {
+	tokens := make([]vp8lToken, 0, len(argb)/2)
+	for i := 0; i < len(argb); {
+		length, distance := 0, 0
+		if i >= 1 {
+			length, distance = vp8lMatchLength(argb, i, 1), vp8lDistanceLeft
+		}
+		if i >= width {
+			if n := vp8lMatchLength(argb, i, width); n > length {
+				length, distance = n, vp8lDistanceAbove
+			}
+		}
+
+		if length >= vp8lMinBackwardLength {
+			tokens = append(tokens, vp8lToken{length: length, distance: distance})
+			i += length
+		} else {
+			tokens = append(tokens, vp8lToken{argb: argb[i]})
+			i++
+		}
+	}
+	return tokens
+}
+
+func vp8lMatchLength(argb []uint32, i, offset int) int {
+	n := 0
+	for i+n < len(argb) && n < vp8lMaxBackwardLength && argb[i+n] == argb[i+n-offset] {
+		n++
+	}
+	return n
+}
+
+func vp8lPrefixEncode(v int) (code int, extraBits uint, extra uint32) {
+	n := v - 1
+	if n < 4 {
+		return n, 0, 0
+	}
+	h := bits.Len(uint(n)) - 1
+	second := (n >> (h - 1)) & 1
+	extraBits = uint(h - 1)
+	return 2*h + second, extraBits, uint32(n & (1<<extraBits - 1))
+}
+
+func (w *vp8lBitWriter) writePrefixCode(freqs []uint32) vp8lPrefixCode {
+	var used []int
+	for s, f := range freqs {
+		if f > 0 {
+			used = append(used, s)
+		}
+	}
+
+	if len(used) <= 2 && (len(used) == 0 || used[len(used)-1] < vp8lNumLiterals) {
+		if len(used) == 0 {
+			used = []int{0}
+		}
+		lengths := make([]uint8, len(freqs))
+
+		w.writeBits(1, 1)
+		w.writeBits(uint32(len(used)-1), 1)
+		if used[0] < 2 {
+			w.writeBits(0, 1)
+			w.writeBits(uint32(used[0]), 1)
+		} else {
+			w.writeBits(1, 1)
+			w.writeBits(uint32(used[0]), 8)
+		}
+		if len(used) == 2 {
+			w.writeBits(uint32(used[1]), 8)
+			lengths[used[0]], lengths[used[1]] = 1, 1
+		}
+		return vp8lPrefixCode{lengths, vp8lCanonicalCodes(lengths)}
+	}
+
+	lengths := vp8lHuffmanLengths(freqs, vp8lMaxCodeLength)
+
+	type clToken struct {
+		symbol int
+		extra  uint32
+	}
+	var tokens []clToken
+	clFreqs := make([]uint32, len(vp8lCodeLengthOrder))
+	for i := 0; i < len(lengths); {
+		if lengths[i] != 0 {
+			tokens = append(tokens, clToken{int(lengths[i]), 0})
+			clFreqs[lengths[i]]++
+			i++
+			continue
+		}
+
+		run := 0
+		for i+run < len(lengths) && lengths[i+run] == 0 && run < 138 {
+			run++
+		}
+		switch {
+		case run >= 11:
+			tokens = append(tokens, clToken{18, uint32(run - 11)})
+			clFreqs[18]++
+		case run >= 3:
+			tokens = append(tokens, clToken{17, uint32(run - 3)})
+			clFreqs[17]++
+		default:
+			for j := 0; j < run; j++ {
+				tokens = append(tokens, clToken{0, 0})
+			}
+			clFreqs[0] += uint32(run)
+		}
+		i += run
+	}
+
+	clLengths := vp8lHuffmanLengths(clFreqs, vp8lMaxCodeLenLength)
+	clCode := vp8lPrefixCode{clLengths, vp8lCanonicalCodes(clLengths)}
+
+	numCodes := len(vp8lCodeLengthOrder)
+	for numCodes > 4 && clLengths[vp8lCodeLengthOrder[numCodes-1]] == 0 {
+		numCodes--
+	}
+
+	w.writeBits(0, 1)
+	w.writeBits(uint32(numCodes-4), 4)
+	for i := 0; i < numCodes; i++ {
+		w.writeBits(uint32(clLengths[vp8lCodeLengthOrder[i]]), 3)
+	}
+	w.writeBits(0, 1)
+
+	for _, t := range tokens {
+		clCode.write(w, t.symbol)
+		switch t.symbol {
+		case 17:
+			w.writeBits(t.extra, 3)
+		case 18:
+			w.writeBits(t.extra, 7)
+		}
+	}
+
+	return vp8lPrefixCode{lengths, vp8lCanonicalCodes(lengths)}
+}
+
+func vp8lHuffmanLengths(freqs []uint32, maxLength int) []uint8 {
+	f := make([]uint64, len(freqs))
+	used := 0
+	for s, v := range freqs {
+		f[s] = uint64(v)
+		if v > 0 {
+			used++
+		}
+	}
+	for s := 0; used < 2; s++ {
+		if f[s] == 0 {
+			f[s] = 1
+			used++
+		}
+	}
+
+	for {
+		lengths, maxDepth := vp8lHuffmanDepths(f)
+		if maxDepth <= maxLength {
+			return lengths
+		}
+		for s := range f {
+			if f[s] > 0 {
+				f[s] = (f[s] + 1) / 2
+			}
+		}
+	}
+}
+
+func vp8lHuffmanDepths(freqs []uint64) ([]uint8, int) {
+	type node struct {
+		weight uint64
+		parent int
+	}
+
+	nodes := make([]node, 0, 2*len(freqs))
+	leaves := make([]int, len(freqs))
+	var active []int
+	for s, f := range freqs {
+		leaves[s] = -1
+		if f > 0 {
+			leaves[s] = len(nodes)
+			active = append(active, len(nodes))
+			nodes = append(nodes, node{f, -1})
+		}
+	}
+
+	for len(active) > 1 {
+		var a, b int
+		for k := 0; k < 2; k++ {
+			min := 0
+			for j := 1; j < len(active); j++ {
+				if nodes[active[j]].weight < nodes[active[min]].weight {
+					min = j
+				}
+			}
+			if k == 0 {
+				a = active[min]
+			} else {
+				b = active[min]
+			}
+			active = append(active[:min], active[min+1:]...)
+		}
+		nodes[a].parent = len(nodes)
+		nodes[b].parent = len(nodes)
+		active = append(active, len(nodes))
+		nodes = append(nodes, node{nodes[a].weight + nodes[b].weight, -1})
+	}
+
+	lengths := make([]uint8, len(freqs))
+	maxDepth := 0
+	for s, leaf := range leaves {
+		if leaf < 0 {
+			continue
+		}
+		depth := 0
+		for n := leaf; nodes[n].parent >= 0; n = nodes[n].parent {
+			depth++
+		}
+		if depth > maxDepth {
+			maxDepth = depth
+		}
+		if depth > 0xff {
+			depth = 0xff
+		}
+		lengths[s] = uint8(depth)
+	}
+	return lengths, maxDepth
+}
+
+func vp8lCanonicalCodes(lengths []uint8) []uint32 {
+	var count [vp8lMaxCodeLength + 1]uint32
+	for _, l := range lengths {
+		if l > 0 {
+			count[l]++
+		}
+	}
+
+	var next [vp8lMaxCodeLength + 1]uint32
+	code := uint32(0)
+	for l := 1; l <= vp8lMaxCodeLength; l++ {
+		code = (code + count[l-1]) << 1
+		next[l] = code
+	}
+
+	codes := make([]uint32, len(lengths))
+	for s, l := range lengths {
+		if l > 0 {
+			codes[s] = next[l]
+			next[l]++
+		}
+	}
+	return codes
+}
+
+func (c vp8lPrefixCode) write(w *vp8lBitWriter, symbol int) {
+	length := uint(c.lengths[symbol])
+	code := c.codes[symbol]
+	for i := int(length) - 1; i >= 0; i-- {
+		w.writeBits(code>>uint(i)&1, 1)
+	}
+}
+
+func (w *vp8lBitWriter) writeBits(v uint32, n uint) {
+	if n == 0 {
+		return
+	}
+	w.acc |= uint64(v&(1<<n-1)) << w.nbits
+	w.nbits += n
+	for w.nbits >= 8 {
+		w.buf = append(w.buf, byte(w.acc))
+		w.acc >>= 8
+		w.nbits -= 8
+	}
+}
+
+func (w *vp8lBitWriter) bytes() []byte {
+	if w.nbits > 0 {
+		w.buf = append(w.buf, byte(w.acc))
+		w.acc, w.nbits = 0, 0
+	}
+	return w.buf
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func absInt(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
@@ -0,0 +1,168 @@
+package adapter
+
+import (
+	"bytes"
+	"golang.org/x/image/webp"
+	"image"
+	"image/color"
+	"math/bits"
+	"math/rand"
+	"testing"
+)
+
+func TestEncodeWebPLossless(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+
+	tests := []struct {
+		name          string
+		width, height int
+		pixel         func(x, y int) color.NRGBA
+	}{
+		{"single pixel", 1, 1, func(x, y int) color.NRGBA {
+			return color.NRGBA{12, 34, 56, 255}
+		}},
+		{"solid", 40, 30, func(x, y int) color.NRGBA {
+			return color.NRGBA{200, 100, 50, 255}
+		}},
+		{"gradient", 255, 97, func(x, y int) color.NRGBA {
+			return color.NRGBA{uint8(x), uint8(y * 2), uint8(x + y), 255}
+		}},
+		{"stripes", 64, 64, func(x, y int) color.NRGBA {
+			if (x/3+y)%5 == 0 {
+				return color.NRGBA{255, 255, 255, 255}
+			}
+			return color.NRGBA{0, 0, 0, 255}
+		}},
+		{"noise", 33, 17, func(x, y int) color.NRGBA {
+			return color.NRGBA{uint8(rnd.Intn(256)), uint8(rnd.Intn(256)), uint8(rnd.Intn(256)), 255}
+		}},
+		{"noise with alpha", 17, 33, func(x, y int) color.NRGBA {
+			return color.NRGBA{uint8(rnd.Intn(256)), uint8(rnd.Intn(256)), uint8(rnd.Intn(256)), uint8(rnd.Intn(256))}
+		}},
+		{"transparent", 20, 20, func(x, y int) color.NRGBA {
+			return color.NRGBA{uint8(x * 10), 0, uint8(y * 10), 0}
+		}},
+		{"row", 300, 1, func(x, y int) color.NRGBA {
+			return color.NRGBA{uint8(x % 7 * 30), uint8(x / 2), 0, 255}
+		}},
+		{"column", 1, 300, func(x, y int) color.NRGBA {
+			return color.NRGBA{0, uint8(y / 2), uint8(y % 7 * 30), 255}
+		}},
+		{"skewed noise", 512, 512, func(x, y int) color.NRGBA {
+			v := uint8(bits.Len32(rnd.Uint32()))
+			return color.NRGBA{v, v * 3, v * 5, 255}
+		}},
+		{"repeated blocks", 260, 130, func(x, y int) color.NRGBA {
+			v := uint8((x/8*7 + y/8*13) % 256)
+			return color.NRGBA{v, 255 - v, v / 2, 255}
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			src := image.NewNRGBA(image.Rect(0, 0, tt.width, tt.height))
+			for y := 0; y < tt.height; y++ {
+				for x := 0; x < tt.width; x++ {
+					src.SetNRGBA(x, y, tt.pixel(x, y))
+				}
+			}
+
+			var buf bytes.Buffer
+			err := encodeWebPLossless(&buf, src)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			decoded, err := webp.Decode(bytes.NewReader(buf.Bytes()))
+			if err != nil {
+				t.Fatalf("decode: %v", err)
+			}
+			if decoded.Bounds() != src.Bounds() {
+				t.Fatalf("bounds = %v, want %v", decoded.Bounds(), src.Bounds())
+			}
+
+			got, ok := decoded.(*image.NRGBA)
+			if !ok {
+				t.Fatalf("decoded %T, want *image.NRGBA", decoded)
+			}
+			for y := 0; y < tt.height; y++ {
+				for x := 0; x < tt.width; x++ {
+					if g, w := got.NRGBAAt(x, y), src.NRGBAAt(x, y); g != w {
+						t.Fatalf("pixel (%d, %d) = %v, want %v", x, y, g, w)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestVP8LHuffmanLengths(t *testing.T) {
+	// Fibonacci frequencies give a tree as deep as the alphabet is long, well past the 15-bit limit.
+	fib := make([]uint32, 40)
+	fib[0], fib[1] = 1, 1
+	for i := 2; i < len(fib); i++ {
+		fib[i] = fib[i-1] + fib[i-2]
+	}
+
+	for _, freqs := range [][]uint32{fib, {0, 5}, {0, 0, 3, 0}, {1, 1, 1, 1, 1}} {
+		lengths := vp8lHuffmanLengths(freqs, vp8lMaxCodeLength)
+
+		kraft := 0.0
+		for s, l := range lengths {
+			if l > vp8lMaxCodeLength {
+				t.Fatalf("%v: symbol %d has length %d", freqs, s, l)
+			}
+			if freqs[s] > 0 && l == 0 {
+				t.Fatalf("%v: used symbol %d has no code", freqs, s)
+			}
+			if l > 0 {
+				kraft += 1 / float64(uint(1)<<l)
+			}
+		}
+		if kraft != 1 {
+			t.Errorf("%v: Kraft sum = %v, want 1", freqs, kraft)
+		}
+	}
+}
+
+func TestEncodeWebPLosslessSubImage(t *testing.T) {
+	src := image.NewNRGBA(image.Rect(0, 0, 50, 40))
+	for y := 0; y < 40; y++ {
+		for x := 0; x < 50; x++ {
+			src.SetNRGBA(x, y, color.NRGBA{uint8(x * 5), uint8(y * 6), uint8(x ^ y), 255})
+		}
+	}
+	sub := src.SubImage(image.Rect(10, 5, 37, 31)).(*image.NRGBA)
+
+	var buf bytes.Buffer
+	err := encodeWebPLossless(&buf, sub)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	decoded, err := webp.Decode(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	got := decoded.(*image.NRGBA)
+	for y := 0; y < 26; y++ {
+		for x := 0; x < 27; x++ {
+			if g, w := got.NRGBAAt(x, y), sub.NRGBAAt(x+10, y+5); g != w {
+				t.Fatalf("pixel (%d, %d) = %v, want %v", x, y, g, w)
+			}
+		}
+	}
+}
+
+func TestEncodeWebPLosslessInvalidSize(t *testing.T) {
+	for _, r := range []image.Rectangle{
+		image.Rect(0, 0, 0, 10),
+		image.Rect(0, 0, 10, 0),
+		image.Rect(0, 0, vp8lMaxDimension+1, 1),
+	} {
+		err := encodeWebPLossless(&bytes.Buffer{}, &image.NRGBA{Rect: r})
+		if err == nil {
+			t.Errorf("%v: expected an error", r)
+		}
+	}
+}
@@ -0,0 +1,273 @@
+package adapter
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"golang.org/x/crypto/hkdf"
+	"io"
+	"math/big"
+	"net/http"
+	"net/url"
+	"news-app-api/internal/dto"
+	"news-app-api/internal/entity"
+	"strings"
+	"time"
+)
+
+const (
+	webPushRecordSize = 4096
+	vapidTokenTTL     = 12 * time.Hour
+)
+
+type (
+	PushSender interface {
+		Send(ctx context.Context, sub entity.PushSubscription, payload []byte, ttl time.Duration) (int, error)
+		PublicKey() string
+	}
+
+	webPushSender struct {
+		client    *http.Client
+		key       *ecdsa.PrivateKey
+		publicKey []byte
+		subject   string
+	}
+)
+
+func NewWebPushSender(
+	vapidPrivateKey, subject string,
+	timeout time.Duration,
+	allowPrivateNetworks bool,
+) (PushSender, error) {
+	key, err := ParseVAPIDPrivateKey(vapidPrivateKey)
+	if err != nil {
+		return nil, err
+	}
+
+	publicKey := make([]byte, 65)
+	publicKey[0] = 4
+	key.X.FillBytes(publicKey[1:33])
+	key.Y.FillBytes(publicKey[33:])
+
+	return &webPushSender{
+		client:    newOutboundHTTPClient(timeout, allowPrivateNetworks),
+		key:       key,
+		publicKey: publicKey,
+		subject:   subject,
+	}, nil
+}
+
+func GenerateVAPIDPrivateKey() (string, error) {
+	key, err := ecdh.P256().GenerateKey(rand.Reader)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(key.Bytes()), nil
+}
+
+func ParseVAPIDPrivateKey(s string) (*ecdsa.PrivateKey, error) {
+	d, err := decodeBase64URL(s)
+	if err != nil {
+		return nil, fmt.Errorf("invalid VAPID private key: %w", err)
+	}
+
+	key, err := ecdh.P256().NewPrivateKey(d)
+	if err != nil {
+		return nil, fmt.Errorf("invalid VAPID private key: %w", err)
+	}
+	pub := key.PublicKey().Bytes()
+
+	return &ecdsa.PrivateKey{
+		PublicKey: ecdsa.PublicKey{
+			Curve: elliptic.P256(),
+			X:     new(big.Int).SetBytes(pub[1:33]),
+			Y:     new(big.Int).SetBytes(pub[33:]),
+		},
+		D: new(big.Int).SetBytes(d),
+	}, nil
+}
+
+func (s *webPushSender) PublicKey() string {
+	return base64.RawURLEncoding.EncodeToString(s.publicKey)
+}
+
+func (s *webPushSender) Send(
+	ctx context.Context,
+	sub entity.PushSubscription,
+	payload []byte,
+	ttl time.Duration,
+) (status int, err error) {
+	defer func() {
+		if err != nil {
+			var appErr *dto.AppError
+			if !errors.As(err, &appErr) {
+				err = fmt.Errorf("WebPushSender - Send: %w", err)
+			}
+		}
+	}()
+
+	uaPublic, err := decodeBase64URL(sub.P256dh)
+	if err != nil {
+		return
+	}
+	authSecret, err := decodeBase64URL(sub.Auth)
+	if err != nil {
+		return
+	}
+
+	asKey, err := ecdh.P256().GenerateKey(rand.Reader)
+	if err != nil {
+		return
+	}
+	salt := make([]byte, 16)
+	_, err = rand.Read(salt)
+	if err != nil {
+		return
+	}
+
+	body, err := encryptWebPushPayload(uaPublic, authSecret, payload, asKey, salt)
+	if err != nil {
+		return
+	}
+
+	token, err := s.vapidToken(sub.Endpoint, time.Now())
+	if err != nil {
+		return
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, sub.Endpoint, bytes.NewReader(body))
+	if err != nil {
+		return
+	}
+	req.Header.Set("Content-Encoding", "aes128gcm")
+	req.Header.Set("Content-Type", "application/octet-stream")
+	req.Header.Set("TTL", fmt.Sprint(int64(ttl.Seconds())))
+	req.Header.Set("Urgency", "high")
+	req.Header.Set("Authorization", fmt.Sprintf("vapid t=%s, k=%s", token, s.PublicKey()))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64*1024))
+
+	return resp.StatusCode, nil
+}
+
+func (s *webPushSender) vapidToken(endpoint string, now time.Time) (string, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return "", err
+	}
+
+	header, err := json.Marshal(map[string]string{"typ": "JWT", "alg": "ES256"})
+	if err != nil {
+		return "", err
+	}
+	claims, err := json.Marshal(map[string]any{
+		"aud": fmt.Sprintf("%s://%s", u.Scheme, u.Host),
+		"exp": now.Add(vapidTokenTTL).Unix(),
+		"sub": s.subject,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	unsigned := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(claims)
+	digest := sha256.Sum256([]byte(unsigned))
+
+	r, sig, err := ecdsa.Sign(rand.Reader, s.key, digest[:])
+	if err != nil {
+		return "", err
+	}
+	signature := make([]byte, 64)
+	r.FillBytes(signature[:32])
+	sig.FillBytes(signature[32:])
+
+	return unsigned + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
+
+func encryptWebPushPayload(
+	uaPublic, authSecret, payload []byte,
+	asKey *ecdh.PrivateKey,
+	salt []byte,
+) ([]byte, error) {
+	uaKey, err := ecdh.P256().NewPublicKey(uaPublic)
+	if err != nil {
+		return nil, fmt.Errorf("invalid p256dh key: %w", err)
+	}
+	if len(authSecret) != 16 {
+		return nil, fmt.Errorf("invalid auth secret length %d", len(authSecret))
+	}
+	if len(payload) > webPushRecordSize-17-86 {
+		return nil, fmt.Errorf("payload is too large")
+	}
+
+	ecdhSecret, err := asKey.ECDH(uaKey)
+	if err != nil {
+		return nil, err
+	}
+	asPublic := asKey.PublicKey().Bytes()
+
+	keyInfo := make([]byte, 0, 14+len(uaPublic)+len(asPublic))
+	keyInfo = append(keyInfo, "WebPush: info\x00"...)
+	keyInfo = append(keyInfo, uaPublic...)
+	keyInfo = append(keyInfo, asPublic...)
+
+	ikm := make([]byte, 32)
+	_, err = io.ReadFull(hkdf.New(sha256.New, ecdhSecret, authSecret, keyInfo), ikm)
+	if err != nil {
+		return nil, err
+	}
+
+	cek := make([]byte, 16)
+	_, err = io.ReadFull(hkdf.New(sha256.New, ikm, salt, []byte("Content-Encoding: aes128gcm\x00")), cek)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, 12)
+	_, err = io.ReadFull(hkdf.New(sha256.New, ikm, salt, []byte("Content-Encoding: nonce\x00")), nonce)
+	if err != nil {
+		return nil, err
+	}
+
+	block, err := aes.NewCipher(cek)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	plaintext := make([]byte, 0, len(payload)+1)
+	plaintext = append(plaintext, payload...)
+	plaintext = append(plaintext, 0x02)
+
+	body := make([]byte, 0, 21+len(asPublic)+len(plaintext)+gcm.Overhead())
+	body = append(body, salt...)
+	body = binary.BigEndian.AppendUint32(body, webPushRecordSize)
+	body = append(body, byte(len(asPublic)))
+	body = append(body, asPublic...)
+	body = gcm.Seal(body, nonce, plaintext, nil)
+
+	return body, nil
+}
+
+func decodeBase64URL(s string) ([]byte, error) {
+	s = strings.TrimRight(s, "=")
+	s = strings.NewReplacer("+", "-", "/", "_").Replace(s)
+	return base64.RawURLEncoding.DecodeString(s)
+}
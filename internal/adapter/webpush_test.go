@@ -0,0 +1,231 @@
+package adapter
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"golang.org/x/crypto/hkdf"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"news-app-api/internal/entity"
+	"strings"
+	"testing"
+	"time"
+)
+
+type fakePushService struct {
+	t        *testing.T
+	key      *ecdh.PrivateKey
+	auth     []byte
+	status   int
+	messages chan []byte
+}
+
+func newFakePushService(t *testing.T, status int) (*fakePushService, *httptest.Server) {
+	key, err := ecdh.P256().GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	auth := make([]byte, 16)
+	_, _ = rand.Read(auth)
+
+	s := &fakePushService{t, key, auth, status, make(chan []byte, 1)}
+	srv := httptest.NewServer(s)
+	t.Cleanup(srv.Close)
+	return s, srv
+}
+
+func (s *fakePushService) subscription(endpoint string) entity.PushSubscription {
+	return entity.PushSubscription{
+		Endpoint: endpoint,
+		P256dh:   base64.RawURLEncoding.EncodeToString(s.key.PublicKey().Bytes()),
+		Auth:     base64.RawURLEncoding.EncodeToString(s.auth),
+	}
+}
+
+func (s *fakePushService) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("Content-Encoding") != "aes128gcm" || r.Header.Get("TTL") == "" {
+		s.t.Errorf("unexpected headers %v", r.Header)
+	}
+
+	err := verifyVAPID(r.Header.Get("Authorization"), "http://"+r.Host)
+	if err != nil {
+		s.t.Errorf("vapid: %v", err)
+	}
+
+	body, _ := io.ReadAll(r.Body)
+	message, err := s.decrypt(body)
+	if err != nil {
+		s.t.Errorf("decrypt: %v", err)
+	}
+	s.messages <- message
+
+	w.WriteHeader(s.status)
+}
+
+func (s *fakePushService) decrypt(body []byte) ([]byte, error) {
+	if len(body) < 21 || len(body) < 21+int(body[20]) {
+		return nil, errors.New("truncated header")
+	}
+	salt, idLen := body[:16], int(body[20])
+	if binary.BigEndian.Uint32(body[16:20]) != webPushRecordSize {
+		return nil, errors.New("unexpected record size")
+	}
+	asPublic, ciphertext := body[21:21+idLen], body[21+idLen:]
+
+	asKey, err := ecdh.P256().NewPublicKey(asPublic)
+	if err != nil {
+		return nil, err
+	}
+	secret, err := s.key.ECDH(asKey)
+	if err != nil {
+		return nil, err
+	}
+
+	info := append([]byte("WebPush: info\x00"), s.key.PublicKey().Bytes()...)
+	info = append(info, asPublic...)
+	ikm := make([]byte, 32)
+	_, _ = io.ReadFull(hkdf.New(sha256.New, secret, s.auth, info), ikm)
+	cek := make([]byte, 16)
+	_, _ = io.ReadFull(hkdf.New(sha256.New, ikm, salt, []byte("Content-Encoding: aes128gcm\x00")), cek)
+	nonce := make([]byte, 12)
+	_, _ = io.ReadFull(hkdf.New(sha256.New, ikm, salt, []byte("Content-Encoding: nonce\x00")), nonce)
+
+	block, err := aes.NewCipher(cek)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	plaintext, err := gcm.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	end := bytes.LastIndexByte(plaintext, 0x02)
+	if end < 0 {
+		return nil, errors.New("missing padding delimiter")
+	}
+	return plaintext[:end], nil
+}
+
+func verifyVAPID(header, audience string) error {
+	var token, key string
+	for _, part := range strings.Split(strings.TrimPrefix(header, "vapid "), ",") {
+		k, v, _ := strings.Cut(strings.TrimSpace(part), "=")
+		switch k {
+		case "t":
+			token = v
+		case "k":
+			key = v
+		}
+	}
+
+	pub, err := base64.RawURLEncoding.DecodeString(key)
+	if err != nil || len(pub) != 65 {
+		return fmt.Errorf("invalid key %q", key)
+	}
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return fmt.Errorf("invalid token %q", token)
+	}
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil || len(sig) != 64 {
+		return errors.New("invalid signature encoding")
+	}
+
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	publicKey := &ecdsa.PublicKey{
+		Curve: elliptic.P256(),
+		X:     new(big.Int).SetBytes(pub[1:33]),
+		Y:     new(big.Int).SetBytes(pub[33:]),
+	}
+	if !ecdsa.Verify(publicKey, digest[:], new(big.Int).SetBytes(sig[:32]), new(big.Int).SetBytes(sig[32:])) {
+		return errors.New("signature mismatch")
+	}
+
+	claimsJSON, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return err
+	}
+	var claims struct {
+		Aud string `json:"aud"`
+		Exp int64  `json:"exp"`
+		Sub string `json:"sub"`
+	}
+	err = json.Unmarshal(claimsJSON, &claims)
+	if err != nil {
+		return err
+	}
+	if claims.Aud != audience || claims.Exp <= time.Now().Unix() || claims.Sub == "" {
+		return fmt.Errorf("unexpected claims %+v", claims)
+	}
+	return nil
+}
+
+func newTestWebPushSender(t *testing.T, allowPrivateNetworks bool) PushSender {
+	key, err := GenerateVAPIDPrivateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sender, err := NewWebPushSender(key, "mailto:news@localhost", 5*time.Second, allowPrivateNetworks)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return sender
+}
+
+func TestWebPushSenderSend(t *testing.T) {
+	for _, status := range []int{http.StatusCreated, http.StatusGone} {
+		t.Run(fmt.Sprint(status), func(t *testing.T) {
+			service, srv := newFakePushService(t, status)
+			sender := newTestWebPushSender(t, true)
+
+			payload := []byte(`{"type":"breaking_news","newsId":1}`)
+			got, err := sender.Send(context.Background(), service.subscription(srv.URL+"/push/1"), payload, time.Hour)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != status {
+				t.Errorf("status = %d, want %d", got, status)
+			}
+			if message := <-service.messages; !bytes.Equal(message, payload) {
+				t.Errorf("message = %q, want %q", message, payload)
+			}
+		})
+	}
+}
+
+func TestWebPushSenderRejectsPrivateNetworks(t *testing.T) {
+	service, srv := newFakePushService(t, http.StatusCreated)
+	sender := newTestWebPushSender(t, false)
+
+	_, err := sender.Send(context.Background(), service.subscription(srv.URL+"/push/1"), []byte("{}"), time.Hour)
+	if !errors.Is(err, errAddressForbidden) {
+		t.Fatalf("error = %v, want %v", err, errAddressForbidden)
+	}
+}
+
+func TestParseVAPIDPrivateKey(t *testing.T) {
+	for _, key := range []string{"", "not base64!", base64.RawURLEncoding.EncodeToString(make([]byte, 32))} {
+		_, err := ParseVAPIDPrivateKey(key)
+		if err == nil {
+			t.Errorf("ParseVAPIDPrivateKey(%q) succeeded", key)
+		}
+	}
+}
@@ -6,14 +6,18 @@ import (
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/cors"
 	"github.com/gofiber/fiber/v2/middleware/encryptcookie"
+	"github.com/gofiber/fiber/v2/middleware/recover"
 	"github.com/jackc/pgx/v5/pgxpool"
 	log "github.com/sirupsen/logrus"
+	"net/http"
 	"news-app-api/config"
 	"news-app-api/internal/adapter"
 	"news-app-api/internal/controller"
+	"news-app-api/internal/scheduler"
 	"news-app-api/internal/usecase"
 	"os"
 	"os/signal"
+	"time"
 )
 
 func Run() {
@@ -30,80 +34,199 @@ func Run() {
 
 	log.Debug("Loaded configuration")
 
-	dbCfg, err := pgxpool.ParseConfig(cfg.DBURL)
-	if err != nil {
-		log.Fatal(err.Error())
-	}
-
-	db, err := pgxpool.NewWithConfig(context.Background(), dbCfg)
+	db, err := connectDB(cfg)
 	if err != nil {
 		log.Fatal(err.Error())
 	}
 	defer db.Close()
 
-	err = db.Ping(context.Background())
-	if err != nil {
-		log.Fatal(err.Error())
-	}
-
 	log.Debug("Connected to PostgreSQL")
 
 	userRepo := adapter.NewUserRepository(db)
 	mediaRepo := adapter.NewMediaRepository(db)
-	audioFileRepo, err := adapter.NewAudioFileRepository()
+	blobStore, err := newBlobStore(cfg)
 	if err != nil {
 		log.Fatal(err.Error())
 	}
-	imageFileRepo, err := adapter.NewImageFileRepository()
+	digestRepo := adapter.NewDigestRepository(db)
+	notificationRepo := adapter.NewNotificationRepository(db)
+	pushRepo := adapter.NewPushSubscriptionRepository(db)
+	webhookRepo := adapter.NewWebhookRepository(db)
+	accountRepo := adapter.NewAccountRepository(db)
+	attachmentRepo := adapter.NewAttachmentRepository(db)
+	uploadRepo := adapter.NewUploadRepository(db)
+	textTrackRepo := adapter.NewTextTrackRepository(db)
+	storageRepo := adapter.NewStorageRepository(db)
+	mailer, err := adapter.NewFileDropMailer(cfg.MailDropDir)
 	if err != nil {
 		log.Fatal(err.Error())
 	}
-	videoFileRepo, err := adapter.NewVideoFileRepository()
+
+	if cfg.VAPIDPrivateKey == "" {
+		cfg.VAPIDPrivateKey, err = adapter.GenerateVAPIDPrivateKey()
+		if err != nil {
+			log.Fatal(err.Error())
+		}
+		log.Warn("VAPID_PRIVATE_KEY is not set, push subscriptions will not survive a restart")
+	}
+	pushSender, err := adapter.NewWebPushSender(
+		cfg.VAPIDPrivateKey,
+		cfg.VAPIDSubject,
+		30*time.Second,
+		cfg.PushAllowPrivateNetworks,
+	)
 	if err != nil {
 		log.Fatal(err.Error())
 	}
 
-	userUC := usecase.NewUserUseCase(userRepo)
+	events := usecase.NewEventBus()
+	attachmentURLs := usecase.NewAttachmentURLSigner(usecase.AttachmentURLConfig{
+		Secret:           cfg.AttachmentURLSecret,
+		TTL:              time.Duration(cfg.AttachmentURLTTLMinutes) * time.Minute,
+		RequireSignature: cfg.AttachmentURLRequireSignature,
+	})
+
+	userUC := usecase.NewUserUseCase(userRepo, mailer, usecase.UserConfig{
+		PublicURL: cfg.PublicURL,
+		MailFrom:  cfg.MailFrom,
+	})
 	mediaUC := usecase.NewMediaUseCase(
 		mediaRepo,
 		func() adapter.NewsRepository {
 			return adapter.NewNewsRepository(db)
 		},
+		blobStore,
+		attachmentURLs,
+		events,
 	)
 	newsUC := usecase.NewNewsUseCase(
 		func() adapter.NewsRepository {
 			return adapter.NewNewsRepository(db)
 		},
 		mediaRepo,
-		audioFileRepo,
-		imageFileRepo,
-		videoFileRepo,
+		blobStore,
+		attachmentRepo,
+		adapter.NewThumbnailer(),
+		adapter.NewMetadataExtractor(),
+		adapter.NewWaveformGenerator(),
+		func() adapter.BlobRepository {
+			return adapter.NewBlobRepository(db)
+		},
+		attachmentURLs,
+		events,
+	)
+	textTrackUC := usecase.NewTextTrackUseCase(
+		func() adapter.NewsRepository {
+			return adapter.NewNewsRepository(db)
+		},
+		mediaRepo,
+		textTrackRepo,
+		blobStore,
+		attachmentURLs,
+	)
+	feedUC := usecase.NewFeedUseCase(
+		func() adapter.NewsRepository {
+			return adapter.NewNewsRepository(db)
+		},
+		mediaRepo,
+		usecase.NewWeightedFeedScorer(usecase.DefaultWeightedFeedScorerConfig()),
+		attachmentURLs,
+	)
+	digestUC, err := usecase.NewDigestUseCase(
+		digestRepo,
+		func() adapter.NewsRepository {
+			return adapter.NewNewsRepository(db)
+		},
+		mailer,
+		usecase.DigestConfig{
+			PublicURL:          cfg.PublicURL,
+			ArticleURLTemplate: cfg.ArticleURLTemplate,
+			MailFrom:           cfg.MailFrom,
+		},
 	)
-	feedUC := usecase.NewFeedUseCase(func() adapter.NewsRepository {
-		return adapter.NewNewsRepository(db)
+	if err != nil {
+		log.Fatal(err.Error())
+	}
+	notificationUC := usecase.NewNotificationUseCase(notificationRepo, mediaRepo)
+	pushUC := usecase.NewPushUseCase(pushRepo, mediaRepo, pushSender, usecase.PushConfig{
+		PublicURL:            cfg.PublicURL,
+		AllowPrivateNetworks: cfg.PushAllowPrivateNetworks,
 	})
+	webhookUC := usecase.NewWebhookUseCase(
+		webhookRepo,
+		func() adapter.NewsRepository {
+			return adapter.NewNewsRepository(db)
+		},
+		adapter.NewHTTPWebhookSender(10*time.Second, cfg.WebhookAllowPrivateNetworks),
+	)
+	accountUC := usecase.NewAccountUseCase(accountRepo, userRepo, mailer, usecase.AccountConfig{
+		DeletionGracePeriod: time.Duration(cfg.AccountDeletionGraceDays) * 24 * time.Hour,
+		MailFrom:            cfg.MailFrom,
+	})
+
+	uploadUC := usecase.NewUploadUseCase(
+		uploadRepo,
+		func() adapter.NewsRepository {
+			return adapter.NewNewsRepository(db)
+		},
+		newsUC,
+		blobStore,
+		usecase.UploadConfig{
+			Expiration: time.Duration(cfg.UploadExpirationHours) * time.Hour,
+		},
+	)
+
+	storageUC := usecase.NewStorageUseCase(storageRepo, blobStore, storageConfig(cfg))
+
+	events.Subscribe(notificationUC)
+	events.Subscribe(pushUC)
+	events.Subscribe(webhookUC)
 
 	middleware := controller.NewMiddleware()
 
+	cacheConfig := controller.CacheConfig{
+		News:  cfg.CacheControlNews,
+		Image: cfg.CacheControlImage,
+		Audio: cfg.CacheControlAudio,
+		Video: cfg.CacheControlVideo,
+	}
+
 	userController := controller.NewUserController(userUC)
 	mediaController := controller.NewMediaController(mediaUC)
-	newsController := controller.NewNewsController(newsUC)
+	newsController := controller.NewNewsController(newsUC, cacheConfig)
+	textTrackController := controller.NewTextTrackController(textTrackUC, cacheConfig)
 	feedController := controller.NewFeedController(feedUC)
 	favoriteController := controller.NewFavoriteController(newsUC)
+	digestController := controller.NewDigestController(digestUC)
+	notificationController := controller.NewNotificationController(notificationUC)
+	pushController := controller.NewPushController(pushUC)
+	webhookController := controller.NewWebhookController(webhookUC)
+	accountController := controller.NewAccountController(accountUC)
+	uploadController := controller.NewUploadController(uploadUC)
 
 	app := fiber.New(fiber.Config{
-		ErrorHandler:          controller.ErrHandler,
-		DisableStartupMessage: true,
-		BodyLimit:             4 * 1024 * 1024 * 1024,
+		ErrorHandler:                 controller.ErrHandler,
+		DisableStartupMessage:        true,
+		BodyLimit:                    4 * 1024 * 1024,
+		StreamRequestBody:            true,
+		DisablePreParseMultipartForm: true,
 	})
 
+	app.Use(recover.New(recover.Config{
+		EnableStackTrace: true,
+	}))
+
 	app.Use(encryptcookie.New(encryptcookie.Config{
 		Key: cfg.Secret,
 	}))
 
 	app.Use(cors.New(cors.Config{
+		Next: func(ctx *fiber.Ctx) bool {
+			return ctx.Method() == fiber.MethodOptions && ctx.Get(fiber.HeaderAccessControlRequestMethod) == ""
+		},
 		AllowCredentials: true,
 		AllowOrigins:     "http://localhost:3000, http://127.0.0.1:3000, http://0.0.0.0:3000",
+		ExposeHeaders:    "Location, Upload-Offset, Upload-Length, Upload-Expires, Upload-Status, Tus-Resumable, Tus-Version, Tus-Extension, Tus-Max-Size",
 	}))
 
 	app.Use(func(ctx *fiber.Ctx) error {
@@ -132,12 +255,36 @@ func Run() {
 	newsRouter := router.Group("news")
 	feedRouter := router.Group("feed")
 	favoriteRouter := router.Group("favorites")
+	digestRouter := router.Group("digest")
+	notificationRouter := router.Group("notifications")
+	pushRouter := router.Group("push")
+	webhookRouter := router.Group("webhooks")
+	uploadRouter := router.Group("uploads")
 
 	userController.RegisterRoutes(userRouter, middleware)
+	accountController.RegisterRoutes(userRouter, middleware)
 	mediaController.RegisterRoutes(mediaRouter, middleware)
 	newsController.RegisterRoutes(newsRouter, middleware)
+	textTrackController.RegisterRoutes(newsRouter, middleware)
 	feedController.RegisterRoutes(feedRouter, middleware)
 	favoriteController.RegisterRoutes(favoriteRouter, middleware)
+	digestController.RegisterRoutes(digestRouter, middleware)
+	notificationController.RegisterRoutes(notificationRouter, middleware)
+	pushController.RegisterRoutes(pushRouter, middleware)
+	webhookController.RegisterRoutes(webhookRouter, middleware)
+	uploadController.RegisterRoutes(uploadRouter, middleware)
+
+	jobsCtx, stopJobs := context.WithCancel(context.Background())
+	jobs := scheduler.New()
+	jobs.Every(jobsCtx, "send-digests", time.Minute, digestUC.SendDueDigests)
+	jobs.Every(jobsCtx, "deliver-pushes", 5*time.Second, pushUC.DeliverDuePushes)
+	jobs.Every(jobsCtx, "deliver-webhooks", 10*time.Second, webhookUC.DeliverDueWebhooks)
+	jobs.Every(jobsCtx, "delete-accounts", time.Hour, accountUC.DeleteDueAccounts)
+	jobs.Every(jobsCtx, "process-uploads", 5*time.Second, uploadUC.ProcessUploads)
+	jobs.Every(jobsCtx, "expire-uploads", time.Hour, uploadUC.DeleteExpired)
+	jobs.Every(jobsCtx, "expire-feed-sessions", time.Hour, feedUC.DeleteExpiredSessions)
+	jobs.Every(jobsCtx, "collect-blobs", time.Hour, newsUC.CollectBlobs)
+	jobs.Every(jobsCtx, "check-storage", 24*time.Hour, storageUC.CheckStorageJob)
 
 	go func() {
 		err = app.Listen(fmt.Sprintf("%s:%d", cfg.Host, cfg.Port))
@@ -148,16 +295,61 @@ func Run() {
 
 	log.Info("Application has started")
 
-	exit := make(chan os.Signal)
+	exit := make(chan os.Signal, 1)
 
 	signal.Notify(exit, os.Interrupt)
 
 	<-exit
 
+	stopJobs()
+	jobs.Wait()
+
 	err = app.Shutdown()
 	if err != nil {
 		log.Fatal(err.Error())
 	}
 
+	events.Wait()
+
 	log.Info("Application has been shut down")
 }
+
+func connectDB(cfg *config.Config) (*pgxpool.Pool, error) {
+	dbCfg, err := pgxpool.ParseConfig(cfg.DBURL)
+	if err != nil {
+		return nil, err
+	}
+
+	db, err := pgxpool.NewWithConfig(context.Background(), dbCfg)
+	if err != nil {
+		return nil, err
+	}
+
+	err = db.Ping(context.Background())
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	return db, nil
+}
+
+func storageConfig(cfg *config.Config) usecase.StorageConfig {
+	return usecase.StorageConfig{
+		OrphanGracePeriod: time.Duration(cfg.StorageOrphanGraceHours) * time.Hour,
+		DeleteOrphans:     cfg.StorageDeleteOrphans,
+	}
+}
+
+func newBlobStore(cfg *config.Config) (adapter.BlobStore, error) {
+	if cfg.StorageDriver == config.StorageDriverS3 {
+		return adapter.NewS3BlobStore(context.Background(), &http.Client{}, adapter.S3Config{
+			Endpoint:        cfg.S3Endpoint,
+			Region:          cfg.S3Region,
+			Bucket:          cfg.S3Bucket,
+			AccessKeyID:     cfg.S3AccessKeyID,
+			SecretAccessKey: cfg.S3SecretAccessKey,
+			PathStyle:       cfg.S3PathStyle,
+		})
+	}
+	return adapter.NewLocalBlobStore(cfg.StorageLocalRoot)
+}
@@ -0,0 +1,78 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	log "github.com/sirupsen/logrus"
+	"news-app-api/config"
+	"news-app-api/internal/adapter"
+	"news-app-api/internal/dto"
+	"news-app-api/internal/usecase"
+	"os"
+	"time"
+)
+
+func CheckStorage(deleteOrphans bool, graceHours int, verbose bool) {
+	log.SetOutput(os.Stderr)
+
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatal(err.Error())
+	}
+
+	db, err := connectDB(cfg)
+	if err != nil {
+		log.Fatal(err.Error())
+	}
+	defer db.Close()
+
+	blobStore, err := newBlobStore(cfg)
+	if err != nil {
+		log.Fatal(err.Error())
+	}
+
+	storageUC := usecase.NewStorageUseCase(adapter.NewStorageRepository(db), blobStore, storageConfig(cfg))
+
+	p := dto.CheckStorageParams{
+		DeleteOrphans: deleteOrphans,
+		GracePeriod:   time.Duration(cfg.StorageOrphanGraceHours) * time.Hour,
+	}
+	if graceHours > 0 {
+		p.GracePeriod = time.Duration(graceHours) * time.Hour
+	}
+
+	res, err := storageUC.CheckStorage(context.Background(), p)
+	if err != nil {
+		log.Fatal(err.Error())
+	}
+
+	if verbose {
+		for _, f := range res.Orphans {
+			fmt.Printf("orphan\t%s\t%d\t%s\n", f.Key, f.Size, f.ModTime.Format(time.RFC3339))
+		}
+		for _, key := range res.Missing {
+			fmt.Printf("missing\t%s\n", key)
+		}
+		for _, m := range res.SizeMismatches {
+			fmt.Printf("size\t%s\texpected %d, got %d\n", m.Key, m.Expected, m.Actual)
+		}
+	}
+
+	mode := "dry run"
+	if deleteOrphans {
+		mode = "delete"
+	}
+	fmt.Printf("Mode:               %s (grace period %s)\n", mode, p.GracePeriod)
+	fmt.Printf("Files:              %d (%d bytes)\n", res.Files, res.Bytes)
+	fmt.Printf("References:         %d\n", res.References)
+	fmt.Printf("Orphans:            %d (%d bytes)\n", len(res.Orphans), res.OrphanBytes)
+	fmt.Printf("Deleted orphans:    %d\n", res.DeletedOrphans)
+	fmt.Printf("Missing files:      %d\n", len(res.Missing))
+	fmt.Printf("Size mismatches:    %d\n", len(res.SizeMismatches))
+	fmt.Printf("Unreferenced blobs: %d\n", res.UnreferencedBlobs)
+
+	if len(res.Missing) > 0 || len(res.SizeMismatches) > 0 {
+		db.Close()
+		os.Exit(1)
+	}
+}
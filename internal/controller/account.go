@@ -0,0 +1,87 @@
+package controller
+
+import (
+	"fmt"
+	"github.com/gofiber/fiber/v2"
+	"news-app-api/internal/dto"
+	"news-app-api/internal/usecase"
+	"time"
+)
+
+type AccountController struct {
+	accountUC usecase.AccountUseCase
+}
+
+func NewAccountController(accountUC usecase.AccountUseCase) *AccountController {
+	return &AccountController{accountUC}
+}
+
+func (c *AccountController) Export() fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		p := dto.ExportAccountParams{
+			UserID: ctx.Locals(userIDKey).(int64),
+		}
+
+		data, err := c.accountUC.Export(ctx.Context(), p)
+		if err != nil {
+			return err
+		}
+
+		ctx.Set(fiber.HeaderContentType, "application/zip")
+		ctx.Set(fiber.HeaderCacheControl, "no-store")
+		ctx.Attachment(fmt.Sprintf("account-%d-%s.zip", p.UserID, time.Now().UTC().Format("20060102")))
+
+		return ctx.Status(fiber.StatusOK).Send(data)
+	}
+}
+
+func (c *AccountController) GetDeletion() fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		userID := ctx.Locals(userIDKey).(int64)
+
+		res, err := c.accountUC.GetDeletion(ctx.Context(), userID)
+		if err != nil {
+			return err
+		}
+
+		return ctx.Status(fiber.StatusOK).JSON(newResponse(res))
+	}
+}
+
+func (c *AccountController) RequestDeletion() fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		var p dto.RequestAccountDeletionParams
+		if err := ctx.BodyParser(&p); err != nil {
+			return ctx.Status(fiber.StatusBadRequest).JSON(newErrResponse(err))
+		}
+
+		p.UserID = ctx.Locals(userIDKey).(int64)
+
+		res, err := c.accountUC.RequestDeletion(ctx.Context(), p)
+		if err != nil {
+			return err
+		}
+
+		return ctx.Status(fiber.StatusAccepted).JSON(newResponse(res))
+	}
+}
+
+func (c *AccountController) CancelDeletion() fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		userID := ctx.Locals(userIDKey).(int64)
+
+		err := c.accountUC.CancelDeletion(ctx.Context(), userID)
+		if err != nil {
+			return err
+		}
+
+		return ctx.SendStatus(fiber.StatusNoContent)
+	}
+}
+
+func (c *AccountController) RegisterRoutes(r fiber.Router, mw *Middleware) {
+	r.Get("me/export", mw.AuthedUser(), c.Export())
+	r.Get("me/deletion", mw.AuthedUser(), c.GetDeletion())
+	r.Post("me/deletion", mw.AuthedUser(), c.RequestDeletion())
+	r.Delete("me/deletion", mw.AuthedUser(), c.CancelDeletion())
+}
@@ -0,0 +1,83 @@
+package controller
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"github.com/gofiber/fiber/v2"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const immutableCacheControl = "public, max-age=31536000, immutable"
+
+type (
+	CacheConfig struct {
+		News  string
+		Image string
+		Audio string
+		Video string
+	}
+)
+
+func setCacheControl(ctx *fiber.Ctx, cacheControl, version string) {
+	if version != "" && ctx.Query("v") == version {
+		cacheControl = immutableCacheControl
+	}
+	if cacheControl != "" {
+		ctx.Set(fiber.HeaderCacheControl, cacheControl)
+	}
+}
+
+func notModified(ctx *fiber.Ctx, etag string, modTime time.Time) bool {
+	if h := ctx.Get(fiber.HeaderIfNoneMatch); h != "" {
+		return etag != "" && etagMatches(h, etag, false)
+	}
+
+	if h := ctx.Get(fiber.HeaderIfModifiedSince); h != "" && !modTime.IsZero() {
+		t, err := http.ParseTime(h)
+		return err == nil && !modTime.Truncate(time.Second).After(t)
+	}
+
+	return false
+}
+
+func etagMatches(h, etag string, strong bool) bool {
+	if strings.TrimSpace(h) == "*" {
+		return true
+	}
+	for _, candidate := range strings.Split(h, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if strings.HasPrefix(candidate, "W/") {
+			if strong {
+				continue
+			}
+			candidate = strings.TrimPrefix(candidate, "W/")
+		}
+		if candidate == etag {
+			return true
+		}
+	}
+	return false
+}
+
+func sendCachedJSON(ctx *fiber.Ctx, status int, v any, cacheControl string) error {
+	body, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	hash := sha256.Sum256(body)
+	etag := `"` + hex.EncodeToString(hash[:]) + `"`
+
+	ctx.Set(fiber.HeaderETag, etag)
+	setCacheControl(ctx, cacheControl, "")
+
+	if notModified(ctx, etag, time.Time{}) {
+		return ctx.SendStatus(fiber.StatusNotModified)
+	}
+
+	ctx.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+	return ctx.Status(status).Send(body)
+}
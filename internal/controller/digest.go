@@ -0,0 +1,114 @@
+package controller
+
+import (
+	"bytes"
+	"github.com/gofiber/fiber/v2"
+	"html/template"
+	"net/url"
+	"news-app-api/internal/dto"
+	"news-app-api/internal/usecase"
+)
+
+var unsubscribeConfirmPage = template.Must(template.New("unsubscribe").Parse(`<!DOCTYPE html>
+<html lang="ru">
+<head>
+    <meta charset="utf-8">
+    <meta name="viewport" content="width=device-width, initial-scale=1">
+    <title>Отписка от рассылки</title>
+</head>
+<body>
+<form method="post" action="{{ . }}">
+    <p>Отписаться от рассылки новостей?</p>
+    <input type="hidden" name="confirm" value="1">
+    <button type="submit">Отписаться</button>
+</form>
+</body>
+</html>
+`))
+
+type DigestController struct {
+	digestUC usecase.DigestUseCase
+}
+
+func NewDigestController(digestUC usecase.DigestUseCase) *DigestController {
+	return &DigestController{digestUC}
+}
+
+func (c *DigestController) GetPreference() fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		userID := ctx.Locals(userIDKey).(int64)
+
+		res, err := c.digestUC.GetPreference(ctx.Context(), userID)
+		if err != nil {
+			return err
+		}
+
+		return ctx.Status(fiber.StatusOK).JSON(newResponse(res))
+	}
+}
+
+func (c *DigestController) UpdatePreference() fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		var p dto.UpdateDigestPreferenceParams
+		if err := ctx.BodyParser(&p); err != nil {
+			return ctx.Status(fiber.StatusBadRequest).JSON(newErrResponse(err))
+		}
+
+		p.UserID = ctx.Locals(userIDKey).(int64)
+
+		res, err := c.digestUC.UpdatePreference(ctx.Context(), p)
+		if err != nil {
+			return err
+		}
+
+		return ctx.Status(fiber.StatusOK).JSON(newResponse(res))
+	}
+}
+
+// ConfirmUnsubscribe shows a form instead of unsubscribing right away, so that link scanners following the URL from
+// the email don't unsubscribe the user.
+func (c *DigestController) ConfirmUnsubscribe() fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		var p dto.UnsubscribeDigestParams
+		if err := ctx.QueryParser(&p); err != nil {
+			return ctx.Status(fiber.StatusBadRequest).JSON(newErrResponse(err))
+		}
+
+		var buf bytes.Buffer
+		err := unsubscribeConfirmPage.Execute(&buf, "?token="+url.QueryEscape(p.Token))
+		if err != nil {
+			return err
+		}
+
+		ctx.Set(fiber.HeaderCacheControl, "no-store")
+		ctx.Type("html", "utf-8")
+		return ctx.Status(fiber.StatusOK).Send(buf.Bytes())
+	}
+}
+
+func (c *DigestController) Unsubscribe() fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		var p dto.UnsubscribeDigestParams
+		if err := ctx.QueryParser(&p); err != nil {
+			return ctx.Status(fiber.StatusBadRequest).JSON(newErrResponse(err))
+		}
+
+		err := c.digestUC.Unsubscribe(ctx.Context(), p)
+		if err != nil {
+			return err
+		}
+
+		if ctx.FormValue("confirm") == "" {
+			return ctx.SendStatus(fiber.StatusNoContent)
+		}
+
+		return ctx.Status(fiber.StatusOK).SendString("Вы отписались от рассылки новостей.")
+	}
+}
+
+func (c *DigestController) RegisterRoutes(r fiber.Router, mw *Middleware) {
+	r.Get("", mw.AuthedUser(), c.GetPreference())
+	r.Put("", mw.AuthedUser(), c.UpdatePreference())
+	r.Get("unsubscribe", c.ConfirmUnsubscribe())
+	r.Post("unsubscribe", c.Unsubscribe())
+}
@@ -32,6 +32,142 @@ func (c *FeedController) GetFeed() fiber.Handler {
 	}
 }
 
+func (c *FeedController) MarkNewsRead() fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		var p dto.MarkFeedNewsReadParams
+		if err := ctx.ParamsParser(&p); err != nil {
+			return ctx.Status(fiber.StatusBadRequest).JSON(newErrResponse(err))
+		}
+
+		p.UserID = ctx.Locals(userIDKey).(int64)
+
+		err := c.feedUC.MarkNewsRead(ctx.Context(), p)
+		if err != nil {
+			return err
+		}
+
+		return ctx.SendStatus(fiber.StatusNoContent)
+	}
+}
+
+func (c *FeedController) HideNews() fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		var p dto.HideNewsParams
+		if err := ctx.ParamsParser(&p); err != nil {
+			return ctx.Status(fiber.StatusBadRequest).JSON(newErrResponse(err))
+		}
+
+		p.UserID = ctx.Locals(userIDKey).(int64)
+
+		err := c.feedUC.HideNews(ctx.Context(), p)
+		if err != nil {
+			return err
+		}
+
+		return ctx.SendStatus(fiber.StatusNoContent)
+	}
+}
+
+func (c *FeedController) UnhideNews() fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		var p dto.UnhideNewsParams
+		if err := ctx.ParamsParser(&p); err != nil {
+			return ctx.Status(fiber.StatusBadRequest).JSON(newErrResponse(err))
+		}
+
+		p.UserID = ctx.Locals(userIDKey).(int64)
+
+		err := c.feedUC.UnhideNews(ctx.Context(), p)
+		if err != nil {
+			return err
+		}
+
+		return ctx.SendStatus(fiber.StatusNoContent)
+	}
+}
+
+func (c *FeedController) GetHiddenNewsList() fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		var p dto.GetHiddenNewsListParams
+		if err := ctx.QueryParser(&p); err != nil {
+			return ctx.Status(fiber.StatusBadRequest).JSON(newErrResponse(err))
+		}
+
+		p.UserID = ctx.Locals(userIDKey).(int64)
+
+		res, err := c.feedUC.GetHiddenNewsList(ctx.Context(), p)
+		if err != nil {
+			return err
+		}
+
+		return ctx.Status(fiber.StatusOK).JSON(newResponse(res))
+	}
+}
+
+func (c *FeedController) MuteMedia() fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		var p dto.MuteMediaParams
+		if err := ctx.ParamsParser(&p); err != nil {
+			return ctx.Status(fiber.StatusBadRequest).JSON(newErrResponse(err))
+		}
+		if err := ctx.BodyParser(&p); err != nil {
+			return ctx.Status(fiber.StatusBadRequest).JSON(newErrResponse(err))
+		}
+
+		p.UserID = ctx.Locals(userIDKey).(int64)
+
+		err := c.feedUC.MuteMedia(ctx.Context(), p)
+		if err != nil {
+			return err
+		}
+
+		return ctx.SendStatus(fiber.StatusNoContent)
+	}
+}
+
+func (c *FeedController) UnmuteMedia() fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		var p dto.UnmuteMediaParams
+		if err := ctx.ParamsParser(&p); err != nil {
+			return ctx.Status(fiber.StatusBadRequest).JSON(newErrResponse(err))
+		}
+
+		p.UserID = ctx.Locals(userIDKey).(int64)
+
+		err := c.feedUC.UnmuteMedia(ctx.Context(), p)
+		if err != nil {
+			return err
+		}
+
+		return ctx.SendStatus(fiber.StatusNoContent)
+	}
+}
+
+func (c *FeedController) GetMutedMediaList() fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		var p dto.GetMutedMediaListParams
+		if err := ctx.QueryParser(&p); err != nil {
+			return ctx.Status(fiber.StatusBadRequest).JSON(newErrResponse(err))
+		}
+
+		p.UserID = ctx.Locals(userIDKey).(int64)
+
+		res, err := c.feedUC.GetMutedMediaList(ctx.Context(), p)
+		if err != nil {
+			return err
+		}
+
+		return ctx.Status(fiber.StatusOK).JSON(newResponse(res))
+	}
+}
+
 func (c *FeedController) RegisterRoutes(r fiber.Router, mw *Middleware) {
 	r.Get("", mw.AuthedUser(), c.GetFeed())
+	r.Put("read/:news_id", mw.AuthedUser(), c.MarkNewsRead())
+	r.Get("hidden", mw.AuthedUser(), c.GetHiddenNewsList())
+	r.Put("hidden/:news_id", mw.AuthedUser(), c.HideNews())
+	r.Delete("hidden/:news_id", mw.AuthedUser(), c.UnhideNews())
+	r.Get("muted", mw.AuthedUser(), c.GetMutedMediaList())
+	r.Put("muted/:media_id", mw.AuthedUser(), c.MuteMedia())
+	r.Delete("muted/:media_id", mw.AuthedUser(), c.UnmuteMedia())
 }
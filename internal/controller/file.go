@@ -0,0 +1,173 @@
+package controller
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"github.com/gofiber/fiber/v2"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"news-app-api/internal/dto"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const maxFormValueSize = 64 << 10
+
+type (
+	fileSection struct {
+		*io.SectionReader
+		io.Closer
+	}
+)
+
+func formFile(ctx *fiber.Ctx, field string) (io.Reader, error) {
+	file, _, err := formFileWithValues(ctx, field)
+	return file, err
+}
+
+func formFileWithValues(ctx *fiber.Ctx, field string) (io.Reader, map[string]string, error) {
+	boundary := string(ctx.Request().Header.MultipartFormBoundary())
+	if boundary == "" {
+		return nil, nil, errors.New("request Content-Type isn't multipart/form-data")
+	}
+
+	body := ctx.Context().RequestBodyStream()
+	if body == nil {
+		body = bytes.NewReader(ctx.Body())
+	}
+
+	values := map[string]string{}
+	mr := multipart.NewReader(body, boundary)
+	for {
+		part, err := mr.NextPart()
+		if err == io.EOF {
+			return nil, nil, fmt.Errorf("missing form file %q", field)
+		} else if err != nil {
+			return nil, nil, err
+		}
+
+		if part.FormName() == field && part.FileName() != "" {
+			return part, values, nil
+		}
+
+		if part.FileName() == "" {
+			value, err := io.ReadAll(io.LimitReader(part, maxFormValueSize+1))
+			if err != nil {
+				return nil, nil, err
+			}
+			if len(value) > maxFormValueSize {
+				return nil, nil, fmt.Errorf("form value %q is too large", part.FormName())
+			}
+			values[part.FormName()] = string(value)
+		}
+	}
+}
+
+func sendFile(ctx *fiber.Ctx, res dto.GetAttachmentResult, cacheControl string) error {
+	f := res.File
+	size := f.Size()
+
+	modTime := f.ModTime()
+	if res.UpdatedAt > 0 {
+		modTime = time.Unix(res.UpdatedAt, 0)
+	}
+
+	etag := ""
+	if res.ContentHash != "" {
+		etag = `"` + res.ContentHash + `"`
+		ctx.Set(fiber.HeaderETag, etag)
+	}
+	ctx.Set(fiber.HeaderAcceptRanges, "bytes")
+	ctx.Set(fiber.HeaderLastModified, modTime.UTC().Format(http.TimeFormat))
+	setCacheControl(ctx, cacheControl, res.Version)
+	if res.ExpiresAt > 0 {
+		ctx.Set(fiber.HeaderCacheControl, fmt.Sprintf("public, max-age=%d", res.ExpiresAt-time.Now().Unix()))
+	}
+
+	if notModified(ctx, etag, modTime) {
+		f.Close()
+		return ctx.SendStatus(fiber.StatusNotModified)
+	}
+
+	start, length, status := int64(0), size, fiber.StatusOK
+	if h := ctx.Get(fiber.HeaderRange); h != "" && ifRangeMatches(ctx.Get(fiber.HeaderIfRange), etag, modTime) {
+		start, length, status = parseByteRange(h, size)
+		switch status {
+		case fiber.StatusRequestedRangeNotSatisfiable:
+			f.Close()
+			ctx.Set(fiber.HeaderContentRange, fmt.Sprintf("bytes */%d", size))
+			return ctx.SendStatus(status)
+		case fiber.StatusPartialContent:
+			ctx.Set(fiber.HeaderContentRange, fmt.Sprintf("bytes %d-%d/%d", start, start+length-1, size))
+		default:
+			start, length = 0, size
+		}
+	}
+
+	ctx.Set(fiber.HeaderContentType, res.ContentType)
+	return ctx.Status(status).SendStream(fileSection{io.NewSectionReader(f, start, length), f}, int(length))
+}
+
+func ifRangeMatches(h, etag string, modTime time.Time) bool {
+	if h == "" {
+		return true
+	}
+	if strings.HasPrefix(h, `"`) || strings.HasPrefix(h, "W/") {
+		return etag != "" && h == etag
+	}
+	t, err := http.ParseTime(h)
+	if err != nil {
+		return false
+	}
+	return modTime.Truncate(time.Second).Equal(t)
+}
+
+func parseByteRange(h string, size int64) (start, length int64, status int) {
+	if !strings.HasPrefix(h, "bytes=") || strings.Contains(h, ",") {
+		return 0, size, fiber.StatusOK
+	}
+
+	first, last, ok := strings.Cut(strings.TrimSpace(strings.TrimPrefix(h, "bytes=")), "-")
+	if !ok {
+		return 0, size, fiber.StatusOK
+	}
+
+	if first == "" {
+		n, err := strconv.ParseInt(last, 10, 64)
+		if err != nil || n < 0 {
+			return 0, size, fiber.StatusOK
+		}
+		if n == 0 || size == 0 {
+			return 0, 0, fiber.StatusRequestedRangeNotSatisfiable
+		}
+		if n > size {
+			n = size
+		}
+		return size - n, n, fiber.StatusPartialContent
+	}
+
+	start, err := strconv.ParseInt(first, 10, 64)
+	if err != nil || start < 0 {
+		return 0, size, fiber.StatusOK
+	}
+
+	end := size - 1
+	if last != "" {
+		end, err = strconv.ParseInt(last, 10, 64)
+		if err != nil || end < start {
+			return 0, size, fiber.StatusOK
+		}
+		if end >= size {
+			end = size - 1
+		}
+	}
+
+	if start >= size {
+		return 0, 0, fiber.StatusRequestedRangeNotSatisfiable
+	}
+
+	return start, end - start + 1, fiber.StatusPartialContent
+}
@@ -0,0 +1,135 @@
+package controller
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"news-app-api/internal/dto"
+	"strings"
+)
+
+func (c *NewsController) AddGalleryImage() fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		var p dto.AddGalleryImageParams
+		if err := ctx.ParamsParser(&p); err != nil {
+			return ctx.Status(fiber.StatusBadRequest).JSON(newErrResponse(err))
+		}
+
+		p.MediaID = ctx.Locals(mediaIDKey).(int64)
+
+		file, values, err := formFileWithValues(ctx, "file")
+		if err != nil {
+			return ctx.Status(fiber.StatusBadRequest).JSON(newErrResponse(err))
+		}
+		p.File = file
+		p.Caption = values["caption"]
+		p.AltText = values["altText"]
+		p.Credit = values["credit"]
+
+		res, err := c.newsUC.AddGalleryImage(ctx.Context(), p)
+		if err != nil {
+			return err
+		}
+
+		return ctx.Status(fiber.StatusCreated).JSON(newResponse(res))
+	}
+}
+
+func (c *NewsController) GetGallery() fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		var p dto.GetGalleryParams
+		if err := ctx.ParamsParser(&p); err != nil {
+			return ctx.Status(fiber.StatusBadRequest).JSON(newErrResponse(err))
+		}
+
+		res, err := c.newsUC.GetGallery(ctx.Context(), p)
+		if err != nil {
+			return err
+		}
+
+		return sendCachedJSON(ctx, fiber.StatusOK, newResponse(res), c.cache.News)
+	}
+}
+
+func (c *NewsController) GetGalleryImage() fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		var p dto.GetGalleryImageParams
+		if err := ctx.ParamsParser(&p); err != nil {
+			return ctx.Status(fiber.StatusBadRequest).JSON(newErrResponse(err))
+		}
+		if err := ctx.QueryParser(&p); err != nil {
+			return ctx.Status(fiber.StatusBadRequest).JSON(newErrResponse(err))
+		}
+
+		p.AcceptsWebP = strings.Contains(ctx.Get(fiber.HeaderAccept), "image/webp")
+
+		res, err := c.newsUC.GetGalleryImage(ctx.Context(), p)
+		if err != nil {
+			return err
+		}
+
+		if !p.Format.Valid && (p.Width.Valid || p.Preset.Valid) {
+			ctx.Vary(fiber.HeaderAccept)
+		}
+
+		return sendFile(ctx, res, c.cache.Image)
+	}
+}
+
+func (c *NewsController) UpdateGalleryImage() fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		var p dto.UpdateGalleryImageParams
+		if err := ctx.ParamsParser(&p); err != nil {
+			return ctx.Status(fiber.StatusBadRequest).JSON(newErrResponse(err))
+		}
+		if err := ctx.BodyParser(&p); err != nil {
+			return ctx.Status(fiber.StatusBadRequest).JSON(newErrResponse(err))
+		}
+
+		p.MediaID = ctx.Locals(mediaIDKey).(int64)
+
+		res, err := c.newsUC.UpdateGalleryImage(ctx.Context(), p)
+		if err != nil {
+			return err
+		}
+
+		return ctx.Status(fiber.StatusOK).JSON(newResponse(res))
+	}
+}
+
+func (c *NewsController) ReorderGallery() fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		var p dto.ReorderGalleryParams
+		if err := ctx.ParamsParser(&p); err != nil {
+			return ctx.Status(fiber.StatusBadRequest).JSON(newErrResponse(err))
+		}
+		if err := ctx.BodyParser(&p); err != nil {
+			return ctx.Status(fiber.StatusBadRequest).JSON(newErrResponse(err))
+		}
+
+		p.MediaID = ctx.Locals(mediaIDKey).(int64)
+
+		res, err := c.newsUC.ReorderGallery(ctx.Context(), p)
+		if err != nil {
+			return err
+		}
+
+		return ctx.Status(fiber.StatusOK).JSON(newResponse(res))
+	}
+}
+
+func (c *NewsController) DeleteGalleryImage() fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		var p dto.DeleteGalleryImageParams
+		if err := ctx.ParamsParser(&p); err != nil {
+			return ctx.Status(fiber.StatusBadRequest).JSON(newErrResponse(err))
+		}
+
+		p.MediaID = ctx.Locals(mediaIDKey).(int64)
+
+		err := c.newsUC.DeleteGalleryImage(ctx.Context(), p)
+		if err != nil {
+			return err
+		}
+
+		return ctx.SendStatus(fiber.StatusNoContent)
+	}
+}
@@ -100,6 +100,24 @@ func (c *MediaController) Authenticate() fiber.Handler {
 	}
 }
 
+func (c *MediaController) UpdateAccount() fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		var p dto.UpdateMediaAccountParams
+		if err := ctx.BodyParser(&p); err != nil {
+			return ctx.Status(fiber.StatusBadRequest).JSON(newErrResponse(err))
+		}
+
+		p.MediaID = ctx.Locals(mediaIDKey).(int64)
+
+		res, err := c.mediaUC.UpdateAccount(ctx.Context(), p)
+		if err != nil {
+			return err
+		}
+
+		return ctx.Status(fiber.StatusOK).JSON(newResponse(res))
+	}
+}
+
 func (c *MediaController) GetMediaList() fiber.Handler {
 	return func(ctx *fiber.Ctx) error {
 		var p dto.GetMediaListParams
@@ -107,6 +125,8 @@ func (c *MediaController) GetMediaList() fiber.Handler {
 			return ctx.Status(fiber.StatusBadRequest).JSON(newErrResponse(err))
 		}
 
+		p.UserID, _ = ctx.Locals(userIDKey).(int64)
+
 		res, err := c.mediaUC.GetMediaList(ctx.Context(), p)
 		if err != nil {
 			return err
@@ -155,12 +175,144 @@ func (c *MediaController) GetNewsList() fiber.Handler {
 	}
 }
 
+func (c *MediaController) GetProfile() fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		var p dto.GetMediaProfileParams
+		if err := ctx.ParamsParser(&p); err != nil {
+			return ctx.Status(fiber.StatusBadRequest).JSON(newErrResponse(err))
+		}
+
+		p.UserID, _ = ctx.Locals(userIDKey).(int64)
+
+		res, err := c.mediaUC.GetProfile(ctx.Context(), p)
+		if err != nil {
+			return err
+		}
+
+		return ctx.Status(fiber.StatusOK).JSON(newResponse(res))
+	}
+}
+
+func (c *MediaController) GetOwnProfile() fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		p := dto.GetMediaProfileParams{MediaID: ctx.Locals(mediaIDKey).(int64)}
+
+		res, err := c.mediaUC.GetProfile(ctx.Context(), p)
+		if err != nil {
+			return err
+		}
+
+		return ctx.Status(fiber.StatusOK).JSON(newResponse(res))
+	}
+}
+
+func (c *MediaController) UpdateProfile() fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		var p dto.UpdateMediaProfileParams
+		if err := ctx.BodyParser(&p); err != nil {
+			return ctx.Status(fiber.StatusBadRequest).JSON(newErrResponse(err))
+		}
+
+		p.MediaID = ctx.Locals(mediaIDKey).(int64)
+
+		res, err := c.mediaUC.UpdateProfile(ctx.Context(), p)
+		if err != nil {
+			return err
+		}
+
+		return ctx.Status(fiber.StatusOK).JSON(newResponse(res))
+	}
+}
+
+func (c *MediaController) UploadImage(kind string) fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		p := dto.UploadMediaImageParams{Kind: kind}
+
+		var err error
+		p.File, err = formFile(ctx, "file")
+		if err != nil {
+			return ctx.Status(fiber.StatusBadRequest).JSON(newErrResponse(err))
+		}
+
+		p.MediaID = ctx.Locals(mediaIDKey).(int64)
+
+		res, err := c.mediaUC.UploadImage(ctx.Context(), p)
+		if err != nil {
+			return err
+		}
+
+		return ctx.Status(fiber.StatusOK).JSON(newResponse(res))
+	}
+}
+
+func (c *MediaController) GetImage(kind string) fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		p := dto.GetMediaImageParams{Kind: kind}
+		if err := ctx.ParamsParser(&p); err != nil {
+			return ctx.Status(fiber.StatusBadRequest).JSON(newErrResponse(err))
+		}
+
+		f, contentType, err := c.mediaUC.GetImage(ctx.Context(), p)
+		if err != nil {
+			return err
+		}
+
+		return sendFile(ctx, dto.GetAttachmentResult{File: f, ContentType: contentType}, "")
+	}
+}
+
+func (c *MediaController) GetSubscriberList() fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		var p dto.GetSubscriberListParams
+		if err := ctx.QueryParser(&p); err != nil {
+			return ctx.Status(fiber.StatusBadRequest).JSON(newErrResponse(err))
+		}
+
+		p.MediaID = ctx.Locals(mediaIDKey).(int64)
+
+		res, err := c.mediaUC.GetSubscriberList(ctx.Context(), p)
+		if err != nil {
+			return err
+		}
+
+		return ctx.Status(fiber.StatusOK).JSON(newResponse(res))
+	}
+}
+
+func (c *MediaController) GetSubscriptionStats() fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		var p dto.GetSubscriptionStatsParams
+		if err := ctx.QueryParser(&p); err != nil {
+			return ctx.Status(fiber.StatusBadRequest).JSON(newErrResponse(err))
+		}
+
+		p.MediaID = ctx.Locals(mediaIDKey).(int64)
+
+		res, err := c.mediaUC.GetSubscriptionStats(ctx.Context(), p)
+		if err != nil {
+			return err
+		}
+
+		return ctx.Status(fiber.StatusOK).JSON(newResponse(res))
+	}
+}
+
 func (c *MediaController) RegisterRoutes(r fiber.Router, mw *Middleware) {
 	r.Post("register", c.Register())
 	r.Post("login", c.Login())
 	r.Post("logout", c.Logout())
 	r.Post("authenticate", mw.AuthedMedia(), c.Authenticate())
-	r.Get("", c.GetMediaList())
+	r.Get("", mw.OptionalAuthedUser(), c.GetMediaList())
+	r.Get("me", mw.AuthedMedia(), c.GetOwnProfile())
+	r.Put("me", mw.AuthedMedia(), c.UpdateProfile())
+	r.Put("me/account", mw.AuthedMedia(), c.UpdateAccount())
+	r.Put("me/logo", mw.AuthedMedia(), c.UploadImage(dto.MediaImageLogo))
+	r.Put("me/cover", mw.AuthedMedia(), c.UploadImage(dto.MediaImageCover))
+	r.Get("me/subscribers", mw.AuthedMedia(), c.GetSubscriberList())
+	r.Get("me/subscribers/stats", mw.AuthedMedia(), c.GetSubscriptionStats())
+	r.Get(":media_id", mw.OptionalAuthedUser(), c.GetProfile())
+	r.Get(":media_id/logo", c.GetImage(dto.MediaImageLogo))
+	r.Get(":media_id/cover", c.GetImage(dto.MediaImageCover))
 	r.Post(":media_id/toggle-subscription", mw.AuthedUser(), c.ToggleSubscription())
 	r.Get(":media_id/news", mw.OptionalAuthedUser(), c.GetNewsList())
 }
@@ -1,18 +1,19 @@
 package controller
 
 import (
-	"fmt"
 	"github.com/gofiber/fiber/v2"
 	"news-app-api/internal/dto"
 	"news-app-api/internal/usecase"
+	"strings"
 )
 
 type NewsController struct {
 	newsUC usecase.NewsUseCase
+	cache  CacheConfig
 }
 
-func NewNewsController(newsUC usecase.NewsUseCase) *NewsController {
-	return &NewsController{newsUC}
+func NewNewsController(newsUC usecase.NewsUseCase, cache CacheConfig) *NewsController {
+	return &NewsController{newsUC, cache}
 }
 
 func (c *NewsController) CreateNews() fiber.Handler {
@@ -42,17 +43,12 @@ func (c *NewsController) CreateOrUpdateAudio() fiber.Handler {
 
 		p.MediaID = ctx.Locals(mediaIDKey).(int64)
 
-		f, err := ctx.FormFile("file")
+		var err error
+		p.File, err = formFile(ctx, "file")
 		if err != nil {
 			return ctx.Status(fiber.StatusBadRequest).JSON(newErrResponse(err))
 		}
 
-		p.File, err = f.Open()
-		if err != nil {
-			return ctx.Status(fiber.StatusBadRequest).JSON(newErrResponse(err))
-		}
-		defer p.File.Close()
-
 		err = c.newsUC.CreateOrUpdateAudio(ctx.Context(), p)
 		if err != nil {
 			return err
@@ -68,54 +64,87 @@ func (c *NewsController) GetAudio() fiber.Handler {
 		if err := ctx.ParamsParser(&p); err != nil {
 			return ctx.Status(fiber.StatusBadRequest).JSON(newErrResponse(err))
 		}
+		if err := ctx.QueryParser(&p); err != nil {
+			return ctx.Status(fiber.StatusBadRequest).JSON(newErrResponse(err))
+		}
 
-		audio, err := c.newsUC.GetAudio(ctx.Context(), p)
+		res, err := c.newsUC.GetAudio(ctx.Context(), p)
 		if err != nil {
 			return err
 		}
 
-		ctx.Set("content-length", fmt.Sprint(len(audio)))
-		ctx.Set("content-type", "application/x-wav")
-		return ctx.Status(fiber.StatusOK).Send(audio)
+		return sendFile(ctx, res, c.cache.Audio)
 	}
 }
 
-func (c *NewsController) GetNews() fiber.Handler {
+func (c *NewsController) GetAudioWaveform() fiber.Handler {
 	return func(ctx *fiber.Ctx) error {
-		var p dto.GetNewsParams
+		var p dto.GetAudioWaveformParams
 		if err := ctx.ParamsParser(&p); err != nil {
 			return ctx.Status(fiber.StatusBadRequest).JSON(newErrResponse(err))
 		}
+		if err := ctx.QueryParser(&p); err != nil {
+			return ctx.Status(fiber.StatusBadRequest).JSON(newErrResponse(err))
+		}
 
-		news, err := c.newsUC.GetNews(ctx.Context(), p)
+		waveform, err := c.newsUC.GetAudioWaveform(ctx.Context(), p)
 		if err != nil {
 			return err
 		}
 
-		return ctx.Status(fiber.StatusOK).JSON(newResponse(news))
+		return sendCachedJSON(ctx, fiber.StatusOK, newResponse(waveform), c.cache.News)
 	}
 }
 
-func (c *NewsController) CreateOrUpdateImage() fiber.Handler {
+func (c *NewsController) GetNews() fiber.Handler {
 	return func(ctx *fiber.Ctx) error {
-		var p dto.CreateOrUpdateImageParams
+		var p dto.GetNewsParams
 		if err := ctx.ParamsParser(&p); err != nil {
 			return ctx.Status(fiber.StatusBadRequest).JSON(newErrResponse(err))
 		}
 
-		f, err := ctx.FormFile("file")
+		news, err := c.newsUC.GetNews(ctx.Context(), p)
 		if err != nil {
 			return err
 		}
 
-		p.File, err = f.Open()
+		return sendCachedJSON(ctx, fiber.StatusOK, newResponse(news), c.cache.News)
+	}
+}
+
+func (c *NewsController) SearchNews() fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		var p dto.SearchNewsParams
+		if err := ctx.QueryParser(&p); err != nil {
+			return ctx.Status(fiber.StatusBadRequest).JSON(newErrResponse(err))
+		}
+
+		p.UserID, _ = ctx.Locals(userIDKey).(int64)
+
+		res, err := c.newsUC.SearchNews(ctx.Context(), p)
 		if err != nil {
 			return err
 		}
-		defer p.File.Close()
+
+		return ctx.Status(fiber.StatusOK).JSON(newResponse(res))
+	}
+}
+
+func (c *NewsController) CreateOrUpdateImage() fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		var p dto.CreateOrUpdateImageParams
+		if err := ctx.ParamsParser(&p); err != nil {
+			return ctx.Status(fiber.StatusBadRequest).JSON(newErrResponse(err))
+		}
 
 		p.MediaID = ctx.Locals(mediaIDKey).(int64)
 
+		var err error
+		p.File, err = formFile(ctx, "file")
+		if err != nil {
+			return ctx.Status(fiber.StatusBadRequest).JSON(newErrResponse(err))
+		}
+
 		err = c.newsUC.CreateOrUpdateImage(ctx.Context(), p)
 		if err != nil {
 			return err
@@ -131,15 +160,22 @@ func (c *NewsController) GetImage() fiber.Handler {
 		if err := ctx.ParamsParser(&p); err != nil {
 			return ctx.Status(fiber.StatusBadRequest).JSON(newErrResponse(err))
 		}
+		if err := ctx.QueryParser(&p); err != nil {
+			return ctx.Status(fiber.StatusBadRequest).JSON(newErrResponse(err))
+		}
+
+		p.AcceptsWebP = strings.Contains(ctx.Get(fiber.HeaderAccept), "image/webp")
 
-		data, err := c.newsUC.GetImage(ctx.Context(), p)
+		res, err := c.newsUC.GetImage(ctx.Context(), p)
 		if err != nil {
 			return err
 		}
 
-		ctx.Set("content-length", fmt.Sprint(len(data)))
-		ctx.Set("content-type", "image/png")
-		return ctx.Status(fiber.StatusOK).Send(data)
+		if !p.Format.Valid && (p.Width.Valid || p.Preset.Valid) {
+			ctx.Vary(fiber.HeaderAccept)
+		}
+
+		return sendFile(ctx, res, c.cache.Image)
 	}
 }
 
@@ -170,18 +206,16 @@ func (c *NewsController) CreateOrUpdateVideo() fiber.Handler {
 
 		p.MediaID = ctx.Locals(mediaIDKey).(int64)
 
-		f, err := ctx.FormFile("file")
+		var err error
+		p.File, err = formFile(ctx, "file")
 		if err != nil {
-			return err
+			return ctx.Status(fiber.StatusBadRequest).JSON(newErrResponse(err))
 		}
 
-		p.File, err = f.Open()
+		err = c.newsUC.CreateOrUpdateVideo(ctx.Context(), p)
 		if err != nil {
 			return err
 		}
-		defer p.File.Close()
-
-		err = c.newsUC.CreateOrUpdateVideo(ctx.Context(), p)
 
 		return ctx.SendStatus(fiber.StatusNoContent)
 	}
@@ -193,25 +227,34 @@ func (c *NewsController) GetVideo() fiber.Handler {
 		if err := ctx.ParamsParser(&p); err != nil {
 			return ctx.Status(fiber.StatusBadRequest).JSON(newErrResponse(err))
 		}
+		if err := ctx.QueryParser(&p); err != nil {
+			return ctx.Status(fiber.StatusBadRequest).JSON(newErrResponse(err))
+		}
 
-		data, err := c.newsUC.GetVideo(ctx.Context(), p)
+		res, err := c.newsUC.GetVideo(ctx.Context(), p)
 		if err != nil {
 			return err
 		}
 
-		ctx.Set("content-length", fmt.Sprint(len(data)))
-		ctx.Set("content-type", "video/mp4")
-		return ctx.Status(fiber.StatusOK).Send(data)
+		return sendFile(ctx, res, c.cache.Video)
 	}
 }
 
 func (c *NewsController) RegisterRoutes(r fiber.Router, mw *Middleware) {
 	r.Post("", mw.AuthedMedia(), c.CreateNews())
+	r.Get("search", mw.OptionalAuthedUser(), c.SearchNews())
 	r.Put(":news_id/audio", mw.AuthedMedia(), c.CreateOrUpdateAudio())
 	r.Get(":news_id/audio", c.GetAudio())
+	r.Get(":news_id/audio/waveform", c.GetAudioWaveform())
 	r.Get(":news_id", c.GetNews())
 	r.Put(":news_id/image", mw.AuthedMedia(), c.CreateOrUpdateImage())
 	r.Get(":news_id/image", c.GetImage())
+	r.Post(":news_id/gallery", mw.AuthedMedia(), c.AddGalleryImage())
+	r.Get(":news_id/gallery", c.GetGallery())
+	r.Put(":news_id/gallery/order", mw.AuthedMedia(), c.ReorderGallery())
+	r.Get(":news_id/gallery/:image_id", c.GetGalleryImage())
+	r.Patch(":news_id/gallery/:image_id", mw.AuthedMedia(), c.UpdateGalleryImage())
+	r.Delete(":news_id/gallery/:image_id", mw.AuthedMedia(), c.DeleteGalleryImage())
 	r.Post(":news_id/toggle-favorite", mw.AuthedUser(), c.ToggleFavorite())
 	r.Put(":news_id/video", mw.AuthedMedia(), c.CreateOrUpdateVideo())
 	r.Get(":news_id/video", c.GetVideo())
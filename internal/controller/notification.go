@@ -0,0 +1,117 @@
+package controller
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"news-app-api/internal/dto"
+	"news-app-api/internal/usecase"
+)
+
+type NotificationController struct {
+	notificationUC usecase.NotificationUseCase
+}
+
+func NewNotificationController(notificationUC usecase.NotificationUseCase) *NotificationController {
+	return &NotificationController{notificationUC}
+}
+
+func (c *NotificationController) GetNotificationList() fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		var p dto.GetNotificationListParams
+		if err := ctx.QueryParser(&p); err != nil {
+			return ctx.Status(fiber.StatusBadRequest).JSON(newErrResponse(err))
+		}
+
+		p.UserID = ctx.Locals(userIDKey).(int64)
+
+		res, err := c.notificationUC.GetNotificationList(ctx.Context(), p)
+		if err != nil {
+			return err
+		}
+
+		return ctx.Status(fiber.StatusOK).JSON(newResponse(res))
+	}
+}
+
+func (c *NotificationController) GetUnreadCount() fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		userID := ctx.Locals(userIDKey).(int64)
+
+		res, err := c.notificationUC.GetUnreadCount(ctx.Context(), userID)
+		if err != nil {
+			return err
+		}
+
+		return ctx.Status(fiber.StatusOK).JSON(newResponse(res))
+	}
+}
+
+func (c *NotificationController) MarkRead() fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		var p dto.MarkNotificationReadParams
+		if err := ctx.ParamsParser(&p); err != nil {
+			return ctx.Status(fiber.StatusBadRequest).JSON(newErrResponse(err))
+		}
+
+		p.UserID = ctx.Locals(userIDKey).(int64)
+
+		err := c.notificationUC.MarkRead(ctx.Context(), p)
+		if err != nil {
+			return err
+		}
+
+		return ctx.SendStatus(fiber.StatusNoContent)
+	}
+}
+
+func (c *NotificationController) MarkAllRead() fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		userID := ctx.Locals(userIDKey).(int64)
+
+		err := c.notificationUC.MarkAllRead(ctx.Context(), userID)
+		if err != nil {
+			return err
+		}
+
+		return ctx.SendStatus(fiber.StatusNoContent)
+	}
+}
+
+func (c *NotificationController) GetPreferenceList() fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		userID := ctx.Locals(userIDKey).(int64)
+
+		res, err := c.notificationUC.GetPreferenceList(ctx.Context(), userID)
+		if err != nil {
+			return err
+		}
+
+		return ctx.Status(fiber.StatusOK).JSON(newResponse(res))
+	}
+}
+
+func (c *NotificationController) UpdatePreferenceList() fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		var p dto.UpdateNotificationPreferenceListParams
+		if err := ctx.BodyParser(&p); err != nil {
+			return ctx.Status(fiber.StatusBadRequest).JSON(newErrResponse(err))
+		}
+
+		p.UserID = ctx.Locals(userIDKey).(int64)
+
+		res, err := c.notificationUC.UpdatePreferenceList(ctx.Context(), p)
+		if err != nil {
+			return err
+		}
+
+		return ctx.Status(fiber.StatusOK).JSON(newResponse(res))
+	}
+}
+
+func (c *NotificationController) RegisterRoutes(r fiber.Router, mw *Middleware) {
+	r.Get("", mw.AuthedUser(), c.GetNotificationList())
+	r.Get("unread-count", mw.AuthedUser(), c.GetUnreadCount())
+	r.Put("read", mw.AuthedUser(), c.MarkAllRead())
+	r.Get("preferences", mw.AuthedUser(), c.GetPreferenceList())
+	r.Put("preferences", mw.AuthedUser(), c.UpdatePreferenceList())
+	r.Put(":notification_id/read", mw.AuthedUser(), c.MarkRead())
+}
@@ -0,0 +1,63 @@
+package controller
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"news-app-api/internal/dto"
+	"news-app-api/internal/usecase"
+)
+
+type PushController struct {
+	pushUC usecase.PushUseCase
+}
+
+func NewPushController(pushUC usecase.PushUseCase) *PushController {
+	return &PushController{pushUC}
+}
+
+func (c *PushController) GetVAPIDPublicKey() fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		return ctx.Status(fiber.StatusOK).JSON(newResponse(c.pushUC.GetVAPIDPublicKey()))
+	}
+}
+
+func (c *PushController) RegisterSubscription() fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		var p dto.RegisterPushSubscriptionParams
+		if err := ctx.BodyParser(&p); err != nil {
+			return ctx.Status(fiber.StatusBadRequest).JSON(newErrResponse(err))
+		}
+
+		p.UserID = ctx.Locals(userIDKey).(int64)
+
+		res, err := c.pushUC.RegisterSubscription(ctx.Context(), p)
+		if err != nil {
+			return err
+		}
+
+		return ctx.Status(fiber.StatusCreated).JSON(newResponse(res))
+	}
+}
+
+func (c *PushController) UnregisterSubscription() fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		var p dto.UnregisterPushSubscriptionParams
+		if err := ctx.BodyParser(&p); err != nil {
+			return ctx.Status(fiber.StatusBadRequest).JSON(newErrResponse(err))
+		}
+
+		p.UserID = ctx.Locals(userIDKey).(int64)
+
+		err := c.pushUC.UnregisterSubscription(ctx.Context(), p)
+		if err != nil {
+			return err
+		}
+
+		return ctx.SendStatus(fiber.StatusNoContent)
+	}
+}
+
+func (c *PushController) RegisterRoutes(r fiber.Router, mw *Middleware) {
+	r.Get("vapid-public-key", c.GetVAPIDPublicKey())
+	r.Post("subscriptions", mw.AuthedUser(), c.RegisterSubscription())
+	r.Delete("subscriptions", mw.AuthedUser(), c.UnregisterSubscription())
+}
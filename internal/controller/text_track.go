@@ -0,0 +1,107 @@
+package controller
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"news-app-api/internal/dto"
+	"news-app-api/internal/entity"
+	"news-app-api/internal/usecase"
+)
+
+type TextTrackController struct {
+	textTrackUC usecase.TextTrackUseCase
+	cache       CacheConfig
+}
+
+func NewTextTrackController(textTrackUC usecase.TextTrackUseCase, cache CacheConfig) *TextTrackController {
+	return &TextTrackController{textTrackUC, cache}
+}
+
+func (c *TextTrackController) PutTextTrack() fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		var p dto.PutTextTrackParams
+		if err := ctx.ParamsParser(&p); err != nil {
+			return ctx.Status(fiber.StatusBadRequest).JSON(newErrResponse(err))
+		}
+
+		p.MediaID = ctx.Locals(mediaIDKey).(int64)
+
+		file, values, err := formFileWithValues(ctx, "file")
+		if err != nil {
+			return ctx.Status(fiber.StatusBadRequest).JSON(newErrResponse(err))
+		}
+		p.File = file
+		p.Label = values["label"]
+
+		res, err := c.textTrackUC.PutTextTrack(ctx.Context(), p)
+		if err != nil {
+			return err
+		}
+
+		return ctx.Status(fiber.StatusOK).JSON(newResponse(res))
+	}
+}
+
+func (c *TextTrackController) GetTextTracks() fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		var p dto.GetTextTracksParams
+		if err := ctx.ParamsParser(&p); err != nil {
+			return ctx.Status(fiber.StatusBadRequest).JSON(newErrResponse(err))
+		}
+
+		res, err := c.textTrackUC.GetTextTracks(ctx.Context(), p)
+		if err != nil {
+			return err
+		}
+
+		return sendCachedJSON(ctx, fiber.StatusOK, newResponse(res), c.cache.News)
+	}
+}
+
+func (c *TextTrackController) GetTextTrack() fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		var p dto.GetTextTrackParams
+		if err := ctx.ParamsParser(&p); err != nil {
+			return ctx.Status(fiber.StatusBadRequest).JSON(newErrResponse(err))
+		}
+		if err := ctx.QueryParser(&p); err != nil {
+			return ctx.Status(fiber.StatusBadRequest).JSON(newErrResponse(err))
+		}
+
+		res, err := c.textTrackUC.GetTextTrack(ctx.Context(), p)
+		if err != nil {
+			return err
+		}
+
+		cacheControl := c.cache.Audio
+		if p.Target == entity.AttachmentKindVideo {
+			cacheControl = c.cache.Video
+		}
+
+		return sendFile(ctx, res, cacheControl)
+	}
+}
+
+func (c *TextTrackController) DeleteTextTrack() fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		var p dto.DeleteTextTrackParams
+		if err := ctx.ParamsParser(&p); err != nil {
+			return ctx.Status(fiber.StatusBadRequest).JSON(newErrResponse(err))
+		}
+
+		p.MediaID = ctx.Locals(mediaIDKey).(int64)
+
+		err := c.textTrackUC.DeleteTextTrack(ctx.Context(), p)
+		if err != nil {
+			return err
+		}
+
+		return ctx.SendStatus(fiber.StatusNoContent)
+	}
+}
+
+func (c *TextTrackController) RegisterRoutes(r fiber.Router, mw *Middleware) {
+	r.Get(":news_id/:target/tracks", c.GetTextTracks())
+	r.Put(":news_id/:target/tracks/:kind/:language", mw.AuthedMedia(), c.PutTextTrack())
+	r.Get(":news_id/:target/tracks/:kind/:language", c.GetTextTrack())
+	r.Delete(":news_id/:target/tracks/:kind/:language", mw.AuthedMedia(), c.DeleteTextTrack())
+}
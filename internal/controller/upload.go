@@ -0,0 +1,208 @@
+package controller
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"github.com/gofiber/fiber/v2"
+	"net/http"
+	"news-app-api/internal/dto"
+	"news-app-api/internal/entity"
+	"news-app-api/internal/usecase"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	tusVersion     = "1.0.0"
+	tusExtensions  = "creation,termination,expiration"
+	tusContentType = "application/offset+octet-stream"
+)
+
+type UploadController struct {
+	uploadUC usecase.UploadUseCase
+}
+
+func NewUploadController(uploadUC usecase.UploadUseCase) *UploadController {
+	return &UploadController{uploadUC}
+}
+
+func (c *UploadController) Tus() fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		ctx.Set("Tus-Resumable", tusVersion)
+
+		tus := ctx.Method() != fiber.MethodOptions && ctx.Method() != fiber.MethodGet
+		if tus && ctx.Get("Tus-Resumable") != tusVersion {
+			ctx.Set("Tus-Version", tusVersion)
+			return ctx.SendStatus(fiber.StatusPreconditionFailed)
+		}
+
+		return ctx.Next()
+	}
+}
+
+func (c *UploadController) Options() fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		ctx.Set("Tus-Version", tusVersion)
+		ctx.Set("Tus-Extension", tusExtensions)
+		ctx.Set("Tus-Max-Size", strconv.FormatInt(c.uploadUC.MaxSize(), 10))
+		return ctx.SendStatus(fiber.StatusNoContent)
+	}
+}
+
+func (c *UploadController) Create() fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		if ctx.Get("Upload-Defer-Length") != "" {
+			return ctx.Status(fiber.StatusBadRequest).JSON(newErrResponse(errors.New("Upload-Defer-Length isn't supported")))
+		}
+
+		length, err := strconv.ParseInt(ctx.Get("Upload-Length"), 10, 64)
+		if err != nil {
+			return ctx.Status(fiber.StatusBadRequest).JSON(newErrResponse(err))
+		}
+
+		metadata, err := parseUploadMetadata(ctx.Get("Upload-Metadata"))
+		if err != nil {
+			return ctx.Status(fiber.StatusBadRequest).JSON(newErrResponse(err))
+		}
+
+		res, err := c.uploadUC.Create(ctx.Context(), dto.CreateUploadParams{
+			MediaID:  ctx.Locals(mediaIDKey).(int64),
+			Length:   length,
+			Metadata: metadata,
+		})
+		if err != nil {
+			return err
+		}
+
+		ctx.Location(strings.TrimSuffix(ctx.Path(), "/") + "/" + res.ID)
+		setUploadHeaders(ctx, res)
+		return ctx.SendStatus(fiber.StatusCreated)
+	}
+}
+
+func (c *UploadController) Head() fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		var p dto.GetUploadParams
+		if err := ctx.ParamsParser(&p); err != nil {
+			return ctx.Status(fiber.StatusBadRequest).JSON(newErrResponse(err))
+		}
+
+		p.MediaID = ctx.Locals(mediaIDKey).(int64)
+
+		res, err := c.uploadUC.Get(ctx.Context(), p)
+		if err != nil {
+			return err
+		}
+
+		ctx.Set(fiber.HeaderCacheControl, "no-store")
+		ctx.Set("Upload-Length", strconv.FormatInt(res.Length, 10))
+		setUploadHeaders(ctx, res)
+		return ctx.SendStatus(fiber.StatusOK)
+	}
+}
+
+func (c *UploadController) Get() fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		var p dto.GetUploadParams
+		if err := ctx.ParamsParser(&p); err != nil {
+			return ctx.Status(fiber.StatusBadRequest).JSON(newErrResponse(err))
+		}
+
+		p.MediaID = ctx.Locals(mediaIDKey).(int64)
+
+		res, err := c.uploadUC.Get(ctx.Context(), p)
+		if err != nil {
+			return err
+		}
+
+		ctx.Set(fiber.HeaderCacheControl, "no-store")
+		return ctx.JSON(res)
+	}
+}
+
+func (c *UploadController) Patch() fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		var p dto.WriteUploadParams
+		if err := ctx.ParamsParser(&p); err != nil {
+			return ctx.Status(fiber.StatusBadRequest).JSON(newErrResponse(err))
+		}
+
+		if ctx.Get(fiber.HeaderContentType) != tusContentType {
+			return ctx.SendStatus(fiber.StatusUnsupportedMediaType)
+		}
+
+		var err error
+		p.Offset, err = strconv.ParseInt(ctx.Get("Upload-Offset"), 10, 64)
+		if err != nil {
+			return ctx.Status(fiber.StatusBadRequest).JSON(newErrResponse(err))
+		}
+
+		p.MediaID = ctx.Locals(mediaIDKey).(int64)
+		p.Body = ctx.Context().RequestBodyStream()
+		if p.Body == nil {
+			p.Body = bytes.NewReader(ctx.Body())
+		}
+
+		res, err := c.uploadUC.Write(ctx.Context(), p)
+		if err != nil {
+			return err
+		}
+
+		setUploadHeaders(ctx, res)
+		return ctx.SendStatus(fiber.StatusNoContent)
+	}
+}
+
+func (c *UploadController) Delete() fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		var p dto.DeleteUploadParams
+		if err := ctx.ParamsParser(&p); err != nil {
+			return ctx.Status(fiber.StatusBadRequest).JSON(newErrResponse(err))
+		}
+
+		p.MediaID = ctx.Locals(mediaIDKey).(int64)
+
+		err := c.uploadUC.Delete(ctx.Context(), p)
+		if err != nil {
+			return err
+		}
+
+		return ctx.SendStatus(fiber.StatusNoContent)
+	}
+}
+
+func (c *UploadController) RegisterRoutes(r fiber.Router, mw *Middleware) {
+	r.Use(c.Tus())
+	r.Options("", c.Options())
+	r.Post("", mw.AuthedMedia(), c.Create())
+	r.Head(":upload_id", mw.AuthedMedia(), c.Head())
+	r.Get(":upload_id", mw.AuthedMedia(), c.Get())
+	r.Patch(":upload_id", mw.AuthedMedia(), c.Patch())
+	r.Delete(":upload_id", mw.AuthedMedia(), c.Delete())
+}
+
+func setUploadHeaders(ctx *fiber.Ctx, u entity.Upload) {
+	ctx.Set("Upload-Offset", strconv.FormatInt(u.Offset, 10))
+	ctx.Set("Upload-Status", u.Status)
+	ctx.Set("Upload-Expires", time.Unix(u.ExpiresAt, 0).UTC().Format(http.TimeFormat))
+}
+
+func parseUploadMetadata(h string) (map[string]string, error) {
+	metadata := make(map[string]string)
+	for _, pair := range strings.Split(h, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+
+		key, value, _ := strings.Cut(pair, " ")
+		decoded, err := base64.StdEncoding.DecodeString(value)
+		if err != nil {
+			return nil, err
+		}
+		metadata[key] = string(decoded)
+	}
+	return metadata, nil
+}
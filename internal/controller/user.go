@@ -119,10 +119,89 @@ func (c *UserController) GetSubscriptionList() fiber.Handler {
 	}
 }
 
+func (c *UserController) UpdateName() fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		var p dto.UpdateUserNameParams
+		if err := ctx.BodyParser(&p); err != nil {
+			return ctx.Status(fiber.StatusBadRequest).JSON(newErrResponse(err))
+		}
+
+		p.UserID = ctx.Locals(userIDKey).(int64)
+
+		res, err := c.userUC.UpdateName(ctx.Context(), p)
+		if err != nil {
+			return err
+		}
+
+		return ctx.Status(fiber.StatusOK).JSON(newResponse(res))
+	}
+}
+
+func (c *UserController) ChangeEmail() fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		var p dto.ChangeUserEmailParams
+		if err := ctx.BodyParser(&p); err != nil {
+			return ctx.Status(fiber.StatusBadRequest).JSON(newErrResponse(err))
+		}
+
+		p.UserID = ctx.Locals(userIDKey).(int64)
+
+		res, err := c.userUC.ChangeEmail(ctx.Context(), p)
+		if err != nil {
+			return err
+		}
+
+		return ctx.Status(fiber.StatusAccepted).JSON(newResponse(res))
+	}
+}
+
+func (c *UserController) ConfirmEmail() fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		var p dto.ConfirmUserEmailParams
+		if err := ctx.QueryParser(&p); err != nil {
+			return ctx.Status(fiber.StatusBadRequest).JSON(newErrResponse(err))
+		}
+
+		res, err := c.userUC.ConfirmEmail(ctx.Context(), p)
+		if err != nil {
+			return err
+		}
+
+		if ctx.Method() == fiber.MethodPost {
+			return ctx.Status(fiber.StatusOK).JSON(newResponse(res))
+		}
+
+		return ctx.Status(fiber.StatusOK).SendString("Новый email подтверждён.")
+	}
+}
+
+func (c *UserController) ChangePassword() fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		var p dto.ChangeUserPasswordParams
+		if err := ctx.BodyParser(&p); err != nil {
+			return ctx.Status(fiber.StatusBadRequest).JSON(newErrResponse(err))
+		}
+
+		p.UserID = ctx.Locals(userIDKey).(int64)
+
+		err := c.userUC.ChangePassword(ctx.Context(), p)
+		if err != nil {
+			return err
+		}
+
+		return ctx.SendStatus(fiber.StatusNoContent)
+	}
+}
+
 func (c *UserController) RegisterRoutes(r fiber.Router, mw *Middleware) {
 	r.Post("register", c.Register())
 	r.Post("login", c.Login())
 	r.Post("logout", c.Logout())
 	r.Post("authenticate", mw.AuthedUser(), c.Authenticate())
+	r.Put("me/name", mw.AuthedUser(), c.UpdateName())
+	r.Put("me/email", mw.AuthedUser(), c.ChangeEmail())
+	r.Get("me/email/confirm", c.ConfirmEmail())
+	r.Post("me/email/confirm", c.ConfirmEmail())
+	r.Put("me/password", mw.AuthedUser(), c.ChangePassword())
 	r.Get(":user_id/subscriptions", c.GetSubscriptionList())
 }
@@ -0,0 +1,197 @@
+package controller
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"news-app-api/internal/dto"
+	"news-app-api/internal/usecase"
+)
+
+type WebhookController struct {
+	webhookUC usecase.WebhookUseCase
+}
+
+func NewWebhookController(webhookUC usecase.WebhookUseCase) *WebhookController {
+	return &WebhookController{webhookUC}
+}
+
+func (c *WebhookController) GetEventTypes() fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		return ctx.Status(fiber.StatusOK).JSON(newResponse(dto.WebhookEventTypes))
+	}
+}
+
+func (c *WebhookController) GetWebhookList() fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		mediaID := ctx.Locals(mediaIDKey).(int64)
+
+		res, err := c.webhookUC.GetWebhookList(ctx.Context(), mediaID)
+		if err != nil {
+			return err
+		}
+
+		return ctx.Status(fiber.StatusOK).JSON(newResponse(res))
+	}
+}
+
+func (c *WebhookController) CreateWebhook() fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		var p dto.CreateWebhookParams
+		if err := ctx.BodyParser(&p); err != nil {
+			return ctx.Status(fiber.StatusBadRequest).JSON(newErrResponse(err))
+		}
+
+		p.MediaID = ctx.Locals(mediaIDKey).(int64)
+
+		res, err := c.webhookUC.CreateWebhook(ctx.Context(), p)
+		if err != nil {
+			return err
+		}
+
+		return ctx.Status(fiber.StatusCreated).JSON(newResponse(res))
+	}
+}
+
+func (c *WebhookController) GetWebhook() fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		var p dto.WebhookParams
+		if err := ctx.ParamsParser(&p); err != nil {
+			return ctx.Status(fiber.StatusBadRequest).JSON(newErrResponse(err))
+		}
+
+		p.MediaID = ctx.Locals(mediaIDKey).(int64)
+
+		res, err := c.webhookUC.GetWebhook(ctx.Context(), p)
+		if err != nil {
+			return err
+		}
+
+		return ctx.Status(fiber.StatusOK).JSON(newResponse(res))
+	}
+}
+
+func (c *WebhookController) UpdateWebhook() fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		var p dto.UpdateWebhookParams
+		if err := ctx.BodyParser(&p); err != nil {
+			return ctx.Status(fiber.StatusBadRequest).JSON(newErrResponse(err))
+		}
+		if err := ctx.ParamsParser(&p); err != nil {
+			return ctx.Status(fiber.StatusBadRequest).JSON(newErrResponse(err))
+		}
+
+		p.MediaID = ctx.Locals(mediaIDKey).(int64)
+
+		res, err := c.webhookUC.UpdateWebhook(ctx.Context(), p)
+		if err != nil {
+			return err
+		}
+
+		return ctx.Status(fiber.StatusOK).JSON(newResponse(res))
+	}
+}
+
+func (c *WebhookController) RotateSecret() fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		var p dto.WebhookParams
+		if err := ctx.ParamsParser(&p); err != nil {
+			return ctx.Status(fiber.StatusBadRequest).JSON(newErrResponse(err))
+		}
+
+		p.MediaID = ctx.Locals(mediaIDKey).(int64)
+
+		res, err := c.webhookUC.RotateSecret(ctx.Context(), p)
+		if err != nil {
+			return err
+		}
+
+		return ctx.Status(fiber.StatusOK).JSON(newResponse(res))
+	}
+}
+
+func (c *WebhookController) DeleteWebhook() fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		var p dto.WebhookParams
+		if err := ctx.ParamsParser(&p); err != nil {
+			return ctx.Status(fiber.StatusBadRequest).JSON(newErrResponse(err))
+		}
+
+		p.MediaID = ctx.Locals(mediaIDKey).(int64)
+
+		err := c.webhookUC.DeleteWebhook(ctx.Context(), p)
+		if err != nil {
+			return err
+		}
+
+		return ctx.SendStatus(fiber.StatusNoContent)
+	}
+}
+
+func (c *WebhookController) GetDeliveryList() fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		var p dto.GetWebhookDeliveryListParams
+		if err := ctx.ParamsParser(&p); err != nil {
+			return ctx.Status(fiber.StatusBadRequest).JSON(newErrResponse(err))
+		}
+		if err := ctx.QueryParser(&p); err != nil {
+			return ctx.Status(fiber.StatusBadRequest).JSON(newErrResponse(err))
+		}
+
+		p.MediaID = ctx.Locals(mediaIDKey).(int64)
+
+		res, err := c.webhookUC.GetDeliveryList(ctx.Context(), p)
+		if err != nil {
+			return err
+		}
+
+		return ctx.Status(fiber.StatusOK).JSON(newResponse(res))
+	}
+}
+
+func (c *WebhookController) GetDelivery() fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		var p dto.WebhookDeliveryParams
+		if err := ctx.ParamsParser(&p); err != nil {
+			return ctx.Status(fiber.StatusBadRequest).JSON(newErrResponse(err))
+		}
+
+		p.MediaID = ctx.Locals(mediaIDKey).(int64)
+
+		res, err := c.webhookUC.GetDelivery(ctx.Context(), p)
+		if err != nil {
+			return err
+		}
+
+		return ctx.Status(fiber.StatusOK).JSON(newResponse(res))
+	}
+}
+
+func (c *WebhookController) ReplayDelivery() fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		var p dto.WebhookDeliveryParams
+		if err := ctx.ParamsParser(&p); err != nil {
+			return ctx.Status(fiber.StatusBadRequest).JSON(newErrResponse(err))
+		}
+
+		p.MediaID = ctx.Locals(mediaIDKey).(int64)
+
+		res, err := c.webhookUC.ReplayDelivery(ctx.Context(), p)
+		if err != nil {
+			return err
+		}
+
+		return ctx.Status(fiber.StatusAccepted).JSON(newResponse(res))
+	}
+}
+
+func (c *WebhookController) RegisterRoutes(r fiber.Router, mw *Middleware) {
+	r.Get("event-types", c.GetEventTypes())
+	r.Get("", mw.AuthedMedia(), c.GetWebhookList())
+	r.Post("", mw.AuthedMedia(), c.CreateWebhook())
+	r.Get(":webhook_id", mw.AuthedMedia(), c.GetWebhook())
+	r.Put(":webhook_id", mw.AuthedMedia(), c.UpdateWebhook())
+	r.Delete(":webhook_id", mw.AuthedMedia(), c.DeleteWebhook())
+	r.Post(":webhook_id/rotate-secret", mw.AuthedMedia(), c.RotateSecret())
+	r.Get(":webhook_id/deliveries", mw.AuthedMedia(), c.GetDeliveryList())
+	r.Get(":webhook_id/deliveries/:delivery_id", mw.AuthedMedia(), c.GetDelivery())
+	r.Post(":webhook_id/deliveries/:delivery_id/replay", mw.AuthedMedia(), c.ReplayDelivery())
+}
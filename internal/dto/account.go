@@ -0,0 +1,22 @@
+package dto
+
+type (
+	ExportAccountParams struct {
+		UserID int64
+	}
+
+	RequestAccountDeletionParams struct {
+		UserID   int64  `json:"-"`
+		Password string `json:"password"`
+	}
+)
+
+func (p *RequestAccountDeletionParams) Validate() error {
+	if p.Password == "" {
+		return &AppError{
+			Message: "Для удаления аккаунта требуется пароль",
+			Code:    ErrCodeBadRequest,
+		}
+	}
+	return nil
+}
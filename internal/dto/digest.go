@@ -0,0 +1,52 @@
+package dto
+
+const (
+	DigestFrequencyOff    = "off"
+	DigestFrequencyDaily  = "daily"
+	DigestFrequencyWeekly = "weekly"
+)
+
+type (
+	UpdateDigestPreferenceParams struct {
+		UserID      int64  `json:"-"`
+		Frequency   string `json:"frequency"`
+		SendHour    int    `json:"sendHour"`
+		SendWeekday int    `json:"sendWeekday"`
+		Timezone    string `json:"timezone"`
+	}
+
+	UnsubscribeDigestParams struct {
+		Token string `query:"token"`
+	}
+)
+
+func (p *UpdateDigestPreferenceParams) Validate() error {
+	if p.Frequency != DigestFrequencyOff &&
+		p.Frequency != DigestFrequencyDaily &&
+		p.Frequency != DigestFrequencyWeekly {
+		return &AppError{
+			Message: "Недопустимая периодичность рассылки",
+			Code:    ErrCodeBadRequest,
+		}
+	} else if p.SendHour < 0 || p.SendHour > 23 {
+		return &AppError{
+			Message: "Час отправки должен быть от 0 до 23",
+			Code:    ErrCodeBadRequest,
+		}
+	} else if p.SendWeekday < 0 || p.SendWeekday > 6 {
+		return &AppError{
+			Message: "День недели должен быть от 0 до 6",
+			Code:    ErrCodeBadRequest,
+		}
+	} else if len(p.Timezone) > 64 {
+		return &AppError{
+			Message: "Максимальная длина часового пояса - 64 символа",
+			Code:    ErrCodeBadRequest,
+		}
+	}
+	if p.Timezone == "" {
+		p.Timezone = "UTC"
+	}
+	_, err := loadTimezone(p.Timezone)
+	return err
+}
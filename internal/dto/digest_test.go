@@ -0,0 +1,24 @@
+package dto
+
+import "testing"
+
+func TestUpdateDigestPreferenceParamsValidateTimezone(t *testing.T) {
+	tests := []struct {
+		timezone string
+		valid    bool
+	}{
+		{"", true},
+		{"UTC", true},
+		{"Europe/Moscow", true},
+		{"Local", false},
+		{"Mars/Olympus", false},
+	}
+
+	for _, tt := range tests {
+		p := UpdateDigestPreferenceParams{Frequency: DigestFrequencyDaily, Timezone: tt.timezone}
+		err := p.Validate()
+		if (err == nil) != tt.valid {
+			t.Errorf("Validate(%q) = %v, want valid=%v", tt.timezone, err, tt.valid)
+		}
+	}
+}
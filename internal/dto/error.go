@@ -1,10 +1,11 @@
 package dto
 
 const (
-	ErrCodeNotFound     = 404
-	ErrCodeBadRequest   = 400
-	ErrCodeUnauthorized = 401
-	ErrCodeConflict     = 409
+	ErrCodeNotFound              = 404
+	ErrCodeBadRequest            = 400
+	ErrCodeUnauthorized          = 401
+	ErrCodeConflict              = 409
+	ErrCodeRequestEntityTooLarge = 413
 )
 
 type (
@@ -3,18 +3,95 @@ package dto
 import (
 	"gopkg.in/guregu/null.v3"
 	"news-app-api/internal/entity"
+	"time"
 )
 
 type (
 	GetFeedParams struct {
+		UserID  int64
+		Since   null.Int    `query:"since"`
+		Limit   null.Int    `query:"limit"`
+		Offset  null.Int    `query:"offset"`
+		Sort    null.String `query:"sort"`
+		Session null.String `query:"session"`
+	}
+
+	GetFeedResult struct {
+		Total   int64                 `json:"total"`
+		Items   []entity.NewsListItem `json:"items"`
+		Session string                `json:"session,omitempty"`
+	}
+
+	MarkFeedNewsReadParams struct {
+		NewsID int64 `params:"news_id"`
+		UserID int64 `params:"-"`
+	}
+
+	HideNewsParams struct {
+		NewsID int64 `params:"news_id"`
+		UserID int64 `params:"-"`
+	}
+
+	UnhideNewsParams struct {
+		NewsID int64 `params:"news_id"`
+		UserID int64 `params:"-"`
+	}
+
+	GetHiddenNewsListParams struct {
 		UserID int64
-		Since  null.Int `query:"since"`
 		Limit  null.Int `query:"limit"`
 		Offset null.Int `query:"offset"`
 	}
 
-	GetFeedResult struct {
+	GetHiddenNewsListResult struct {
 		Total int64                 `json:"total"`
 		Items []entity.NewsListItem `json:"items"`
 	}
+
+	MuteMediaParams struct {
+		MediaID int64 `params:"media_id" json:"-"`
+		UserID  int64 `params:"-" json:"-"`
+		Until   int64 `json:"until"`
+	}
+
+	UnmuteMediaParams struct {
+		MediaID int64 `params:"media_id"`
+		UserID  int64 `params:"-"`
+	}
+
+	GetMutedMediaListParams struct {
+		UserID int64
+		Limit  null.Int `query:"limit"`
+		Offset null.Int `query:"offset"`
+	}
+
+	GetMutedMediaListResult struct {
+		Total int64               `json:"total"`
+		Items []entity.MutedMedia `json:"items"`
+	}
+)
+
+const (
+	FeedSortRecent = "recent"
+	FeedSortRanked = "ranked"
 )
+
+func (p *GetFeedParams) Validate() error {
+	if p.Sort.Valid && p.Sort.String != FeedSortRecent && p.Sort.String != FeedSortRanked {
+		return &AppError{
+			Message: "Недопустимый порядок сортировки ленты",
+			Code:    ErrCodeBadRequest,
+		}
+	}
+	return nil
+}
+
+func (p *MuteMediaParams) Validate() error {
+	if p.Until <= time.Now().Unix() {
+		return &AppError{
+			Message: "Время окончания заглушения должно быть в будущем",
+			Code:    ErrCodeBadRequest,
+		}
+	}
+	return nil
+}
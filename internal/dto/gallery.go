@@ -0,0 +1,101 @@
+package dto
+
+import (
+	"gopkg.in/guregu/null.v3"
+	"io"
+	"news-app-api/internal/entity"
+	"unicode/utf8"
+)
+
+type (
+	AddGalleryImageParams struct {
+		NewsID  int64 `params:"news_id"`
+		MediaID int64
+		File    io.Reader
+		Caption string
+		AltText string
+		Credit  string
+	}
+
+	GetGalleryParams struct {
+		NewsID int64 `params:"news_id"`
+	}
+
+	GetGalleryResult struct {
+		Items []entity.GalleryImage `json:"items"`
+	}
+
+	GetGalleryImageParams struct {
+		GetImageParams
+		ImageID int64 `params:"image_id"`
+	}
+
+	UpdateGalleryImageParams struct {
+		NewsID  int64       `params:"news_id" json:"-"`
+		ImageID int64       `params:"image_id" json:"-"`
+		MediaID int64       `json:"-"`
+		Caption null.String `json:"caption"`
+		AltText null.String `json:"altText"`
+		Credit  null.String `json:"credit"`
+	}
+
+	ReorderGalleryParams struct {
+		NewsID   int64   `params:"news_id" json:"-"`
+		MediaID  int64   `json:"-"`
+		ImageIDs []int64 `json:"imageIds"`
+	}
+
+	DeleteGalleryImageParams struct {
+		NewsID  int64 `params:"news_id"`
+		ImageID int64 `params:"image_id"`
+		MediaID int64
+	}
+)
+
+const (
+	maxGalleryCaptionLength = 2000
+	maxGalleryAltTextLength = 1000
+	maxGalleryCreditLength  = 255
+)
+
+func (p *AddGalleryImageParams) Validate() error {
+	return validateGalleryImageText(p.Caption, p.AltText, p.Credit)
+}
+
+func (p *UpdateGalleryImageParams) Validate() error {
+	return validateGalleryImageText(p.Caption.String, p.AltText.String, p.Credit.String)
+}
+
+func (p *ReorderGalleryParams) Validate() error {
+	seen := make(map[int64]bool, len(p.ImageIDs))
+	for _, id := range p.ImageIDs {
+		if seen[id] {
+			return &AppError{
+				Message: "Изображения в порядке галереи не должны повторяться",
+				Code:    ErrCodeBadRequest,
+			}
+		}
+		seen[id] = true
+	}
+	return nil
+}
+
+func validateGalleryImageText(caption, altText, credit string) error {
+	if utf8.RuneCountInString(caption) > maxGalleryCaptionLength {
+		return &AppError{
+			Message: "Максимальная длина подписи - 2000 символов",
+			Code:    ErrCodeBadRequest,
+		}
+	} else if utf8.RuneCountInString(altText) > maxGalleryAltTextLength {
+		return &AppError{
+			Message: "Максимальная длина альтернативного текста - 1000 символов",
+			Code:    ErrCodeBadRequest,
+		}
+	} else if utf8.RuneCountInString(credit) > maxGalleryCreditLength {
+		return &AppError{
+			Message: "Максимальная длина указания автора - 255 символов",
+			Code:    ErrCodeBadRequest,
+		}
+	}
+	return nil
+}
@@ -2,12 +2,17 @@ package dto
 
 import (
 	"gopkg.in/guregu/null.v3"
+	"io"
+	"net/url"
 	"news-app-api/internal/entity"
+	"strings"
+	"time"
+	"unicode/utf8"
 )
 
 type (
 	RegisterMediaParams struct {
-		RegistrationNumber int64         `json:"registrationNumber"`
+		RegistrationNumber string        `json:"registrationNumber"`
 		Name               string        `json:"name"`
 		Email              string        `json:"email"`
 		Editor             entity.Editor `json:"editor"`
@@ -15,13 +20,24 @@ type (
 	}
 
 	LoginMediaParams struct {
-		RegistrationNumber int64  `json:"registrationNumber"`
+		RegistrationNumber string `json:"registrationNumber"`
 		Password           string `json:"password"`
 	}
 
+	UpdateMediaAccountParams struct {
+		MediaID int64         `json:"-"`
+		Name    string        `json:"name"`
+		Email   string        `json:"email"`
+		Editor  entity.Editor `json:"editor"`
+	}
+
 	GetMediaListParams struct {
-		Limit  null.Int `query:"limit"`
-		Offset null.Int `query:"offset"`
+		UserID           int64
+		Query            null.String `query:"q"`
+		Sort             null.String `query:"sort"`
+		ActiveWithinDays null.Int    `query:"activeWithinDays"`
+		Limit            null.Int    `query:"limit"`
+		Offset           null.Int    `query:"offset"`
 	}
 
 	GetMediaListResult struct {
@@ -38,6 +54,57 @@ type (
 		IsSubscribed bool `json:"isSubscribed"`
 	}
 
+	GetMediaProfileParams struct {
+		MediaID int64 `params:"media_id"`
+		UserID  int64 `params:"-"`
+	}
+
+	UpdateMediaProfileParams struct {
+		MediaID     int64                    `json:"-"`
+		Description string                   `json:"description"`
+		Website     null.String              `json:"website"`
+		SocialLinks []entity.MediaSocialLink `json:"socialLinks"`
+	}
+
+	UploadMediaImageParams struct {
+		MediaID int64
+		Kind    string
+		File    io.Reader
+	}
+
+	GetMediaImageParams struct {
+		MediaID int64 `params:"media_id"`
+		Kind    string
+	}
+
+	GetSubscriberListParams struct {
+		MediaID int64
+		Limit   null.Int `query:"limit"`
+		Offset  null.Int `query:"offset"`
+	}
+
+	GetSubscriberListResult struct {
+		Total int64               `json:"total"`
+		Items []entity.Subscriber `json:"items"`
+	}
+
+	GetSubscriptionStatsParams struct {
+		MediaID  int64
+		From     null.String `query:"from"`
+		To       null.String `query:"to"`
+		Timezone null.String `query:"timezone"`
+	}
+
+	GetSubscriptionStatsResult struct {
+		From         string                        `json:"from"`
+		To           string                        `json:"to"`
+		Timezone     string                        `json:"timezone"`
+		Subscribed   int64                         `json:"subscribed"`
+		Unsubscribed int64                         `json:"unsubscribed"`
+		Net          int64                         `json:"net"`
+		Days         []entity.SubscriptionStatsDay `json:"days"`
+	}
+
 	GetNewsListParams struct {
 		MediaID int64 `params:"media_id"`
 		UserID  int64
@@ -51,7 +118,227 @@ type (
 	}
 )
 
+const (
+	MediaSortName        = "name"
+	MediaSortSubscribers = "subscribers"
+	MediaSortNewest      = "newest"
+	MediaSortActive      = "active"
+	MediaSortRelevance   = "relevance"
+
+	MediaImageLogo  = "logo"
+	MediaImageCover = "cover"
+
+	maxMediaSocialLinks = 10
+
+	subscriptionStatsDateLayout  = "2006-01-02"
+	maxSubscriptionStatsDays     = 366
+	defaultSubscriptionStatsDays = 30
+)
+
+func (p *GetMediaListParams) Validate() error {
+	p.Query.String = strings.TrimSpace(p.Query.String)
+	p.Query.Valid = p.Query.Valid && p.Query.String != ""
+
+	if utf8.RuneCountInString(p.Query.String) > 64 {
+		return &AppError{
+			Message: "Максимальная длина поискового запроса - 64 символа",
+			Code:    ErrCodeBadRequest,
+		}
+	}
+
+	if !p.Sort.Valid {
+		p.Sort = null.StringFrom(MediaSortName)
+		if p.Query.Valid {
+			p.Sort = null.StringFrom(MediaSortRelevance)
+		}
+	}
+
+	switch p.Sort.String {
+	case MediaSortName, MediaSortSubscribers, MediaSortNewest, MediaSortActive:
+	case MediaSortRelevance:
+		if !p.Query.Valid {
+			return &AppError{
+				Message: "Сортировка по релевантности доступна только при поиске",
+				Code:    ErrCodeBadRequest,
+			}
+		}
+	default:
+		return &AppError{
+			Message: "Неизвестный вид сортировки",
+			Code:    ErrCodeBadRequest,
+		}
+	}
+
+	if p.ActiveWithinDays.Valid && (p.ActiveWithinDays.Int64 < 1 || p.ActiveWithinDays.Int64 > 3650) {
+		return &AppError{
+			Message: "Период активности должен быть от 1 до 3650 дней",
+			Code:    ErrCodeBadRequest,
+		}
+	}
+
+	return nil
+}
+
+func (p *GetSubscriptionStatsParams) Validate(now time.Time) error {
+	if !p.Timezone.Valid || p.Timezone.String == "" {
+		p.Timezone = null.StringFrom("UTC")
+	}
+	loc, err := loadTimezone(p.Timezone.String)
+	if err != nil {
+		return err
+	}
+
+	to := now.In(loc)
+	if p.To.Valid {
+		to, err = time.ParseInLocation(subscriptionStatsDateLayout, p.To.String, loc)
+		if err != nil {
+			return &AppError{
+				Message: "Дата окончания периода должна быть в формате ГГГГ-ММ-ДД",
+				Code:    ErrCodeBadRequest,
+			}
+		}
+	}
+
+	from := to.AddDate(0, 0, 1-defaultSubscriptionStatsDays)
+	if p.From.Valid {
+		from, err = time.ParseInLocation(subscriptionStatsDateLayout, p.From.String, loc)
+		if err != nil {
+			return &AppError{
+				Message: "Дата начала периода должна быть в формате ГГГГ-ММ-ДД",
+				Code:    ErrCodeBadRequest,
+			}
+		}
+	}
+
+	from = time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, time.UTC)
+	to = time.Date(to.Year(), to.Month(), to.Day(), 0, 0, 0, 0, time.UTC)
+
+	if from.After(to) {
+		return &AppError{
+			Message: "Дата начала периода не может быть позже даты окончания",
+			Code:    ErrCodeBadRequest,
+		}
+	} else if int(to.Sub(from).Hours()/24)+1 > maxSubscriptionStatsDays {
+		return &AppError{
+			Message: "Максимальная длина периода - 366 дней",
+			Code:    ErrCodeBadRequest,
+		}
+	}
+
+	p.From = null.StringFrom(from.Format(subscriptionStatsDateLayout))
+	p.To = null.StringFrom(to.Format(subscriptionStatsDateLayout))
+
+	return nil
+}
+
+func (p *UpdateMediaProfileParams) Validate() error {
+	p.Description = strings.TrimSpace(p.Description)
+	p.Website.String = strings.TrimSpace(p.Website.String)
+	p.Website.Valid = p.Website.Valid && p.Website.String != ""
+
+	if utf8.RuneCountInString(p.Description) > 2000 {
+		return &AppError{
+			Message: "Максимальная длина описания - 2000 символов",
+			Code:    ErrCodeBadRequest,
+		}
+	}
+
+	if p.Website.Valid && !isWebURL(p.Website.String, 512) {
+		return &AppError{
+			Message: "Недопустимый адрес сайта",
+			Code:    ErrCodeBadRequest,
+		}
+	}
+
+	if len(p.SocialLinks) > maxMediaSocialLinks {
+		return &AppError{
+			Message: "Можно указать не более 10 ссылок на социальные сети",
+			Code:    ErrCodeBadRequest,
+		}
+	}
+
+	if p.SocialLinks == nil {
+		p.SocialLinks = []entity.MediaSocialLink{}
+	}
+	for i, link := range p.SocialLinks {
+		link.Network = strings.ToLower(strings.TrimSpace(link.Network))
+		link.URL = strings.TrimSpace(link.URL)
+		if link.Network == "" || utf8.RuneCountInString(link.Network) > 32 {
+			return &AppError{
+				Message: "Название социальной сети должно содержать от 1 до 32 символов",
+				Code:    ErrCodeBadRequest,
+			}
+		}
+		if !isWebURL(link.URL, 512) {
+			return &AppError{
+				Message: "Недопустимая ссылка на социальную сеть",
+				Code:    ErrCodeBadRequest,
+			}
+		}
+		p.SocialLinks[i] = link
+	}
+
+	return nil
+}
+
+func isWebURL(s string, maxLen int) bool {
+	if len(s) > maxLen {
+		return false
+	}
+	u, err := url.Parse(s)
+	return err == nil && u.Host != "" && (u.Scheme == "https" || u.Scheme == "http")
+}
+
+func (p *UpdateMediaAccountParams) Validate() error {
+	p.Name = strings.TrimSpace(p.Name)
+	p.Email = strings.TrimSpace(p.Email)
+	p.Editor.FirstName = strings.TrimSpace(p.Editor.FirstName)
+	p.Editor.LastName = strings.TrimSpace(p.Editor.LastName)
+
+	if p.Name == "" {
+		return &AppError{
+			Message: "Название не может быть пустым",
+			Code:    ErrCodeBadRequest,
+		}
+	} else if len(p.Name) > 32 {
+		return &AppError{
+			Message: "Максимальная длина названия - 32 символа",
+			Code:    ErrCodeBadRequest,
+		}
+	} else if len(p.Email) > 16 {
+		return &AppError{
+			Message: "Максимальная длина email - 16 символа",
+			Code:    ErrCodeBadRequest,
+		}
+	} else if !isEmail(p.Email) {
+		return &AppError{
+			Message: "Недопустимый email",
+			Code:    ErrCodeBadRequest,
+		}
+	} else if len(p.Editor.FirstName) > 16 {
+		return &AppError{
+			Message: "Максимальная длина имени - 16 символа",
+			Code:    ErrCodeBadRequest,
+		}
+	} else if len(p.Editor.LastName) > 16 {
+		return &AppError{
+			Message: "Максимальная длина фамилии - 16 символа",
+			Code:    ErrCodeBadRequest,
+		}
+	}
+	return nil
+}
+
 func (p *RegisterMediaParams) Validate() error {
+	canonical, ok := ParseRegistrationNumber(p.RegistrationNumber)
+	if !ok {
+		return &AppError{
+			Message: "Недопустимый регистрационный номер. Ожидается номер свидетельства вида «ЭЛ № ФС 77-12345»",
+			Code:    ErrCodeBadRequest,
+		}
+	}
+	p.RegistrationNumber = canonical
+
 	if len(p.Name) > 32 {
 		return &AppError{
 			Message: "Максимальная длина названия - 32 символа",
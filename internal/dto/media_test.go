@@ -0,0 +1,36 @@
+package dto
+
+import (
+	"gopkg.in/guregu/null.v3"
+	"testing"
+	"time"
+)
+
+func TestGetSubscriptionStatsParamsValidateTimezone(t *testing.T) {
+	now := time.Date(2024, 3, 10, 22, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		timezone null.String
+		valid    bool
+		to       string
+	}{
+		{null.String{}, true, "2024-03-10"},
+		{null.StringFrom(""), true, "2024-03-10"},
+		{null.StringFrom("UTC"), true, "2024-03-10"},
+		{null.StringFrom("Europe/Moscow"), true, "2024-03-11"},
+		{null.StringFrom("Local"), false, ""},
+		{null.StringFrom("Mars/Olympus"), false, ""},
+	}
+
+	for _, tt := range tests {
+		p := GetSubscriptionStatsParams{Timezone: tt.timezone}
+		err := p.Validate(now)
+		if (err == nil) != tt.valid {
+			t.Errorf("Validate(%v) = %v, want valid=%v", tt.timezone, err, tt.valid)
+			continue
+		}
+		if tt.valid && p.To.String != tt.to {
+			t.Errorf("Validate(%v): to = %q, want %q", tt.timezone, p.To.String, tt.to)
+		}
+	}
+}
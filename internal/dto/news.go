@@ -1,26 +1,41 @@
 package dto
 
 import (
-	"mime/multipart"
+	"gopkg.in/guregu/null.v3"
+	"io"
+	"news-app-api/internal/entity"
 )
 
 type (
 	CreateNewsParams struct {
-		MediaID int64  `json:"-"`
-		Title   string `json:"title"`
-		Text    string `json:"text"`
+		MediaID    int64  `json:"-"`
+		Title      string `json:"title"`
+		Text       string `json:"text"`
+		IsBreaking bool   `json:"isBreaking"`
 	}
 
 	CreateOrUpdateAudioParams struct {
 		NewsID  int64 `params:"news_id"`
 		MediaID int64
-		File    multipart.File
+		File    io.Reader
+	}
+
+	AttachmentSignature struct {
+		Expires   null.Int    `query:"exp"`
+		Signature null.String `query:"sig"`
 	}
 
 	GetAudioParams struct {
+		AttachmentSignature
 		NewsID int64 `params:"news_id"`
 	}
 
+	GetAudioWaveformParams struct {
+		AttachmentSignature
+		NewsID     int64    `params:"news_id"`
+		Resolution null.Int `query:"resolution"`
+	}
+
 	GetNewsParams struct {
 		NewsID int64 `params:"news_id"`
 	}
@@ -28,11 +43,25 @@ type (
 	CreateOrUpdateImageParams struct {
 		NewsID  int64 `params:"news_id"`
 		MediaID int64
-		File    multipart.File
+		File    io.Reader
+	}
+
+	GetAttachmentResult struct {
+		File        entity.File
+		ContentType string
+		ContentHash string
+		Version     string
+		UpdatedAt   int64
+		ExpiresAt   int64
 	}
 
 	GetImageParams struct {
-		NewsID int64 `params:"news_id"`
+		AttachmentSignature
+		NewsID      int64       `params:"news_id"`
+		Width       null.Int    `query:"w"`
+		Preset      null.String `query:"preset"`
+		Format      null.String `query:"format"`
+		AcceptsWebP bool
 	}
 
 	ToggleFavoriteParams struct {
@@ -47,10 +76,62 @@ type (
 	CreateOrUpdateVideoParams struct {
 		NewsID  int64 `params:"news_id"`
 		MediaID int64
-		File    multipart.File
+		File    io.Reader
 	}
 
 	GetVideoParams struct {
+		AttachmentSignature
 		NewsID int64 `params:"news_id"`
 	}
 )
+
+const (
+	ImagePresetThumbnail = "thumbnail"
+	ImagePresetMedium    = "medium"
+	ImagePresetLarge     = "large"
+
+	ImageFormatWebP = "webp"
+	ImageFormatJPEG = "jpeg"
+)
+
+var imagePresetWidths = map[string]int64{
+	ImagePresetThumbnail: 160,
+	ImagePresetMedium:    480,
+	ImagePresetLarge:     1080,
+}
+
+func (p *GetImageParams) Validate() error {
+	if p.Width.Valid && p.Preset.Valid {
+		return &AppError{
+			Message: "Укажите либо ширину, либо пресет изображения",
+			Code:    ErrCodeBadRequest,
+		}
+	}
+
+	if p.Preset.Valid {
+		width, ok := imagePresetWidths[p.Preset.String]
+		if !ok {
+			return &AppError{
+				Message: "Неизвестный пресет изображения",
+				Code:    ErrCodeBadRequest,
+			}
+		}
+		p.Width = null.IntFrom(width)
+	}
+
+	if p.Width.Valid && (p.Width.Int64 < 1 || p.Width.Int64 > 4096) {
+		return &AppError{
+			Message: "Ширина изображения должна быть от 1 до 4096 пикселей",
+			Code:    ErrCodeBadRequest,
+		}
+	}
+
+	if p.Format.Valid && p.Format.String != ImageFormatWebP && p.Format.String != ImageFormatJPEG {
+		return &AppError{
+			Message: "Допустимые форматы изображения: webp, jpeg",
+			Code:    ErrCodeBadRequest,
+		}
+	}
+
+	return nil
+}
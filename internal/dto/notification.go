@@ -0,0 +1,58 @@
+package dto
+
+import (
+	"gopkg.in/guregu/null.v3"
+	"news-app-api/internal/entity"
+)
+
+type (
+	GetNotificationListParams struct {
+		UserID     int64
+		UnreadOnly null.Bool `query:"unreadOnly"`
+		Limit      null.Int  `query:"limit"`
+		Offset     null.Int  `query:"offset"`
+	}
+
+	GetNotificationListResult struct {
+		Total int64                 `json:"total"`
+		Items []entity.Notification `json:"items"`
+	}
+
+	GetUnreadNotificationCountResult struct {
+		Count int64 `json:"count"`
+	}
+
+	MarkNotificationReadParams struct {
+		NotificationID int64 `params:"notification_id"`
+		UserID         int64 `params:"-"`
+	}
+
+	UpdateNotificationPreferenceListParams struct {
+		UserID int64                           `json:"-"`
+		Items  []entity.NotificationPreference `json:"items"`
+	}
+)
+
+var NotificationTypes = []string{
+	entity.NotificationTypeNews,
+	entity.NotificationTypeBreakingNews,
+}
+
+func (p *UpdateNotificationPreferenceListParams) Validate() error {
+	for _, item := range p.Items {
+		known := false
+		for _, t := range NotificationTypes {
+			if item.Type == t {
+				known = true
+				break
+			}
+		}
+		if !known {
+			return &AppError{
+				Message: "Неизвестный тип уведомлений",
+				Code:    ErrCodeBadRequest,
+			}
+		}
+	}
+	return nil
+}
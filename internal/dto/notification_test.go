@@ -0,0 +1,26 @@
+package dto
+
+import (
+	"news-app-api/internal/entity"
+	"testing"
+)
+
+func TestUpdateNotificationPreferenceListParamsValidate(t *testing.T) {
+	tests := []struct {
+		typ   string
+		valid bool
+	}{
+		{entity.NotificationTypeNews, true},
+		{entity.NotificationTypeBreakingNews, true},
+		{"reply", false},
+		{"", false},
+	}
+
+	for _, tt := range tests {
+		p := UpdateNotificationPreferenceListParams{Items: []entity.NotificationPreference{{Type: tt.typ, Enabled: true}}}
+		err := p.Validate()
+		if (err == nil) != tt.valid {
+			t.Errorf("Validate(%q) = %v, want valid=%v", tt.typ, err, tt.valid)
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package dto
+
+import (
+	"encoding/base64"
+	"net"
+	"net/url"
+	"strings"
+)
+
+type (
+	PushSubscriptionKeys struct {
+		P256dh string `json:"p256dh"`
+		Auth   string `json:"auth"`
+	}
+
+	RegisterPushSubscriptionParams struct {
+		UserID               int64                `json:"-"`
+		Endpoint             string               `json:"endpoint"`
+		Keys                 PushSubscriptionKeys `json:"keys"`
+		AllowPrivateNetworks bool                 `json:"-"`
+	}
+
+	UnregisterPushSubscriptionParams struct {
+		UserID   int64  `json:"-"`
+		Endpoint string `json:"endpoint"`
+	}
+
+	GetVAPIDPublicKeyResult struct {
+		PublicKey string `json:"publicKey"`
+	}
+)
+
+func (p *RegisterPushSubscriptionParams) Validate() error {
+	if len(p.Endpoint) > 2048 {
+		return &AppError{
+			Message: "Максимальная длина адреса push-подписки - 2048 символов",
+			Code:    ErrCodeBadRequest,
+		}
+	}
+
+	u, err := url.Parse(p.Endpoint)
+	if err != nil || u.Host == "" ||
+		!(u.Scheme == "https" || u.Scheme == "http" && p.AllowPrivateNetworks) ||
+		!p.AllowPrivateNetworks && isPrivateHost(u.Hostname()) {
+		return &AppError{
+			Message: "Недопустимый адрес push-подписки",
+			Code:    ErrCodeBadRequest,
+		}
+	}
+
+	if n, err := decodedLen(p.Keys.P256dh); err != nil || n != 65 {
+		return &AppError{
+			Message: "Недопустимый ключ p256dh",
+			Code:    ErrCodeBadRequest,
+		}
+	} else if n, err := decodedLen(p.Keys.Auth); err != nil || n != 16 {
+		return &AppError{
+			Message: "Недопустимый ключ auth",
+			Code:    ErrCodeBadRequest,
+		}
+	}
+
+	return nil
+}
+
+func isPrivateHost(host string) bool {
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && (ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsMulticast())
+}
+
+func decodedLen(s string) (int, error) {
+	s = strings.TrimRight(s, "=")
+	s = strings.NewReplacer("+", "-", "/", "_").Replace(s)
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	return len(b), err
+}
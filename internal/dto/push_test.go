@@ -0,0 +1,39 @@
+package dto
+
+import "testing"
+
+func TestRegisterPushSubscriptionParamsValidate(t *testing.T) {
+	keys := PushSubscriptionKeys{
+		P256dh: "BNcRdreALRFXTkOOUHK1EtK2wtaz5Ry4YfYCA_0QTpQtUbVlUls0VJXg7A8u-Ts1XbjhazAkj7I99e8QcYP7DkM",
+		Auth:   "tBHItJI5svbpez7KI4CCXg",
+	}
+
+	tests := []struct {
+		endpoint             string
+		allowPrivateNetworks bool
+		valid                bool
+	}{
+		{"https://fcm.googleapis.com/fcm/send/abc", false, true},
+		{"http://fcm.googleapis.com/fcm/send/abc", false, false},
+		{"https://localhost/push", false, false},
+		{"https://127.0.0.1/push", false, false},
+		{"https://10.0.0.5/push", false, false},
+		{"https://169.254.169.254/latest", false, false},
+		{"https://[::1]/push", false, false},
+		{"http://127.0.0.1:8080/push", true, true},
+		{"ftp://push.example/abc", true, false},
+	}
+
+	for _, tt := range tests {
+		p := RegisterPushSubscriptionParams{
+			Endpoint:             tt.endpoint,
+			Keys:                 keys,
+			AllowPrivateNetworks: tt.allowPrivateNetworks,
+		}
+		err := p.Validate()
+		if (err == nil) != tt.valid {
+			t.Errorf("Validate(%q, allowPrivate=%v) = %v, want valid=%v",
+				tt.endpoint, tt.allowPrivateNetworks, err, tt.valid)
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package dto
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var (
+	registrationNumberTranslit = strings.NewReplacer(
+		"П", "P", "Р", "P", "И", "I", "Э", "E", "Е", "E", "Л", "L", "А", "A",
+		"Ф", "F", "С", "S", "C", "S", "Т", "T", "У", "U", "Y", "U",
+		"№", " ", "#", " ", "N", " ", "Н", " ", "O", " ", "О", " ",
+		"-", " ", "–", " ", "—", " ", ".", " ", "_", " ", "/", " ", ":", " ",
+	)
+
+	registrationNumberPattern = regexp.MustCompile(`^(PI|EL|IA) ?(FS|TU)? ?(\d{2}) ?(\d{3,6})$`)
+	legacyRegistrationPattern = regexp.MustCompile(`^\d{1,10}$`)
+
+	registrationNumberSeries = map[string]string{
+		"PI": "ПИ",
+		"EL": "ЭЛ",
+		"IA": "ИА",
+	}
+
+	registrationNumberAuthorities = map[string]string{
+		"FS": "ФС",
+		"TU": "ТУ",
+	}
+)
+
+func ParseRegistrationNumber(s string) (string, bool) {
+	key := registrationNumberTranslit.Replace(strings.ToUpper(s))
+	key = strings.Join(strings.Fields(key), " ")
+
+	m := registrationNumberPattern.FindStringSubmatch(key)
+	if m == nil {
+		return "", false
+	}
+
+	if m[2] == "" {
+		return fmt.Sprintf("%s № %s-%s", registrationNumberSeries[m[1]], m[3], m[4]), true
+	}
+	return fmt.Sprintf(
+		"%s № %s %s-%s",
+		registrationNumberSeries[m[1]],
+		registrationNumberAuthorities[m[2]],
+		m[3],
+		m[4],
+	), true
+}
+
+func ParseLoginRegistrationNumber(s string) (string, bool) {
+	if canonical, ok := ParseRegistrationNumber(s); ok {
+		return canonical, true
+	}
+
+	digits := strings.TrimSpace(s)
+	if !legacyRegistrationPattern.MatchString(digits) {
+		return "", false
+	}
+
+	digits = strings.TrimLeft(digits, "0")
+	if digits == "" {
+		digits = "0"
+	}
+	return digits, true
+}
@@ -0,0 +1,138 @@
+package dto
+
+import (
+	"os"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestParseRegistrationNumber(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+		ok   bool
+	}{
+		{"ЭЛ № ФС 77-12345", "ЭЛ № ФС 77-12345", true},
+		{"ЭЛ № ФС 77 - 12345", "ЭЛ № ФС 77-12345", true},
+		{"  ЭЛ  №  ФС  77  –  12345  ", "ЭЛ № ФС 77-12345", true},
+		{"ЭЛ № ФС 77—12345", "ЭЛ № ФС 77-12345", true},
+		{"ЭЛ №ФС77-12345", "ЭЛ № ФС 77-12345", true},
+		{"ЭЛФС7712345", "ЭЛ № ФС 77-12345", true},
+		{"ЭЛ ФС 77 12345", "ЭЛ № ФС 77-12345", true},
+		{"эл № фс 77-12345", "ЭЛ № ФС 77-12345", true},
+		{"ЭЛ № ФС 77.12345", "ЭЛ № ФС 77-12345", true},
+		{"ЭЛ № ФС 77/12345", "ЭЛ № ФС 77-12345", true},
+		{"ЭЛ_№_ФС_77_12345", "ЭЛ № ФС 77-12345", true},
+		{"ЭЛ # ФС 77:12345", "ЭЛ № ФС 77-12345", true},
+		{"ПИ № ТУ 50-00123", "ПИ № ТУ 50-00123", true},
+		{"ИА № ФС 77-123456", "ИА № ФС 77-123456", true},
+		{"ПИ № 77-123", "ПИ № 77-123", true},
+		{"ЭЛ77123", "ЭЛ № 77-123", true},
+
+		// Latin letters that look like Cyrillic ones.
+		{"EL № FS 77-12345", "ЭЛ № ФС 77-12345", true},
+		{"EL No FS 77-12345", "ЭЛ № ФС 77-12345", true},
+		{"EL N FS 77-12345", "ЭЛ № ФС 77-12345", true},
+		{"ЭЛ № ФC 77-12345", "ЭЛ № ФС 77-12345", true},
+		{"ЭЛ № Фc 77-12345", "ЭЛ № ФС 77-12345", true},
+		{"ПИ № TY 50-00123", "ПИ № ТУ 50-00123", true},
+		{"PI № ТУ 50-00123", "ПИ № ТУ 50-00123", true},
+		{"ИA № ФС 77-12345", "ИА № ФС 77-12345", true},
+		{"ЭЛ Nо ФС 77-12345", "ЭЛ № ФС 77-12345", true},
+
+		{"", "", false},
+		{"12345", "", false},
+		{"ЭЛ № ФС 7-12345", "", false},
+		{"ЭЛ № ФС 777-12", "", false},
+		{"ЭЛ № ФС 77-12", "", false},
+		{"ЭЛ № ФС 77-1234567", "", false},
+		{"АБ № ФС 77-12345", "", false},
+		{"ЭЛ № МВ 77-12345", "", false},
+		{"ЭЛ № ФС ТУ 77-12345", "", false},
+		{"ЭЛ № ФС 77-12345 от 2020", "", false},
+		{"ЭЛ № ФС 77-12345-1", "", false},
+		{"ЭЛ № ФС ७७-12345", "", false},
+	}
+
+	for _, tt := range tests {
+		got, ok := ParseRegistrationNumber(tt.in)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("ParseRegistrationNumber(%q) = %q, %v, want %q, %v", tt.in, got, ok, tt.want, tt.ok)
+		}
+
+		migrated, ok := migrateRegistrationNumber(t, tt.in)
+		if migrated != tt.want || ok != tt.ok {
+			t.Errorf("migration 000030 canonicalizes %q as %q, %v, want %q, %v", tt.in, migrated, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestParseLoginRegistrationNumber(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+		ok   bool
+	}{
+		{"ЭЛ № ФС 77 - 12345", "ЭЛ № ФС 77-12345", true},
+		{"EL No FS 77-12345", "ЭЛ № ФС 77-12345", true},
+		{"12345", "12345", true},
+		{" 12345 ", "12345", true},
+		{"0012345", "12345", true},
+		{"000", "0", true},
+		{"1234567890", "1234567890", true},
+
+		{"", "", false},
+		{"12345678901", "", false},
+		{"12 345", "", false},
+		{"12-345", "", false},
+		{"-12345", "", false},
+		{"12345a", "", false},
+		{"ЭЛ № ФС 7-12345", "", false},
+	}
+
+	for _, tt := range tests {
+		got, ok := ParseLoginRegistrationNumber(tt.in)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("ParseLoginRegistrationNumber(%q) = %q, %v, want %q, %v", tt.in, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+// migrateRegistrationNumber applies the TRANSLATE and REGEXP_MATCH of migration 000030 to s.
+func migrateRegistrationNumber(t *testing.T, s string) (string, bool) {
+	t.Helper()
+
+	sql, err := os.ReadFile("../../migrations/000030_canonicalize_registration_numbers.up.sql")
+	if err != nil {
+		t.Fatal(err)
+	}
+	translate := regexp.MustCompile(`TRANSLATE\(\s*UPPER\(Num_reg_media_r\),\s*'([^']*)',\s*'([^']*)'`).
+		FindSubmatch(sql)
+	match := regexp.MustCompile(`'(\^\(PI[^']*)'`).FindSubmatch(sql)
+	if translate == nil || match == nil {
+		t.Fatal("migration 000030 has no TRANSLATE or REGEXP_MATCH")
+	}
+
+	from, to := []rune(string(translate[1])), []rune(string(translate[2]))
+	if len(from) != len(to) {
+		t.Fatalf("TRANSLATE maps %d characters to %d", len(from), len(to))
+	}
+	key := strings.Map(func(r rune) rune {
+		for i, f := range from {
+			if f == r {
+				return to[i]
+			}
+		}
+		return r
+	}, strings.ToUpper(s))
+	key = regexp.MustCompile(`\s+`).ReplaceAllString(strings.Trim(key, " "), " ")
+
+	m := regexp.MustCompile(string(match[1])).FindStringSubmatch(key)
+	if m == nil {
+		return "", false
+	}
+	return registrationNumberSeries[m[1]] + " № " +
+		strings.TrimPrefix(registrationNumberAuthorities[m[2]]+" ", " ") +
+		m[3] + "-" + m[4], true
+}
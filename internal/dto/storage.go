@@ -0,0 +1,25 @@
+package dto
+
+import (
+	"news-app-api/internal/entity"
+	"time"
+)
+
+type (
+	CheckStorageParams struct {
+		DeleteOrphans bool
+		GracePeriod   time.Duration
+	}
+
+	CheckStorageResult struct {
+		Files             int64                        `json:"files"`
+		Bytes             int64                        `json:"bytes"`
+		References        int64                        `json:"references"`
+		Orphans           []entity.StoredFile          `json:"orphans"`
+		OrphanBytes       int64                        `json:"orphanBytes"`
+		DeletedOrphans    int64                        `json:"deletedOrphans"`
+		Missing           []string                     `json:"missing"`
+		SizeMismatches    []entity.StorageSizeMismatch `json:"sizeMismatches"`
+		UnreferencedBlobs int64                        `json:"unreferencedBlobs"`
+	}
+)
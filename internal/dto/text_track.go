@@ -0,0 +1,148 @@
+package dto
+
+import (
+	"gopkg.in/guregu/null.v3"
+	"io"
+	"news-app-api/internal/entity"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+type (
+	PutTextTrackParams struct {
+		NewsID   int64  `params:"news_id"`
+		Target   string `params:"target"`
+		Kind     string `params:"kind"`
+		Language string `params:"language"`
+		MediaID  int64
+		File     io.Reader
+		Label    string
+	}
+
+	GetTextTracksParams struct {
+		NewsID int64  `params:"news_id"`
+		Target string `params:"target"`
+	}
+
+	GetTextTracksResult struct {
+		Items []entity.TextTrack `json:"items"`
+	}
+
+	GetTextTrackParams struct {
+		AttachmentSignature
+		NewsID   int64  `params:"news_id"`
+		Target   string `params:"target"`
+		Kind     string `params:"kind"`
+		Language string `params:"language"`
+	}
+
+	DeleteTextTrackParams struct {
+		NewsID   int64  `params:"news_id"`
+		Target   string `params:"target"`
+		Kind     string `params:"kind"`
+		Language string `params:"language"`
+		MediaID  int64
+	}
+
+	SearchNewsParams struct {
+		UserID int64
+		Query  null.String `query:"q"`
+		Limit  null.Int    `query:"limit"`
+		Offset null.Int    `query:"offset"`
+	}
+
+	SearchNewsResult struct {
+		Total int64                 `json:"total"`
+		Items []entity.NewsListItem `json:"items"`
+	}
+)
+
+var languageTagPattern = regexp.MustCompile(`^[a-zA-Z]{2,3}(-[a-zA-Z0-9]{1,8})*$`)
+
+func (p *PutTextTrackParams) Validate() (err error) {
+	p.Language, err = validateTextTrack(p.Target, p.Kind, p.Language)
+	if err != nil {
+		return
+	}
+
+	p.Label = strings.TrimSpace(p.Label)
+	if utf8.RuneCountInString(p.Label) > 64 {
+		return &AppError{
+			Message: "Максимальная длина названия дорожки - 64 символа",
+			Code:    ErrCodeBadRequest,
+		}
+	}
+	return nil
+}
+
+func (p *GetTextTracksParams) Validate() error {
+	return validateTextTrackTarget(p.Target)
+}
+
+func (p *GetTextTrackParams) Validate() (err error) {
+	p.Language, err = validateTextTrack(p.Target, p.Kind, p.Language)
+	return
+}
+
+func (p *DeleteTextTrackParams) Validate() (err error) {
+	p.Language, err = validateTextTrack(p.Target, p.Kind, p.Language)
+	return
+}
+
+func (p *SearchNewsParams) Validate() error {
+	p.Query.String = strings.TrimSpace(p.Query.String)
+	if p.Query.String == "" {
+		return &AppError{
+			Message: "Укажите поисковый запрос",
+			Code:    ErrCodeBadRequest,
+		}
+	} else if utf8.RuneCountInString(p.Query.String) > 256 {
+		return &AppError{
+			Message: "Максимальная длина поискового запроса - 256 символов",
+			Code:    ErrCodeBadRequest,
+		}
+	}
+	return nil
+}
+
+func validateTextTrackTarget(target string) error {
+	if target != entity.AttachmentKindAudio && target != entity.AttachmentKindVideo {
+		return &AppError{
+			Message: "Субтитры и расшифровки доступны только для аудио и видео",
+			Code:    ErrCodeBadRequest,
+		}
+	}
+	return nil
+}
+
+func validateTextTrack(target, kind, language string) (string, error) {
+	if err := validateTextTrackTarget(target); err != nil {
+		return "", err
+	}
+
+	if kind != entity.TextTrackKindSubtitles && kind != entity.TextTrackKindTranscript {
+		return "", &AppError{
+			Message: "Допустимые типы дорожек: subtitles, transcript",
+			Code:    ErrCodeBadRequest,
+		}
+	} else if len(language) > 35 || !languageTagPattern.MatchString(language) {
+		return "", &AppError{
+			Message: "Некорректный код языка",
+			Code:    ErrCodeBadRequest,
+		}
+	}
+
+	subtags := strings.Split(language, "-")
+	for i, tag := range subtags {
+		switch {
+		case i > 0 && len(tag) == 2:
+			subtags[i] = strings.ToUpper(tag)
+		case i > 0 && len(tag) == 4:
+			subtags[i] = strings.ToUpper(tag[:1]) + strings.ToLower(tag[1:])
+		default:
+			subtags[i] = strings.ToLower(tag)
+		}
+	}
+	return strings.Join(subtags, "-"), nil
+}
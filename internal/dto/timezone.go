@@ -0,0 +1,16 @@
+package dto
+
+import "time"
+
+// loadTimezone loads an IANA time zone. "Local" is rejected: Go resolves it to the server's zone and Postgres doesn't
+// know it.
+func loadTimezone(name string) (*time.Location, error) {
+	loc, err := time.LoadLocation(name)
+	if err != nil || name == "Local" || len(name) > 64 {
+		return nil, &AppError{
+			Message: "Неизвестный часовой пояс",
+			Code:    ErrCodeBadRequest,
+		}
+	}
+	return loc, nil
+}
@@ -0,0 +1,30 @@
+package dto
+
+import (
+	"io"
+)
+
+type (
+	CreateUploadParams struct {
+		MediaID  int64
+		Length   int64
+		Metadata map[string]string
+	}
+
+	GetUploadParams struct {
+		ID      string `params:"upload_id"`
+		MediaID int64
+	}
+
+	WriteUploadParams struct {
+		ID      string `params:"upload_id"`
+		MediaID int64
+		Offset  int64
+		Body    io.Reader
+	}
+
+	DeleteUploadParams struct {
+		ID      string `params:"upload_id"`
+		MediaID int64
+	}
+)
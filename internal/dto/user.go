@@ -2,7 +2,9 @@ package dto
 
 import (
 	"gopkg.in/guregu/null.v3"
+	"net/mail"
 	"news-app-api/internal/entity"
+	"strings"
 )
 
 type (
@@ -18,6 +20,31 @@ type (
 		Password string `json:"password"`
 	}
 
+	UpdateUserNameParams struct {
+		UserID int64  `json:"-"`
+		Name   string `json:"name"`
+	}
+
+	ChangeUserEmailParams struct {
+		UserID int64  `json:"-"`
+		Email  string `json:"email"`
+	}
+
+	ChangeUserEmailResult struct {
+		PendingEmail string `json:"pendingEmail"`
+		ExpiresAt    int64  `json:"expiresAt"`
+	}
+
+	ConfirmUserEmailParams struct {
+		Token string `query:"token"`
+	}
+
+	ChangeUserPasswordParams struct {
+		UserID          int64  `json:"-"`
+		CurrentPassword string `json:"currentPassword"`
+		NewPassword     string `json:"newPassword"`
+	}
+
 	GetSubscriptionListParams struct {
 		UserID int64    `params:"user_id"`
 		Limit  null.Int `query:"limit"`
@@ -54,3 +81,55 @@ func (p *RegisterUserParams) Validate() error {
 	}
 	return nil
 }
+
+func (p *UpdateUserNameParams) Validate() error {
+	p.Name = strings.TrimSpace(p.Name)
+	if p.Name == "" {
+		return &AppError{
+			Message: "ФИО не может быть пустым",
+			Code:    ErrCodeBadRequest,
+		}
+	} else if len(p.Name) > 255 {
+		return &AppError{
+			Message: "Максимальная длина ФИО - 255 символов",
+			Code:    ErrCodeBadRequest,
+		}
+	}
+	return nil
+}
+
+func (p *ChangeUserEmailParams) Validate() error {
+	p.Email = strings.TrimSpace(p.Email)
+	if len(p.Email) > 16 {
+		return &AppError{
+			Message: "Максимальная длина email - 16 символов",
+			Code:    ErrCodeBadRequest,
+		}
+	} else if !isEmail(p.Email) {
+		return &AppError{
+			Message: "Недопустимый email",
+			Code:    ErrCodeBadRequest,
+		}
+	}
+	return nil
+}
+
+func (p *ChangeUserPasswordParams) Validate() error {
+	if p.NewPassword == "" {
+		return &AppError{
+			Message: "Пароль не может быть пустым",
+			Code:    ErrCodeBadRequest,
+		}
+	} else if len(p.NewPassword) > 32 {
+		return &AppError{
+			Message: "Максимальная длина пароля - 32 символа",
+			Code:    ErrCodeBadRequest,
+		}
+	}
+	return nil
+}
+
+func isEmail(s string) bool {
+	addr, err := mail.ParseAddress(s)
+	return err == nil && addr.Address == s
+}
@@ -0,0 +1,117 @@
+package dto
+
+import (
+	"gopkg.in/guregu/null.v3"
+	"net/url"
+	"news-app-api/internal/entity"
+)
+
+type (
+	CreateWebhookParams struct {
+		MediaID    int64    `json:"-"`
+		URL        string   `json:"url"`
+		EventTypes []string `json:"eventTypes"`
+	}
+
+	UpdateWebhookParams struct {
+		WebhookID  int64    `json:"-" params:"webhook_id"`
+		MediaID    int64    `json:"-" params:"-"`
+		URL        string   `json:"url" params:"-"`
+		EventTypes []string `json:"eventTypes" params:"-"`
+		IsActive   bool     `json:"isActive" params:"-"`
+	}
+
+	WebhookParams struct {
+		WebhookID int64 `params:"webhook_id"`
+		MediaID   int64 `params:"-"`
+	}
+
+	GetWebhookDeliveryListParams struct {
+		WebhookID int64       `params:"webhook_id"`
+		MediaID   int64       `params:"-"`
+		Status    null.String `query:"status"`
+		Limit     null.Int    `query:"limit"`
+		Offset    null.Int    `query:"offset"`
+	}
+
+	GetWebhookDeliveryListResult struct {
+		Total int64                    `json:"total"`
+		Items []entity.WebhookDelivery `json:"items"`
+	}
+
+	WebhookDeliveryParams struct {
+		WebhookID  int64 `params:"webhook_id"`
+		DeliveryID int64 `params:"delivery_id"`
+		MediaID    int64 `params:"-"`
+	}
+)
+
+var WebhookEventTypes = []string{
+	entity.WebhookEventSubscriptionCreated,
+	entity.WebhookEventSubscriptionDeleted,
+	entity.WebhookEventNewsFavorited,
+	entity.WebhookEventNewsPublished,
+}
+
+func (p *CreateWebhookParams) Validate() error {
+	return validateWebhook(p.URL, p.EventTypes)
+}
+
+func (p *UpdateWebhookParams) Validate() error {
+	return validateWebhook(p.URL, p.EventTypes)
+}
+
+func (p *GetWebhookDeliveryListParams) Validate() error {
+	if p.Status.Valid &&
+		p.Status.String != entity.WebhookDeliveryPending &&
+		p.Status.String != entity.WebhookDeliverySucceeded &&
+		p.Status.String != entity.WebhookDeliveryFailed {
+		return &AppError{
+			Message: "Неизвестный статус доставки",
+			Code:    ErrCodeBadRequest,
+		}
+	}
+	return nil
+}
+
+func validateWebhook(rawURL string, eventTypes []string) error {
+	if len(rawURL) > 2048 {
+		return &AppError{
+			Message: "Максимальная длина адреса вебхука - 2048 символов",
+			Code:    ErrCodeBadRequest,
+		}
+	}
+
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Host == "" || !(u.Scheme == "https" || u.Scheme == "http") {
+		return &AppError{
+			Message: "Недопустимый адрес вебхука",
+			Code:    ErrCodeBadRequest,
+		}
+	}
+
+	if len(eventTypes) == 0 {
+		return &AppError{
+			Message: "Необходимо выбрать хотя бы один тип событий",
+			Code:    ErrCodeBadRequest,
+		}
+	}
+
+	for _, t := range eventTypes {
+		known := false
+		for _, k := range WebhookEventTypes {
+			if t == k {
+				known = true
+				break
+			}
+		}
+		if !known {
+			return &AppError{
+				Message: "Неизвестный тип событий",
+				Code:    ErrCodeBadRequest,
+			}
+		}
+	}
+
+	return nil
+}
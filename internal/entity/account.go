@@ -0,0 +1,24 @@
+package entity
+
+import "encoding/json"
+
+type (
+	AccountDeletion struct {
+		RequestedAt int64 `json:"requestedAt"`
+		ScheduledAt int64 `json:"scheduledAt"`
+	}
+
+	AccountExport struct {
+		Profile                 json.RawMessage
+		Subscriptions           json.RawMessage
+		SubscriptionHistory     json.RawMessage
+		Favorites               json.RawMessage
+		FeedReadState           json.RawMessage
+		HiddenNews              json.RawMessage
+		MutedMedia              json.RawMessage
+		Notifications           json.RawMessage
+		NotificationPreferences json.RawMessage
+		Digest                  json.RawMessage
+		PushSubscriptions       json.RawMessage
+	}
+)
@@ -0,0 +1,64 @@
+package entity
+
+import "gopkg.in/guregu/null.v3"
+
+const (
+	AttachmentKindAudio   = "audio"
+	AttachmentKindImage   = "image"
+	AttachmentKindVideo   = "video"
+	AttachmentKindGallery = "gallery"
+)
+
+type (
+	Attachment struct {
+		ID          int64              `json:"id"`
+		NewsID      int64              `json:"newsId"`
+		Kind        string             `json:"kind"`
+		ContentType string             `json:"contentType"`
+		Size        int64              `json:"size"`
+		ContentHash string             `json:"-"`
+		StorageKey  string             `json:"-"`
+		BlobHash    null.String        `json:"-"`
+		Metadata    AttachmentMetadata `json:"metadata"`
+		CreatedAt   int64              `json:"createdAt"`
+		UpdatedAt   int64              `json:"updatedAt"`
+	}
+
+	AttachmentMetadata struct {
+		Width      int     `json:"width,omitempty"`
+		Height     int     `json:"height,omitempty"`
+		Duration   float64 `json:"duration,omitempty"`
+		Bitrate    int64   `json:"bitrate,omitempty"`
+		SampleRate int     `json:"sampleRate,omitempty"`
+		Channels   int     `json:"channels,omitempty"`
+		Codec      string  `json:"codec,omitempty"`
+	}
+
+	AttachmentVariant struct {
+		Width       int         `json:"width"`
+		Height      int         `json:"height"`
+		ContentType string      `json:"contentType"`
+		Size        int64       `json:"size"`
+		ContentHash string      `json:"-"`
+		StorageKey  string      `json:"-"`
+		BlobHash    null.String `json:"-"`
+		UpdatedAt   int64       `json:"-"`
+	}
+
+	Waveform struct {
+		Duration    float64              `json:"duration"`
+		Resolutions []WaveformResolution `json:"resolutions"`
+	}
+
+	WaveformResolution struct {
+		Resolution int       `json:"resolution"`
+		Peaks      []float64 `json:"peaks"`
+	}
+
+	Thumbnail struct {
+		Width       int
+		Height      int
+		ContentType string
+		Data        []byte
+	}
+)
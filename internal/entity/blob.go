@@ -0,0 +1,17 @@
+package entity
+
+import "gopkg.in/guregu/null.v3"
+
+type (
+	Blob struct {
+		ContentHash string
+		StorageKey  string
+		Size        int64
+		CreatedAt   int64
+	}
+
+	StoredObject struct {
+		StorageKey string
+		BlobHash   null.String
+	}
+)
@@ -0,0 +1,22 @@
+package entity
+
+import "gopkg.in/guregu/null.v3"
+
+type (
+	DigestPreference struct {
+		Frequency   string   `json:"frequency"`
+		SendHour    int      `json:"sendHour"`
+		SendWeekday int      `json:"sendWeekday"`
+		Timezone    string   `json:"timezone"`
+		LastSentAt  null.Int `json:"lastSentAt"`
+		NextSendAt  null.Int `json:"nextSendAt"`
+	}
+
+	DigestRecipient struct {
+		UserID           int64
+		Name             string
+		Email            string
+		UnsubscribeToken string
+		Preference       DigestPreference
+	}
+)
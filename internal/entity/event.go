@@ -0,0 +1,42 @@
+package entity
+
+const (
+	EventNewsCreated        = "news.created"
+	EventSubscriptionToggle = "subscription.toggled"
+	EventFavoriteToggle     = "favorite.toggled"
+)
+
+type (
+	Event interface {
+		EventType() string
+	}
+
+	NewsCreatedEvent struct {
+		News    News
+		MediaID int64
+	}
+
+	SubscriptionToggledEvent struct {
+		MediaID      int64
+		UserID       int64
+		IsSubscribed bool
+	}
+
+	FavoriteToggledEvent struct {
+		NewsID     int64
+		UserID     int64
+		IsFavorite bool
+	}
+)
+
+func (NewsCreatedEvent) EventType() string {
+	return EventNewsCreated
+}
+
+func (SubscriptionToggledEvent) EventType() string {
+	return EventSubscriptionToggle
+}
+
+func (FavoriteToggledEvent) EventType() string {
+	return EventFavoriteToggle
+}
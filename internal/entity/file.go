@@ -0,0 +1,15 @@
+package entity
+
+import (
+	"io"
+	"time"
+)
+
+type (
+	File interface {
+		io.ReadSeekCloser
+		io.ReaderAt
+		Size() int64
+		ModTime() time.Time
+	}
+)
@@ -0,0 +1,20 @@
+package entity
+
+type (
+	GalleryImage struct {
+		Attachment
+		Caption  string `json:"caption"`
+		AltText  string `json:"altText"`
+		Credit   string `json:"credit"`
+		Position int    `json:"position"`
+		URL      string `json:"url"`
+	}
+
+	NewsGalleryImage struct {
+		ID       int64  `json:"id"`
+		Caption  string `json:"caption"`
+		AltText  string `json:"altText"`
+		Credit   string `json:"credit"`
+		Position int    `json:"position"`
+	}
+)
@@ -0,0 +1,12 @@
+package entity
+
+type (
+	Mail struct {
+		From    string
+		To      string
+		Subject string
+		Text    string
+		HTML    string
+		Headers map[string]string
+	}
+)
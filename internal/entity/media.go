@@ -1,5 +1,7 @@
 package entity
 
+import "gopkg.in/guregu/null.v3"
+
 type (
 	Editor struct {
 		FirstName string `json:"firstName"`
@@ -8,7 +10,7 @@ type (
 
 	Media struct {
 		ID                 int64  `json:"id"`
-		RegistrationNumber int64  `json:"registrationNumber"`
+		RegistrationNumber string `json:"registrationNumber"`
 		Name               string `json:"name"`
 		Email              string `json:"email"`
 		Editor             Editor `json:"editor"`
@@ -17,10 +19,59 @@ type (
 
 	MediaListItem struct {
 		ID                 int64  `json:"id"`
-		RegistrationNumber int64  `json:"registrationNumber"`
+		RegistrationNumber string `json:"registrationNumber"`
 		Name               string `json:"name"`
 		Email              string `json:"email"`
 		Editor             Editor `json:"editor"`
 		SubscriptionCount  int64  `json:"subscriptionCount"`
+		IsSubscribed       bool   `json:"isSubscribed"`
+	}
+
+	MediaSocialLink struct {
+		Network string `json:"network"`
+		URL     string `json:"url"`
+	}
+
+	MediaStats struct {
+		SubscriptionCount int64    `json:"subscriptionCount"`
+		NewsCount         int64    `json:"newsCount"`
+		FavoriteCount     int64    `json:"favoriteCount"`
+		LastPublishedAt   null.Int `json:"lastPublishedAt"`
+	}
+
+	MediaProfile struct {
+		ID                 int64             `json:"id"`
+		RegistrationNumber string            `json:"registrationNumber"`
+		Name               string            `json:"name"`
+		Email              string            `json:"email"`
+		Editor             Editor            `json:"editor"`
+		Description        string            `json:"description"`
+		Website            null.String       `json:"website"`
+		SocialLinks        []MediaSocialLink `json:"socialLinks"`
+		LogoURL            null.String       `json:"logoUrl"`
+		CoverURL           null.String       `json:"coverUrl"`
+		LogoUpdatedAt      null.Int          `json:"-"`
+		CoverUpdatedAt     null.Int          `json:"-"`
+		IsSubscribed       bool              `json:"isSubscribed"`
+		CreatedAt          int64             `json:"createdAt"`
+		Stats              MediaStats        `json:"stats"`
+	}
+
+	Subscriber struct {
+		UserID       int64    `json:"userId"`
+		Name         string   `json:"name"`
+		SubscribedAt null.Int `json:"subscribedAt"`
+	}
+
+	SubscriptionStatsDay struct {
+		Date         string `json:"date"`
+		Subscribed   int64  `json:"subscribed"`
+		Unsubscribed int64  `json:"unsubscribed"`
+		Net          int64  `json:"net"`
+	}
+
+	MutedMedia struct {
+		Media MediaListItem `json:"media"`
+		Until int64         `json:"until"`
 	}
 )
@@ -1,20 +1,52 @@
 package entity
 
+import "gopkg.in/guregu/null.v3"
+
 type (
 	News struct {
 		ID                      int64  `json:"id"`
-		MediaRegistrationNumber int64  `json:"mediaRegistrationNumber"`
+		MediaRegistrationNumber string `json:"mediaRegistrationNumber"`
 		Title                   string `json:"title"`
 		Text                    string `json:"text"`
+		IsBreaking              bool   `json:"isBreaking"`
 		CreatedAt               int64  `json:"createdAt"`
 	}
 
 	NewsListItem struct {
-		ID         int64         `json:"id"`
-		Media      MediaListItem `json:"media"`
-		Title      string        `json:"title"`
-		Text       string        `json:"text"`
-		IsFavorite bool          `json:"isFavorite"`
-		CreatedAt  int64         `json:"createdAt"`
+		ID          int64            `json:"id"`
+		Media       MediaListItem    `json:"media"`
+		Title       string           `json:"title"`
+		Text        string           `json:"text"`
+		IsFavorite  bool             `json:"isFavorite"`
+		CreatedAt   int64            `json:"createdAt"`
+		Attachments []NewsAttachment `json:"attachments"`
+	}
+
+	NewsAttachment struct {
+		Kind        string             `json:"kind"`
+		ContentType string             `json:"contentType"`
+		Size        int64              `json:"size"`
+		Version     string             `json:"version"`
+		URL         string             `json:"url"`
+		WaveformURL string             `json:"waveformUrl,omitempty"`
+		Metadata    AttachmentMetadata `json:"metadata"`
+		UpdatedAt   int64              `json:"updatedAt"`
+		Gallery     *NewsGalleryImage  `json:"gallery,omitempty"`
+	}
+
+	FeedCandidate struct {
+		NewsID        int64
+		MediaID       int64
+		CreatedAt     int64
+		IsRead        bool
+		FavoriteCount int64
+		MediaAffinity int64
+	}
+
+	FeedSession struct {
+		Token        string
+		UserID       int64
+		NewsIDs      []int64
+		CutoffNewsID null.Int
 	}
 )
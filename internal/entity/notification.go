@@ -0,0 +1,26 @@
+package entity
+
+import "gopkg.in/guregu/null.v3"
+
+const (
+	NotificationTypeNews         = "news"
+	NotificationTypeBreakingNews = "breaking_news"
+)
+
+type (
+	Notification struct {
+		ID        int64    `json:"id"`
+		Type      string   `json:"type"`
+		NewsID    null.Int `json:"newsId"`
+		MediaID   null.Int `json:"mediaId"`
+		Title     string   `json:"title"`
+		Body      string   `json:"body"`
+		IsRead    bool     `json:"isRead"`
+		CreatedAt int64    `json:"createdAt"`
+	}
+
+	NotificationPreference struct {
+		Type    string `json:"type"`
+		Enabled bool   `json:"enabled"`
+	}
+)
@@ -0,0 +1,18 @@
+package entity
+
+type (
+	PushSubscription struct {
+		ID       int64  `json:"id"`
+		UserID   int64  `json:"-"`
+		Endpoint string `json:"endpoint"`
+		P256dh   string `json:"-"`
+		Auth     string `json:"-"`
+	}
+
+	PushDeliveryJob struct {
+		DeliveryID   int64
+		Payload      string
+		Attempts     int
+		Subscription PushSubscription
+	}
+)
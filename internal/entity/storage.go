@@ -0,0 +1,26 @@
+package entity
+
+import (
+	"gopkg.in/guregu/null.v3"
+	"time"
+)
+
+type (
+	StoredFile struct {
+		Key     string    `json:"key"`
+		Size    int64     `json:"size"`
+		ModTime time.Time `json:"modTime"`
+	}
+
+	StorageReference struct {
+		Key      string
+		Size     null.Int
+		Optional bool
+	}
+
+	StorageSizeMismatch struct {
+		Key      string `json:"key"`
+		Expected int64  `json:"expected"`
+		Actual   int64  `json:"actual"`
+	}
+)
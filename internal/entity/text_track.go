@@ -0,0 +1,24 @@
+package entity
+
+const (
+	TextTrackKindSubtitles  = "subtitles"
+	TextTrackKindTranscript = "transcript"
+)
+
+type (
+	TextTrack struct {
+		ID          int64  `json:"id"`
+		NewsID      int64  `json:"newsId"`
+		Target      string `json:"target"`
+		Kind        string `json:"kind"`
+		Language    string `json:"language"`
+		Label       string `json:"label"`
+		ContentType string `json:"contentType"`
+		Size        int64  `json:"size"`
+		ContentHash string `json:"-"`
+		StorageKey  string `json:"-"`
+		URL         string `json:"url"`
+		CreatedAt   int64  `json:"createdAt"`
+		UpdatedAt   int64  `json:"updatedAt"`
+	}
+)
@@ -0,0 +1,31 @@
+package entity
+
+import "gopkg.in/guregu/null.v3"
+
+const (
+	UploadStatusUploading  = "uploading"
+	UploadStatusProcessing = "processing"
+	UploadStatusCompleted  = "completed"
+	UploadStatusFailed     = "failed"
+)
+
+type (
+	Upload struct {
+		ID        string      `json:"id"`
+		MediaID   int64       `json:"mediaId"`
+		NewsID    int64       `json:"newsId"`
+		Kind      string      `json:"kind"`
+		Length    int64       `json:"length"`
+		Offset    int64       `json:"offset"`
+		Status    string      `json:"status"`
+		Error     null.String `json:"error"`
+		CreatedAt int64       `json:"createdAt"`
+		ExpiresAt int64       `json:"expiresAt"`
+	}
+
+	UploadChunk struct {
+		Offset     int64
+		Size       int64
+		StorageKey string
+	}
+)
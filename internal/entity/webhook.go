@@ -0,0 +1,54 @@
+package entity
+
+import (
+	"encoding/json"
+	"gopkg.in/guregu/null.v3"
+)
+
+const (
+	WebhookEventSubscriptionCreated = "subscription.created"
+	WebhookEventSubscriptionDeleted = "subscription.deleted"
+	WebhookEventNewsFavorited       = "news.favorited"
+	WebhookEventNewsPublished       = "news.published"
+
+	WebhookDeliveryPending   = "pending"
+	WebhookDeliverySucceeded = "succeeded"
+	WebhookDeliveryFailed    = "failed"
+)
+
+type (
+	Webhook struct {
+		ID         int64    `json:"id"`
+		MediaID    int64    `json:"-"`
+		URL        string   `json:"url"`
+		Secret     string   `json:"secret"`
+		EventTypes []string `json:"eventTypes"`
+		IsActive   bool     `json:"isActive"`
+		CreatedAt  int64    `json:"createdAt"`
+	}
+
+	WebhookDelivery struct {
+		ID             int64           `json:"id"`
+		WebhookID      int64           `json:"webhookId"`
+		EventType      string          `json:"eventType"`
+		Payload        json.RawMessage `json:"payload"`
+		Status         string          `json:"status"`
+		Attempts       int             `json:"attempts"`
+		ResponseStatus null.Int        `json:"responseStatus"`
+		ResponseBody   null.String     `json:"responseBody"`
+		Error          null.String     `json:"error"`
+		ReplayOf       null.Int        `json:"replayOf"`
+		CreatedAt      int64           `json:"createdAt"`
+		LastAttemptAt  null.Int        `json:"lastAttemptAt"`
+		NextAttemptAt  null.Int        `json:"nextAttemptAt"`
+	}
+
+	WebhookDeliveryJob struct {
+		DeliveryID int64
+		EventType  string
+		Payload    string
+		Attempts   int
+		URL        string
+		Secret     string
+	}
+)
@@ -0,0 +1,57 @@
+package scheduler
+
+import (
+	"context"
+	log "github.com/sirupsen/logrus"
+	"sync"
+	"time"
+)
+
+type (
+	Job func(ctx context.Context) error
+
+	Scheduler struct {
+		wg sync.WaitGroup
+	}
+)
+
+func New() *Scheduler {
+	return &Scheduler{}
+}
+
+func (s *Scheduler) Every(ctx context.Context, name string, interval time.Duration, job Job) {
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			s.run(ctx, name, job)
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+func (s *Scheduler) Wait() {
+	s.wg.Wait()
+}
+
+func (s *Scheduler) run(ctx context.Context, name string, job Job) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.WithField("job", name).Errorf("Job panicked: %v", r)
+		}
+	}()
+
+	err := job(ctx)
+	if err != nil && ctx.Err() == nil {
+		log.WithField("job", name).Error(err.Error())
+	}
+}
@@ -0,0 +1,229 @@
+package usecase
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	log "github.com/sirupsen/logrus"
+	"io"
+	"news-app-api/internal/adapter"
+	"news-app-api/internal/dto"
+	"news-app-api/internal/entity"
+	"time"
+)
+
+type (
+	AccountUseCase interface {
+		Export(ctx context.Context, p dto.ExportAccountParams) ([]byte, error)
+		RequestDeletion(ctx context.Context, p dto.RequestAccountDeletionParams) (entity.AccountDeletion, error)
+		GetDeletion(ctx context.Context, userID int64) (entity.AccountDeletion, error)
+		CancelDeletion(ctx context.Context, userID int64) error
+		DeleteDueAccounts(ctx context.Context) error
+	}
+
+	AccountConfig struct {
+		DeletionGracePeriod time.Duration
+		MailFrom            string
+	}
+
+	accountUseCase struct {
+		accountRepo adapter.AccountRepository
+		userRepo    adapter.UserRepository
+		mailer      adapter.Mailer
+		cfg         AccountConfig
+	}
+)
+
+const accountDeletionBatchSize = 100
+
+func NewAccountUseCase(
+	accountRepo adapter.AccountRepository,
+	userRepo adapter.UserRepository,
+	mailer adapter.Mailer,
+	cfg AccountConfig,
+) AccountUseCase {
+	return &accountUseCase{accountRepo, userRepo, mailer, cfg}
+}
+
+func (u *accountUseCase) Export(ctx context.Context, p dto.ExportAccountParams) (data []byte, err error) {
+	defer func() {
+		if err != nil {
+			var appErr *dto.AppError
+			if !errors.As(err, &appErr) {
+				err = fmt.Errorf("AccountUseCase - Export: %w", err)
+			}
+		}
+	}()
+
+	_, err = u.userRepo.GetUserByID(ctx, p.UserID)
+	if err != nil {
+		return
+	}
+
+	export, err := u.accountRepo.GetAccountExport(ctx, p.UserID)
+	if err != nil {
+		return
+	}
+
+	files := []struct {
+		name string
+		data json.RawMessage
+	}{
+		{"profile.json", export.Profile},
+		{"subscriptions.json", export.Subscriptions},
+		{"subscription_history.json", export.SubscriptionHistory},
+		{"favorites.json", export.Favorites},
+		{"feed_read_state.json", export.FeedReadState},
+		{"hidden_news.json", export.HiddenNews},
+		{"muted_media.json", export.MutedMedia},
+		{"notifications.json", export.Notifications},
+		{"notification_preferences.json", export.NotificationPreferences},
+		{"digest.json", export.Digest},
+		{"push_subscriptions.json", export.PushSubscriptions},
+	}
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	now := time.Now()
+	for _, f := range files {
+		if len(f.data) == 0 {
+			f.data = json.RawMessage("null")
+		}
+
+		var indented bytes.Buffer
+		err = json.Indent(&indented, f.data, "", "  ")
+		if err != nil {
+			return
+		}
+		indented.WriteByte('\n')
+
+		var w io.Writer
+		w, err = zw.CreateHeader(&zip.FileHeader{
+			Name:     f.name,
+			Method:   zip.Deflate,
+			Modified: now,
+		})
+		if err != nil {
+			return
+		}
+		_, err = w.Write(indented.Bytes())
+		if err != nil {
+			return
+		}
+	}
+
+	err = zw.Close()
+	if err != nil {
+		return
+	}
+
+	return buf.Bytes(), nil
+}
+
+func (u *accountUseCase) RequestDeletion(
+	ctx context.Context,
+	p dto.RequestAccountDeletionParams,
+) (d entity.AccountDeletion, err error) {
+	defer func() {
+		if err != nil {
+			var appErr *dto.AppError
+			if !errors.As(err, &appErr) {
+				err = fmt.Errorf("AccountUseCase - RequestDeletion: %w", err)
+			}
+		}
+	}()
+
+	err = p.Validate()
+	if err != nil {
+		return
+	}
+
+	user, err := u.userRepo.GetUserByID(ctx, p.UserID)
+	if err != nil {
+		return
+	}
+
+	if user.Password != p.Password {
+		return d, &dto.AppError{
+			Message: "Неверный пароль",
+			Code:    dto.ErrCodeUnauthorized,
+		}
+	}
+
+	d, err = u.accountRepo.UpsertAccountDeletion(ctx, p.UserID, time.Now().Add(u.cfg.DeletionGracePeriod).Unix())
+	if err != nil {
+		return
+	}
+
+	if user.Email == "" {
+		return
+	}
+
+	err = u.mailer.Send(ctx, entity.Mail{
+		From:    u.cfg.MailFrom,
+		To:      user.Email,
+		Subject: "Удаление аккаунта",
+		Text: fmt.Sprintf(
+			"Здравствуйте, %s!\n\nМы получили запрос на удаление вашего аккаунта. "+
+				"Аккаунт и все связанные с ним данные будут удалены %s.\n\n"+
+				"Если вы передумали, войдите в аккаунт и отмените удаление до этого времени.\n",
+			user.Name,
+			time.Unix(d.ScheduledAt, 0).UTC().Format("02.01.2006 15:04 UTC"),
+		),
+	})
+	return
+}
+
+func (u *accountUseCase) GetDeletion(ctx context.Context, userID int64) (d entity.AccountDeletion, err error) {
+	defer func() {
+		if err != nil {
+			var appErr *dto.AppError
+			if !errors.As(err, &appErr) {
+				err = fmt.Errorf("AccountUseCase - GetDeletion: %w", err)
+			}
+		}
+	}()
+	return u.accountRepo.GetAccountDeletion(ctx, userID)
+}
+
+func (u *accountUseCase) CancelDeletion(ctx context.Context, userID int64) (err error) {
+	defer func() {
+		if err != nil {
+			var appErr *dto.AppError
+			if !errors.As(err, &appErr) {
+				err = fmt.Errorf("AccountUseCase - CancelDeletion: %w", err)
+			}
+		}
+	}()
+	return u.accountRepo.DeleteAccountDeletion(ctx, userID)
+}
+
+func (u *accountUseCase) DeleteDueAccounts(ctx context.Context) (err error) {
+	defer func() {
+		if err != nil {
+			var appErr *dto.AppError
+			if !errors.As(err, &appErr) {
+				err = fmt.Errorf("AccountUseCase - DeleteDueAccounts: %w", err)
+			}
+		}
+	}()
+
+	for {
+		var ids []int64
+		ids, err = u.accountRepo.DeleteDueAccounts(ctx, accountDeletionBatchSize)
+		if err != nil {
+			return
+		}
+
+		for _, id := range ids {
+			log.WithField("userID", id).Info("Deleted user account")
+		}
+
+		if len(ids) < accountDeletionBatchSize {
+			return
+		}
+	}
+}
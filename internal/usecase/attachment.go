@@ -0,0 +1,152 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	log "github.com/sirupsen/logrus"
+	"gopkg.in/guregu/null.v3"
+	"io"
+	"news-app-api/internal/dto"
+	"news-app-api/internal/entity"
+	"strconv"
+)
+
+type (
+	legacyAttachment struct {
+		keyFormat   string
+		contentType string
+	}
+)
+
+var legacyAttachments = map[string]legacyAttachment{
+	entity.AttachmentKindAudio: {"audio/%d.wav", "audio/wav"},
+	entity.AttachmentKindImage: {"image/%d.png", "image/png"},
+	entity.AttachmentKindVideo: {"video/%d.mp4", "video/mp4"},
+}
+
+func (u *newsUseCase) storeAttachment(
+	ctx context.Context,
+	newsID int64,
+	kind string,
+	r io.Reader,
+) (a entity.Attachment, err error) {
+	blob, err := u.putAttachmentBlob(ctx, kind, r)
+	if err != nil {
+		return
+	}
+	blob.NewsID = newsID
+
+	a, previous, err := u.attachmentRepo.UpsertAttachment(ctx, blob)
+	if err != nil {
+		return
+	}
+
+	if len(previous) == 0 {
+		previous = append(previous, entity.StoredObject{StorageKey: fmt.Sprintf(legacyAttachments[kind].keyFormat, newsID)})
+	}
+	return a, u.releaseStoredObjects(ctx, previous)
+}
+
+func (u *newsUseCase) putAttachmentBlob(
+	ctx context.Context,
+	kind string,
+	r io.Reader,
+) (a entity.Attachment, err error) {
+	format := attachmentFormats[kind]
+
+	contentType, body, err := sniffUpload(r)
+	if err != nil {
+		return
+	}
+
+	if _, ok := format.contentTypes[contentType]; !ok {
+		return a, &dto.AppError{
+			Message: format.typeMessage,
+			Code:    dto.ErrCodeBadRequest,
+		}
+	}
+
+	blob, err := u.storeBlob(ctx, limitUpload(body, format.maxSize, format.sizeMessage))
+	if err != nil {
+		return
+	}
+
+	metadata, err := u.extractMetadata(ctx, blob.StorageKey, contentType)
+	if err != nil {
+		return
+	}
+
+	return entity.Attachment{
+		Kind:        kind,
+		ContentType: contentType,
+		Size:        blob.Size,
+		ContentHash: blob.ContentHash,
+		StorageKey:  blob.StorageKey,
+		BlobHash:    null.StringFrom(blob.ContentHash),
+		Metadata:    metadata,
+	}, nil
+}
+
+func (u *newsUseCase) extractMetadata(
+	ctx context.Context,
+	key, contentType string,
+) (m entity.AttachmentMetadata, err error) {
+	f, err := u.blobStore.Open(ctx, key)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+
+	m, extractErr := u.metadataExtractor.Extract(contentType, f, f.Size())
+	var appErr *dto.AppError
+	if errors.As(extractErr, &appErr) {
+		return m, appErr
+	} else if extractErr != nil {
+		log.WithField("key", key).Warn(extractErr.Error())
+	}
+	return
+}
+
+func (u *newsUseCase) openAttachment(
+	ctx context.Context,
+	newsID int64,
+	kind string,
+) (res dto.GetAttachmentResult, err error) {
+	a, err := u.attachmentRepo.GetAttachment(ctx, newsID, kind)
+	var appErr *dto.AppError
+	if errors.As(err, &appErr) && appErr.Code == dto.ErrCodeNotFound {
+		legacy := legacyAttachments[kind]
+		res.ContentType = legacy.contentType
+		res.File, err = u.blobStore.Open(ctx, fmt.Sprintf(legacy.keyFormat, newsID))
+		return
+	} else if err != nil {
+		return
+	}
+
+	return u.openAttachmentBlob(ctx, a, a.StorageKey, a.ContentType, a.ContentHash, a.UpdatedAt)
+}
+
+func (u *newsUseCase) openAttachmentBlob(
+	ctx context.Context,
+	a entity.Attachment,
+	key, contentType, contentHash string,
+	updatedAt int64,
+) (res dto.GetAttachmentResult, err error) {
+	res.File, err = u.blobStore.Open(ctx, key)
+	if err != nil {
+		return
+	}
+	res.ContentType = contentType
+	res.ContentHash = contentHash
+	res.Version = attachmentVersion(a)
+	res.UpdatedAt = updatedAt
+	return
+}
+
+func attachmentVersion(a entity.Attachment) string {
+	if len(a.ContentHash) >= 16 {
+		return a.ContentHash[:16]
+	}
+	return strconv.FormatInt(a.UpdatedAt, 10)
+}
@@ -0,0 +1,111 @@
+package usecase
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"news-app-api/internal/dto"
+	"news-app-api/internal/entity"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	signedKindWaveform = "waveform"
+	signedKindTracks   = "tracks"
+)
+
+type (
+	AttachmentURLSigner interface {
+		Sign(url string) string
+		SignAttachments(attachments []entity.NewsAttachment)
+		SignNewsList(items []entity.NewsListItem)
+		Verify(kind, path string, p dto.AttachmentSignature) (int64, error)
+	}
+
+	AttachmentURLConfig struct {
+		Secret           string
+		TTL              time.Duration
+		RequireSignature []string
+	}
+
+	attachmentURLSigner struct {
+		secret   []byte
+		ttl      int64
+		required map[string]bool
+	}
+)
+
+func NewAttachmentURLSigner(cfg AttachmentURLConfig) AttachmentURLSigner {
+	required := make(map[string]bool, len(cfg.RequireSignature))
+	for _, kind := range cfg.RequireSignature {
+		required[kind] = true
+	}
+
+	ttl := int64(cfg.TTL / time.Second)
+	if ttl < 1 {
+		ttl = 1
+	}
+
+	return &attachmentURLSigner{[]byte(cfg.Secret), ttl, required}
+}
+
+func (s *attachmentURLSigner) Sign(url string) string {
+	if len(s.secret) == 0 {
+		return url
+	}
+	path, _, hasQuery := strings.Cut(url, "?")
+
+	// Expiry is rounded up to the end of the next TTL window so that a URL stays the same for a while and can be
+	// cached, which makes a fresh URL valid for between one and two TTLs.
+	expires := (time.Now().Unix()/s.ttl + 2) * s.ttl
+
+	sep := "?"
+	if hasQuery {
+		sep = "&"
+	}
+	return fmt.Sprintf("%s%sexp=%d&sig=%s", url, sep, expires, s.signature(path, expires))
+}
+
+func (s *attachmentURLSigner) SignAttachments(attachments []entity.NewsAttachment) {
+	for i := range attachments {
+		if attachments[i].Kind == entity.AttachmentKindAudio {
+			path, _, _ := strings.Cut(attachments[i].URL, "?")
+			attachments[i].WaveformURL = s.Sign(path + "/waveform")
+		}
+		attachments[i].URL = s.Sign(attachments[i].URL)
+	}
+}
+
+func (s *attachmentURLSigner) SignNewsList(items []entity.NewsListItem) {
+	for i := range items {
+		s.SignAttachments(items[i].Attachments)
+	}
+}
+
+func (s *attachmentURLSigner) Verify(kind, path string, p dto.AttachmentSignature) (expires int64, err error) {
+	if !s.required[kind] {
+		return
+	}
+
+	if !p.Expires.Valid || !p.Signature.Valid || p.Expires.Int64 <= time.Now().Unix() ||
+		!hmac.Equal([]byte(p.Signature.String), []byte(s.signature(path, p.Expires.Int64))) {
+		return 0, &dto.AppError{
+			Message: "Ссылка на файл недействительна или устарела",
+			Code:    dto.ErrCodeUnauthorized,
+		}
+	}
+	return p.Expires.Int64, nil
+}
+
+func (s *attachmentURLSigner) signature(path string, expires int64) string {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte(path + "?exp=" + strconv.FormatInt(expires, 10)))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func attachmentPath(newsID int64, kind string) string {
+	return fmt.Sprintf("/api/news/%d/%s", newsID, kind)
+}
@@ -0,0 +1,155 @@
+package usecase
+
+import (
+	"errors"
+	"gopkg.in/guregu/null.v3"
+	"net/url"
+	"news-app-api/internal/dto"
+	"news-app-api/internal/entity"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func signatureFromURL(t *testing.T, rawURL string) (string, dto.AttachmentSignature) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	q := u.Query()
+
+	var p dto.AttachmentSignature
+	if exp := q.Get("exp"); exp != "" {
+		n, err := strconv.ParseInt(exp, 10, 64)
+		if err != nil {
+			t.Fatal(err)
+		}
+		p.Expires = null.IntFrom(n)
+	}
+	if sig := q.Get("sig"); sig != "" {
+		p.Signature = null.StringFrom(sig)
+	}
+	return u.Path, p
+}
+
+func TestAttachmentURLSignerVerify(t *testing.T) {
+	signer := NewAttachmentURLSigner(AttachmentURLConfig{
+		Secret:           "secret",
+		TTL:              time.Hour,
+		RequireSignature: []string{entity.AttachmentKindAudio, signedKindTracks},
+	})
+	other := NewAttachmentURLSigner(AttachmentURLConfig{
+		Secret:           "other",
+		TTL:              time.Hour,
+		RequireSignature: []string{entity.AttachmentKindAudio},
+	})
+
+	path := attachmentPath(1, entity.AttachmentKindAudio)
+	signed := signer.Sign(path + "?v=abc")
+	if !strings.HasPrefix(signed, path+"?v=abc&exp=") {
+		t.Fatalf("signed url = %q", signed)
+	}
+	_, sig := signatureFromURL(t, signed)
+
+	now := time.Now().Unix()
+	if lifetime := sig.Expires.Int64 - now; lifetime < 3600 || lifetime > 7200 {
+		t.Errorf("lifetime = %d, want between one and two TTLs", lifetime)
+	}
+
+	expiresAt, err := signer.Verify(entity.AttachmentKindAudio, path, sig)
+	if err != nil || expiresAt != sig.Expires.Int64 {
+		t.Errorf("Verify(valid) = %d, %v", expiresAt, err)
+	}
+
+	tests := []struct {
+		name   string
+		signer AttachmentURLSigner
+		path   string
+		sig    dto.AttachmentSignature
+	}{
+		{"unsigned", signer, path, dto.AttachmentSignature{}},
+		{"other path", signer, attachmentPath(2, entity.AttachmentKindAudio), sig},
+		{"other secret", other, path, sig},
+		{"extended expiry", signer, path, dto.AttachmentSignature{
+			Expires:   null.IntFrom(sig.Expires.Int64 + 3600),
+			Signature: sig.Signature,
+		}},
+		{"tampered signature", signer, path, dto.AttachmentSignature{
+			Expires:   sig.Expires,
+			Signature: null.StringFrom(sig.Signature.String[1:] + "A"),
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.signer.Verify(entity.AttachmentKindAudio, tt.path, tt.sig)
+			var appErr *dto.AppError
+			if !errors.As(err, &appErr) || appErr.Code != dto.ErrCodeUnauthorized {
+				t.Errorf("error = %v, want unauthorized AppError", err)
+			}
+		})
+	}
+
+	expired := attachmentURLSigner{[]byte("secret"), 3600, map[string]bool{entity.AttachmentKindAudio: true}}
+	past := now - 10
+	_, err = expired.Verify(entity.AttachmentKindAudio, path, dto.AttachmentSignature{
+		Expires:   null.IntFrom(past),
+		Signature: null.StringFrom(expired.signature(path, past)),
+	})
+	if err == nil {
+		t.Error("Verify(expired) succeeded")
+	}
+
+	_, err = signer.Verify(entity.AttachmentKindImage, attachmentPath(1, entity.AttachmentKindImage), dto.AttachmentSignature{})
+	if err != nil {
+		t.Errorf("Verify(not required) = %v", err)
+	}
+}
+
+func TestAttachmentURLSignerSignAttachments(t *testing.T) {
+	signer := NewAttachmentURLSigner(AttachmentURLConfig{
+		Secret:           "secret",
+		TTL:              time.Hour,
+		RequireSignature: []string{signedKindWaveform},
+	})
+
+	attachments := []entity.NewsAttachment{
+		{Kind: entity.AttachmentKindAudio, URL: "/api/news/7/audio?v=abc"},
+		{Kind: entity.AttachmentKindImage, URL: "/api/news/7/image?v=def"},
+	}
+	signer.SignAttachments(attachments)
+
+	if attachments[1].WaveformURL != "" {
+		t.Errorf("image waveform url = %q", attachments[1].WaveformURL)
+	}
+	path, sig := signatureFromURL(t, attachments[0].WaveformURL)
+	if path != attachmentPath(7, "audio/waveform") {
+		t.Errorf("waveform path = %q", path)
+	}
+	_, err := signer.Verify(signedKindWaveform, path, sig)
+	if err != nil {
+		t.Errorf("Verify(waveform) = %v", err)
+	}
+
+	trackURL := signer.Sign(textTrackURL(entity.TextTrack{
+		NewsID:      7,
+		Target:      entity.AttachmentKindVideo,
+		Kind:        entity.TextTrackKindSubtitles,
+		Language:    "en",
+		ContentHash: strings.Repeat("a", 64),
+	}))
+	path, sig = signatureFromURL(t, trackURL)
+	if path != textTrackPath(7, entity.AttachmentKindVideo, entity.TextTrackKindSubtitles, "en") {
+		t.Errorf("track path = %q", path)
+	}
+	if !sig.Signature.Valid {
+		t.Errorf("track url %q is not signed", trackURL)
+	}
+}
+
+func TestAttachmentURLSignerWithoutSecret(t *testing.T) {
+	signer := NewAttachmentURLSigner(AttachmentURLConfig{TTL: time.Hour})
+	if got := signer.Sign("/api/news/1/audio?v=abc"); got != "/api/news/1/audio?v=abc" {
+		t.Errorf("Sign() = %q, want the url unchanged", got)
+	}
+}
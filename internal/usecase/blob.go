@@ -0,0 +1,179 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"news-app-api/internal/dto"
+	"news-app-api/internal/entity"
+	"time"
+)
+
+const (
+	blobCollectBatchSize   = 100
+	blobCollectGracePeriod = time.Hour
+)
+
+type countingHash struct {
+	hash.Hash
+	size int64
+}
+
+func (u *newsUseCase) storeBlob(ctx context.Context, r io.Reader) (b entity.Blob, err error) {
+	token, err := newRandomToken()
+	if err != nil {
+		return
+	}
+
+	staging := "staging/" + token
+	h := &countingHash{Hash: sha256.New()}
+	err = u.blobStore.Put(ctx, staging, io.TeeReader(r, h))
+	if err != nil {
+		return
+	}
+	defer func() {
+		if err != nil {
+			_ = u.blobStore.Delete(ctx, staging)
+		}
+	}()
+
+	return u.acquireBlob(ctx, hex.EncodeToString(h.Sum(nil)), h.size, func(key string) error {
+		return u.blobStore.Move(ctx, staging, key)
+	}, func() error {
+		return u.blobStore.Delete(ctx, staging)
+	})
+}
+
+func (u *newsUseCase) storeBlobBytes(ctx context.Context, data []byte) (entity.Blob, error) {
+	sum := sha256.Sum256(data)
+	return u.acquireBlob(ctx, hex.EncodeToString(sum[:]), int64(len(data)), func(key string) error {
+		return u.blobStore.Put(ctx, key, bytes.NewReader(data))
+	}, func() error {
+		return nil
+	})
+}
+
+func (u *newsUseCase) acquireBlob(
+	ctx context.Context,
+	contentHash string,
+	size int64,
+	store func(key string) error,
+	discard func() error,
+) (b entity.Blob, err error) {
+	// Blobs are not reference counted: a blob is collected once no attachment points to it and it has not been
+	// acquired within blobCollectGracePeriod, which covers the window before the caller stores the reference.
+	b, created, err := u.blobRepo().AcquireBlob(ctx, entity.Blob{
+		ContentHash: contentHash,
+		StorageKey:  blobKey(contentHash),
+		Size:        size,
+	})
+	if err != nil {
+		return
+	}
+
+	if !created {
+		var f entity.File
+		f, err = u.blobStore.Open(ctx, b.StorageKey)
+		if err == nil {
+			f.Close()
+			return b, discard()
+		}
+
+		var appErr *dto.AppError
+		if !errors.As(err, &appErr) || appErr.Code != dto.ErrCodeNotFound {
+			return
+		}
+	}
+
+	err = store(b.StorageKey)
+	return
+}
+
+func (u *newsUseCase) releaseStoredObjects(ctx context.Context, objects []entity.StoredObject) (err error) {
+	var hashes []string
+	for _, o := range objects {
+		if o.BlobHash.Valid {
+			hashes = append(hashes, o.BlobHash.String)
+			continue
+		}
+
+		err = u.blobStore.Delete(ctx, o.StorageKey)
+		if err != nil {
+			return
+		}
+	}
+
+	return u.releaseBlobs(ctx, hashes)
+}
+
+func (u *newsUseCase) releaseBlobs(ctx context.Context, hashes []string) (err error) {
+	if len(hashes) == 0 {
+		return
+	}
+
+	_, err = u.collectBlobs(ctx, hashes)
+	return
+}
+
+func (u *newsUseCase) CollectBlobs(ctx context.Context) (err error) {
+	defer func() {
+		if err != nil {
+			err = fmt.Errorf("NewsUseCase - CollectBlobs: %w", err)
+		}
+	}()
+
+	for {
+		var n int
+		n, err = u.collectBlobs(ctx, nil)
+		if err != nil || n < blobCollectBatchSize {
+			return
+		}
+	}
+}
+
+func (u *newsUseCase) collectBlobs(ctx context.Context, hashes []string) (n int, err error) {
+	repo := u.blobRepo()
+	err = repo.Begin(ctx)
+	if err != nil {
+		return
+	}
+	defer func() {
+		if err != nil {
+			_ = repo.Rollback(ctx)
+		}
+	}()
+
+	acquiredBefore := time.Now().Add(-blobCollectGracePeriod).Unix()
+	blobs, err := repo.LockUnreferencedBlobs(ctx, hashes, acquiredBefore, blobCollectBatchSize)
+	if err != nil {
+		return
+	}
+
+	for _, b := range blobs {
+		err = repo.DeleteBlob(ctx, b.ContentHash)
+		if err != nil {
+			return
+		}
+
+		err = u.blobStore.Delete(ctx, b.StorageKey)
+		if err != nil {
+			return
+		}
+	}
+
+	return len(blobs), repo.Commit(ctx)
+}
+
+func (h *countingHash) Write(p []byte) (int, error) {
+	h.size += int64(len(p))
+	return h.Hash.Write(p)
+}
+
+func blobKey(contentHash string) string {
+	return fmt.Sprintf("blobs/%s/%s/%s", contentHash[:2], contentHash[2:4], contentHash)
+}
@@ -0,0 +1,25 @@
+package usecase
+
+import (
+	"errors"
+	"news-app-api/internal/dto"
+)
+
+func checkConflict(exceptID int64, message string, lookup func() (int64, error)) error {
+	id, err := lookup()
+	if err == nil {
+		if id == exceptID {
+			return nil
+		}
+		return &dto.AppError{
+			Message: message,
+			Code:    dto.ErrCodeConflict,
+		}
+	}
+
+	var appErr *dto.AppError
+	if errors.As(err, &appErr) && appErr.Code == dto.ErrCodeNotFound {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,72 @@
+package usecase
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+type deliveryQueue struct {
+	batchSize    int
+	concurrency  int
+	claimLease   time.Duration
+	maxAttempts  int
+	retryBackoff time.Duration
+}
+
+// deliverDue claims due jobs batch by batch and delivers each batch concurrently until the queue runs dry.
+// It stops at the end of the first batch with a failed delivery and returns its error.
+func deliverDue[J any](
+	ctx context.Context,
+	q deliveryQueue,
+	claim func(ctx context.Context, limit int64, lease time.Duration) ([]J, error),
+	deliver func(ctx context.Context, job J) error,
+) error {
+	for {
+		jobs, err := claim(ctx, int64(q.batchSize), q.claimLease)
+		if err != nil {
+			return err
+		}
+
+		var (
+			wg       sync.WaitGroup
+			mu       sync.Mutex
+			firstErr error
+			sem      = make(chan struct{}, q.concurrency)
+		)
+		for _, job := range jobs {
+			wg.Add(1)
+			sem <- struct{}{}
+			go func(job J) {
+				defer wg.Done()
+				defer func() { <-sem }()
+				if e := deliver(ctx, job); e != nil {
+					mu.Lock()
+					if firstErr == nil {
+						firstErr = e
+					}
+					mu.Unlock()
+				}
+			}(job)
+		}
+		wg.Wait()
+
+		if firstErr != nil {
+			return firstErr
+		}
+
+		if len(jobs) < q.batchSize {
+			return nil
+		}
+	}
+}
+
+// retryAt returns when to retry a job that has just failed after attempts earlier attempts,
+// or false if it has run out of attempts.
+func (q deliveryQueue) retryAt(now time.Time, attempts int) (time.Time, bool) {
+	attempts++
+	if attempts >= q.maxAttempts {
+		return time.Time{}, false
+	}
+	return now.Add(q.retryBackoff << (attempts - 1)), true
+}
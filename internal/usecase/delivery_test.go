@@ -0,0 +1,107 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestDeliverDue(t *testing.T) {
+	q := deliveryQueue{batchSize: 10, concurrency: 3, claimLease: time.Minute}
+
+	tests := []struct {
+		name       string
+		jobs       int
+		failed     int
+		wantClaims int
+		wantErr    bool
+	}{
+		{"empty queue", 0, -1, 1, false},
+		{"partial batch", 7, -1, 1, false},
+		{"full batches", 30, -1, 4, false},
+		{"several batches", 25, -1, 3, false},
+		{"failure stops after its batch", 25, 12, 2, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var queue []int
+			for i := 0; i < tt.jobs; i++ {
+				queue = append(queue, i)
+			}
+
+			claims := 0
+			claim := func(_ context.Context, limit int64, lease time.Duration) ([]int, error) {
+				claims++
+				if limit != int64(q.batchSize) || lease != q.claimLease {
+					t.Errorf("claimed %d jobs for %v", limit, lease)
+				}
+				n := int(limit)
+				if n > len(queue) {
+					n = len(queue)
+				}
+				jobs := queue[:n]
+				queue = queue[n:]
+				return jobs, nil
+			}
+
+			var (
+				mu        sync.Mutex
+				delivered = map[int]bool{}
+				running   int
+			)
+			deliver := func(_ context.Context, job int) error {
+				mu.Lock()
+				running++
+				if running > q.concurrency {
+					t.Errorf("%d deliveries running at once", running)
+				}
+				delivered[job] = true
+				mu.Unlock()
+
+				time.Sleep(time.Millisecond)
+
+				mu.Lock()
+				running--
+				mu.Unlock()
+				if job == tt.failed {
+					return errors.New("unavailable")
+				}
+				return nil
+			}
+
+			err := deliverDue(context.Background(), q, claim, deliver)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if claims != tt.wantClaims {
+				t.Errorf("claimed %d times, want %d", claims, tt.wantClaims)
+			}
+			want := tt.wantClaims * q.batchSize
+			if want > tt.jobs {
+				want = tt.jobs
+			}
+			if len(delivered) != want {
+				t.Errorf("delivered %d jobs, want %d", len(delivered), want)
+			}
+		})
+	}
+}
+
+func TestDeliveryQueueRetryAt(t *testing.T) {
+	q := deliveryQueue{maxAttempts: 4, retryBackoff: 10 * time.Second}
+	now := time.Unix(1000, 0)
+
+	for attempts, want := range []time.Duration{10 * time.Second, 20 * time.Second, 40 * time.Second} {
+		next, ok := q.retryAt(now, attempts)
+		if !ok || next.Sub(now) != want {
+			t.Errorf("after %d attempts: retry in %v, %v, want %v", attempts, next.Sub(now), ok, want)
+		}
+	}
+
+	if _, ok := q.retryAt(now, 3); ok {
+		t.Error("retried after running out of attempts")
+	}
+}
@@ -0,0 +1,345 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"embed"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	log "github.com/sirupsen/logrus"
+	"gopkg.in/guregu/null.v3"
+	htmltemplate "html/template"
+	"news-app-api/internal/adapter"
+	"news-app-api/internal/dto"
+	"news-app-api/internal/entity"
+	"strconv"
+	"strings"
+	texttemplate "text/template"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	digestBatchSize    = 100
+	digestItemLimit    = 30
+	digestExcerptRunes = 200
+	digestClaimLease   = 15 * time.Minute
+)
+
+//go:embed templates/digest.html.tmpl templates/digest.txt.tmpl
+var digestTemplates embed.FS
+
+type (
+	DigestUseCase interface {
+		GetPreference(ctx context.Context, userID int64) (entity.DigestPreference, error)
+		UpdatePreference(ctx context.Context, p dto.UpdateDigestPreferenceParams) (entity.DigestPreference, error)
+		Unsubscribe(ctx context.Context, p dto.UnsubscribeDigestParams) error
+		SendDueDigests(ctx context.Context) error
+	}
+
+	DigestConfig struct {
+		PublicURL          string
+		ArticleURLTemplate string
+		MailFrom           string
+	}
+
+	digestUseCase struct {
+		digestRepo adapter.DigestRepository
+		newsRepo   func() adapter.NewsRepository
+		mailer     adapter.Mailer
+		cfg        DigestConfig
+		html       *htmltemplate.Template
+		text       *texttemplate.Template
+	}
+
+	digestView struct {
+		Subject        string
+		Name           string
+		Frequency      string
+		UnsubscribeURL string
+		Items          []digestViewItem
+	}
+
+	digestViewItem struct {
+		Title     string
+		MediaName string
+		Date      string
+		Excerpt   string
+		URL       string
+	}
+)
+
+func NewDigestUseCase(
+	digestRepo adapter.DigestRepository,
+	newsRepo func() adapter.NewsRepository,
+	mailer adapter.Mailer,
+	cfg DigestConfig,
+) (DigestUseCase, error) {
+	html, err := htmltemplate.ParseFS(digestTemplates, "templates/digest.html.tmpl")
+	if err != nil {
+		return nil, err
+	}
+
+	text, err := texttemplate.ParseFS(digestTemplates, "templates/digest.txt.tmpl")
+	if err != nil {
+		return nil, err
+	}
+
+	cfg.PublicURL = strings.TrimRight(cfg.PublicURL, "/")
+
+	return &digestUseCase{digestRepo, newsRepo, mailer, cfg, html, text}, nil
+}
+
+func (u *digestUseCase) GetPreference(ctx context.Context, userID int64) (p entity.DigestPreference, err error) {
+	defer func() {
+		if err != nil {
+			var appErr *dto.AppError
+			if !errors.As(err, &appErr) {
+				err = fmt.Errorf("DigestUseCase - GetPreference: %w", err)
+			}
+		}
+	}()
+
+	p, err = u.digestRepo.GetDigestPreference(ctx, userID)
+	if err != nil {
+		var appErr *dto.AppError
+		if errors.As(err, &appErr) && appErr.Code == dto.ErrCodeNotFound {
+			return defaultDigestPreference(), nil
+		}
+		return
+	}
+
+	return
+}
+
+func (u *digestUseCase) UpdatePreference(
+	ctx context.Context,
+	p dto.UpdateDigestPreferenceParams,
+) (pref entity.DigestPreference, err error) {
+	defer func() {
+		if err != nil {
+			var appErr *dto.AppError
+			if !errors.As(err, &appErr) {
+				err = fmt.Errorf("DigestUseCase - UpdatePreference: %w", err)
+			}
+		}
+	}()
+
+	err = p.Validate()
+	if err != nil {
+		return
+	}
+
+	token, err := newRandomToken()
+	if err != nil {
+		return
+	}
+
+	next := nextDigestSendAt(entity.DigestPreference{
+		Frequency:   p.Frequency,
+		SendHour:    p.SendHour,
+		SendWeekday: p.SendWeekday,
+		Timezone:    p.Timezone,
+	}, time.Now())
+
+	return u.digestRepo.UpsertDigestPreference(ctx, p, token, next)
+}
+
+func (u *digestUseCase) Unsubscribe(ctx context.Context, p dto.UnsubscribeDigestParams) (err error) {
+	defer func() {
+		if err != nil {
+			var appErr *dto.AppError
+			if !errors.As(err, &appErr) {
+				err = fmt.Errorf("DigestUseCase - Unsubscribe: %w", err)
+			}
+		}
+	}()
+
+	if p.Token == "" {
+		return &dto.AppError{
+			Message: "Ссылка для отписки недействительна",
+			Code:    dto.ErrCodeBadRequest,
+		}
+	}
+
+	return u.digestRepo.UnsubscribeDigest(ctx, p.Token)
+}
+
+func (u *digestUseCase) SendDueDigests(ctx context.Context) (err error) {
+	defer func() {
+		if err != nil {
+			var appErr *dto.AppError
+			if !errors.As(err, &appErr) {
+				err = fmt.Errorf("DigestUseCase - SendDueDigests: %w", err)
+			}
+		}
+	}()
+
+	for {
+		var recipients []entity.DigestRecipient
+		recipients, err = u.digestRepo.ClaimDueDigests(ctx, digestBatchSize, digestClaimLease)
+		if err != nil {
+			return
+		}
+
+		// A failed recipient stays claimed until the lease expires, so it is retried later without blocking the others.
+		for _, rcpt := range recipients {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			if e := u.sendDigest(ctx, rcpt); e != nil {
+				log.WithField("userID", rcpt.UserID).Errorf("Failed to send digest: %v", e)
+			}
+		}
+
+		if len(recipients) < digestBatchSize {
+			return
+		}
+	}
+}
+
+func (u *digestUseCase) sendDigest(ctx context.Context, rcpt entity.DigestRecipient) error {
+	now := time.Now()
+	next := nextDigestSendAt(rcpt.Preference, now)
+
+	if rcpt.Email == "" {
+		return u.digestRepo.MarkDigestSent(ctx, rcpt.UserID, now.Unix(), next)
+	}
+
+	since := rcpt.Preference.LastSentAt.Int64
+	if !rcpt.Preference.LastSentAt.Valid {
+		since = now.Add(-digestPeriod(rcpt.Preference.Frequency)).Unix()
+	}
+
+	items, err := u.newsRepo().GetDigestNewsList(ctx, rcpt.UserID, since, digestItemLimit)
+	if err != nil {
+		return err
+	}
+
+	if len(items) > 0 {
+		mail, err := u.renderDigest(rcpt, items)
+		if err != nil {
+			return err
+		}
+
+		err = u.mailer.Send(ctx, mail)
+		if err != nil {
+			return err
+		}
+	}
+
+	return u.digestRepo.MarkDigestSent(ctx, rcpt.UserID, now.Unix(), next)
+}
+
+func (u *digestUseCase) renderDigest(rcpt entity.DigestRecipient, items []entity.NewsListItem) (entity.Mail, error) {
+	loc, err := time.LoadLocation(rcpt.Preference.Timezone)
+	if err != nil {
+		loc = time.UTC
+	}
+
+	subject := "Ежедневная подборка новостей"
+	if rcpt.Preference.Frequency == dto.DigestFrequencyWeekly {
+		subject = "Еженедельная подборка новостей"
+	}
+
+	view := digestView{
+		Subject:        subject,
+		Name:           rcpt.Name,
+		Frequency:      rcpt.Preference.Frequency,
+		UnsubscribeURL: fmt.Sprintf("%s/api/digest/unsubscribe?token=%s", u.cfg.PublicURL, rcpt.UnsubscribeToken),
+		Items:          make([]digestViewItem, 0, len(items)),
+	}
+	for _, item := range items {
+		view.Items = append(view.Items, digestViewItem{
+			Title:     item.Title,
+			MediaName: item.Media.Name,
+			Date:      time.Unix(item.CreatedAt, 0).In(loc).Format("02.01.2006 15:04"),
+			Excerpt:   digestExcerpt(item.Text),
+			URL:       strings.ReplaceAll(u.cfg.ArticleURLTemplate, "{id}", strconv.FormatInt(item.ID, 10)),
+		})
+	}
+
+	var html, text bytes.Buffer
+	err = u.html.Execute(&html, view)
+	if err != nil {
+		return entity.Mail{}, err
+	}
+	err = u.text.Execute(&text, view)
+	if err != nil {
+		return entity.Mail{}, err
+	}
+
+	return entity.Mail{
+		From:    u.cfg.MailFrom,
+		To:      rcpt.Email,
+		Subject: subject,
+		Text:    text.String(),
+		HTML:    html.String(),
+		Headers: map[string]string{
+			"List-Unsubscribe":      fmt.Sprintf("<%s>", view.UnsubscribeURL),
+			"List-Unsubscribe-Post": "List-Unsubscribe=One-Click",
+		},
+	}, nil
+}
+
+func defaultDigestPreference() entity.DigestPreference {
+	return entity.DigestPreference{
+		Frequency:   dto.DigestFrequencyOff,
+		SendHour:    8,
+		SendWeekday: int(time.Monday),
+		Timezone:    "UTC",
+	}
+}
+
+func digestPeriod(frequency string) time.Duration {
+	if frequency == dto.DigestFrequencyWeekly {
+		return 7 * 24 * time.Hour
+	}
+	return 24 * time.Hour
+}
+
+func nextDigestSendAt(p entity.DigestPreference, after time.Time) null.Int {
+	if p.Frequency != dto.DigestFrequencyDaily && p.Frequency != dto.DigestFrequencyWeekly {
+		return null.Int{}
+	}
+
+	loc, err := time.LoadLocation(p.Timezone)
+	if err != nil {
+		loc = time.UTC
+	}
+
+	local := after.In(loc)
+	next := time.Date(local.Year(), local.Month(), local.Day(), p.SendHour, 0, 0, 0, loc)
+
+	if p.Frequency == dto.DigestFrequencyWeekly {
+		days := (p.SendWeekday - int(next.Weekday()) + 7) % 7
+		next = next.AddDate(0, 0, days)
+		if !next.After(local) {
+			next = next.AddDate(0, 0, 7)
+		}
+	} else if !next.After(local) {
+		next = next.AddDate(0, 0, 1)
+	}
+
+	return null.IntFrom(next.Unix())
+}
+
+func digestExcerpt(text string) string {
+	text = strings.Join(strings.Fields(text), " ")
+	if utf8.RuneCountInString(text) <= digestExcerptRunes {
+		return text
+	}
+	runes := []rune(text)
+	return strings.TrimSpace(string(runes[:digestExcerptRunes])) + "…"
+}
+
+func newRandomToken() (string, error) {
+	b := make([]byte, 32)
+	_, err := rand.Read(b)
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
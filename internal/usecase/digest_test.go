@@ -0,0 +1,194 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"gopkg.in/guregu/null.v3"
+	"news-app-api/internal/adapter"
+	"news-app-api/internal/dto"
+	"news-app-api/internal/entity"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+type fakeDigestRepository struct {
+	due    []entity.DigestRecipient
+	failed map[int64]bool
+	sent   []int64
+}
+
+func (r *fakeDigestRepository) GetDigestPreference(context.Context, int64) (entity.DigestPreference, error) {
+	return entity.DigestPreference{}, nil
+}
+
+func (r *fakeDigestRepository) UpsertDigestPreference(
+	context.Context,
+	dto.UpdateDigestPreferenceParams,
+	string,
+	null.Int,
+) (entity.DigestPreference, error) {
+	return entity.DigestPreference{}, nil
+}
+
+func (r *fakeDigestRepository) ClaimDueDigests(
+	_ context.Context,
+	limit int64,
+	_ time.Duration,
+) ([]entity.DigestRecipient, error) {
+	n := int(limit)
+	if n > len(r.due) {
+		n = len(r.due)
+	}
+	claimed := r.due[:n]
+	r.due = r.due[n:]
+	return claimed, nil
+}
+
+func (r *fakeDigestRepository) MarkDigestSent(_ context.Context, userID, _ int64, _ null.Int) error {
+	if r.failed[userID] {
+		return errors.New("connection reset")
+	}
+	r.sent = append(r.sent, userID)
+	return nil
+}
+
+func (r *fakeDigestRepository) UnsubscribeDigest(context.Context, string) error {
+	return nil
+}
+
+type fakeDigestNewsRepository struct {
+	adapter.NewsRepository
+	items []entity.NewsListItem
+}
+
+func (r *fakeDigestNewsRepository) GetDigestNewsList(context.Context, int64, int64, int64) ([]entity.NewsListItem, error) {
+	return r.items, nil
+}
+
+type fakeMailer struct {
+	fail map[string]bool
+	sent []entity.Mail
+}
+
+func (m *fakeMailer) Send(_ context.Context, mail entity.Mail) error {
+	if m.fail[mail.To] {
+		return errors.New("mailbox unavailable")
+	}
+	m.sent = append(m.sent, mail)
+	return nil
+}
+
+func TestSendDueDigests(t *testing.T) {
+	repo := &fakeDigestRepository{failed: map[int64]bool{150: true}}
+	mailer := &fakeMailer{fail: map[string]bool{"user2@example.com": true}}
+	news := &fakeDigestNewsRepository{items: []entity.NewsListItem{
+		{
+			ID:        7,
+			Media:     entity.MediaListItem{Name: "Вести"},
+			Title:     "Дождь & ветер",
+			Text:      "Завтра ожидается <сильный> дождь.",
+			CreatedAt: time.Date(2024, 3, 10, 21, 30, 0, 0, time.UTC).Unix(),
+		},
+	}}
+
+	var wantSent []int64
+	wantMail := map[string]int64{}
+	for id := int64(1); id <= digestBatchSize+50; id++ {
+		rcpt := entity.DigestRecipient{
+			UserID:           id,
+			Name:             "Анна",
+			Email:            fmt.Sprintf("user%d@example.com", id),
+			UnsubscribeToken: fmt.Sprintf("token%d", id),
+			Preference: entity.DigestPreference{
+				Frequency: dto.DigestFrequencyDaily,
+				Timezone:  "Europe/Moscow",
+			},
+		}
+		if id == 3 {
+			rcpt.Email = ""
+		}
+		repo.due = append(repo.due, rcpt)
+
+		if rcpt.Email != "" && !mailer.fail[rcpt.Email] {
+			wantMail[rcpt.Email] = id
+		}
+		if !repo.failed[id] && !mailer.fail[rcpt.Email] {
+			wantSent = append(wantSent, id)
+		}
+	}
+
+	u, err := NewDigestUseCase(
+		repo,
+		func() adapter.NewsRepository { return news },
+		mailer,
+		DigestConfig{
+			PublicURL:          "https://api.example.com/",
+			ArticleURLTemplate: "https://news.example.com/articles/{id}",
+			MailFrom:           "digest@example.com",
+		},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = u.SendDueDigests(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !reflect.DeepEqual(repo.sent, wantSent) {
+		t.Errorf("marked %d recipients as sent, want %d", len(repo.sent), len(wantSent))
+	}
+	if len(mailer.sent) != len(wantMail) {
+		t.Errorf("sent %d emails, want %d", len(mailer.sent), len(wantMail))
+	}
+
+	for _, mail := range mailer.sent {
+		id, ok := wantMail[mail.To]
+		if !ok {
+			t.Errorf("unexpected email to %q", mail.To)
+			continue
+		}
+		delete(wantMail, mail.To)
+
+		unsubscribe := fmt.Sprintf("https://api.example.com/api/digest/unsubscribe?token=token%d", id)
+		if mail.From != "digest@example.com" || mail.Subject != "Ежедневная подборка новостей" {
+			t.Errorf("%s: from %q, subject %q", mail.To, mail.From, mail.Subject)
+		}
+		if mail.Headers["List-Unsubscribe"] != "<"+unsubscribe+">" ||
+			mail.Headers["List-Unsubscribe-Post"] != "List-Unsubscribe=One-Click" {
+			t.Errorf("%s: unsubscribe headers %v", mail.To, mail.Headers)
+		}
+
+		for _, part := range []struct {
+			name, body string
+			want       []string
+		}{
+			{"text", mail.Text, []string{
+				"Анна, здесь новости",
+				"Вести · 11.03.2024 00:30",
+				"Дождь & ветер",
+				"Завтра ожидается <сильный> дождь.",
+				"https://news.example.com/articles/7",
+				"Отписаться от рассылки: " + unsubscribe,
+			}},
+			{"html", mail.HTML, []string{
+				"Анна, здесь новости",
+				"Вести · 11.03.2024 00:30",
+				"Дождь &amp; ветер",
+				"Завтра ожидается &lt;сильный&gt; дождь.",
+				`href="https://news.example.com/articles/7"`,
+				`href="` + unsubscribe + `"`,
+			}},
+		} {
+			for _, want := range part.want {
+				if !strings.Contains(part.body, want) {
+					t.Errorf("%s: %s body doesn't contain %q", mail.To, part.name, want)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package usecase
+
+import (
+	"context"
+	log "github.com/sirupsen/logrus"
+	"news-app-api/internal/entity"
+	"sync"
+	"time"
+)
+
+const eventHandlerTimeout = 30 * time.Second
+
+type (
+	EventPublisher interface {
+		Publish(ctx context.Context, e entity.Event)
+	}
+
+	EventHandler interface {
+		HandleEvent(ctx context.Context, e entity.Event) error
+	}
+
+	EventBus struct {
+		mu       sync.RWMutex
+		handlers []EventHandler
+		wg       sync.WaitGroup
+	}
+)
+
+func NewEventBus() *EventBus {
+	return &EventBus{}
+}
+
+func (b *EventBus) Subscribe(h EventHandler) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.handlers = append(b.handlers, h)
+}
+
+func (b *EventBus) Publish(_ context.Context, e entity.Event) {
+	b.mu.RLock()
+	handlers := make([]EventHandler, len(b.handlers))
+	copy(handlers, b.handlers)
+	b.mu.RUnlock()
+
+	for _, h := range handlers {
+		b.wg.Add(1)
+		go func(h EventHandler) {
+			defer b.wg.Done()
+			defer func() {
+				if r := recover(); r != nil {
+					log.WithField("event", e.EventType()).Errorf("Event handler panicked: %v", r)
+				}
+			}()
+
+			ctx, cancel := context.WithTimeout(context.Background(), eventHandlerTimeout)
+			defer cancel()
+
+			err := h.HandleEvent(ctx, e)
+			if err != nil {
+				log.WithField("event", e.EventType()).Error(err.Error())
+			}
+		}(h)
+	}
+}
+
+func (b *EventBus) Wait() {
+	b.wg.Wait()
+}
@@ -4,22 +4,41 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"gopkg.in/guregu/null.v3"
 	"news-app-api/internal/adapter"
 	"news-app-api/internal/dto"
+	"news-app-api/internal/entity"
+	"time"
 )
 
 type (
 	FeedUseCase interface {
 		GetFeed(ctx context.Context, p dto.GetFeedParams) (dto.GetFeedResult, error)
+		DeleteExpiredSessions(ctx context.Context) error
+		HideNews(ctx context.Context, p dto.HideNewsParams) error
+		UnhideNews(ctx context.Context, p dto.UnhideNewsParams) error
+		GetHiddenNewsList(ctx context.Context, p dto.GetHiddenNewsListParams) (dto.GetHiddenNewsListResult, error)
+		MuteMedia(ctx context.Context, p dto.MuteMediaParams) error
+		UnmuteMedia(ctx context.Context, p dto.UnmuteMediaParams) error
+		GetMutedMediaList(ctx context.Context, p dto.GetMutedMediaListParams) (dto.GetMutedMediaListResult, error)
+		MarkNewsRead(ctx context.Context, p dto.MarkFeedNewsReadParams) error
 	}
 
 	feedUseCase struct {
-		newsRepo func() adapter.NewsRepository
+		newsRepo  func() adapter.NewsRepository
+		mediaRepo adapter.MediaRepository
+		scorer    FeedScorer
+		urls      AttachmentURLSigner
 	}
 )
 
-func NewFeedUseCase(newsRepo func() adapter.NewsRepository) FeedUseCase {
-	return &feedUseCase{newsRepo}
+func NewFeedUseCase(
+	newsRepo func() adapter.NewsRepository,
+	mediaRepo adapter.MediaRepository,
+	scorer FeedScorer,
+	urls AttachmentURLSigner,
+) FeedUseCase {
+	return &feedUseCase{newsRepo, mediaRepo, scorer, urls}
 }
 
 func (u *feedUseCase) GetFeed(ctx context.Context, p dto.GetFeedParams) (res dto.GetFeedResult, err error) {
@@ -32,13 +51,250 @@ func (u *feedUseCase) GetFeed(ctx context.Context, p dto.GetFeedParams) (res dto
 		}
 	}()
 
+	err = p.Validate()
+	if err != nil {
+		return
+	}
+
+	if p.Sort.String == dto.FeedSortRanked {
+		return u.getRankedFeed(ctx, p)
+	}
+
 	r := u.newsRepo()
 
 	res.Items, err = r.GetFeedNewsList(ctx, p)
 	if err != nil {
 		return
 	}
+	u.urls.SignNewsList(res.Items)
 
 	res.Total, err = r.CountFeedNews(ctx, p.UserID, p.Since)
 	return
 }
+
+func (u *feedUseCase) getRankedFeed(ctx context.Context, p dto.GetFeedParams) (res dto.GetFeedResult, err error) {
+	r := u.newsRepo()
+
+	session, ok, err := r.GetFeedSession(ctx, p.UserID, p.Session.String, rankedFeedSessionTTL)
+	if err != nil {
+		return
+	}
+	if !ok {
+		session, err = u.createRankedFeedSession(ctx, r, p)
+		if err != nil {
+			return
+		}
+	}
+	res.Session = session.Token
+	p.Session = null.StringFrom(session.Token)
+
+	// News past the ranked candidates continue in chronological order behind the session cutoff.
+	var tail int64
+	if session.CutoffNewsID.Valid {
+		tail, err = r.CountFeedSessionTail(ctx, p.UserID, p.Since, session.Token)
+		if err != nil {
+			return
+		}
+	}
+	ranked := int64(len(session.NewsIDs))
+	res.Total = ranked + tail
+
+	offset := p.Offset.Int64
+	if offset < 0 || offset > res.Total {
+		offset = res.Total
+	}
+	end := res.Total
+	if p.Limit.Valid && p.Limit.Int64 >= 0 && offset+p.Limit.Int64 < end {
+		end = offset + p.Limit.Int64
+	}
+
+	if offset < ranked {
+		ids := session.NewsIDs[offset:]
+		if end < ranked {
+			ids = session.NewsIDs[offset:end]
+		}
+		res.Items, err = r.GetFeedNewsListByIDs(ctx, p.UserID, ids)
+		if err != nil {
+			return
+		}
+	}
+	if end > ranked {
+		var items []entity.NewsListItem
+		tailOffset := offset - ranked
+		if tailOffset < 0 {
+			tailOffset = 0
+		}
+		p.Offset = null.IntFrom(tailOffset)
+		p.Limit = null.IntFrom(end - ranked - tailOffset)
+		items, err = r.GetFeedSessionTail(ctx, p)
+		if err != nil {
+			return
+		}
+		res.Items = append(res.Items, items...)
+	}
+	if res.Items == nil {
+		res.Items = []entity.NewsListItem{}
+	}
+
+	u.urls.SignNewsList(res.Items)
+	return
+}
+
+func (u *feedUseCase) createRankedFeedSession(
+	ctx context.Context,
+	r adapter.NewsRepository,
+	p dto.GetFeedParams,
+) (s entity.FeedSession, err error) {
+	candidates, err := r.GetFeedCandidates(ctx, p.UserID, p.Since, rankedFeedCandidateLimit)
+	if err != nil {
+		return
+	}
+
+	s.Token, err = newRandomToken()
+	if err != nil {
+		return
+	}
+	s.UserID = p.UserID
+	s.NewsIDs = rankFeedCandidates(u.scorer, p.UserID, candidates, time.Now())
+	if len(candidates) == rankedFeedCandidateLimit {
+		s.CutoffNewsID = null.IntFrom(candidates[len(candidates)-1].NewsID)
+	}
+
+	err = r.CreateFeedSession(ctx, s, rankedFeedSessionTTL)
+	return
+}
+
+func (u *feedUseCase) DeleteExpiredSessions(ctx context.Context) (err error) {
+	defer func() {
+		if err != nil {
+			err = fmt.Errorf("FeedUseCase - DeleteExpiredSessions: %w", err)
+		}
+	}()
+	return u.newsRepo().DeleteExpiredFeedSessions(ctx)
+}
+
+func (u *feedUseCase) MarkNewsRead(ctx context.Context, p dto.MarkFeedNewsReadParams) (err error) {
+	defer func() {
+		if err != nil {
+			var appErr *dto.AppError
+			if !errors.As(err, &appErr) {
+				err = fmt.Errorf("FeedUseCase - MarkNewsRead: %w", err)
+			}
+		}
+	}()
+	return u.newsRepo().MarkFeedNewsRead(ctx, p.UserID, p.NewsID)
+}
+
+func (u *feedUseCase) HideNews(ctx context.Context, p dto.HideNewsParams) (err error) {
+	defer func() {
+		if err != nil {
+			var appErr *dto.AppError
+			if !errors.As(err, &appErr) {
+				err = fmt.Errorf("FeedUseCase - HideNews: %w", err)
+			}
+		}
+	}()
+
+	r := u.newsRepo()
+
+	_, err = r.GetNews(ctx, p.NewsID)
+	if err != nil {
+		return
+	}
+
+	return r.HideNews(ctx, p.UserID, p.NewsID)
+}
+
+func (u *feedUseCase) UnhideNews(ctx context.Context, p dto.UnhideNewsParams) (err error) {
+	defer func() {
+		if err != nil {
+			var appErr *dto.AppError
+			if !errors.As(err, &appErr) {
+				err = fmt.Errorf("FeedUseCase - UnhideNews: %w", err)
+			}
+		}
+	}()
+	return u.newsRepo().UnhideNews(ctx, p.UserID, p.NewsID)
+}
+
+func (u *feedUseCase) GetHiddenNewsList(
+	ctx context.Context,
+	p dto.GetHiddenNewsListParams,
+) (res dto.GetHiddenNewsListResult, err error) {
+	defer func() {
+		if err != nil {
+			var appErr *dto.AppError
+			if !errors.As(err, &appErr) {
+				err = fmt.Errorf("FeedUseCase - GetHiddenNewsList: %w", err)
+			}
+		}
+	}()
+
+	r := u.newsRepo()
+
+	res.Items, err = r.GetHiddenNewsList(ctx, p)
+	if err != nil {
+		return
+	}
+	u.urls.SignNewsList(res.Items)
+
+	res.Total, err = r.CountHiddenNews(ctx, p.UserID)
+	return
+}
+
+func (u *feedUseCase) MuteMedia(ctx context.Context, p dto.MuteMediaParams) (err error) {
+	defer func() {
+		if err != nil {
+			var appErr *dto.AppError
+			if !errors.As(err, &appErr) {
+				err = fmt.Errorf("FeedUseCase - MuteMedia: %w", err)
+			}
+		}
+	}()
+
+	err = p.Validate()
+	if err != nil {
+		return
+	}
+
+	_, err = u.mediaRepo.GetMediaByID(ctx, p.MediaID)
+	if err != nil {
+		return
+	}
+
+	return u.mediaRepo.MuteMedia(ctx, p.MediaID, p.UserID, p.Until)
+}
+
+func (u *feedUseCase) UnmuteMedia(ctx context.Context, p dto.UnmuteMediaParams) (err error) {
+	defer func() {
+		if err != nil {
+			var appErr *dto.AppError
+			if !errors.As(err, &appErr) {
+				err = fmt.Errorf("FeedUseCase - UnmuteMedia: %w", err)
+			}
+		}
+	}()
+	return u.mediaRepo.UnmuteMedia(ctx, p.MediaID, p.UserID)
+}
+
+func (u *feedUseCase) GetMutedMediaList(
+	ctx context.Context,
+	p dto.GetMutedMediaListParams,
+) (res dto.GetMutedMediaListResult, err error) {
+	defer func() {
+		if err != nil {
+			var appErr *dto.AppError
+			if !errors.As(err, &appErr) {
+				err = fmt.Errorf("FeedUseCase - GetMutedMediaList: %w", err)
+			}
+		}
+	}()
+
+	res.Items, err = u.mediaRepo.GetMutedMediaList(ctx, p)
+	if err != nil {
+		return
+	}
+
+	res.Total, err = u.mediaRepo.CountMutedMedia(ctx, p.UserID)
+	return
+}
@@ -0,0 +1,138 @@
+package usecase
+
+import (
+	"errors"
+	"math"
+	"news-app-api/internal/entity"
+	"sort"
+	"time"
+)
+
+const (
+	rankedFeedCandidateLimit = 500
+	rankedFeedMaxRun         = 2
+	rankedFeedSessionTTL     = 30 * time.Minute
+)
+
+type (
+	FeedScorer interface {
+		Score(userID int64, c entity.FeedCandidate, now time.Time) float64
+	}
+
+	WeightedFeedScorerConfig struct {
+		RecencyHalfLife  time.Duration
+		AffinityWeight   float64
+		EngagementWeight float64
+		ReadPenalty      float64
+	}
+
+	weightedFeedScorer struct {
+		cfg WeightedFeedScorerConfig
+	}
+
+	splitFeedScorer struct {
+		variants []FeedScorer
+	}
+)
+
+func DefaultWeightedFeedScorerConfig() WeightedFeedScorerConfig {
+	return WeightedFeedScorerConfig{
+		RecencyHalfLife:  12 * time.Hour,
+		AffinityWeight:   0.5,
+		EngagementWeight: 0.25,
+		ReadPenalty:      0.3,
+	}
+}
+
+func NewWeightedFeedScorer(cfg WeightedFeedScorerConfig) FeedScorer {
+	return &weightedFeedScorer{cfg}
+}
+
+func (s *weightedFeedScorer) Score(_ int64, c entity.FeedCandidate, now time.Time) float64 {
+	age := now.Sub(time.Unix(c.CreatedAt, 0))
+	if age < 0 {
+		age = 0
+	}
+	score := math.Exp2(-age.Hours() / s.cfg.RecencyHalfLife.Hours())
+	score *= 1 + s.cfg.AffinityWeight*math.Log1p(float64(c.MediaAffinity))
+	score *= 1 + s.cfg.EngagementWeight*math.Log1p(float64(c.FavoriteCount))
+	if c.IsRead {
+		score *= s.cfg.ReadPenalty
+	}
+	return score
+}
+
+func NewSplitFeedScorer(variants ...FeedScorer) (FeedScorer, error) {
+	if len(variants) == 0 {
+		return nil, errors.New("split feed scorer needs at least one variant")
+	}
+	return &splitFeedScorer{variants}, nil
+}
+
+func (s *splitFeedScorer) Score(userID int64, c entity.FeedCandidate, now time.Time) float64 {
+	i := userID % int64(len(s.variants))
+	if i < 0 {
+		i = -i
+	}
+	return s.variants[i].Score(userID, c, now)
+}
+
+func rankFeedCandidates(
+	scorer FeedScorer,
+	userID int64,
+	candidates []entity.FeedCandidate,
+	now time.Time,
+) []int64 {
+	scores := make(map[int64]float64, len(candidates))
+	for _, c := range candidates {
+		scores[c.NewsID] = scorer.Score(userID, c, now)
+	}
+
+	sorted := make([]entity.FeedCandidate, len(candidates))
+	copy(sorted, candidates)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		si, sj := scores[sorted[i].NewsID], scores[sorted[j].NewsID]
+		if si != sj {
+			return si > sj
+		}
+		return sorted[i].NewsID > sorted[j].NewsID
+	})
+
+	return diversifyFeedCandidates(sorted, rankedFeedMaxRun)
+}
+
+func diversifyFeedCandidates(sorted []entity.FeedCandidate, maxRun int) []int64 {
+	ids := make([]int64, 0, len(sorted))
+	used := make([]bool, len(sorted))
+	var lastMediaID int64
+	run := 0
+
+	for len(ids) < len(sorted) {
+		next := -1
+		for i, c := range sorted {
+			if used[i] {
+				continue
+			}
+			if next == -1 {
+				next = i
+			}
+			if run < maxRun || c.MediaID != lastMediaID {
+				next = i
+				break
+			}
+		}
+
+		c := sorted[next]
+		used[next] = true
+		ids = append(ids, c.NewsID)
+
+		if c.MediaID == lastMediaID {
+			run++
+		} else {
+			lastMediaID = c.MediaID
+			run = 1
+		}
+	}
+
+	return ids
+}
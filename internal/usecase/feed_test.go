@@ -0,0 +1,370 @@
+package usecase
+
+import (
+	"context"
+	"gopkg.in/guregu/null.v3"
+	"news-app-api/internal/adapter"
+	"news-app-api/internal/dto"
+	"news-app-api/internal/entity"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// fakeFeedNewsRepository keeps the feed newest first; NewsRepository methods the ranked feed doesn't use are not
+// implemented.
+type fakeFeedNewsRepository struct {
+	adapter.NewsRepository
+	feed     []entity.FeedCandidate
+	sessions map[string]entity.FeedSession
+}
+
+func (r *fakeFeedNewsRepository) GetFeedCandidates(
+	_ context.Context,
+	_ int64,
+	_ null.Int,
+	limit int64,
+) ([]entity.FeedCandidate, error) {
+	if int64(len(r.feed)) < limit {
+		limit = int64(len(r.feed))
+	}
+	return append([]entity.FeedCandidate{}, r.feed[:limit]...), nil
+}
+
+func (r *fakeFeedNewsRepository) CreateFeedSession(_ context.Context, s entity.FeedSession, _ time.Duration) error {
+	r.sessions[s.Token] = s
+	return nil
+}
+
+func (r *fakeFeedNewsRepository) GetFeedSession(
+	_ context.Context,
+	userID int64,
+	token string,
+	_ time.Duration,
+) (entity.FeedSession, bool, error) {
+	s, ok := r.sessions[token]
+	return s, ok && s.UserID == userID, nil
+}
+
+func (r *fakeFeedNewsRepository) GetFeedNewsListByIDs(
+	_ context.Context,
+	_ int64,
+	ids []int64,
+) ([]entity.NewsListItem, error) {
+	items := make([]entity.NewsListItem, 0, len(ids))
+	for _, id := range ids {
+		items = append(items, entity.NewsListItem{ID: id})
+	}
+	return items, nil
+}
+
+func (r *fakeFeedNewsRepository) tail(token string) []entity.FeedCandidate {
+	cutoff := r.sessions[token].CutoffNewsID.Int64
+	for i, c := range r.feed {
+		if c.NewsID == cutoff {
+			return r.feed[i+1:]
+		}
+	}
+	return nil
+}
+
+func (r *fakeFeedNewsRepository) CountFeedSessionTail(
+	_ context.Context,
+	_ int64,
+	_ null.Int,
+	token string,
+) (int64, error) {
+	return int64(len(r.tail(token))), nil
+}
+
+func (r *fakeFeedNewsRepository) GetFeedSessionTail(
+	_ context.Context,
+	p dto.GetFeedParams,
+) ([]entity.NewsListItem, error) {
+	tail := r.tail(p.Session.String)
+	items := []entity.NewsListItem{}
+	for i := p.Offset.Int64; i < int64(len(tail)) && i < p.Offset.Int64+p.Limit.Int64; i++ {
+		items = append(items, entity.NewsListItem{ID: tail[i].NewsID})
+	}
+	return items, nil
+}
+
+func TestWeightedFeedScorer(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	s := NewWeightedFeedScorer(DefaultWeightedFeedScorerConfig())
+	base := entity.FeedCandidate{CreatedAt: now.Add(-time.Hour).Unix(), MediaAffinity: 2, FavoriteCount: 3}
+
+	tests := []struct {
+		name          string
+		higher, lower func(c entity.FeedCandidate) entity.FeedCandidate
+	}{
+		{
+			"recency",
+			func(c entity.FeedCandidate) entity.FeedCandidate { return c },
+			func(c entity.FeedCandidate) entity.FeedCandidate {
+				c.CreatedAt -= int64((24 * time.Hour).Seconds())
+				return c
+			},
+		},
+		{
+			"future news counts as new",
+			func(c entity.FeedCandidate) entity.FeedCandidate {
+				c.CreatedAt = now.Add(time.Hour).Unix()
+				return c
+			},
+			func(c entity.FeedCandidate) entity.FeedCandidate { return c },
+		},
+		{
+			"outlet affinity",
+			func(c entity.FeedCandidate) entity.FeedCandidate {
+				c.MediaAffinity = 10
+				return c
+			},
+			func(c entity.FeedCandidate) entity.FeedCandidate { return c },
+		},
+		{
+			"engagement",
+			func(c entity.FeedCandidate) entity.FeedCandidate {
+				c.FavoriteCount = 50
+				return c
+			},
+			func(c entity.FeedCandidate) entity.FeedCandidate { return c },
+		},
+		{
+			"unread",
+			func(c entity.FeedCandidate) entity.FeedCandidate { return c },
+			func(c entity.FeedCandidate) entity.FeedCandidate {
+				c.IsRead = true
+				return c
+			},
+		},
+		{
+			"fresh read news over stale unread news",
+			func(c entity.FeedCandidate) entity.FeedCandidate {
+				c.IsRead = true
+				return c
+			},
+			func(c entity.FeedCandidate) entity.FeedCandidate {
+				c.CreatedAt -= int64((72 * time.Hour).Seconds())
+				return c
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			higher := s.Score(1, tt.higher(base), now)
+			lower := s.Score(1, tt.lower(base), now)
+			if higher <= lower {
+				t.Errorf("score %v, want more than %v", higher, lower)
+			}
+		})
+	}
+}
+
+type constFeedScorer float64
+
+func (s constFeedScorer) Score(int64, entity.FeedCandidate, time.Time) float64 {
+	return float64(s)
+}
+
+func TestSplitFeedScorer(t *testing.T) {
+	_, err := NewSplitFeedScorer()
+	if err == nil {
+		t.Fatal("expected an error without variants")
+	}
+
+	s, err := NewSplitFeedScorer(constFeedScorer(0), constFeedScorer(1), constFeedScorer(2))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for userID, want := range map[int64]float64{0: 0, 1: 1, 2: 2, 3: 0, 7: 1, -1: 1, -5: 2} {
+		if got := s.Score(userID, entity.FeedCandidate{}, time.Time{}); got != want {
+			t.Errorf("user %d got variant %v, want %v", userID, got, want)
+		}
+	}
+}
+
+func TestRankFeedCandidates(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	candidates := []entity.FeedCandidate{
+		{NewsID: 1, MediaID: 1, CreatedAt: now.Add(-5 * time.Hour).Unix()},
+		{NewsID: 2, MediaID: 2, CreatedAt: now.Add(-time.Hour).Unix(), IsRead: true},
+		{NewsID: 3, MediaID: 3, CreatedAt: now.Add(-2 * time.Hour).Unix(), MediaAffinity: 20},
+		{NewsID: 4, MediaID: 4, CreatedAt: now.Add(-3 * time.Hour).Unix()},
+		{NewsID: 5, MediaID: 5, CreatedAt: now.Add(-3 * time.Hour).Unix()},
+	}
+
+	got := rankFeedCandidates(NewWeightedFeedScorer(DefaultWeightedFeedScorerConfig()), 1, candidates, now)
+	want := []int64{3, 5, 4, 1, 2}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ranked %v, want %v", got, want)
+	}
+}
+
+func TestDiversifyFeedCandidates(t *testing.T) {
+	tests := []struct {
+		name  string
+		media []int64
+		want  []int64
+	}{
+		{"no runs", []int64{1, 2, 1, 2}, []int64{1, 2, 3, 4}},
+		{"run of two is kept", []int64{1, 1, 2, 2}, []int64{1, 2, 3, 4}},
+		{"run of three is broken", []int64{1, 1, 1, 2}, []int64{1, 2, 4, 3}},
+		{"long run interleaved", []int64{1, 1, 1, 1, 1, 2, 3}, []int64{1, 2, 6, 3, 4, 7, 5}},
+		{"only one outlet", []int64{1, 1, 1, 1}, []int64{1, 2, 3, 4}},
+		{"one outlet left at the end", []int64{2, 1, 1, 1, 1, 1}, []int64{1, 2, 3, 4, 5, 6}},
+		{"run after a break", []int64{1, 1, 2, 1, 1, 1, 3}, []int64{1, 2, 3, 4, 5, 7, 6}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sorted := make([]entity.FeedCandidate, len(tt.media))
+			mediaOf := make(map[int64]int64, len(tt.media))
+			for i, m := range tt.media {
+				sorted[i] = entity.FeedCandidate{NewsID: int64(i + 1), MediaID: m}
+				mediaOf[int64(i+1)] = m
+			}
+
+			got := diversifyFeedCandidates(sorted, rankedFeedMaxRun)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+
+			checkFeedRuns(t, got, mediaOf)
+		})
+	}
+}
+
+func TestGetRankedFeedPages(t *testing.T) {
+	tests := []struct {
+		name   string
+		news   int
+		limit  int64
+		cutoff bool
+	}{
+		{"fewer than the candidate limit", 120, 25, false},
+		{"exactly the candidate limit", rankedFeedCandidateLimit, 50, true},
+		{"past the candidate limit", rankedFeedCandidateLimit + 137, 37, true},
+		{"page straddling the cutoff", rankedFeedCandidateLimit + 40, 30, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			now := time.Now()
+			repo := &fakeFeedNewsRepository{sessions: map[string]entity.FeedSession{}}
+			for i := 0; i < tt.news; i++ {
+				repo.feed = append(repo.feed, entity.FeedCandidate{
+					NewsID:        int64(tt.news - i),
+					MediaID:       int64(i%7/4 + 1),
+					CreatedAt:     now.Add(-time.Duration(i) * time.Minute).Unix(),
+					FavoriteCount: int64(i % 5),
+					MediaAffinity: int64(i % 3),
+				})
+			}
+
+			u := NewFeedUseCase(
+				func() adapter.NewsRepository { return repo },
+				nil,
+				NewWeightedFeedScorer(DefaultWeightedFeedScorerConfig()),
+				NewAttachmentURLSigner(AttachmentURLConfig{}),
+			)
+
+			var (
+				session string
+				seen    = map[int64]bool{}
+				got     []int64
+			)
+			for offset := int64(0); ; offset += tt.limit {
+				res, err := u.GetFeed(context.Background(), dto.GetFeedParams{
+					UserID:  1,
+					Limit:   null.IntFrom(tt.limit),
+					Offset:  null.IntFrom(offset),
+					Sort:    null.StringFrom(dto.FeedSortRanked),
+					Session: null.NewString(session, session != ""),
+				})
+				if err != nil {
+					t.Fatalf("offset %d: unexpected error: %v", offset, err)
+				}
+				if session != "" && res.Session != session {
+					t.Fatalf("offset %d: session changed", offset)
+				}
+				session = res.Session
+				if res.Total != int64(tt.news) {
+					t.Fatalf("offset %d: total = %d, want %d", offset, res.Total, tt.news)
+				}
+				if len(res.Items) == 0 {
+					break
+				}
+				if int64(len(res.Items)) > tt.limit {
+					t.Fatalf("offset %d: %d items, limit %d", offset, len(res.Items), tt.limit)
+				}
+
+				for _, item := range res.Items {
+					if seen[item.ID] {
+						t.Fatalf("news %d returned twice", item.ID)
+					}
+					seen[item.ID] = true
+					got = append(got, item.ID)
+				}
+
+				// News published while paging must not shift the session.
+				repo.feed = append([]entity.FeedCandidate{{
+					NewsID:    int64(1000000 + offset),
+					MediaID:   9,
+					CreatedAt: now.Unix(),
+				}}, repo.feed...)
+			}
+
+			if len(got) != tt.news {
+				t.Fatalf("paged through %d news, want %d", len(got), tt.news)
+			}
+			for id := int64(1); id <= int64(tt.news); id++ {
+				if !seen[id] {
+					t.Errorf("news %d is missing", id)
+				}
+			}
+
+			mediaOf := make(map[int64]int64, len(repo.feed))
+			for _, c := range repo.feed {
+				mediaOf[c.NewsID] = c.MediaID
+			}
+			checkFeedRuns(t, repo.sessions[session].NewsIDs, mediaOf)
+
+			if repo.sessions[session].CutoffNewsID.Valid != tt.cutoff {
+				t.Errorf("cutoff set = %v, want %v", repo.sessions[session].CutoffNewsID.Valid, tt.cutoff)
+			}
+			ranked := len(repo.sessions[session].NewsIDs)
+			if !reflect.DeepEqual(got[:ranked], repo.sessions[session].NewsIDs) {
+				t.Error("ranked part doesn't follow the session order")
+			}
+			for i, id := range got[ranked:] {
+				if want := int64(tt.news - rankedFeedCandidateLimit - i); id != want {
+					t.Fatalf("tail item %d = %d, want %d", i, id, want)
+				}
+			}
+		})
+	}
+}
+
+// checkFeedRuns fails if an outlet has more than rankedFeedMaxRun items in a row while another outlet is left.
+func checkFeedRuns(t *testing.T, ids []int64, mediaOf map[int64]int64) {
+	t.Helper()
+	run := 0
+	for i, id := range ids {
+		if i > 0 && mediaOf[id] == mediaOf[ids[i-1]] {
+			run++
+		} else {
+			run = 1
+		}
+		if run <= rankedFeedMaxRun {
+			continue
+		}
+		for _, rest := range ids[i:] {
+			if mediaOf[rest] != mediaOf[id] {
+				t.Errorf("outlet %d has %d items in a row at %d while outlet %d is left", mediaOf[id], run, i, mediaOf[rest])
+				return
+			}
+		}
+	}
+}
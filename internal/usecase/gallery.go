@@ -0,0 +1,298 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"news-app-api/internal/dto"
+	"news-app-api/internal/entity"
+)
+
+const maxGalleryImages = 100
+
+func (u *newsUseCase) AddGalleryImage(
+	ctx context.Context,
+	p dto.AddGalleryImageParams,
+) (img entity.GalleryImage, err error) {
+	defer func() {
+		if err != nil {
+			var appErr *dto.AppError
+			if !errors.As(err, &appErr) {
+				err = fmt.Errorf("NewsUseCase - AddGalleryImage: %w", err)
+			}
+		}
+	}()
+
+	err = p.Validate()
+	if err != nil {
+		return
+	}
+
+	err = u.authorizeNewsMedia(ctx, p.NewsID, p.MediaID)
+	if err != nil {
+		return
+	}
+
+	images, err := u.attachmentRepo.GetGalleryImages(ctx, p.NewsID)
+	if err != nil {
+		return
+	}
+	if len(images) >= maxGalleryImages {
+		return img, &dto.AppError{
+			Message: "В галерее может быть не более 100 изображений",
+			Code:    dto.ErrCodeBadRequest,
+		}
+	}
+
+	data, thumbnails, err := u.readImageUpload(p.File)
+	if err != nil {
+		return
+	}
+
+	blob, err := u.putAttachmentBlob(ctx, entity.AttachmentKindImage, bytes.NewReader(data))
+	if err != nil {
+		return
+	}
+	blob.NewsID = p.NewsID
+
+	img, err = u.attachmentRepo.CreateGalleryImage(ctx, entity.GalleryImage{
+		Attachment: blob,
+		Caption:    p.Caption,
+		AltText:    p.AltText,
+		Credit:     p.Credit,
+	})
+	if err != nil {
+		return
+	}
+
+	err = u.storeImageVariants(ctx, img.Attachment, thumbnails)
+	if err != nil {
+		return
+	}
+
+	img.URL = u.galleryImageURL(img)
+	return
+}
+
+func (u *newsUseCase) GetGallery(ctx context.Context, p dto.GetGalleryParams) (res dto.GetGalleryResult, err error) {
+	defer func() {
+		if err != nil {
+			var appErr *dto.AppError
+			if !errors.As(err, &appErr) {
+				err = fmt.Errorf("NewsUseCase - GetGallery: %w", err)
+			}
+		}
+	}()
+
+	n, err := u.newsRepo().GetNews(ctx, p.NewsID)
+	if err != nil {
+		return
+	}
+
+	return u.gallery(ctx, n.ID)
+}
+
+func (u *newsUseCase) GetGalleryImage(
+	ctx context.Context,
+	p dto.GetGalleryImageParams,
+) (res dto.GetAttachmentResult, err error) {
+	defer func() {
+		if err != nil {
+			var appErr *dto.AppError
+			if !errors.As(err, &appErr) {
+				err = fmt.Errorf("NewsUseCase - GetGalleryImage: %w", err)
+			}
+		}
+	}()
+
+	err = p.Validate()
+	if err != nil {
+		return
+	}
+
+	expiresAt, err := u.urls.Verify(
+		entity.AttachmentKindGallery,
+		galleryImagePath(p.NewsID, p.ImageID),
+		p.AttachmentSignature,
+	)
+	if err != nil {
+		return
+	}
+
+	img, err := u.attachmentRepo.GetGalleryImage(ctx, p.NewsID, p.ImageID)
+	if err != nil {
+		return
+	}
+
+	res, err = u.openGalleryImage(ctx, img.Attachment, p)
+	res.ExpiresAt = expiresAt
+	return
+}
+
+func (u *newsUseCase) openGalleryImage(
+	ctx context.Context,
+	a entity.Attachment,
+	p dto.GetGalleryImageParams,
+) (res dto.GetAttachmentResult, err error) {
+	if !p.Width.Valid && !p.Format.Valid {
+		return u.openAttachmentBlob(ctx, a, a.StorageKey, a.ContentType, a.ContentHash, a.UpdatedAt)
+	}
+
+	variants, err := u.attachmentRepo.GetAttachmentVariants(ctx, a.ID)
+	if err != nil {
+		return
+	}
+
+	v, ok := selectImageVariant(variants, p.GetImageParams)
+	if !ok {
+		return u.openAttachmentBlob(ctx, a, a.StorageKey, a.ContentType, a.ContentHash, a.UpdatedAt)
+	}
+
+	return u.openAttachmentBlob(ctx, a, v.StorageKey, v.ContentType, v.ContentHash, v.UpdatedAt)
+}
+
+func (u *newsUseCase) UpdateGalleryImage(
+	ctx context.Context,
+	p dto.UpdateGalleryImageParams,
+) (img entity.GalleryImage, err error) {
+	defer func() {
+		if err != nil {
+			var appErr *dto.AppError
+			if !errors.As(err, &appErr) {
+				err = fmt.Errorf("NewsUseCase - UpdateGalleryImage: %w", err)
+			}
+		}
+	}()
+
+	err = p.Validate()
+	if err != nil {
+		return
+	}
+
+	err = u.authorizeNewsMedia(ctx, p.NewsID, p.MediaID)
+	if err != nil {
+		return
+	}
+
+	img, err = u.attachmentRepo.UpdateGalleryImage(ctx, p)
+	if err != nil {
+		return
+	}
+
+	img.URL = u.galleryImageURL(img)
+	return
+}
+
+func (u *newsUseCase) ReorderGallery(
+	ctx context.Context,
+	p dto.ReorderGalleryParams,
+) (res dto.GetGalleryResult, err error) {
+	defer func() {
+		if err != nil {
+			var appErr *dto.AppError
+			if !errors.As(err, &appErr) {
+				err = fmt.Errorf("NewsUseCase - ReorderGallery: %w", err)
+			}
+		}
+	}()
+
+	err = p.Validate()
+	if err != nil {
+		return
+	}
+
+	err = u.authorizeNewsMedia(ctx, p.NewsID, p.MediaID)
+	if err != nil {
+		return
+	}
+
+	images, err := u.attachmentRepo.GetGalleryImages(ctx, p.NewsID)
+	if err != nil {
+		return
+	}
+
+	existing := make(map[int64]bool, len(images))
+	for _, img := range images {
+		existing[img.ID] = true
+	}
+	for _, id := range p.ImageIDs {
+		delete(existing, id)
+	}
+	if len(p.ImageIDs) != len(images) || len(existing) != 0 {
+		return res, &dto.AppError{
+			Message: "Порядок должен содержать все изображения галереи",
+			Code:    dto.ErrCodeBadRequest,
+		}
+	}
+
+	err = u.attachmentRepo.ReorderGalleryImages(ctx, p.NewsID, p.ImageIDs)
+	if err != nil {
+		return
+	}
+
+	return u.gallery(ctx, p.NewsID)
+}
+
+func (u *newsUseCase) DeleteGalleryImage(ctx context.Context, p dto.DeleteGalleryImageParams) (err error) {
+	defer func() {
+		if err != nil {
+			var appErr *dto.AppError
+			if !errors.As(err, &appErr) {
+				err = fmt.Errorf("NewsUseCase - DeleteGalleryImage: %w", err)
+			}
+		}
+	}()
+
+	err = u.authorizeNewsMedia(ctx, p.NewsID, p.MediaID)
+	if err != nil {
+		return
+	}
+
+	deleted, err := u.attachmentRepo.DeleteGalleryImage(ctx, p.NewsID, p.ImageID)
+	if err != nil {
+		return
+	}
+
+	return u.releaseStoredObjects(ctx, deleted)
+}
+
+func (u *newsUseCase) gallery(ctx context.Context, newsID int64) (res dto.GetGalleryResult, err error) {
+	res.Items, err = u.attachmentRepo.GetGalleryImages(ctx, newsID)
+	if err != nil {
+		return
+	}
+
+	for i := range res.Items {
+		res.Items[i].URL = u.galleryImageURL(res.Items[i])
+	}
+	return
+}
+
+func (u *newsUseCase) authorizeNewsMedia(ctx context.Context, newsID, mediaID int64) (err error) {
+	n, err := u.newsRepo().GetNews(ctx, newsID)
+	if err != nil {
+		return
+	}
+
+	m, err := u.mediaRepo.GetMediaByRegistrationNumber(ctx, n.Media.RegistrationNumber)
+	if err != nil {
+		return
+	}
+
+	if m.ID != mediaID {
+		return &dto.AppError{
+			Code:    dto.ErrCodeUnauthorized,
+			Message: "Недостаточно прав для совершения данной операции",
+		}
+	}
+	return
+}
+
+func (u *newsUseCase) galleryImageURL(img entity.GalleryImage) string {
+	return u.urls.Sign(fmt.Sprintf("%s?v=%s", galleryImagePath(img.NewsID, img.ID), attachmentVersion(img.Attachment)))
+}
+
+func galleryImagePath(newsID, imageID int64) string {
+	return attachmentPath(newsID, fmt.Sprintf("%s/%d", entity.AttachmentKindGallery, imageID))
+}
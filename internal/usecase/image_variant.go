@@ -0,0 +1,94 @@
+package usecase
+
+import (
+	"context"
+	"gopkg.in/guregu/null.v3"
+	"io"
+	"news-app-api/internal/dto"
+	"news-app-api/internal/entity"
+)
+
+var imageVariantWidths = []int{160, 480, 1080}
+
+func (u *newsUseCase) readImageUpload(r io.Reader) (data []byte, thumbnails []entity.Thumbnail, err error) {
+	format := attachmentFormats[entity.AttachmentKindImage]
+
+	data, err = io.ReadAll(limitUpload(r, format.maxSize, format.sizeMessage))
+	if err != nil {
+		return
+	}
+
+	if _, ok := format.contentTypes[detectMediaType(data)]; !ok {
+		return nil, nil, &dto.AppError{
+			Message: format.typeMessage,
+			Code:    dto.ErrCodeBadRequest,
+		}
+	}
+
+	thumbnails, err = u.thumbnailer.Generate(data, imageVariantWidths)
+	return
+}
+
+func (u *newsUseCase) storeImageVariants(
+	ctx context.Context,
+	a entity.Attachment,
+	thumbnails []entity.Thumbnail,
+) (err error) {
+	variants := make([]entity.AttachmentVariant, 0, len(thumbnails))
+
+	for _, t := range thumbnails {
+		var blob entity.Blob
+		blob, err = u.storeBlobBytes(ctx, t.Data)
+		if err != nil {
+			return
+		}
+
+		variants = append(variants, entity.AttachmentVariant{
+			Width:       t.Width,
+			Height:      t.Height,
+			ContentType: t.ContentType,
+			Size:        blob.Size,
+			ContentHash: blob.ContentHash,
+			StorageKey:  blob.StorageKey,
+			BlobHash:    null.StringFrom(blob.ContentHash),
+		})
+	}
+
+	previous, err := u.attachmentRepo.ReplaceAttachmentVariants(ctx, a.ID, variants)
+	if err != nil {
+		return
+	}
+
+	return u.releaseStoredObjects(ctx, previous)
+}
+
+func selectImageVariant(variants []entity.AttachmentVariant, p dto.GetImageParams) (entity.AttachmentVariant, bool) {
+	accepted := map[string]bool{"image/jpeg": true, "image/webp": p.AcceptsWebP}
+	if p.Format.Valid {
+		accepted = map[string]bool{"image/" + p.Format.String: true}
+	}
+
+	width := 0
+	for _, v := range variants {
+		if !accepted[v.ContentType] {
+			continue
+		}
+		fits := p.Width.Valid && int64(v.Width) >= p.Width.Int64
+		switch {
+		case width == 0:
+			width = v.Width
+		case fits && (v.Width < width || int64(width) < p.Width.Int64):
+			width = v.Width
+		case !fits && v.Width > width && (!p.Width.Valid || int64(width) < p.Width.Int64):
+			width = v.Width
+		}
+	}
+
+	var res entity.AttachmentVariant
+	for _, v := range variants {
+		if accepted[v.ContentType] && v.Width == width && (res.StorageKey == "" || v.Size < res.Size) {
+			res = v
+		}
+	}
+	return res, res.StorageKey != ""
+}
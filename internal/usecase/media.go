@@ -1,12 +1,16 @@
 package usecase
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
+	"gopkg.in/guregu/null.v3"
+	"io"
 	"news-app-api/internal/adapter"
 	"news-app-api/internal/dto"
 	"news-app-api/internal/entity"
+	"time"
 )
 
 type (
@@ -14,19 +18,40 @@ type (
 		Register(ctx context.Context, p dto.RegisterMediaParams) (entity.Media, error)
 		LoginMedia(ctx context.Context, p dto.LoginMediaParams) (entity.Media, error)
 		GetMediaByID(ctx context.Context, mediaID int64) (entity.Media, error)
+		UpdateAccount(ctx context.Context, p dto.UpdateMediaAccountParams) (entity.Media, error)
 		GetMediaList(ctx context.Context, p dto.GetMediaListParams) (dto.GetMediaListResult, error)
 		ToggleSubscription(ctx context.Context, p dto.ToggleSubscriptionParams) (dto.ToggleSubscriptionResult, error)
 		GetNewsList(ctx context.Context, p dto.GetNewsListParams) (dto.GetNewsListResult, error)
+		GetProfile(ctx context.Context, p dto.GetMediaProfileParams) (entity.MediaProfile, error)
+		UpdateProfile(ctx context.Context, p dto.UpdateMediaProfileParams) (entity.MediaProfile, error)
+		UploadImage(ctx context.Context, p dto.UploadMediaImageParams) (entity.MediaProfile, error)
+		GetImage(ctx context.Context, p dto.GetMediaImageParams) (entity.File, string, error)
+		GetSubscriberList(ctx context.Context, p dto.GetSubscriberListParams) (dto.GetSubscriberListResult, error)
+		GetSubscriptionStats(
+			ctx context.Context,
+			p dto.GetSubscriptionStatsParams,
+		) (dto.GetSubscriptionStatsResult, error)
 	}
 
 	mediaUseCase struct {
 		mediaRepo adapter.MediaRepository
 		newsRepo  func() adapter.NewsRepository
+		blobStore adapter.BlobStore
+		urls      AttachmentURLSigner
+		events    EventPublisher
 	}
 )
 
-func NewMediaUseCase(mediaRepo adapter.MediaRepository, newsRepo func() adapter.NewsRepository) MediaUseCase {
-	return &mediaUseCase{mediaRepo, newsRepo}
+const maxMediaImageSize = 5 << 20
+
+func NewMediaUseCase(
+	mediaRepo adapter.MediaRepository,
+	newsRepo func() adapter.NewsRepository,
+	blobStore adapter.BlobStore,
+	urls AttachmentURLSigner,
+	events EventPublisher,
+) MediaUseCase {
+	return &mediaUseCase{mediaRepo, newsRepo, blobStore, urls, events}
 }
 
 func (u *mediaUseCase) Register(ctx context.Context, p dto.RegisterMediaParams) (m entity.Media, err error) {
@@ -44,49 +69,22 @@ func (u *mediaUseCase) Register(ctx context.Context, p dto.RegisterMediaParams)
 		return
 	}
 
-	_, err = u.mediaRepo.GetMediaByEmail(ctx, p.Email)
-	if err == nil {
-		err = &dto.AppError{
-			Message: "Email уже занят",
-			Code:    dto.ErrCodeConflict,
-		}
-		return
-	}
+	err = u.checkEmailConflict(ctx, p.Email, 0)
 	if err != nil {
-		var appErr *dto.AppError
-		if !errors.As(err, &appErr) || appErr.Code != dto.ErrCodeNotFound {
-			return
-		}
-	}
-
-	_, err = u.mediaRepo.GetMediaByName(ctx, p.Name)
-	if err == nil {
-		err = &dto.AppError{
-			Message: "Название уже занято",
-			Code:    dto.ErrCodeConflict,
-		}
 		return
 	}
-	if err != nil {
-		var appErr *dto.AppError
-		if !errors.As(err, &appErr) || appErr.Code != dto.ErrCodeNotFound {
-			return
-		}
-	}
 
-	_, err = u.mediaRepo.GetMediaByRegistrationNumber(ctx, p.RegistrationNumber)
-	if err == nil {
-		err = &dto.AppError{
-			Message: "Регистрационный номер уже занят",
-			Code:    dto.ErrCodeConflict,
-		}
+	err = u.checkNameConflict(ctx, p.Name, 0)
+	if err != nil {
 		return
 	}
+
+	err = checkConflict(0, "Регистрационный номер уже занят", func() (int64, error) {
+		m, err := u.mediaRepo.GetMediaByRegistrationNumber(ctx, p.RegistrationNumber)
+		return m.ID, err
+	})
 	if err != nil {
-		var appErr *dto.AppError
-		if !errors.As(err, &appErr) || appErr.Code != dto.ErrCodeNotFound {
-			return
-		}
+		return
 	}
 
 	return u.mediaRepo.CreateMedia(ctx, p)
@@ -102,7 +100,16 @@ func (u *mediaUseCase) LoginMedia(ctx context.Context, p dto.LoginMediaParams) (
 		}
 	}()
 
-	m, err = u.mediaRepo.GetMediaByRegistrationNumber(ctx, p.RegistrationNumber)
+	registrationNumber, ok := dto.ParseLoginRegistrationNumber(p.RegistrationNumber)
+	if !ok {
+		err = &dto.AppError{
+			Message: "СМИ не найдено",
+			Code:    dto.ErrCodeNotFound,
+		}
+		return
+	}
+
+	m, err = u.mediaRepo.GetMediaByRegistrationNumber(ctx, registrationNumber)
 	if err != nil {
 		return
 	}
@@ -130,6 +137,48 @@ func (u *mediaUseCase) GetMediaByID(ctx context.Context, mediaID int64) (m entit
 	return u.mediaRepo.GetMediaByID(ctx, mediaID)
 }
 
+func (u *mediaUseCase) UpdateAccount(ctx context.Context, p dto.UpdateMediaAccountParams) (m entity.Media, err error) {
+	defer func() {
+		if err != nil {
+			var appErr *dto.AppError
+			if !errors.As(err, &appErr) {
+				err = fmt.Errorf("MediaUseCase - UpdateAccount: %w", err)
+			}
+		}
+	}()
+
+	err = p.Validate()
+	if err != nil {
+		return
+	}
+
+	err = u.checkEmailConflict(ctx, p.Email, p.MediaID)
+	if err != nil {
+		return
+	}
+
+	err = u.checkNameConflict(ctx, p.Name, p.MediaID)
+	if err != nil {
+		return
+	}
+
+	return u.mediaRepo.UpdateMediaAccount(ctx, p)
+}
+
+func (u *mediaUseCase) checkEmailConflict(ctx context.Context, email string, exceptMediaID int64) error {
+	return checkConflict(exceptMediaID, "Email уже занят", func() (int64, error) {
+		m, err := u.mediaRepo.GetMediaByEmail(ctx, email)
+		return m.ID, err
+	})
+}
+
+func (u *mediaUseCase) checkNameConflict(ctx context.Context, name string, exceptMediaID int64) error {
+	return checkConflict(exceptMediaID, "Название уже занято", func() (int64, error) {
+		m, err := u.mediaRepo.GetMediaByName(ctx, name)
+		return m.ID, err
+	})
+}
+
 func (u *mediaUseCase) GetMediaList(
 	ctx context.Context,
 	p dto.GetMediaListParams,
@@ -143,12 +192,17 @@ func (u *mediaUseCase) GetMediaList(
 		}
 	}()
 
+	err = p.Validate()
+	if err != nil {
+		return
+	}
+
 	res.Items, err = u.mediaRepo.GetMediaList(ctx, p)
 	if err != nil {
 		return
 	}
 
-	res.Total, err = u.mediaRepo.CountMedia(ctx)
+	res.Total, err = u.mediaRepo.CountMedia(ctx, p)
 
 	return
 }
@@ -182,6 +236,12 @@ func (u *mediaUseCase) ToggleSubscription(
 
 	res.IsSubscribed = !isExists
 
+	u.events.Publish(ctx, entity.SubscriptionToggledEvent{
+		MediaID:      p.MediaID,
+		UserID:       p.UserID,
+		IsSubscribed: res.IsSubscribed,
+	})
+
 	return
 }
 
@@ -204,7 +264,204 @@ func (u *mediaUseCase) GetNewsList(
 	if err != nil {
 		return
 	}
+	u.urls.SignNewsList(res.Items)
 
 	res.Total, err = r.CountNews(ctx, p.MediaID)
 	return
 }
+
+func (u *mediaUseCase) GetProfile(
+	ctx context.Context,
+	p dto.GetMediaProfileParams,
+) (m entity.MediaProfile, err error) {
+	defer func() {
+		if err != nil {
+			var appErr *dto.AppError
+			if !errors.As(err, &appErr) {
+				err = fmt.Errorf("MediaUseCase - GetProfile: %w", err)
+			}
+		}
+	}()
+
+	m, err = u.mediaRepo.GetMediaProfile(ctx, p.MediaID, p.UserID)
+	if err != nil {
+		return
+	}
+
+	m.LogoURL = mediaImageURL(m.ID, dto.MediaImageLogo, m.LogoUpdatedAt)
+	m.CoverURL = mediaImageURL(m.ID, dto.MediaImageCover, m.CoverUpdatedAt)
+
+	return
+}
+
+func (u *mediaUseCase) UpdateProfile(
+	ctx context.Context,
+	p dto.UpdateMediaProfileParams,
+) (m entity.MediaProfile, err error) {
+	defer func() {
+		if err != nil {
+			var appErr *dto.AppError
+			if !errors.As(err, &appErr) {
+				err = fmt.Errorf("MediaUseCase - UpdateProfile: %w", err)
+			}
+		}
+	}()
+
+	err = p.Validate()
+	if err != nil {
+		return
+	}
+
+	err = u.mediaRepo.UpdateMediaProfile(ctx, p)
+	if err != nil {
+		return
+	}
+
+	return u.GetProfile(ctx, dto.GetMediaProfileParams{MediaID: p.MediaID})
+}
+
+func (u *mediaUseCase) UploadImage(
+	ctx context.Context,
+	p dto.UploadMediaImageParams,
+) (m entity.MediaProfile, err error) {
+	defer func() {
+		if err != nil {
+			var appErr *dto.AppError
+			if !errors.As(err, &appErr) {
+				err = fmt.Errorf("MediaUseCase - UploadImage: %w", err)
+			}
+		}
+	}()
+
+	data, err := io.ReadAll(io.LimitReader(p.File, maxMediaImageSize+1))
+	if err != nil {
+		return
+	}
+
+	if len(data) > maxMediaImageSize {
+		return m, &dto.AppError{
+			Message: "Максимальный размер изображения - 5 МБ",
+			Code:    dto.ErrCodeBadRequest,
+		}
+	}
+
+	format := attachmentFormats[entity.AttachmentKindImage]
+	if _, ok := format.contentTypes[detectMediaType(data)]; !ok {
+		return m, &dto.AppError{
+			Message: format.typeMessage,
+			Code:    dto.ErrCodeBadRequest,
+		}
+	}
+
+	err = u.blobStore.Put(ctx, mediaImageKey(p.MediaID, p.Kind), bytes.NewReader(data))
+	if err != nil {
+		return
+	}
+
+	err = u.mediaRepo.TouchMediaImage(ctx, p.MediaID, p.Kind)
+	if err != nil {
+		return
+	}
+
+	return u.GetProfile(ctx, dto.GetMediaProfileParams{MediaID: p.MediaID})
+}
+
+func (u *mediaUseCase) GetImage(
+	ctx context.Context,
+	p dto.GetMediaImageParams,
+) (f entity.File, contentType string, err error) {
+	defer func() {
+		if err != nil {
+			var appErr *dto.AppError
+			if !errors.As(err, &appErr) {
+				err = fmt.Errorf("MediaUseCase - GetImage: %w", err)
+			}
+		}
+	}()
+
+	f, err = u.blobStore.Open(ctx, mediaImageKey(p.MediaID, p.Kind))
+	if err != nil {
+		return
+	}
+
+	head := make([]byte, mediaTypeSniffLen)
+	n, err := f.ReadAt(head, 0)
+	if err != nil && err != io.EOF {
+		f.Close()
+		return nil, "", err
+	}
+
+	contentType = detectMediaType(head[:n])
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	return f, contentType, nil
+}
+
+func (u *mediaUseCase) GetSubscriberList(
+	ctx context.Context,
+	p dto.GetSubscriberListParams,
+) (res dto.GetSubscriberListResult, err error) {
+	defer func() {
+		if err != nil {
+			var appErr *dto.AppError
+			if !errors.As(err, &appErr) {
+				err = fmt.Errorf("MediaUseCase - GetSubscriberList: %w", err)
+			}
+		}
+	}()
+
+	res.Items, err = u.mediaRepo.GetSubscriberList(ctx, p)
+	if err != nil {
+		return
+	}
+
+	res.Total, err = u.mediaRepo.CountSubscribers(ctx, p.MediaID)
+	return
+}
+
+func (u *mediaUseCase) GetSubscriptionStats(
+	ctx context.Context,
+	p dto.GetSubscriptionStatsParams,
+) (res dto.GetSubscriptionStatsResult, err error) {
+	defer func() {
+		if err != nil {
+			var appErr *dto.AppError
+			if !errors.As(err, &appErr) {
+				err = fmt.Errorf("MediaUseCase - GetSubscriptionStats: %w", err)
+			}
+		}
+	}()
+
+	err = p.Validate(time.Now())
+	if err != nil {
+		return
+	}
+
+	res.Days, err = u.mediaRepo.GetSubscriptionStats(ctx, p)
+	if err != nil {
+		return
+	}
+
+	res.From = p.From.String
+	res.To = p.To.String
+	res.Timezone = p.Timezone.String
+	for _, day := range res.Days {
+		res.Subscribed += day.Subscribed
+		res.Unsubscribed += day.Unsubscribed
+	}
+	res.Net = res.Subscribed - res.Unsubscribed
+
+	return
+}
+
+func mediaImageKey(mediaID int64, kind string) string {
+	return fmt.Sprintf("image/media-%d-%s", mediaID, kind)
+}
+
+func mediaImageURL(mediaID int64, kind string, updatedAt null.Int) null.String {
+	if !updatedAt.Valid {
+		return null.String{}
+	}
+	return null.StringFrom(fmt.Sprintf("/api/media/%d/%s?v=%d", mediaID, kind, updatedAt.Int64))
+}
@@ -0,0 +1,90 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"news-app-api/internal/adapter"
+	"news-app-api/internal/dto"
+	"news-app-api/internal/entity"
+	"testing"
+)
+
+type fakeMediaRepository struct {
+	adapter.MediaRepository
+	touched []string
+}
+
+func (r *fakeMediaRepository) TouchMediaImage(_ context.Context, _ int64, kind string) error {
+	r.touched = append(r.touched, kind)
+	return nil
+}
+
+func (r *fakeMediaRepository) GetMediaProfile(_ context.Context, mediaID, _ int64) (entity.MediaProfile, error) {
+	return entity.MediaProfile{ID: mediaID}, nil
+}
+
+func TestMediaImageContentType(t *testing.T) {
+	webp := append([]byte("RIFF\x00\x00\x00\x00WEBPVP8 "), make([]byte, 100)...)
+
+	tests := []struct {
+		name string
+		data []byte
+		want string
+	}{
+		{"jpeg", append([]byte{0xFF, 0xD8, 0xFF, 0xE0}, make([]byte, 100)...), "image/jpeg"},
+		{"png", append([]byte("\x89PNG\r\n\x1a\n"), make([]byte, 100)...), "image/png"},
+		{"gif", append([]byte("GIF89a"), make([]byte, 100)...), "image/gif"},
+		{"webp", webp, "image/webp"},
+		{"bmp", append([]byte("BM"), make([]byte, 100)...), ""},
+		{"svg", []byte(`<svg xmlns="http://www.w3.org/2000/svg"></svg>`), ""},
+		{"html", []byte("<!DOCTYPE html><html></html>"), ""},
+		{"wav", testUploadWAV(100), ""},
+		{"mp4", append([]byte("\x00\x00\x00\x18ftypisom"), make([]byte, 100)...), ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeMediaRepository{}
+			store := &fakeBlobStore{files: map[string][]byte{}}
+			u := NewMediaUseCase(repo, nil, store, nil, nil)
+
+			_, err := u.UploadImage(context.Background(), dto.UploadMediaImageParams{
+				MediaID: 1,
+				Kind:    dto.MediaImageLogo,
+				File:    bytes.NewReader(tt.data),
+			})
+			if tt.want == "" {
+				var appErr *dto.AppError
+				if !errors.As(err, &appErr) || appErr.Code != dto.ErrCodeBadRequest {
+					t.Fatalf("err = %v, want bad request", err)
+				}
+				if len(store.files) != 0 || len(repo.touched) != 0 {
+					t.Error("rejected image was stored")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			f, contentType, err := u.GetImage(context.Background(), dto.GetMediaImageParams{
+				MediaID: 1,
+				Kind:    dto.MediaImageLogo,
+			})
+			if err != nil {
+				t.Fatalf("GetImage: %v", err)
+			}
+			defer f.Close()
+
+			if contentType != tt.want {
+				t.Errorf("content type = %q, want %q", contentType, tt.want)
+			}
+			data, err := io.ReadAll(f)
+			if err != nil || !bytes.Equal(data, tt.data) {
+				t.Errorf("stored image differs from the upload")
+			}
+		})
+	}
+}
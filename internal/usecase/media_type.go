@@ -0,0 +1,101 @@
+package usecase
+
+import (
+	"bytes"
+	"io"
+	"news-app-api/internal/entity"
+)
+
+const mediaTypeSniffLen = 512
+
+type (
+	attachmentFormat struct {
+		contentTypes map[string]string
+		maxSize      int64
+		typeMessage  string
+		sizeMessage  string
+	}
+)
+
+var attachmentFormats = map[string]attachmentFormat{
+	entity.AttachmentKindImage: {
+		contentTypes: map[string]string{
+			"image/jpeg": ".jpg",
+			"image/png":  ".png",
+			"image/webp": ".webp",
+			"image/gif":  ".gif",
+		},
+		maxSize:     maxImageUploadSize,
+		typeMessage: "Допустимые форматы изображения: JPEG, PNG, WebP, GIF",
+		sizeMessage: "Максимальный размер изображения - 32 МБ",
+	},
+	entity.AttachmentKindAudio: {
+		contentTypes: map[string]string{
+			"audio/mpeg": ".mp3",
+			"audio/ogg":  ".ogg",
+			"audio/wav":  ".wav",
+			"audio/mp4":  ".m4a",
+		},
+		maxSize:     maxAudioUploadSize,
+		typeMessage: "Допустимые форматы аудио: MP3, OGG, WAV, M4A",
+		sizeMessage: "Максимальный размер аудиофайла - 512 МБ",
+	},
+	entity.AttachmentKindVideo: {
+		contentTypes: map[string]string{
+			"video/mp4":  ".mp4",
+			"video/webm": ".webm",
+		},
+		maxSize:     maxVideoUploadSize,
+		typeMessage: "Допустимые форматы видео: MP4, WebM",
+		sizeMessage: "Максимальный размер видеофайла - 4 ГБ",
+	},
+}
+
+var (
+	isoAudioBrands = map[string]bool{"M4A ": true, "M4B ": true}
+	isoVideoBrands = map[string]bool{
+		"isom": true, "iso2": true, "iso4": true, "iso5": true, "iso6": true,
+		"mp41": true, "mp42": true, "avc1": true, "M4V ": true, "dash": true, "MSNV": true,
+	}
+)
+
+func sniffUpload(r io.Reader) (string, io.Reader, error) {
+	head := make([]byte, mediaTypeSniffLen)
+	n, err := io.ReadFull(r, head)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return "", nil, err
+	}
+	head = head[:n]
+	return detectMediaType(head), io.MultiReader(bytes.NewReader(head), r), nil
+}
+
+func detectMediaType(b []byte) string {
+	switch {
+	case bytes.HasPrefix(b, []byte{0xFF, 0xD8, 0xFF}):
+		return "image/jpeg"
+	case bytes.HasPrefix(b, []byte("\x89PNG\r\n\x1a\n")):
+		return "image/png"
+	case bytes.HasPrefix(b, []byte("GIF87a")), bytes.HasPrefix(b, []byte("GIF89a")):
+		return "image/gif"
+	case len(b) >= 12 && bytes.Equal(b[:4], []byte("RIFF")) && bytes.Equal(b[8:12], []byte("WEBP")):
+		return "image/webp"
+	case len(b) >= 12 && bytes.Equal(b[:4], []byte("RIFF")) && bytes.Equal(b[8:12], []byte("WAVE")):
+		return "audio/wav"
+	case bytes.HasPrefix(b, []byte("OggS")):
+		return "audio/ogg"
+	case bytes.HasPrefix(b, []byte("ID3")):
+		return "audio/mpeg"
+	case len(b) >= 2 && b[0] == 0xFF && b[1]&0xE0 == 0xE0 && b[1]&0x06 == 0x02:
+		return "audio/mpeg"
+	case len(b) >= 12 && bytes.Equal(b[4:8], []byte("ftyp")):
+		brand := string(b[8:12])
+		if isoAudioBrands[brand] {
+			return "audio/mp4"
+		} else if isoVideoBrands[brand] {
+			return "video/mp4"
+		}
+	case bytes.HasPrefix(b, []byte{0x1A, 0x45, 0xDF, 0xA3}) && bytes.Contains(b, []byte("webm")):
+		return "video/webm"
+	}
+	return ""
+}
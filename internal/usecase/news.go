@@ -1,10 +1,10 @@
 package usecase
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
-	"io"
 	"news-app-api/internal/adapter"
 	"news-app-api/internal/dto"
 	"news-app-api/internal/entity"
@@ -15,37 +15,61 @@ type (
 		CreateNews(ctx context.Context, p dto.CreateNewsParams) (entity.News, error)
 		CreateOrUpdateAudio(ctx context.Context, p dto.CreateOrUpdateAudioParams) error
 		CreateOrUpdateImage(ctx context.Context, p dto.CreateOrUpdateImageParams) error
-		GetAudio(ctx context.Context, p dto.GetAudioParams) ([]byte, error)
+		GetAudio(ctx context.Context, p dto.GetAudioParams) (dto.GetAttachmentResult, error)
+		GetAudioWaveform(ctx context.Context, p dto.GetAudioWaveformParams) (entity.Waveform, error)
 		GetNews(ctx context.Context, p dto.GetNewsParams) (entity.NewsListItem, error)
-		GetImage(ctx context.Context, p dto.GetImageParams) ([]byte, error)
+		SearchNews(ctx context.Context, p dto.SearchNewsParams) (dto.SearchNewsResult, error)
+		GetImage(ctx context.Context, p dto.GetImageParams) (dto.GetAttachmentResult, error)
 		ToggleFavorite(ctx context.Context, p dto.ToggleFavoriteParams) (dto.ToggleFavoriteResult, error)
 		GetFavoriteList(ctx context.Context, p dto.GetFavoriteListParams) (dto.GetFavoriteListResult, error)
 		CreateOrUpdateVideo(ctx context.Context, p dto.CreateOrUpdateVideoParams) error
-		GetVideo(ctx context.Context, p dto.GetVideoParams) ([]byte, error)
+		GetVideo(ctx context.Context, p dto.GetVideoParams) (dto.GetAttachmentResult, error)
+		AddGalleryImage(ctx context.Context, p dto.AddGalleryImageParams) (entity.GalleryImage, error)
+		GetGallery(ctx context.Context, p dto.GetGalleryParams) (dto.GetGalleryResult, error)
+		GetGalleryImage(ctx context.Context, p dto.GetGalleryImageParams) (dto.GetAttachmentResult, error)
+		UpdateGalleryImage(ctx context.Context, p dto.UpdateGalleryImageParams) (entity.GalleryImage, error)
+		ReorderGallery(ctx context.Context, p dto.ReorderGalleryParams) (dto.GetGalleryResult, error)
+		DeleteGalleryImage(ctx context.Context, p dto.DeleteGalleryImageParams) error
+		CollectBlobs(ctx context.Context) error
 	}
 
 	newsUseCase struct {
-		newsRepo      func() adapter.NewsRepository
-		mediaRepo     adapter.MediaRepository
-		audioFileRepo adapter.AudioFileRepository
-		imageFileRepo adapter.ImageFileRepository
-		videoFileRepo adapter.VideoFileRepository
+		newsRepo          func() adapter.NewsRepository
+		mediaRepo         adapter.MediaRepository
+		blobStore         adapter.BlobStore
+		attachmentRepo    adapter.AttachmentRepository
+		thumbnailer       adapter.Thumbnailer
+		metadataExtractor adapter.MetadataExtractor
+		waveformGenerator adapter.WaveformGenerator
+		blobRepo          func() adapter.BlobRepository
+		urls              AttachmentURLSigner
+		events            EventPublisher
 	}
 )
 
 func NewNewsUseCase(
 	newsRepo func() adapter.NewsRepository,
 	mediaRepo adapter.MediaRepository,
-	audioFileRepo adapter.AudioFileRepository,
-	imageFileRepo adapter.ImageFileRepository,
-	videoFileRepo adapter.VideoFileRepository,
+	blobStore adapter.BlobStore,
+	attachmentRepo adapter.AttachmentRepository,
+	thumbnailer adapter.Thumbnailer,
+	metadataExtractor adapter.MetadataExtractor,
+	waveformGenerator adapter.WaveformGenerator,
+	blobRepo func() adapter.BlobRepository,
+	urls AttachmentURLSigner,
+	events EventPublisher,
 ) NewsUseCase {
 	return &newsUseCase{
 		newsRepo,
 		mediaRepo,
-		audioFileRepo,
-		imageFileRepo,
-		videoFileRepo,
+		blobStore,
+		attachmentRepo,
+		thumbnailer,
+		metadataExtractor,
+		waveformGenerator,
+		blobRepo,
+		urls,
+		events,
 	}
 }
 
@@ -78,6 +102,11 @@ func (u *newsUseCase) CreateNews(ctx context.Context, p dto.CreateNewsParams) (n
 	}
 
 	err = r.Commit(ctx)
+	if err != nil {
+		return
+	}
+
+	u.events.Publish(ctx, entity.NewsCreatedEvent{News: n, MediaID: p.MediaID})
 	return
 }
 
@@ -108,16 +137,16 @@ func (u *newsUseCase) CreateOrUpdateAudio(ctx context.Context, p dto.CreateOrUpd
 		}
 	}
 
-	data, err := io.ReadAll(p.File)
+	a, err := u.storeAttachment(ctx, p.NewsID, entity.AttachmentKindAudio, p.File)
 	if err != nil {
 		return
 	}
 
-	err = u.audioFileRepo.Store(ctx, fmt.Sprintf("%d.wav", p.NewsID), data)
+	err = u.storeWaveform(ctx, a)
 	return
 }
 
-func (u *newsUseCase) GetAudio(ctx context.Context, p dto.GetAudioParams) (data []byte, err error) {
+func (u *newsUseCase) GetAudio(ctx context.Context, p dto.GetAudioParams) (res dto.GetAttachmentResult, err error) {
 	defer func() {
 		if err != nil {
 			var appErr *dto.AppError
@@ -127,15 +156,49 @@ func (u *newsUseCase) GetAudio(ctx context.Context, p dto.GetAudioParams) (data
 		}
 	}()
 
+	expiresAt, err := u.urls.Verify(entity.AttachmentKindAudio, attachmentPath(p.NewsID, entity.AttachmentKindAudio), p.AttachmentSignature)
+	if err != nil {
+		return
+	}
+
 	n, err := u.newsRepo().GetNews(ctx, p.NewsID)
 	if err != nil {
 		return
 	}
 
-	data, err = u.audioFileRepo.Get(ctx, fmt.Sprintf("%d.wav", n.ID))
+	res, err = u.openAttachment(ctx, n.ID, entity.AttachmentKindAudio)
+	res.ExpiresAt = expiresAt
 	return
 }
 
+func (u *newsUseCase) GetAudioWaveform(ctx context.Context, p dto.GetAudioWaveformParams) (w entity.Waveform, err error) {
+	defer func() {
+		if err != nil {
+			var appErr *dto.AppError
+			if !errors.As(err, &appErr) {
+				err = fmt.Errorf("NewsUseCase - GetAudioWaveform: %w", err)
+			}
+		}
+	}()
+
+	_, err = u.urls.Verify(signedKindWaveform, attachmentPath(p.NewsID, "audio/waveform"), p.AttachmentSignature)
+	if err != nil {
+		return
+	}
+
+	n, err := u.newsRepo().GetNews(ctx, p.NewsID)
+	if err != nil {
+		return
+	}
+
+	w, err = u.attachmentRepo.GetAttachmentWaveform(ctx, n.ID, entity.AttachmentKindAudio)
+	if err != nil {
+		return
+	}
+
+	return selectWaveformResolution(w, p)
+}
+
 func (u *newsUseCase) GetNews(ctx context.Context, p dto.GetNewsParams) (n entity.NewsListItem, err error) {
 	defer func() {
 		if err != nil {
@@ -147,6 +210,38 @@ func (u *newsUseCase) GetNews(ctx context.Context, p dto.GetNewsParams) (n entit
 	}()
 
 	n, err = u.newsRepo().GetNews(ctx, p.NewsID)
+	if err != nil {
+		return
+	}
+
+	u.urls.SignAttachments(n.Attachments)
+	return
+}
+
+func (u *newsUseCase) SearchNews(ctx context.Context, p dto.SearchNewsParams) (res dto.SearchNewsResult, err error) {
+	defer func() {
+		if err != nil {
+			var appErr *dto.AppError
+			if !errors.As(err, &appErr) {
+				err = fmt.Errorf("NewsUseCase - SearchNews: %w", err)
+			}
+		}
+	}()
+
+	err = p.Validate()
+	if err != nil {
+		return
+	}
+
+	r := u.newsRepo()
+
+	res.Items, err = r.SearchNews(ctx, p)
+	if err != nil {
+		return
+	}
+	u.urls.SignNewsList(res.Items)
+
+	res.Total, err = r.CountSearchNews(ctx, p.Query.String)
 	return
 }
 
@@ -177,15 +272,21 @@ func (u *newsUseCase) CreateOrUpdateImage(ctx context.Context, p dto.CreateOrUpd
 		}
 	}
 
-	data, err := io.ReadAll(p.File)
+	data, thumbnails, err := u.readImageUpload(p.File)
 	if err != nil {
 		return
 	}
 
-	return u.imageFileRepo.Store(ctx, fmt.Sprintf("%d.png", p.NewsID), data)
+	a, err := u.storeAttachment(ctx, p.NewsID, entity.AttachmentKindImage, bytes.NewReader(data))
+	if err != nil {
+		return
+	}
+
+	err = u.storeImageVariants(ctx, a, thumbnails)
+	return
 }
 
-func (u *newsUseCase) GetImage(ctx context.Context, p dto.GetImageParams) (data []byte, err error) {
+func (u *newsUseCase) GetImage(ctx context.Context, p dto.GetImageParams) (res dto.GetAttachmentResult, err error) {
 	defer func() {
 		if err != nil {
 			var appErr *dto.AppError
@@ -195,12 +296,50 @@ func (u *newsUseCase) GetImage(ctx context.Context, p dto.GetImageParams) (data
 		}
 	}()
 
+	err = p.Validate()
+	if err != nil {
+		return
+	}
+
+	expiresAt, err := u.urls.Verify(entity.AttachmentKindImage, attachmentPath(p.NewsID, entity.AttachmentKindImage), p.AttachmentSignature)
+	if err != nil {
+		return
+	}
+
 	n, err := u.newsRepo().GetNews(ctx, p.NewsID)
 	if err != nil {
 		return
 	}
 
-	return u.imageFileRepo.Get(ctx, fmt.Sprintf("%d.png", n.ID))
+	res, err = u.openImage(ctx, n.ID, p)
+	res.ExpiresAt = expiresAt
+	return
+}
+
+func (u *newsUseCase) openImage(ctx context.Context, newsID int64, p dto.GetImageParams) (res dto.GetAttachmentResult, err error) {
+	if !p.Width.Valid && !p.Format.Valid {
+		return u.openAttachment(ctx, newsID, entity.AttachmentKindImage)
+	}
+
+	a, err := u.attachmentRepo.GetAttachment(ctx, newsID, entity.AttachmentKindImage)
+	var appErr *dto.AppError
+	if errors.As(err, &appErr) && appErr.Code == dto.ErrCodeNotFound {
+		return u.openAttachment(ctx, newsID, entity.AttachmentKindImage)
+	} else if err != nil {
+		return
+	}
+
+	variants, err := u.attachmentRepo.GetAttachmentVariants(ctx, a.ID)
+	if err != nil {
+		return
+	}
+
+	v, ok := selectImageVariant(variants, p)
+	if !ok {
+		return u.openAttachmentBlob(ctx, a, a.StorageKey, a.ContentType, a.ContentHash, a.UpdatedAt)
+	}
+
+	return u.openAttachmentBlob(ctx, a, v.StorageKey, v.ContentType, v.ContentHash, v.UpdatedAt)
 }
 
 func (u *newsUseCase) ToggleFavorite(
@@ -234,6 +373,7 @@ func (u *newsUseCase) ToggleFavorite(
 
 	res.IsFavorite = !isFavorite
 
+	u.events.Publish(ctx, entity.FavoriteToggledEvent{NewsID: p.NewsID, UserID: p.UserID, IsFavorite: res.IsFavorite})
 	return
 }
 
@@ -256,6 +396,7 @@ func (u *newsUseCase) GetFavoriteList(
 	if err != nil {
 		return
 	}
+	u.urls.SignNewsList(res.Items)
 
 	res.Total, err = r.CountFavorites(ctx, p.UserID)
 	return
@@ -288,15 +429,11 @@ func (u *newsUseCase) CreateOrUpdateVideo(ctx context.Context, p dto.CreateOrUpd
 		}
 	}
 
-	data, err := io.ReadAll(p.File)
-	if err != nil {
-		return
-	}
-
-	return u.videoFileRepo.Store(ctx, fmt.Sprintf("%d.mp4", p.NewsID), data)
+	_, err = u.storeAttachment(ctx, p.NewsID, entity.AttachmentKindVideo, p.File)
+	return
 }
 
-func (u *newsUseCase) GetVideo(ctx context.Context, p dto.GetVideoParams) (data []byte, err error) {
+func (u *newsUseCase) GetVideo(ctx context.Context, p dto.GetVideoParams) (res dto.GetAttachmentResult, err error) {
 	defer func() {
 		if err != nil {
 			var appErr *dto.AppError
@@ -306,10 +443,17 @@ func (u *newsUseCase) GetVideo(ctx context.Context, p dto.GetVideoParams) (data
 		}
 	}()
 
+	expiresAt, err := u.urls.Verify(entity.AttachmentKindVideo, attachmentPath(p.NewsID, entity.AttachmentKindVideo), p.AttachmentSignature)
+	if err != nil {
+		return
+	}
+
 	n, err := u.newsRepo().GetNews(ctx, p.NewsID)
 	if err != nil {
 		return
 	}
 
-	return u.videoFileRepo.Get(ctx, fmt.Sprintf("%d.mp4", n.ID))
+	res, err = u.openAttachment(ctx, n.ID, entity.AttachmentKindVideo)
+	res.ExpiresAt = expiresAt
+	return
 }
@@ -0,0 +1,235 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"gopkg.in/guregu/null.v3"
+	"news-app-api/internal/adapter"
+	"news-app-api/internal/dto"
+	"news-app-api/internal/entity"
+	"unicode/utf8"
+)
+
+const notificationBodyRunes = 280
+
+type (
+	NotificationUseCase interface {
+		EventHandler
+		GetNotificationList(ctx context.Context, p dto.GetNotificationListParams) (dto.GetNotificationListResult, error)
+		GetUnreadCount(ctx context.Context, userID int64) (dto.GetUnreadNotificationCountResult, error)
+		MarkRead(ctx context.Context, p dto.MarkNotificationReadParams) error
+		MarkAllRead(ctx context.Context, userID int64) error
+		GetPreferenceList(ctx context.Context, userID int64) ([]entity.NotificationPreference, error)
+		UpdatePreferenceList(
+			ctx context.Context,
+			p dto.UpdateNotificationPreferenceListParams,
+		) ([]entity.NotificationPreference, error)
+	}
+
+	notificationUseCase struct {
+		notificationRepo adapter.NotificationRepository
+		mediaRepo        adapter.MediaRepository
+	}
+)
+
+var notificationTypeDefaults = map[string]bool{
+	entity.NotificationTypeNews:         false,
+	entity.NotificationTypeBreakingNews: true,
+}
+
+func NewNotificationUseCase(
+	notificationRepo adapter.NotificationRepository,
+	mediaRepo adapter.MediaRepository,
+) NotificationUseCase {
+	return &notificationUseCase{notificationRepo, mediaRepo}
+}
+
+func (u *notificationUseCase) HandleEvent(ctx context.Context, e entity.Event) (err error) {
+	defer func() {
+		if err != nil {
+			var appErr *dto.AppError
+			if !errors.As(err, &appErr) {
+				err = fmt.Errorf("NotificationUseCase - HandleEvent: %w", err)
+			}
+		}
+	}()
+
+	switch e := e.(type) {
+	case entity.NewsCreatedEvent:
+		return u.notifyNewsCreated(ctx, e)
+	case entity.SubscriptionToggledEvent:
+		if !e.IsSubscribed {
+			return u.notificationRepo.DeleteUnreadMediaNotifications(ctx, e.UserID, e.MediaID)
+		}
+	}
+
+	return
+}
+
+func (u *notificationUseCase) notifyNewsCreated(ctx context.Context, e entity.NewsCreatedEvent) error {
+	m, err := u.mediaRepo.GetMediaByID(ctx, e.MediaID)
+	if err != nil {
+		return err
+	}
+
+	t := entity.NotificationTypeNews
+	if e.News.IsBreaking {
+		t = entity.NotificationTypeBreakingNews
+	}
+
+	n := entity.Notification{
+		Type:   t,
+		NewsID: null.IntFrom(e.News.ID),
+		Title:  truncateRunes(fmt.Sprintf("%s: %s", m.Name, e.News.Title), 255),
+		Body:   truncateRunes(e.News.Text, notificationBodyRunes),
+	}
+
+	return u.notificationRepo.CreateMediaSubscriberNotifications(ctx, m.ID, n, notificationTypeDefaults[t])
+}
+
+func (u *notificationUseCase) GetNotificationList(
+	ctx context.Context,
+	p dto.GetNotificationListParams,
+) (res dto.GetNotificationListResult, err error) {
+	defer func() {
+		if err != nil {
+			var appErr *dto.AppError
+			if !errors.As(err, &appErr) {
+				err = fmt.Errorf("NotificationUseCase - GetNotificationList: %w", err)
+			}
+		}
+	}()
+
+	res.Items, err = u.notificationRepo.GetNotificationList(ctx, p)
+	if err != nil {
+		return
+	}
+
+	res.Total, err = u.notificationRepo.CountNotifications(ctx, p.UserID, p.UnreadOnly.Bool)
+	return
+}
+
+func (u *notificationUseCase) GetUnreadCount(
+	ctx context.Context,
+	userID int64,
+) (res dto.GetUnreadNotificationCountResult, err error) {
+	defer func() {
+		if err != nil {
+			var appErr *dto.AppError
+			if !errors.As(err, &appErr) {
+				err = fmt.Errorf("NotificationUseCase - GetUnreadCount: %w", err)
+			}
+		}
+	}()
+
+	res.Count, err = u.notificationRepo.CountNotifications(ctx, userID, true)
+	return
+}
+
+func (u *notificationUseCase) MarkRead(ctx context.Context, p dto.MarkNotificationReadParams) (err error) {
+	defer func() {
+		if err != nil {
+			var appErr *dto.AppError
+			if !errors.As(err, &appErr) {
+				err = fmt.Errorf("NotificationUseCase - MarkRead: %w", err)
+			}
+		}
+	}()
+
+	isExists, err := u.notificationRepo.IsNotificationExists(ctx, p.NotificationID, p.UserID)
+	if err != nil {
+		return
+	}
+
+	if !isExists {
+		return &dto.AppError{
+			Message: "Уведомление не найдено",
+			Code:    dto.ErrCodeNotFound,
+		}
+	}
+
+	return u.notificationRepo.MarkNotificationRead(ctx, p.NotificationID, p.UserID)
+}
+
+func (u *notificationUseCase) MarkAllRead(ctx context.Context, userID int64) (err error) {
+	defer func() {
+		if err != nil {
+			var appErr *dto.AppError
+			if !errors.As(err, &appErr) {
+				err = fmt.Errorf("NotificationUseCase - MarkAllRead: %w", err)
+			}
+		}
+	}()
+	return u.notificationRepo.MarkAllNotificationsRead(ctx, userID)
+}
+
+func (u *notificationUseCase) GetPreferenceList(
+	ctx context.Context,
+	userID int64,
+) (list []entity.NotificationPreference, err error) {
+	defer func() {
+		if err != nil {
+			var appErr *dto.AppError
+			if !errors.As(err, &appErr) {
+				err = fmt.Errorf("NotificationUseCase - GetPreferenceList: %w", err)
+			}
+		}
+	}()
+
+	stored, err := u.notificationRepo.GetNotificationPreferenceList(ctx, userID)
+	if err != nil {
+		return
+	}
+
+	enabled := make(map[string]bool, len(stored))
+	for _, p := range stored {
+		enabled[p.Type] = p.Enabled
+	}
+
+	list = make([]entity.NotificationPreference, 0, len(dto.NotificationTypes))
+	for _, t := range dto.NotificationTypes {
+		v, ok := enabled[t]
+		if !ok {
+			v = notificationTypeDefaults[t]
+		}
+		list = append(list, entity.NotificationPreference{Type: t, Enabled: v})
+	}
+
+	return
+}
+
+func (u *notificationUseCase) UpdatePreferenceList(
+	ctx context.Context,
+	p dto.UpdateNotificationPreferenceListParams,
+) (list []entity.NotificationPreference, err error) {
+	defer func() {
+		if err != nil {
+			var appErr *dto.AppError
+			if !errors.As(err, &appErr) {
+				err = fmt.Errorf("NotificationUseCase - UpdatePreferenceList: %w", err)
+			}
+		}
+	}()
+
+	err = p.Validate()
+	if err != nil {
+		return
+	}
+
+	for _, item := range p.Items {
+		err = u.notificationRepo.UpsertNotificationPreference(ctx, p.UserID, item)
+		if err != nil {
+			return
+		}
+	}
+
+	return u.GetPreferenceList(ctx, p.UserID)
+}
+
+func truncateRunes(s string, n int) string {
+	if utf8.RuneCountInString(s) <= n {
+		return s
+	}
+	return string([]rune(s)[:n-1]) + "…"
+}
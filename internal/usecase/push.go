@@ -0,0 +1,189 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	log "github.com/sirupsen/logrus"
+	"net/http"
+	"news-app-api/internal/adapter"
+	"news-app-api/internal/dto"
+	"news-app-api/internal/entity"
+	"strings"
+	"time"
+)
+
+const (
+	pushMessageTTL  = 24 * time.Hour
+	pushSendTimeout = 30 * time.Second
+	pushBodyRunes   = 140
+)
+
+var pushQueue = deliveryQueue{
+	batchSize:    100,
+	concurrency:  8,
+	claimLease:   2 * time.Minute,
+	maxAttempts:  5,
+	retryBackoff: 10 * time.Second,
+}
+
+type (
+	PushUseCase interface {
+		EventHandler
+		GetVAPIDPublicKey() dto.GetVAPIDPublicKeyResult
+		RegisterSubscription(ctx context.Context, p dto.RegisterPushSubscriptionParams) (entity.PushSubscription, error)
+		UnregisterSubscription(ctx context.Context, p dto.UnregisterPushSubscriptionParams) error
+		DeliverDuePushes(ctx context.Context) error
+	}
+
+	PushConfig struct {
+		PublicURL            string
+		AllowPrivateNetworks bool
+	}
+
+	pushUseCase struct {
+		pushRepo  adapter.PushSubscriptionRepository
+		mediaRepo adapter.MediaRepository
+		sender    adapter.PushSender
+		cfg       PushConfig
+	}
+
+	pushPayload struct {
+		Type   string `json:"type"`
+		NewsID int64  `json:"newsId"`
+		Title  string `json:"title"`
+		Body   string `json:"body"`
+		URL    string `json:"url"`
+	}
+)
+
+func NewPushUseCase(
+	pushRepo adapter.PushSubscriptionRepository,
+	mediaRepo adapter.MediaRepository,
+	sender adapter.PushSender,
+	cfg PushConfig,
+) PushUseCase {
+	cfg.PublicURL = strings.TrimRight(cfg.PublicURL, "/")
+	return &pushUseCase{pushRepo, mediaRepo, sender, cfg}
+}
+
+func (u *pushUseCase) GetVAPIDPublicKey() dto.GetVAPIDPublicKeyResult {
+	return dto.GetVAPIDPublicKeyResult{PublicKey: u.sender.PublicKey()}
+}
+
+func (u *pushUseCase) RegisterSubscription(
+	ctx context.Context,
+	p dto.RegisterPushSubscriptionParams,
+) (s entity.PushSubscription, err error) {
+	defer func() {
+		if err != nil {
+			var appErr *dto.AppError
+			if !errors.As(err, &appErr) {
+				err = fmt.Errorf("PushUseCase - RegisterSubscription: %w", err)
+			}
+		}
+	}()
+
+	p.AllowPrivateNetworks = u.cfg.AllowPrivateNetworks
+	err = p.Validate()
+	if err != nil {
+		return
+	}
+
+	return u.pushRepo.UpsertPushSubscription(ctx, p)
+}
+
+func (u *pushUseCase) UnregisterSubscription(ctx context.Context, p dto.UnregisterPushSubscriptionParams) (err error) {
+	defer func() {
+		if err != nil {
+			var appErr *dto.AppError
+			if !errors.As(err, &appErr) {
+				err = fmt.Errorf("PushUseCase - UnregisterSubscription: %w", err)
+			}
+		}
+	}()
+	return u.pushRepo.DeletePushSubscription(ctx, p.UserID, p.Endpoint)
+}
+
+func (u *pushUseCase) HandleEvent(ctx context.Context, e entity.Event) (err error) {
+	defer func() {
+		if err != nil {
+			var appErr *dto.AppError
+			if !errors.As(err, &appErr) {
+				err = fmt.Errorf("PushUseCase - HandleEvent: %w", err)
+			}
+		}
+	}()
+
+	created, ok := e.(entity.NewsCreatedEvent)
+	if !ok || !created.News.IsBreaking {
+		return
+	}
+
+	m, err := u.mediaRepo.GetMediaByID(ctx, created.MediaID)
+	if err != nil {
+		return
+	}
+
+	payload, err := json.Marshal(pushPayload{
+		Type:   entity.NotificationTypeBreakingNews,
+		NewsID: created.News.ID,
+		Title:  m.Name,
+		Body:   truncateRunes(created.News.Title, pushBodyRunes),
+		URL:    fmt.Sprintf("%s/api/news/%d", u.cfg.PublicURL, created.News.ID),
+	})
+	if err != nil {
+		return
+	}
+
+	return u.pushRepo.EnqueuePushDeliveries(ctx, m.ID, entity.NotificationTypeBreakingNews, string(payload))
+}
+
+func (u *pushUseCase) DeliverDuePushes(ctx context.Context) (err error) {
+	defer func() {
+		if err != nil {
+			var appErr *dto.AppError
+			if !errors.As(err, &appErr) {
+				err = fmt.Errorf("PushUseCase - DeliverDuePushes: %w", err)
+			}
+		}
+	}()
+
+	return deliverDue(ctx, pushQueue, u.pushRepo.ClaimDuePushDeliveries, u.deliver)
+}
+
+func (u *pushUseCase) deliver(ctx context.Context, job entity.PushDeliveryJob) (err error) {
+	defer func() {
+		if err != nil {
+			err = fmt.Errorf("delivery %d: %w", job.DeliveryID, err)
+		}
+	}()
+
+	sendCtx, cancel := context.WithTimeout(ctx, pushSendTimeout)
+	defer cancel()
+
+	status, sendErr := u.sender.Send(sendCtx, job.Subscription, []byte(job.Payload), pushMessageTTL)
+	if ctx.Err() != nil {
+		return nil
+	}
+
+	entry := log.WithField("endpoint", job.Subscription.Endpoint)
+	switch {
+	case sendErr == nil && status >= 200 && status < 300:
+		return u.pushRepo.DeletePushDelivery(ctx, job.DeliveryID)
+	case sendErr == nil && (status == http.StatusGone || status == http.StatusNotFound):
+		return u.pushRepo.DeletePushSubscriptionByEndpoint(ctx, job.Subscription.Endpoint)
+	case sendErr == nil && status != http.StatusTooManyRequests && status < 500:
+		entry.WithField("status", status).Warn("Push service rejected message")
+		return u.pushRepo.DeletePushDelivery(ctx, job.DeliveryID)
+	}
+
+	next, ok := pushQueue.retryAt(time.Now(), job.Attempts)
+	if !ok {
+		entry.WithField("status", status).WithError(sendErr).Warn("Push delivery failed")
+		return u.pushRepo.DeletePushDelivery(ctx, job.DeliveryID)
+	}
+
+	return u.pushRepo.RetryPushDelivery(ctx, job.DeliveryID, next.Unix())
+}
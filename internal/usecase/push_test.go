@@ -0,0 +1,148 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"news-app-api/internal/dto"
+	"news-app-api/internal/entity"
+	"sync"
+	"testing"
+	"time"
+)
+
+type fakePushRepository struct {
+	mu               sync.Mutex
+	jobs             []entity.PushDeliveryJob
+	deleted          []int64
+	retried          map[int64]int64
+	deletedEndpoints []string
+}
+
+func (r *fakePushRepository) UpsertPushSubscription(
+	_ context.Context,
+	p dto.RegisterPushSubscriptionParams,
+) (entity.PushSubscription, error) {
+	return entity.PushSubscription{UserID: p.UserID, Endpoint: p.Endpoint}, nil
+}
+
+func (r *fakePushRepository) DeletePushSubscription(context.Context, int64, string) error {
+	return nil
+}
+
+func (r *fakePushRepository) DeletePushSubscriptionByEndpoint(_ context.Context, endpoint string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.deletedEndpoints = append(r.deletedEndpoints, endpoint)
+	return nil
+}
+
+func (r *fakePushRepository) EnqueuePushDeliveries(context.Context, int64, string, string) error {
+	return nil
+}
+
+func (r *fakePushRepository) ClaimDuePushDeliveries(
+	_ context.Context,
+	limit int64,
+	_ time.Duration,
+) ([]entity.PushDeliveryJob, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	n := int(limit)
+	if n > len(r.jobs) {
+		n = len(r.jobs)
+	}
+	jobs := r.jobs[:n]
+	r.jobs = r.jobs[n:]
+	return jobs, nil
+}
+
+func (r *fakePushRepository) RetryPushDelivery(_ context.Context, deliveryID int64, nextAttemptAt int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.retried[deliveryID] = nextAttemptAt
+	return nil
+}
+
+func (r *fakePushRepository) DeletePushDelivery(_ context.Context, deliveryID int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.deleted = append(r.deleted, deliveryID)
+	return nil
+}
+
+type fakePushSender struct {
+	status map[string]int
+}
+
+func (s *fakePushSender) Send(_ context.Context, sub entity.PushSubscription, _ []byte, _ time.Duration) (int, error) {
+	status, ok := s.status[sub.Endpoint]
+	if !ok {
+		return 0, errors.New("connection refused")
+	}
+	return status, nil
+}
+
+func (s *fakePushSender) PublicKey() string {
+	return ""
+}
+
+func TestPushUseCaseDeliverDuePushes(t *testing.T) {
+	job := func(id int64, endpoint string, attempts int) entity.PushDeliveryJob {
+		return entity.PushDeliveryJob{
+			DeliveryID:   id,
+			Payload:      "{}",
+			Attempts:     attempts,
+			Subscription: entity.PushSubscription{Endpoint: endpoint},
+		}
+	}
+
+	repo := &fakePushRepository{
+		jobs: []entity.PushDeliveryJob{
+			job(1, "https://push.example/ok", 0),
+			job(2, "https://push.example/gone", 0),
+			job(3, "https://push.example/bad-request", 0),
+			job(4, "https://push.example/unavailable", 1),
+			job(5, "https://push.example/down", pushQueue.maxAttempts-1),
+		},
+		retried: map[int64]int64{},
+	}
+	sender := &fakePushSender{status: map[string]int{
+		"https://push.example/ok":          http.StatusCreated,
+		"https://push.example/gone":        http.StatusGone,
+		"https://push.example/bad-request": http.StatusBadRequest,
+		"https://push.example/unavailable": http.StatusServiceUnavailable,
+	}}
+	uc := NewPushUseCase(repo, nil, sender, PushConfig{})
+
+	start := time.Now()
+	err := uc.DeliverDuePushes(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	deleted := map[int64]bool{}
+	for _, id := range repo.deleted {
+		deleted[id] = true
+	}
+	for _, id := range []int64{1, 3, 5} {
+		if !deleted[id] {
+			t.Errorf("delivery %d was not deleted", id)
+		}
+	}
+	if len(repo.deleted) != 3 {
+		t.Errorf("deleted = %v, want deliveries 1, 3 and 5", repo.deleted)
+	}
+
+	if len(repo.deletedEndpoints) != 1 || repo.deletedEndpoints[0] != "https://push.example/gone" {
+		t.Errorf("deleted subscriptions = %v, want the gone endpoint", repo.deletedEndpoints)
+	}
+
+	next, ok := repo.retried[4]
+	if len(repo.retried) != 1 || !ok {
+		t.Fatalf("retried = %v, want delivery 4", repo.retried)
+	}
+	if want := start.Add(pushQueue.retryBackoff << 1).Unix(); next < want || next > want+1 {
+		t.Errorf("next attempt = %d, want %d", next, want)
+	}
+}
@@ -0,0 +1,146 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	log "github.com/sirupsen/logrus"
+	"news-app-api/internal/adapter"
+	"news-app-api/internal/dto"
+	"news-app-api/internal/entity"
+	"sort"
+	"time"
+)
+
+var storagePrefixes = []string{"audio", "image", "video", "gallery", "blobs", "staging", "tracks", "uploads"}
+
+type (
+	StorageUseCase interface {
+		CheckStorage(ctx context.Context, p dto.CheckStorageParams) (dto.CheckStorageResult, error)
+		CheckStorageJob(ctx context.Context) error
+	}
+
+	StorageConfig struct {
+		OrphanGracePeriod time.Duration
+		DeleteOrphans     bool
+	}
+
+	storageUseCase struct {
+		storageRepo adapter.StorageRepository
+		blobStore   adapter.BlobStore
+		cfg         StorageConfig
+	}
+)
+
+func NewStorageUseCase(
+	storageRepo adapter.StorageRepository,
+	blobStore adapter.BlobStore,
+	cfg StorageConfig,
+) StorageUseCase {
+	return &storageUseCase{storageRepo, blobStore, cfg}
+}
+
+func (u *storageUseCase) CheckStorage(
+	ctx context.Context,
+	p dto.CheckStorageParams,
+) (res dto.CheckStorageResult, err error) {
+	defer func() {
+		if err != nil {
+			var appErr *dto.AppError
+			if !errors.As(err, &appErr) {
+				err = fmt.Errorf("StorageUseCase - CheckStorage: %w", err)
+			}
+		}
+	}()
+
+	refs, err := u.storageRepo.GetStorageReferences(ctx)
+	if err != nil {
+		return
+	}
+
+	expected := make(map[string]entity.StorageReference, len(refs))
+	for _, ref := range refs {
+		expected[ref.Key] = ref
+	}
+	res.References = int64(len(expected))
+
+	cutoff := time.Now().Add(-p.GracePeriod)
+	seen := make(map[string]bool, len(expected))
+	for _, prefix := range storagePrefixes {
+		err = u.blobStore.List(ctx, prefix, func(f entity.StoredFile) error {
+			res.Files++
+			res.Bytes += f.Size
+
+			ref, ok := expected[f.Key]
+			if ok {
+				seen[f.Key] = true
+				if ref.Size.Valid && ref.Size.Int64 != f.Size {
+					res.SizeMismatches = append(res.SizeMismatches, entity.StorageSizeMismatch{
+						Key:      f.Key,
+						Expected: ref.Size.Int64,
+						Actual:   f.Size,
+					})
+				}
+				return nil
+			}
+
+			res.Orphans = append(res.Orphans, f)
+			res.OrphanBytes += f.Size
+			if !p.DeleteOrphans || !f.ModTime.Before(cutoff) {
+				return nil
+			}
+
+			err := u.blobStore.Delete(ctx, f.Key)
+			if err != nil {
+				return err
+			}
+			res.DeletedOrphans++
+			return nil
+		})
+		if err != nil {
+			return
+		}
+	}
+
+	for key, ref := range expected {
+		if !seen[key] && !ref.Optional {
+			res.Missing = append(res.Missing, key)
+		}
+	}
+	sort.Strings(res.Missing)
+
+	res.UnreferencedBlobs, err = u.storageRepo.CountUnreferencedBlobs(ctx, cutoff.Unix())
+	return
+}
+
+func (u *storageUseCase) CheckStorageJob(ctx context.Context) (err error) {
+	defer func() {
+		if err != nil {
+			err = fmt.Errorf("StorageUseCase - CheckStorageJob: %w", err)
+		}
+	}()
+
+	res, err := u.CheckStorage(ctx, dto.CheckStorageParams{
+		DeleteOrphans: u.cfg.DeleteOrphans,
+		GracePeriod:   u.cfg.OrphanGracePeriod,
+	})
+	if err != nil {
+		return
+	}
+
+	entry := log.WithFields(log.Fields{
+		"files":             res.Files,
+		"orphans":           len(res.Orphans),
+		"orphanBytes":       res.OrphanBytes,
+		"deletedOrphans":    res.DeletedOrphans,
+		"missing":           len(res.Missing),
+		"sizeMismatches":    len(res.SizeMismatches),
+		"unreferencedBlobs": res.UnreferencedBlobs,
+	})
+	if len(res.Missing) > 0 || len(res.SizeMismatches) > 0 {
+		entry.Warn("storage is inconsistent with the database")
+	} else {
+		entry.Info("storage check finished")
+	}
+	return
+}
@@ -0,0 +1,174 @@
+package usecase
+
+import (
+	"bytes"
+	"fmt"
+	"news-app-api/internal/dto"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+var (
+	vttTimingPattern = regexp.MustCompile(
+		`^((?:\d{2,}:)?[0-5]\d:[0-5]\d\.\d{3})[ \t]+-->[ \t]+((?:\d{2,}:)?[0-5]\d:[0-5]\d\.\d{3})(?:[ \t].*)?$`,
+	)
+	srtTimingPattern = regexp.MustCompile(
+		`^(\d{1,}:[0-5]\d:[0-5]\d[,.]\d{1,3})[ \t]+-->[ \t]+(\d{1,}:[0-5]\d:[0-5]\d[,.]\d{1,3})(?:[ \t].*)?$`,
+	)
+	srtFontTagPattern = regexp.MustCompile(`(?i)</?font[^>]*>`)
+	srtAssTagPattern  = regexp.MustCompile(`\{\\[^}]*\}`)
+	subtitleBlockGap  = regexp.MustCompile(`\n[ \t]*\n`)
+)
+
+func normalizeTrackText(data []byte) (string, error) {
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+	if !utf8.Valid(data) {
+		return "", &dto.AppError{
+			Message: "Файл должен быть в кодировке UTF-8",
+			Code:    dto.ErrCodeBadRequest,
+		}
+	}
+
+	text := strings.ReplaceAll(string(data), "\r\n", "\n")
+	text = strings.ReplaceAll(text, "\r", "\n")
+	return strings.ReplaceAll(text, "\x00", "�"), nil
+}
+
+func normalizeSubtitles(data []byte) (string, error) {
+	text, err := normalizeTrackText(data)
+	if err != nil {
+		return "", err
+	}
+
+	if isWebVTT(text) {
+		err = validateWebVTT(text)
+		if err != nil {
+			return "", err
+		}
+		return strings.TrimRight(text, "\n") + "\n", nil
+	}
+
+	return convertSRT(text)
+}
+
+func isWebVTT(text string) bool {
+	if !strings.HasPrefix(text, "WEBVTT") {
+		return false
+	}
+	rest := text[len("WEBVTT"):]
+	return rest == "" || rest[0] == ' ' || rest[0] == '\t' || rest[0] == '\n'
+}
+
+func validateWebVTT(text string) error {
+	cues := 0
+	for _, block := range splitSubtitleBlocks(text)[1:] {
+		lines := strings.Split(block, "\n")
+		if fields := strings.Fields(lines[0]); len(fields) > 0 {
+			switch fields[0] {
+			case "NOTE":
+				continue
+			case "STYLE", "REGION":
+				if cues == 0 {
+					continue
+				}
+			}
+		}
+
+		timing := lines[0]
+		if !strings.Contains(timing, "-->") && len(lines) > 1 {
+			timing = lines[1]
+		}
+
+		m := vttTimingPattern.FindStringSubmatch(timing)
+		if m == nil || parseSubtitleTimestamp(m[1]) > parseSubtitleTimestamp(m[2]) {
+			return invalidSubtitlesError(cues + 1)
+		}
+		cues++
+	}
+
+	if cues == 0 {
+		return errSubtitlesHaveNoCues()
+	}
+	return nil
+}
+
+func convertSRT(text string) (string, error) {
+	var b strings.Builder
+	b.WriteString("WEBVTT\n")
+
+	cues := 0
+	for _, block := range splitSubtitleBlocks(text) {
+		lines := strings.Split(block, "\n")
+		if len(lines) > 1 && !strings.Contains(lines[0], "-->") {
+			lines = lines[1:]
+		}
+
+		m := srtTimingPattern.FindStringSubmatch(strings.TrimSpace(lines[0]))
+		if m == nil {
+			return "", invalidSubtitlesError(cues + 1)
+		}
+
+		start, end := parseSubtitleTimestamp(m[1]), parseSubtitleTimestamp(m[2])
+		if start > end {
+			return "", invalidSubtitlesError(cues + 1)
+		}
+		cues++
+
+		fmt.Fprintf(&b, "\n%d\n%s --> %s\n", cues, formatVTTTimestamp(start), formatVTTTimestamp(end))
+		for _, line := range lines[1:] {
+			line = srtAssTagPattern.ReplaceAllString(srtFontTagPattern.ReplaceAllString(line, ""), "")
+			line = strings.ReplaceAll(line, "-->", "->")
+			if strings.TrimSpace(line) != "" {
+				b.WriteString(line)
+				b.WriteByte('\n')
+			}
+		}
+	}
+
+	if cues == 0 {
+		return "", errSubtitlesHaveNoCues()
+	}
+	return b.String(), nil
+}
+
+func splitSubtitleBlocks(text string) []string {
+	var blocks []string
+	for _, block := range subtitleBlockGap.Split(strings.Trim(text, "\n"), -1) {
+		block = strings.Trim(block, "\n")
+		if strings.TrimSpace(block) != "" {
+			blocks = append(blocks, block)
+		}
+	}
+	return blocks
+}
+
+func parseSubtitleTimestamp(s string) (ms int64) {
+	main, frac, _ := strings.Cut(strings.Replace(s, ",", ".", 1), ".")
+	for _, part := range strings.Split(main, ":") {
+		v, _ := strconv.ParseInt(part, 10, 64)
+		ms = ms*60 + v
+	}
+
+	v, _ := strconv.ParseInt((frac + "000")[:3], 10, 64)
+	return ms*1000 + v
+}
+
+func formatVTTTimestamp(ms int64) string {
+	return fmt.Sprintf("%02d:%02d:%02d.%03d", ms/3600000, ms/60000%60, ms/1000%60, ms%1000)
+}
+
+func invalidSubtitlesError(cue int) error {
+	return &dto.AppError{
+		Message: fmt.Sprintf("Некорректная разметка времени в реплике %d", cue),
+		Code:    dto.ErrCodeBadRequest,
+	}
+}
+
+func errSubtitlesHaveNoCues() error {
+	return &dto.AppError{
+		Message: "Файл субтитров не содержит реплик",
+		Code:    dto.ErrCodeBadRequest,
+	}
+}
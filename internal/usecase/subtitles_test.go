@@ -0,0 +1,222 @@
+package usecase
+
+import (
+	"errors"
+	"news-app-api/internal/dto"
+	"testing"
+)
+
+func TestParseSubtitleTimestamp(t *testing.T) {
+	tests := []struct {
+		in   string
+		want int64
+	}{
+		{"00:00:00,000", 0},
+		{"00:00:01,500", 1500},
+		{"00:00:01.500", 1500},
+		{"01:02:03,004", 3723004},
+		{"00:00:01,5", 1500},
+		{"00:00:01,05", 1050},
+		{"00:00:01.005", 1005},
+		{"1:00:00,000", 3600000},
+		{"123:00:00.001", 442800001},
+		{"02:03.004", 123004},
+		{"59:59.999", 3599999},
+	}
+
+	for _, tt := range tests {
+		if got := parseSubtitleTimestamp(tt.in); got != tt.want {
+			t.Errorf("parseSubtitleTimestamp(%q) = %d, want %d", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestConvertSRT(t *testing.T) {
+	tests := []struct {
+		name    string
+		in      string
+		want    string
+		wantErr string
+	}{
+		{
+			name: "comma and dot milliseconds",
+			in: "1\n00:00:01,000 --> 00:00:02,500\nПервая\n\n" +
+				"2\n00:00:03.000 --> 00:00:04.250\nВторая\n",
+			want: "WEBVTT\n\n1\n00:00:01.000 --> 00:00:02.500\nПервая\n\n" +
+				"2\n00:00:03.000 --> 00:00:04.250\nВторая\n",
+		},
+		{
+			name: "short fractions",
+			in:   "1\n00:00:01,5 --> 00:00:02,25\nТекст\n",
+			want: "WEBVTT\n\n1\n00:00:01.500 --> 00:00:02.250\nТекст\n",
+		},
+		{
+			name: "missing cue index",
+			in:   "00:00:01,000 --> 00:00:02,000\nБез номера\n\n7\n00:00:03,000 --> 00:00:04,000\nС номером\n",
+			want: "WEBVTT\n\n1\n00:00:01.000 --> 00:00:02.000\nБез номера\n\n" +
+				"2\n00:00:03.000 --> 00:00:04.000\nС номером\n",
+		},
+		{
+			name: "tags",
+			in: "1\n00:00:01,000 --> 00:00:02,000\n" +
+				"<font color=\"#ffff00\">Жёлтый</font> и {\\an8}сверху\n<FONT face=Arial>{\\i1}</FONT>\n<i>курсив</i>\n",
+			want: "WEBVTT\n\n1\n00:00:01.000 --> 00:00:02.000\nЖёлтый и сверху\n<i>курсив</i>\n",
+		},
+		{
+			name: "arrow in cue text",
+			in:   "1\n00:00:01,000 --> 00:00:02,000\nТуда --> сюда\n",
+			want: "WEBVTT\n\n1\n00:00:01.000 --> 00:00:02.000\nТуда -> сюда\n",
+		},
+		{
+			name: "cue settings and long hours",
+			in:   "1\n100:00:01,000 --> 100:00:02,000 X1:40 X2:600\nТекст\n",
+			want: "WEBVTT\n\n1\n100:00:01.000 --> 100:00:02.000\nТекст\n",
+		},
+		{
+			name: "extra blank lines",
+			in:   "\n\n1\n00:00:01,000 --> 00:00:02,000\nТекст\n \n\n\n2\n00:00:02,000 --> 00:00:02,000\nЕщё\n\n",
+			want: "WEBVTT\n\n1\n00:00:01.000 --> 00:00:02.000\nТекст\n\n" +
+				"2\n00:00:02.000 --> 00:00:02.000\nЕщё\n",
+		},
+		{
+			name:    "start after end",
+			in:      "1\n00:00:01,000 --> 00:00:02,000\nПервая\n\n2\n00:00:05,000 --> 00:00:04,999\nВторая\n",
+			wantErr: "Некорректная разметка времени в реплике 2",
+		},
+		{
+			name:    "minutes out of range",
+			in:      "1\n00:60:01,000 --> 00:61:02,000\nТекст\n",
+			wantErr: "Некорректная разметка времени в реплике 1",
+		},
+		{
+			name:    "missing timing",
+			in:      "1\nТекст\n",
+			wantErr: "Некорректная разметка времени в реплике 1",
+		},
+		{
+			name:    "no cues",
+			in:      "\n\n \n",
+			wantErr: "Файл субтитров не содержит реплик",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := convertSRT(tt.in)
+			checkSubtitles(t, got, err, tt.want, tt.wantErr)
+		})
+	}
+}
+
+func TestValidateWebVTT(t *testing.T) {
+	tests := []struct {
+		name    string
+		in      string
+		wantErr string
+	}{
+		{
+			name: "cues with and without identifiers",
+			in:   "WEBVTT\n\n00:01.000 --> 00:02.000\nТекст\n\nintro\n00:00:02.000 --> 00:00:03.000 align:start\nЕщё\n",
+		},
+		{
+			name: "header text",
+			in:   "WEBVTT - Новости\nKind: captions\n\n00:01.000 --> 00:02.000\nТекст\n",
+		},
+		{
+			name: "note and style blocks",
+			in: "WEBVTT\n\nSTYLE\n::cue { color: yellow }\n\nNOTE автор субтитров\n\n" +
+				"00:01.000 --> 00:02.000\nТекст\n\nNOTE\nпосле реплики\n",
+		},
+		{
+			name:    "style after a cue",
+			in:      "WEBVTT\n\n00:01.000 --> 00:02.000\nТекст\n\nSTYLE\n::cue { color: red }\n",
+			wantErr: "Некорректная разметка времени в реплике 2",
+		},
+		{
+			name:    "comma milliseconds",
+			in:      "WEBVTT\n\n00:01,000 --> 00:02,000\nТекст\n",
+			wantErr: "Некорректная разметка времени в реплике 1",
+		},
+		{
+			name:    "short fraction",
+			in:      "WEBVTT\n\n00:01.5 --> 00:02.000\nТекст\n",
+			wantErr: "Некорректная разметка времени в реплике 1",
+		},
+		{
+			name:    "start after end",
+			in:      "WEBVTT\n\n00:01.000 --> 00:02.000\nПервая\n\n01:00.000 --> 00:59.999\nВторая\n",
+			wantErr: "Некорректная разметка времени в реплике 2",
+		},
+		{
+			name:    "only notes",
+			in:      "WEBVTT\n\nNOTE пусто\n",
+			wantErr: "Файл субтитров не содержит реплик",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checkSubtitles(t, "", validateWebVTT(tt.in), "", tt.wantErr)
+		})
+	}
+}
+
+func TestNormalizeSubtitles(t *testing.T) {
+	tests := []struct {
+		name    string
+		in      string
+		want    string
+		wantErr string
+	}{
+		{
+			name: "srt with bom and crlf",
+			in:   "\xef\xbb\xbf1\r\n00:00:01,000 --> 00:00:02,000\r\nТекст\r\n\r\n2\r\n00:00:03,000 --> 00:00:04,000\r\nЕщё\r\n",
+			want: "WEBVTT\n\n1\n00:00:01.000 --> 00:00:02.000\nТекст\n\n2\n00:00:03.000 --> 00:00:04.000\nЕщё\n",
+		},
+		{
+			name: "vtt with bom and crlf",
+			in:   "\xef\xbb\xbfWEBVTT\r\n\r\n00:01.000 --> 00:02.000\r\nТекст\r\n\r\n\r\n",
+			want: "WEBVTT\n\n00:01.000 --> 00:02.000\nТекст\n",
+		},
+		{
+			name: "srt with cr",
+			in:   "1\r00:00:01,000 --> 00:00:02,000\rТекст\r",
+			want: "WEBVTT\n\n1\n00:00:01.000 --> 00:00:02.000\nТекст\n",
+		},
+		{
+			name:    "srt starting with webvtt text",
+			in:      "WEBVTTX\n\n1\n00:00:01,000 --> 00:00:02,000\nТекст\n",
+			wantErr: "Некорректная разметка времени в реплике 1",
+		},
+		{
+			name:    "invalid utf-8",
+			in:      "1\n00:00:01,000 --> 00:00:02,000\n\xcf\xf0\xe8\xe2\xe5\xf2\n",
+			wantErr: "Файл должен быть в кодировке UTF-8",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := normalizeSubtitles([]byte(tt.in))
+			checkSubtitles(t, got, err, tt.want, tt.wantErr)
+		})
+	}
+}
+
+func checkSubtitles(t *testing.T, got string, err error, want, wantErr string) {
+	t.Helper()
+
+	if wantErr != "" {
+		var appErr *dto.AppError
+		if !errors.As(err, &appErr) || appErr.Code != dto.ErrCodeBadRequest || appErr.Message != wantErr {
+			t.Fatalf("err = %v, want %q", err, wantErr)
+		}
+		return
+	}
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got != want {
+		t.Errorf("got\n%q\nwant\n%q", got, want)
+	}
+}
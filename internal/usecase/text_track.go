@@ -0,0 +1,306 @@
+package usecase
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"gopkg.in/guregu/null.v3"
+	"io"
+	"news-app-api/internal/adapter"
+	"news-app-api/internal/dto"
+	"news-app-api/internal/entity"
+	"strings"
+)
+
+const (
+	maxSubtitlesUploadSize  = 2 << 20
+	maxTranscriptUploadSize = 512 << 10
+)
+
+type (
+	TextTrackUseCase interface {
+		PutTextTrack(ctx context.Context, p dto.PutTextTrackParams) (entity.TextTrack, error)
+		GetTextTracks(ctx context.Context, p dto.GetTextTracksParams) (dto.GetTextTracksResult, error)
+		GetTextTrack(ctx context.Context, p dto.GetTextTrackParams) (dto.GetAttachmentResult, error)
+		DeleteTextTrack(ctx context.Context, p dto.DeleteTextTrackParams) error
+	}
+
+	textTrackUseCase struct {
+		newsRepo      func() adapter.NewsRepository
+		mediaRepo     adapter.MediaRepository
+		textTrackRepo adapter.TextTrackRepository
+		blobStore     adapter.BlobStore
+		urls          AttachmentURLSigner
+	}
+)
+
+var textSearchConfigs = map[string]string{
+	"da": "danish",
+	"de": "german",
+	"en": "english",
+	"es": "spanish",
+	"fi": "finnish",
+	"fr": "french",
+	"hu": "hungarian",
+	"it": "italian",
+	"nb": "norwegian",
+	"nl": "dutch",
+	"no": "norwegian",
+	"pt": "portuguese",
+	"ro": "romanian",
+	"ru": "russian",
+	"sv": "swedish",
+	"tr": "turkish",
+}
+
+func NewTextTrackUseCase(
+	newsRepo func() adapter.NewsRepository,
+	mediaRepo adapter.MediaRepository,
+	textTrackRepo adapter.TextTrackRepository,
+	blobStore adapter.BlobStore,
+	urls AttachmentURLSigner,
+) TextTrackUseCase {
+	return &textTrackUseCase{
+		newsRepo,
+		mediaRepo,
+		textTrackRepo,
+		blobStore,
+		urls,
+	}
+}
+
+func (u *textTrackUseCase) PutTextTrack(ctx context.Context, p dto.PutTextTrackParams) (t entity.TextTrack, err error) {
+	defer func() {
+		if err != nil {
+			var appErr *dto.AppError
+			if !errors.As(err, &appErr) {
+				err = fmt.Errorf("TextTrackUseCase - PutTextTrack: %w", err)
+			}
+		}
+	}()
+
+	err = p.Validate()
+	if err != nil {
+		return
+	}
+
+	n, err := u.newsRepo().GetNews(ctx, p.NewsID)
+	if err != nil {
+		return
+	}
+
+	m, err := u.mediaRepo.GetMediaByRegistrationNumber(ctx, n.Media.RegistrationNumber)
+	if err != nil {
+		return
+	}
+
+	if m.ID != p.MediaID {
+		return t, &dto.AppError{
+			Code:    dto.ErrCodeUnauthorized,
+			Message: "Недостаточно прав для совершения данной операции",
+		}
+	}
+
+	var (
+		text                     string
+		ext                      string
+		searchConfig, searchText null.String
+	)
+	switch p.Kind {
+	case entity.TextTrackKindSubtitles:
+		var data []byte
+		data, err = io.ReadAll(limitUpload(p.File, maxSubtitlesUploadSize, "Максимальный размер файла субтитров - 2 МБ"))
+		if err != nil {
+			return
+		}
+
+		text, err = normalizeSubtitles(data)
+		if err != nil {
+			return
+		}
+		t.ContentType, ext = "text/vtt; charset=utf-8", ".vtt"
+	case entity.TextTrackKindTranscript:
+		var data []byte
+		data, err = io.ReadAll(limitUpload(p.File, maxTranscriptUploadSize, "Максимальный размер расшифровки - 512 КБ"))
+		if err != nil {
+			return
+		}
+
+		text, err = normalizeTrackText(data)
+		if err != nil {
+			return
+		}
+		text = strings.TrimSpace(text)
+		if text == "" {
+			return t, &dto.AppError{
+				Message: "Расшифровка не может быть пустой",
+				Code:    dto.ErrCodeBadRequest,
+			}
+		}
+		text += "\n"
+		t.ContentType, ext = "text/plain; charset=utf-8", ".txt"
+		searchConfig, searchText = null.StringFrom(textSearchConfig(p.Language)), null.StringFrom(text)
+	}
+
+	key := fmt.Sprintf("tracks/%d/%s-%s-%s%s", n.ID, p.Target, p.Kind, p.Language, ext)
+	err = u.blobStore.Put(ctx, key, strings.NewReader(text))
+	if err != nil {
+		return
+	}
+
+	hash := sha256.Sum256([]byte(text))
+	t, err = u.textTrackRepo.UpsertTextTrack(ctx, entity.TextTrack{
+		NewsID:      n.ID,
+		Target:      p.Target,
+		Kind:        p.Kind,
+		Language:    p.Language,
+		Label:       p.Label,
+		ContentType: t.ContentType,
+		Size:        int64(len(text)),
+		ContentHash: hex.EncodeToString(hash[:]),
+		StorageKey:  key,
+	}, searchConfig, searchText)
+	if err != nil {
+		return
+	}
+
+	t.URL = u.urls.Sign(textTrackURL(t))
+	return
+}
+
+func (u *textTrackUseCase) GetTextTracks(
+	ctx context.Context,
+	p dto.GetTextTracksParams,
+) (res dto.GetTextTracksResult, err error) {
+	defer func() {
+		if err != nil {
+			var appErr *dto.AppError
+			if !errors.As(err, &appErr) {
+				err = fmt.Errorf("TextTrackUseCase - GetTextTracks: %w", err)
+			}
+		}
+	}()
+
+	err = p.Validate()
+	if err != nil {
+		return
+	}
+
+	n, err := u.newsRepo().GetNews(ctx, p.NewsID)
+	if err != nil {
+		return
+	}
+
+	res.Items, err = u.textTrackRepo.GetTextTracks(ctx, n.ID, p.Target)
+	if err != nil {
+		return
+	}
+
+	for i := range res.Items {
+		res.Items[i].URL = u.urls.Sign(textTrackURL(res.Items[i]))
+	}
+	return
+}
+
+func (u *textTrackUseCase) GetTextTrack(
+	ctx context.Context,
+	p dto.GetTextTrackParams,
+) (res dto.GetAttachmentResult, err error) {
+	defer func() {
+		if err != nil {
+			var appErr *dto.AppError
+			if !errors.As(err, &appErr) {
+				err = fmt.Errorf("TextTrackUseCase - GetTextTrack: %w", err)
+			}
+		}
+	}()
+
+	err = p.Validate()
+	if err != nil {
+		return
+	}
+
+	expiresAt, err := u.urls.Verify(
+		signedKindTracks,
+		textTrackPath(p.NewsID, p.Target, p.Kind, p.Language),
+		p.AttachmentSignature,
+	)
+	if err != nil {
+		return
+	}
+
+	t, err := u.textTrackRepo.GetTextTrack(ctx, p.NewsID, p.Target, p.Kind, p.Language)
+	if err != nil {
+		return
+	}
+
+	res.File, err = u.blobStore.Open(ctx, t.StorageKey)
+	if err != nil {
+		return
+	}
+	res.ContentType = t.ContentType
+	res.ContentHash = t.ContentHash
+	res.Version = t.ContentHash[:16]
+	res.UpdatedAt = t.UpdatedAt
+	res.ExpiresAt = expiresAt
+	return
+}
+
+func (u *textTrackUseCase) DeleteTextTrack(ctx context.Context, p dto.DeleteTextTrackParams) (err error) {
+	defer func() {
+		if err != nil {
+			var appErr *dto.AppError
+			if !errors.As(err, &appErr) {
+				err = fmt.Errorf("TextTrackUseCase - DeleteTextTrack: %w", err)
+			}
+		}
+	}()
+
+	err = p.Validate()
+	if err != nil {
+		return
+	}
+
+	n, err := u.newsRepo().GetNews(ctx, p.NewsID)
+	if err != nil {
+		return
+	}
+
+	m, err := u.mediaRepo.GetMediaByRegistrationNumber(ctx, n.Media.RegistrationNumber)
+	if err != nil {
+		return
+	}
+
+	if m.ID != p.MediaID {
+		return &dto.AppError{
+			Code:    dto.ErrCodeUnauthorized,
+			Message: "Недостаточно прав для совершения данной операции",
+		}
+	}
+
+	key, err := u.textTrackRepo.DeleteTextTrack(ctx, n.ID, p.Target, p.Kind, p.Language)
+	if err != nil {
+		return
+	}
+
+	err = u.blobStore.Delete(ctx, key)
+	return
+}
+
+func textSearchConfig(language string) string {
+	primary, _, _ := strings.Cut(language, "-")
+	if config, ok := textSearchConfigs[primary]; ok {
+		return config
+	}
+	return "simple"
+}
+
+func textTrackURL(t entity.TextTrack) string {
+	return fmt.Sprintf("%s?v=%s", textTrackPath(t.NewsID, t.Target, t.Kind, t.Language), t.ContentHash[:16])
+}
+
+func textTrackPath(newsID int64, target, kind, language string) string {
+	return fmt.Sprintf("/api/news/%d/%s/tracks/%s/%s", newsID, target, kind, language)
+}
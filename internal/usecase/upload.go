@@ -0,0 +1,446 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	log "github.com/sirupsen/logrus"
+	"gopkg.in/guregu/null.v3"
+	"io"
+	"news-app-api/internal/adapter"
+	"news-app-api/internal/dto"
+	"news-app-api/internal/entity"
+	"strconv"
+	"time"
+)
+
+const (
+	maxAudioUploadSize = 512 << 20
+	maxImageUploadSize = 32 << 20
+	maxVideoUploadSize = 4 << 30
+
+	uploadProcessLease = 30 * time.Minute
+)
+
+type (
+	uploadLimitReader struct {
+		r       io.Reader
+		max     int64
+		read    int64
+		message string
+	}
+)
+
+func limitUpload(r io.Reader, max int64, message string) *uploadLimitReader {
+	return &uploadLimitReader{io.LimitReader(r, max+1), max, 0, message}
+}
+
+func (l *uploadLimitReader) Read(p []byte) (n int, err error) {
+	n, err = l.r.Read(p)
+	l.read += int64(n)
+	if l.read > l.max {
+		return n, &dto.AppError{
+			Message: l.message,
+			Code:    dto.ErrCodeBadRequest,
+		}
+	}
+	return
+}
+
+type (
+	UploadUseCase interface {
+		Create(ctx context.Context, p dto.CreateUploadParams) (entity.Upload, error)
+		Get(ctx context.Context, p dto.GetUploadParams) (entity.Upload, error)
+		Write(ctx context.Context, p dto.WriteUploadParams) (entity.Upload, error)
+		Delete(ctx context.Context, p dto.DeleteUploadParams) error
+		DeleteExpired(ctx context.Context) error
+		ProcessUploads(ctx context.Context) error
+		MaxSize() int64
+	}
+
+	UploadConfig struct {
+		Expiration time.Duration
+	}
+
+	uploadUseCase struct {
+		uploadRepo adapter.UploadRepository
+		newsRepo   func() adapter.NewsRepository
+		newsUC     NewsUseCase
+		blobStore  adapter.BlobStore
+		cfg        UploadConfig
+	}
+
+	partialUploadReader struct {
+		r   io.Reader
+		err error
+	}
+
+	uploadChunkReader struct {
+		ctx       context.Context
+		blobStore adapter.BlobStore
+		chunks    []entity.UploadChunk
+		current   entity.File
+	}
+)
+
+func NewUploadUseCase(
+	uploadRepo adapter.UploadRepository,
+	newsRepo func() adapter.NewsRepository,
+	newsUC NewsUseCase,
+	blobStore adapter.BlobStore,
+	cfg UploadConfig,
+) UploadUseCase {
+	return &uploadUseCase{uploadRepo, newsRepo, newsUC, blobStore, cfg}
+}
+
+func (u *uploadUseCase) MaxSize() int64 {
+	var max int64
+	for _, format := range attachmentFormats {
+		if format.maxSize > max {
+			max = format.maxSize
+		}
+	}
+	return max
+}
+
+func (u *uploadUseCase) Create(ctx context.Context, p dto.CreateUploadParams) (res entity.Upload, err error) {
+	defer func() {
+		if err != nil {
+			var appErr *dto.AppError
+			if !errors.As(err, &appErr) {
+				err = fmt.Errorf("UploadUseCase - Create: %w", err)
+			}
+		}
+	}()
+
+	format, ok := attachmentFormats[p.Metadata["kind"]]
+	if !ok {
+		return res, &dto.AppError{
+			Message: "Тип вложения должен быть audio, image или video",
+			Code:    dto.ErrCodeBadRequest,
+		}
+	}
+
+	newsID, err := strconv.ParseInt(p.Metadata["newsId"], 10, 64)
+	if err != nil {
+		return res, &dto.AppError{
+			Message: "Не указан идентификатор новости",
+			Code:    dto.ErrCodeBadRequest,
+		}
+	}
+
+	if p.Length < 1 {
+		return res, &dto.AppError{
+			Message: "Размер загрузки должен быть больше нуля",
+			Code:    dto.ErrCodeBadRequest,
+		}
+	}
+	if p.Length > format.maxSize {
+		return res, &dto.AppError{
+			Message: format.sizeMessage,
+			Code:    dto.ErrCodeRequestEntityTooLarge,
+		}
+	}
+
+	n, err := u.newsRepo().GetNews(ctx, newsID)
+	if err != nil {
+		return
+	}
+
+	if n.Media.ID != p.MediaID {
+		return res, &dto.AppError{
+			Code:    dto.ErrCodeUnauthorized,
+			Message: "Недостаточно прав для совершения данной операции",
+		}
+	}
+
+	id, err := newRandomToken()
+	if err != nil {
+		return
+	}
+
+	return u.uploadRepo.CreateUpload(ctx, entity.Upload{
+		ID:        id,
+		MediaID:   p.MediaID,
+		NewsID:    newsID,
+		Kind:      p.Metadata["kind"],
+		Length:    p.Length,
+		ExpiresAt: time.Now().Add(u.cfg.Expiration).Unix(),
+	})
+}
+
+func (u *uploadUseCase) Get(ctx context.Context, p dto.GetUploadParams) (res entity.Upload, err error) {
+	defer func() {
+		if err != nil {
+			var appErr *dto.AppError
+			if !errors.As(err, &appErr) {
+				err = fmt.Errorf("UploadUseCase - Get: %w", err)
+			}
+		}
+	}()
+
+	return u.getOwnUpload(ctx, p.ID, p.MediaID)
+}
+
+func (u *uploadUseCase) Write(ctx context.Context, p dto.WriteUploadParams) (res entity.Upload, err error) {
+	defer func() {
+		if err != nil {
+			var appErr *dto.AppError
+			if !errors.As(err, &appErr) {
+				err = fmt.Errorf("UploadUseCase - Write: %w", err)
+			}
+		}
+	}()
+
+	res, err = u.getOwnUpload(ctx, p.ID, p.MediaID)
+	if err != nil {
+		return
+	}
+
+	if p.Offset != res.Offset {
+		return res, &dto.AppError{
+			Message: "Смещение загрузки не совпадает с текущим",
+			Code:    dto.ErrCodeConflict,
+		}
+	}
+
+	token, err := newRandomToken()
+	if err != nil {
+		return
+	}
+	key := fmt.Sprintf("uploads/%s/%d-%s", res.ID, p.Offset, token[:16])
+
+	body := limitUpload(p.Body, res.Length-res.Offset, "Объём данных превышает размер загрузки")
+	partial := &partialUploadReader{r: body}
+
+	var r io.Reader = partial
+	if p.Offset == 0 {
+		r, err = sniffUploadChunk(res, body, partial)
+		if err != nil {
+			return
+		}
+	}
+
+	err = u.blobStore.Put(ctx, key, r)
+	if err != nil {
+		return
+	}
+
+	if body.read == 0 {
+		err = u.blobStore.Delete(ctx, key)
+		if err == nil {
+			err = partial.err
+		}
+		return
+	}
+
+	res, err = u.uploadRepo.AddUploadChunk(
+		ctx,
+		p.ID,
+		entity.UploadChunk{Offset: p.Offset, Size: body.read, StorageKey: key},
+		time.Now().Add(u.cfg.Expiration).Unix(),
+	)
+	if err != nil {
+		_ = u.blobStore.Delete(ctx, key)
+		return
+	}
+
+	return res, partial.err
+}
+
+func (u *uploadUseCase) Delete(ctx context.Context, p dto.DeleteUploadParams) (err error) {
+	defer func() {
+		if err != nil {
+			var appErr *dto.AppError
+			if !errors.As(err, &appErr) {
+				err = fmt.Errorf("UploadUseCase - Delete: %w", err)
+			}
+		}
+	}()
+
+	_, err = u.getOwnUpload(ctx, p.ID, p.MediaID)
+	if err != nil {
+		return
+	}
+
+	return u.deleteUpload(ctx, p.ID)
+}
+
+func (u *uploadUseCase) DeleteExpired(ctx context.Context) (err error) {
+	defer func() {
+		if err != nil {
+			err = fmt.Errorf("UploadUseCase - DeleteExpired: %w", err)
+		}
+	}()
+
+	keys, err := u.uploadRepo.DeleteExpiredUploads(ctx)
+	if err != nil {
+		return
+	}
+
+	return u.deleteBlobs(ctx, keys)
+}
+
+func (u *uploadUseCase) ProcessUploads(ctx context.Context) (err error) {
+	defer func() {
+		if err != nil {
+			err = fmt.Errorf("UploadUseCase - ProcessUploads: %w", err)
+		}
+	}()
+
+	for {
+		var (
+			up entity.Upload
+			ok bool
+		)
+		up, ok, err = u.uploadRepo.ClaimProcessingUpload(ctx, uploadProcessLease)
+		if err != nil || !ok {
+			return
+		}
+
+		// A failed upload stays claimed until the lease expires, so it is retried later without blocking the others.
+		if e := u.process(ctx, up); e != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			log.WithField("uploadID", up.ID).Errorf("Failed to process upload: %v", e)
+		}
+	}
+}
+
+func (u *uploadUseCase) getOwnUpload(ctx context.Context, id string, mediaID int64) (entity.Upload, error) {
+	res, err := u.uploadRepo.GetUpload(ctx, id)
+	if err != nil {
+		return res, err
+	}
+
+	if res.MediaID != mediaID {
+		return res, &dto.AppError{
+			Message: "Загрузка не найдена",
+			Code:    dto.ErrCodeNotFound,
+		}
+	}
+	return res, nil
+}
+
+// process attaches a fully written upload to its news. Uploads rejected with an AppError are marked as failed, other
+// errors leave the upload to be retried once its lease expires.
+func (u *uploadUseCase) process(ctx context.Context, up entity.Upload) error {
+	status, message := entity.UploadStatusCompleted, null.String{}
+
+	err := u.attach(ctx, up)
+	var appErr *dto.AppError
+	if errors.As(err, &appErr) {
+		status, message = entity.UploadStatusFailed, null.StringFrom(appErr.Message)
+	} else if err != nil {
+		return err
+	}
+
+	keys, err := u.uploadRepo.FinishUpload(ctx, up.ID, status, message)
+	if err != nil {
+		return err
+	}
+	return u.deleteBlobs(ctx, keys)
+}
+
+func (u *uploadUseCase) attach(ctx context.Context, up entity.Upload) (err error) {
+	chunks, err := u.uploadRepo.GetUploadChunks(ctx, up.ID)
+	if err != nil {
+		return
+	}
+
+	file := &uploadChunkReader{ctx: ctx, blobStore: u.blobStore, chunks: chunks}
+	defer file.Close()
+
+	switch up.Kind {
+	case entity.AttachmentKindAudio:
+		return u.newsUC.CreateOrUpdateAudio(ctx, dto.CreateOrUpdateAudioParams{NewsID: up.NewsID, MediaID: up.MediaID, File: file})
+	case entity.AttachmentKindImage:
+		return u.newsUC.CreateOrUpdateImage(ctx, dto.CreateOrUpdateImageParams{NewsID: up.NewsID, MediaID: up.MediaID, File: file})
+	case entity.AttachmentKindVideo:
+		return u.newsUC.CreateOrUpdateVideo(ctx, dto.CreateOrUpdateVideoParams{NewsID: up.NewsID, MediaID: up.MediaID, File: file})
+	}
+	return fmt.Errorf("unknown upload kind %q", up.Kind)
+}
+
+func (u *uploadUseCase) deleteUpload(ctx context.Context, id string) error {
+	keys, err := u.uploadRepo.DeleteUpload(ctx, id)
+	if err != nil {
+		return err
+	}
+	return u.deleteBlobs(ctx, keys)
+}
+
+func (u *uploadUseCase) deleteBlobs(ctx context.Context, keys []string) (err error) {
+	for _, key := range keys {
+		deleteErr := u.blobStore.Delete(ctx, key)
+		if deleteErr != nil {
+			err = deleteErr
+		}
+	}
+	return
+}
+
+// sniffUploadChunk rejects a first chunk that doesn't start like a file of the upload's kind. A chunk too short to be
+// recognised is accepted and checked again when the upload is processed.
+func sniffUploadChunk(up entity.Upload, body *uploadLimitReader, r io.Reader) (io.Reader, error) {
+	contentType, rest, err := sniffUpload(r)
+	if err != nil {
+		return nil, err
+	}
+
+	format := attachmentFormats[up.Kind]
+	if _, ok := format.contentTypes[contentType]; ok {
+		return rest, nil
+	}
+	if contentType == "" && body.read < mediaTypeSniffLen && body.read < up.Length {
+		return rest, nil
+	}
+	return nil, &dto.AppError{
+		Message: format.typeMessage,
+		Code:    dto.ErrCodeBadRequest,
+	}
+}
+
+func (r *partialUploadReader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	var appErr *dto.AppError
+	if err != nil && err != io.EOF && !errors.As(err, &appErr) {
+		r.err = err
+		return n, io.EOF
+	}
+	return n, err
+}
+
+func (r *uploadChunkReader) Read(p []byte) (int, error) {
+	for {
+		if r.current == nil {
+			if len(r.chunks) == 0 {
+				return 0, io.EOF
+			}
+			f, err := r.blobStore.Open(r.ctx, r.chunks[0].StorageKey)
+			if err != nil {
+				return 0, err
+			}
+			r.current, r.chunks = f, r.chunks[1:]
+		}
+
+		n, err := r.current.Read(p)
+		if err == io.EOF {
+			r.current.Close()
+			r.current = nil
+			if n > 0 {
+				return n, nil
+			}
+			continue
+		}
+		return n, err
+	}
+}
+
+func (r *uploadChunkReader) Close() error {
+	if r.current == nil {
+		return nil
+	}
+	return r.current.Close()
+}
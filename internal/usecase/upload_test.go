@@ -0,0 +1,288 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"gopkg.in/guregu/null.v3"
+	"io"
+	"news-app-api/internal/dto"
+	"news-app-api/internal/entity"
+	"testing"
+	"time"
+)
+
+type fakeUploadRepository struct {
+	upload   entity.Upload
+	chunks   []entity.UploadChunk
+	claimed  bool
+	claimErr error
+	status   string
+	message  null.String
+	finished bool
+}
+
+func (r *fakeUploadRepository) CreateUpload(_ context.Context, u entity.Upload) (entity.Upload, error) {
+	return u, nil
+}
+
+func (r *fakeUploadRepository) GetUpload(context.Context, string) (entity.Upload, error) {
+	return r.upload, nil
+}
+
+func (r *fakeUploadRepository) AddUploadChunk(
+	_ context.Context,
+	_ string,
+	c entity.UploadChunk,
+	_ int64,
+) (entity.Upload, error) {
+	r.chunks = append(r.chunks, c)
+	r.upload.Offset += c.Size
+	if r.upload.Offset == r.upload.Length {
+		r.upload.Status = entity.UploadStatusProcessing
+	}
+	return r.upload, nil
+}
+
+func (r *fakeUploadRepository) GetUploadChunks(context.Context, string) ([]entity.UploadChunk, error) {
+	return r.chunks, nil
+}
+
+func (r *fakeUploadRepository) ClaimProcessingUpload(context.Context, time.Duration) (entity.Upload, bool, error) {
+	if r.claimErr != nil {
+		return entity.Upload{}, false, r.claimErr
+	}
+	if r.claimed || r.upload.Status != entity.UploadStatusProcessing {
+		return entity.Upload{}, false, nil
+	}
+	r.claimed = true
+	return r.upload, true, nil
+}
+
+func (r *fakeUploadRepository) FinishUpload(_ context.Context, _, status string, message null.String) ([]string, error) {
+	r.status, r.message, r.finished = status, message, true
+	var keys []string
+	for _, c := range r.chunks {
+		keys = append(keys, c.StorageKey)
+	}
+	r.chunks = nil
+	return keys, nil
+}
+
+func (r *fakeUploadRepository) DeleteUpload(context.Context, string) ([]string, error) {
+	return nil, nil
+}
+
+func (r *fakeUploadRepository) DeleteExpiredUploads(context.Context) ([]string, error) {
+	return nil, nil
+}
+
+type fakeBlobStore struct {
+	files map[string][]byte
+}
+
+func (s *fakeBlobStore) Put(_ context.Context, key string, r io.Reader) error {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	s.files[key] = data
+	return nil
+}
+
+func (s *fakeBlobStore) Open(_ context.Context, key string) (entity.File, error) {
+	data, ok := s.files[key]
+	if !ok {
+		return nil, &dto.AppError{Code: dto.ErrCodeNotFound, Message: "Файл не найден"}
+	}
+	return &fakeFile{bytes.NewReader(data)}, nil
+}
+
+func (s *fakeBlobStore) Move(_ context.Context, from, to string) error {
+	s.files[to] = s.files[from]
+	delete(s.files, from)
+	return nil
+}
+
+func (s *fakeBlobStore) Delete(_ context.Context, key string) error {
+	delete(s.files, key)
+	return nil
+}
+
+func (s *fakeBlobStore) List(context.Context, string, func(entity.StoredFile) error) error {
+	return nil
+}
+
+type fakeFile struct {
+	*bytes.Reader
+}
+
+func (f *fakeFile) ModTime() time.Time {
+	return time.Time{}
+}
+
+func (f *fakeFile) Close() error {
+	return nil
+}
+
+// fakeNewsUseCase reads attached files to the end and fails with err; other NewsUseCase methods are not used.
+type fakeNewsUseCase struct {
+	NewsUseCase
+	err      error
+	attached []byte
+}
+
+func (u *fakeNewsUseCase) CreateOrUpdateImage(_ context.Context, p dto.CreateOrUpdateImageParams) error {
+	data, err := io.ReadAll(p.File)
+	if err != nil {
+		return err
+	}
+	u.attached = data
+	return u.err
+}
+
+func TestUploadWriteSniffsFirstChunk(t *testing.T) {
+	png := append([]byte("\x89PNG\r\n\x1a\n"), make([]byte, 1000)...)
+	wav := testUploadWAV(1000)
+
+	tests := []struct {
+		name    string
+		length  int64
+		chunk   []byte
+		wantErr bool
+	}{
+		{"matching type", int64(len(png)), png, false},
+		{"matching type in a partial chunk", int64(len(png)), png[:600], false},
+		{"other type", int64(len(wav)), wav, true},
+		{"other type in a partial chunk", int64(len(wav)), wav[:600], true},
+		{"too short to recognise", int64(len(wav)), wav[:4], false},
+		{"unrecognised complete upload", 4, wav[:4], true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeUploadRepository{upload: entity.Upload{
+				ID:      "id",
+				MediaID: 1,
+				Kind:    entity.AttachmentKindImage,
+				Length:  tt.length,
+				Status:  entity.UploadStatusUploading,
+			}}
+			store := &fakeBlobStore{files: map[string][]byte{}}
+			u := NewUploadUseCase(repo, nil, nil, store, UploadConfig{Expiration: time.Hour})
+
+			_, err := u.Write(context.Background(), dto.WriteUploadParams{
+				ID:      "id",
+				MediaID: 1,
+				Body:    bytes.NewReader(tt.chunk),
+			})
+
+			var appErr *dto.AppError
+			if tt.wantErr {
+				if !errors.As(err, &appErr) || appErr.Code != dto.ErrCodeBadRequest {
+					t.Fatalf("err = %v, want bad request", err)
+				}
+				if len(store.files) != 0 || len(repo.chunks) != 0 {
+					t.Errorf("rejected chunk was stored")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(repo.chunks) != 1 || !bytes.Equal(store.files[repo.chunks[0].StorageKey], tt.chunk) {
+				t.Errorf("chunk was not stored intact")
+			}
+		})
+	}
+}
+
+func TestUploadProcess(t *testing.T) {
+	png := append([]byte("\x89PNG\r\n\x1a\n"), make([]byte, 1000)...)
+
+	tests := []struct {
+		name        string
+		attachErr   error
+		wantStatus  string
+		wantMessage null.String
+	}{
+		{"completed", nil, entity.UploadStatusCompleted, null.String{}},
+		{
+			"rejected",
+			&dto.AppError{Code: dto.ErrCodeBadRequest, Message: "Недопустимый файл"},
+			entity.UploadStatusFailed,
+			null.StringFrom("Недопустимый файл"),
+		},
+		{"retried", errors.New("storage is unavailable"), "", null.String{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeUploadRepository{upload: entity.Upload{
+				ID:      "id",
+				MediaID: 1,
+				Kind:    entity.AttachmentKindImage,
+				Length:  int64(len(png)),
+				Status:  entity.UploadStatusUploading,
+			}}
+			store := &fakeBlobStore{files: map[string][]byte{}}
+			newsUC := &fakeNewsUseCase{err: tt.attachErr}
+			u := NewUploadUseCase(repo, nil, newsUC, store, UploadConfig{Expiration: time.Hour})
+
+			for offset := 0; offset < len(png); offset += 300 {
+				end := offset + 300
+				if end > len(png) {
+					end = len(png)
+				}
+				_, err := u.Write(context.Background(), dto.WriteUploadParams{
+					ID:      "id",
+					MediaID: 1,
+					Offset:  int64(offset),
+					Body:    bytes.NewReader(png[offset:end]),
+				})
+				if err != nil {
+					t.Fatalf("Write at %d: %v", offset, err)
+				}
+			}
+			if newsUC.attached != nil {
+				t.Fatal("upload was attached while writing")
+			}
+
+			err := u.ProcessUploads(context.Background())
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !bytes.Equal(newsUC.attached, png) {
+				t.Errorf("attached %d bytes, want the %d uploaded", len(newsUC.attached), len(png))
+			}
+
+			if tt.wantStatus == "" {
+				if repo.finished || len(store.files) != len(repo.chunks) {
+					t.Errorf("upload was finished on a retryable error")
+				}
+				return
+			}
+			if repo.status != tt.wantStatus || repo.message != tt.wantMessage {
+				t.Errorf("finished as %q, %v, want %q, %v", repo.status, repo.message, tt.wantStatus, tt.wantMessage)
+			}
+			if len(store.files) != 0 {
+				t.Errorf("%d chunks left in the store", len(store.files))
+			}
+		})
+	}
+}
+
+func TestUploadProcessClaimError(t *testing.T) {
+	repo := &fakeUploadRepository{claimErr: errors.New("connection reset")}
+	u := NewUploadUseCase(repo, nil, nil, &fakeBlobStore{}, UploadConfig{Expiration: time.Hour})
+
+	err := u.ProcessUploads(context.Background())
+	if !errors.Is(err, repo.claimErr) {
+		t.Errorf("err = %v, want the claim error", err)
+	}
+}
+
+func testUploadWAV(n int) []byte {
+	b := []byte("RIFF\x00\x00\x00\x00WAVEfmt ")
+	return append(b, make([]byte, n)...)
+}
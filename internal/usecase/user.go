@@ -7,6 +7,8 @@ import (
 	"news-app-api/internal/adapter"
 	"news-app-api/internal/dto"
 	"news-app-api/internal/entity"
+	"strings"
+	"time"
 )
 
 type (
@@ -15,15 +17,29 @@ type (
 		LoginUser(ctx context.Context, p dto.LoginUserParams) (entity.User, error)
 		GetUserByID(ctx context.Context, userID int64) (entity.User, error)
 		GetSubscriptionList(ctx context.Context, p dto.GetSubscriptionListParams) (dto.GetSubscriptionListResult, error)
+		UpdateName(ctx context.Context, p dto.UpdateUserNameParams) (entity.User, error)
+		ChangeEmail(ctx context.Context, p dto.ChangeUserEmailParams) (dto.ChangeUserEmailResult, error)
+		ConfirmEmail(ctx context.Context, p dto.ConfirmUserEmailParams) (entity.User, error)
+		ChangePassword(ctx context.Context, p dto.ChangeUserPasswordParams) error
+	}
+
+	UserConfig struct {
+		PublicURL string
+		MailFrom  string
 	}
 
 	userUseCase struct {
 		userRepo adapter.UserRepository
+		mailer   adapter.Mailer
+		cfg      UserConfig
 	}
 )
 
-func NewUserUseCase(userRepo adapter.UserRepository) UserUseCase {
-	return &userUseCase{userRepo}
+const emailChangeTTL = 24 * time.Hour
+
+func NewUserUseCase(userRepo adapter.UserRepository, mailer adapter.Mailer, cfg UserConfig) UserUseCase {
+	cfg.PublicURL = strings.TrimRight(cfg.PublicURL, "/")
+	return &userUseCase{userRepo, mailer, cfg}
 }
 
 func (u *userUseCase) RegisterUser(ctx context.Context, p dto.RegisterUserParams) (user entity.User, err error) {
@@ -41,34 +57,14 @@ func (u *userUseCase) RegisterUser(ctx context.Context, p dto.RegisterUserParams
 		return
 	}
 
-	_, err = u.userRepo.GetUserByLogin(ctx, p.Login)
-	if err == nil {
-		err = &dto.AppError{
-			Message: "Логин уже занят",
-			Code:    dto.ErrCodeConflict,
-		}
-		return
-	}
+	err = u.checkLoginConflict(ctx, p.Login, 0)
 	if err != nil {
-		var appErr *dto.AppError
-		if !errors.As(err, &appErr) || appErr.Code != dto.ErrCodeNotFound {
-			return
-		}
-	}
-
-	_, err = u.userRepo.GetUserByEmail(ctx, p.Email)
-	if err == nil {
-		err = &dto.AppError{
-			Message: "Email уже занят",
-			Code:    dto.ErrCodeConflict,
-		}
 		return
 	}
+
+	err = u.checkEmailConflict(ctx, p.Email, 0)
 	if err != nil {
-		var appErr *dto.AppError
-		if !errors.As(err, &appErr) || appErr.Code != dto.ErrCodeNotFound {
-			return
-		}
+		return
 	}
 
 	return u.userRepo.CreateUser(ctx, p)
@@ -134,3 +130,162 @@ func (u *userUseCase) GetSubscriptionList(
 
 	return
 }
+
+func (u *userUseCase) UpdateName(ctx context.Context, p dto.UpdateUserNameParams) (user entity.User, err error) {
+	defer func() {
+		if err != nil {
+			var appErr *dto.AppError
+			if !errors.As(err, &appErr) {
+				err = fmt.Errorf("UserUseCase - UpdateName: %w", err)
+			}
+		}
+	}()
+
+	err = p.Validate()
+	if err != nil {
+		return
+	}
+
+	return u.userRepo.UpdateUserName(ctx, p.UserID, p.Name)
+}
+
+func (u *userUseCase) ChangeEmail(
+	ctx context.Context,
+	p dto.ChangeUserEmailParams,
+) (res dto.ChangeUserEmailResult, err error) {
+	defer func() {
+		if err != nil {
+			var appErr *dto.AppError
+			if !errors.As(err, &appErr) {
+				err = fmt.Errorf("UserUseCase - ChangeEmail: %w", err)
+			}
+		}
+	}()
+
+	err = p.Validate()
+	if err != nil {
+		return
+	}
+
+	user, err := u.userRepo.GetUserByID(ctx, p.UserID)
+	if err != nil {
+		return
+	}
+
+	if user.Email == p.Email {
+		return res, &dto.AppError{
+			Message: "Новый email совпадает с текущим",
+			Code:    dto.ErrCodeBadRequest,
+		}
+	}
+
+	err = u.checkEmailConflict(ctx, p.Email, p.UserID)
+	if err != nil {
+		return
+	}
+
+	token, err := newRandomToken()
+	if err != nil {
+		return
+	}
+
+	expiresAt := time.Now().Add(emailChangeTTL)
+	err = u.userRepo.UpsertEmailChange(ctx, p.UserID, p.Email, token, expiresAt.Unix())
+	if err != nil {
+		return
+	}
+
+	confirmURL := fmt.Sprintf("%s/api/users/me/email/confirm?token=%s", u.cfg.PublicURL, token)
+	err = u.mailer.Send(ctx, entity.Mail{
+		From:    u.cfg.MailFrom,
+		To:      p.Email,
+		Subject: "Подтверждение email",
+		Text: fmt.Sprintf(
+			"Здравствуйте, %s!\n\nЧтобы подтвердить новый адрес электронной почты, перейдите по ссылке:\n%s\n\n"+
+				"Ссылка действительна 24 часа. Если вы не меняли email, просто проигнорируйте это письмо.\n",
+			user.Name,
+			confirmURL,
+		),
+	})
+	if err != nil {
+		return
+	}
+
+	res.PendingEmail = p.Email
+	res.ExpiresAt = expiresAt.Unix()
+	return
+}
+
+func (u *userUseCase) ConfirmEmail(ctx context.Context, p dto.ConfirmUserEmailParams) (user entity.User, err error) {
+	defer func() {
+		if err != nil {
+			var appErr *dto.AppError
+			if !errors.As(err, &appErr) {
+				err = fmt.Errorf("UserUseCase - ConfirmEmail: %w", err)
+			}
+		}
+	}()
+
+	if p.Token == "" {
+		return user, &dto.AppError{
+			Message: "Ссылка для подтверждения недействительна или устарела",
+			Code:    dto.ErrCodeBadRequest,
+		}
+	}
+
+	userID, email, err := u.userRepo.GetEmailChangeByToken(ctx, p.Token)
+	if err != nil {
+		return
+	}
+
+	err = u.checkEmailConflict(ctx, email, userID)
+	if err != nil {
+		return
+	}
+
+	return u.userRepo.ConfirmEmailChange(ctx, p.Token)
+}
+
+func (u *userUseCase) ChangePassword(ctx context.Context, p dto.ChangeUserPasswordParams) (err error) {
+	defer func() {
+		if err != nil {
+			var appErr *dto.AppError
+			if !errors.As(err, &appErr) {
+				err = fmt.Errorf("UserUseCase - ChangePassword: %w", err)
+			}
+		}
+	}()
+
+	err = p.Validate()
+	if err != nil {
+		return
+	}
+
+	user, err := u.userRepo.GetUserByID(ctx, p.UserID)
+	if err != nil {
+		return
+	}
+
+	if user.Password != p.CurrentPassword {
+		return &dto.AppError{
+			Message: "Неверный пароль",
+			Code:    dto.ErrCodeUnauthorized,
+		}
+	}
+
+	return u.userRepo.UpdateUserPassword(ctx, p.UserID, p.NewPassword)
+}
+
+func (u *userUseCase) checkLoginConflict(ctx context.Context, login string, exceptUserID int64) error {
+	return checkConflict(exceptUserID, "Логин уже занят", func() (int64, error) {
+		user, err := u.userRepo.GetUserByLogin(ctx, login)
+		return user.ID, err
+	})
+}
+
+func (u *userUseCase) checkEmailConflict(ctx context.Context, email string, exceptUserID int64) error {
+	return checkConflict(exceptUserID, "Email уже занят", func() (int64, error) {
+		user, err := u.userRepo.GetUserByEmail(ctx, email)
+		return user.ID, err
+	})
+}
@@ -0,0 +1,43 @@
+package usecase
+
+import (
+	"context"
+	log "github.com/sirupsen/logrus"
+	"news-app-api/internal/dto"
+	"news-app-api/internal/entity"
+)
+
+func (u *newsUseCase) storeWaveform(ctx context.Context, a entity.Attachment) (err error) {
+	f, err := u.blobStore.Open(ctx, a.StorageKey)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+
+	var waveform *entity.Waveform
+	w, generateErr := u.waveformGenerator.Generate(a.ContentType, f)
+	if generateErr != nil {
+		log.WithField("key", a.StorageKey).Warn(generateErr.Error())
+	} else {
+		waveform = &w
+	}
+
+	return u.attachmentRepo.UpdateAttachmentWaveform(ctx, a.ID, a.ContentHash, waveform)
+}
+
+func selectWaveformResolution(w entity.Waveform, p dto.GetAudioWaveformParams) (entity.Waveform, error) {
+	if !p.Resolution.Valid {
+		return w, nil
+	}
+
+	for _, r := range w.Resolutions {
+		if int64(r.Resolution) == p.Resolution.Int64 {
+			w.Resolutions = []entity.WaveformResolution{r}
+			return w, nil
+		}
+	}
+	return w, &dto.AppError{
+		Message: "Недоступное разрешение волновой формы",
+		Code:    dto.ErrCodeBadRequest,
+	}
+}
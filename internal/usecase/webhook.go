@@ -0,0 +1,363 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"gopkg.in/guregu/null.v3"
+	"news-app-api/internal/adapter"
+	"news-app-api/internal/dto"
+	"news-app-api/internal/entity"
+	"strings"
+	"time"
+)
+
+const (
+	webhookSendTimeout    = 15 * time.Second
+	webhookErrorRuneLimit = 1024
+)
+
+var webhookQueue = deliveryQueue{
+	batchSize:    50,
+	concurrency:  8,
+	claimLease:   2 * time.Minute,
+	maxAttempts:  8,
+	retryBackoff: 30 * time.Second,
+}
+
+type (
+	WebhookUseCase interface {
+		EventHandler
+		GetWebhookList(ctx context.Context, mediaID int64) ([]entity.Webhook, error)
+		CreateWebhook(ctx context.Context, p dto.CreateWebhookParams) (entity.Webhook, error)
+		GetWebhook(ctx context.Context, p dto.WebhookParams) (entity.Webhook, error)
+		UpdateWebhook(ctx context.Context, p dto.UpdateWebhookParams) (entity.Webhook, error)
+		RotateSecret(ctx context.Context, p dto.WebhookParams) (entity.Webhook, error)
+		DeleteWebhook(ctx context.Context, p dto.WebhookParams) error
+		GetDeliveryList(
+			ctx context.Context,
+			p dto.GetWebhookDeliveryListParams,
+		) (dto.GetWebhookDeliveryListResult, error)
+		GetDelivery(ctx context.Context, p dto.WebhookDeliveryParams) (entity.WebhookDelivery, error)
+		ReplayDelivery(ctx context.Context, p dto.WebhookDeliveryParams) (entity.WebhookDelivery, error)
+		DeliverDueWebhooks(ctx context.Context) error
+	}
+
+	webhookUseCase struct {
+		webhookRepo adapter.WebhookRepository
+		newsRepo    func() adapter.NewsRepository
+		sender      adapter.WebhookSender
+	}
+
+	webhookPayload struct {
+		Type      string `json:"type"`
+		CreatedAt int64  `json:"createdAt"`
+		Data      any    `json:"data"`
+	}
+
+	webhookSubscriptionData struct {
+		MediaID int64 `json:"mediaId"`
+		UserID  int64 `json:"userId"`
+	}
+
+	webhookFavoriteData struct {
+		NewsID int64  `json:"newsId"`
+		Title  string `json:"title"`
+		UserID int64  `json:"userId"`
+	}
+
+	webhookNewsData struct {
+		News entity.News `json:"news"`
+	}
+)
+
+func NewWebhookUseCase(
+	webhookRepo adapter.WebhookRepository,
+	newsRepo func() adapter.NewsRepository,
+	sender adapter.WebhookSender,
+) WebhookUseCase {
+	return &webhookUseCase{webhookRepo, newsRepo, sender}
+}
+
+func (u *webhookUseCase) HandleEvent(ctx context.Context, e entity.Event) (err error) {
+	defer func() {
+		if err != nil {
+			var appErr *dto.AppError
+			if !errors.As(err, &appErr) {
+				err = fmt.Errorf("WebhookUseCase - HandleEvent: %w", err)
+			}
+		}
+	}()
+
+	switch e := e.(type) {
+	case entity.NewsCreatedEvent:
+		return u.enqueue(ctx, e.MediaID, entity.WebhookEventNewsPublished, webhookNewsData{News: e.News})
+	case entity.SubscriptionToggledEvent:
+		t := entity.WebhookEventSubscriptionDeleted
+		if e.IsSubscribed {
+			t = entity.WebhookEventSubscriptionCreated
+		}
+		return u.enqueue(ctx, e.MediaID, t, webhookSubscriptionData{MediaID: e.MediaID, UserID: e.UserID})
+	case entity.FavoriteToggledEvent:
+		if !e.IsFavorite {
+			return
+		}
+		var n entity.NewsListItem
+		n, err = u.newsRepo().GetNews(ctx, e.NewsID)
+		if err != nil {
+			return
+		}
+		return u.enqueue(ctx, n.Media.ID, entity.WebhookEventNewsFavorited, webhookFavoriteData{
+			NewsID: n.ID,
+			Title:  n.Title,
+			UserID: e.UserID,
+		})
+	}
+
+	return
+}
+
+func (u *webhookUseCase) enqueue(ctx context.Context, mediaID int64, eventType string, data any) error {
+	payload, err := json.Marshal(webhookPayload{
+		Type:      eventType,
+		CreatedAt: time.Now().Unix(),
+		Data:      data,
+	})
+	if err != nil {
+		return err
+	}
+	return u.webhookRepo.EnqueueWebhookDeliveries(ctx, mediaID, eventType, string(payload))
+}
+
+func (u *webhookUseCase) GetWebhookList(ctx context.Context, mediaID int64) (list []entity.Webhook, err error) {
+	defer func() {
+		if err != nil {
+			var appErr *dto.AppError
+			if !errors.As(err, &appErr) {
+				err = fmt.Errorf("WebhookUseCase - GetWebhookList: %w", err)
+			}
+		}
+	}()
+	return u.webhookRepo.GetWebhookList(ctx, mediaID)
+}
+
+func (u *webhookUseCase) CreateWebhook(ctx context.Context, p dto.CreateWebhookParams) (w entity.Webhook, err error) {
+	defer func() {
+		if err != nil {
+			var appErr *dto.AppError
+			if !errors.As(err, &appErr) {
+				err = fmt.Errorf("WebhookUseCase - CreateWebhook: %w", err)
+			}
+		}
+	}()
+
+	err = p.Validate()
+	if err != nil {
+		return
+	}
+
+	secret, err := newRandomToken()
+	if err != nil {
+		return
+	}
+
+	return u.webhookRepo.CreateWebhook(ctx, p, secret)
+}
+
+func (u *webhookUseCase) GetWebhook(ctx context.Context, p dto.WebhookParams) (w entity.Webhook, err error) {
+	defer func() {
+		if err != nil {
+			var appErr *dto.AppError
+			if !errors.As(err, &appErr) {
+				err = fmt.Errorf("WebhookUseCase - GetWebhook: %w", err)
+			}
+		}
+	}()
+	return u.webhookRepo.GetWebhook(ctx, p.WebhookID, p.MediaID)
+}
+
+func (u *webhookUseCase) UpdateWebhook(ctx context.Context, p dto.UpdateWebhookParams) (w entity.Webhook, err error) {
+	defer func() {
+		if err != nil {
+			var appErr *dto.AppError
+			if !errors.As(err, &appErr) {
+				err = fmt.Errorf("WebhookUseCase - UpdateWebhook: %w", err)
+			}
+		}
+	}()
+
+	err = p.Validate()
+	if err != nil {
+		return
+	}
+
+	return u.webhookRepo.UpdateWebhook(ctx, p)
+}
+
+func (u *webhookUseCase) RotateSecret(ctx context.Context, p dto.WebhookParams) (w entity.Webhook, err error) {
+	defer func() {
+		if err != nil {
+			var appErr *dto.AppError
+			if !errors.As(err, &appErr) {
+				err = fmt.Errorf("WebhookUseCase - RotateSecret: %w", err)
+			}
+		}
+	}()
+
+	secret, err := newRandomToken()
+	if err != nil {
+		return
+	}
+
+	return u.webhookRepo.RotateWebhookSecret(ctx, p.WebhookID, p.MediaID, secret)
+}
+
+func (u *webhookUseCase) DeleteWebhook(ctx context.Context, p dto.WebhookParams) (err error) {
+	defer func() {
+		if err != nil {
+			var appErr *dto.AppError
+			if !errors.As(err, &appErr) {
+				err = fmt.Errorf("WebhookUseCase - DeleteWebhook: %w", err)
+			}
+		}
+	}()
+	return u.webhookRepo.DeleteWebhook(ctx, p.WebhookID, p.MediaID)
+}
+
+func (u *webhookUseCase) GetDeliveryList(
+	ctx context.Context,
+	p dto.GetWebhookDeliveryListParams,
+) (res dto.GetWebhookDeliveryListResult, err error) {
+	defer func() {
+		if err != nil {
+			var appErr *dto.AppError
+			if !errors.As(err, &appErr) {
+				err = fmt.Errorf("WebhookUseCase - GetDeliveryList: %w", err)
+			}
+		}
+	}()
+
+	err = p.Validate()
+	if err != nil {
+		return
+	}
+
+	_, err = u.webhookRepo.GetWebhook(ctx, p.WebhookID, p.MediaID)
+	if err != nil {
+		return
+	}
+
+	res.Items, err = u.webhookRepo.GetWebhookDeliveryList(ctx, p)
+	if err != nil {
+		return
+	}
+
+	res.Total, err = u.webhookRepo.CountWebhookDeliveries(ctx, p)
+	return
+}
+
+func (u *webhookUseCase) GetDelivery(
+	ctx context.Context,
+	p dto.WebhookDeliveryParams,
+) (d entity.WebhookDelivery, err error) {
+	defer func() {
+		if err != nil {
+			var appErr *dto.AppError
+			if !errors.As(err, &appErr) {
+				err = fmt.Errorf("WebhookUseCase - GetDelivery: %w", err)
+			}
+		}
+	}()
+	return u.webhookRepo.GetWebhookDelivery(ctx, p)
+}
+
+func (u *webhookUseCase) ReplayDelivery(
+	ctx context.Context,
+	p dto.WebhookDeliveryParams,
+) (d entity.WebhookDelivery, err error) {
+	defer func() {
+		if err != nil {
+			var appErr *dto.AppError
+			if !errors.As(err, &appErr) {
+				err = fmt.Errorf("WebhookUseCase - ReplayDelivery: %w", err)
+			}
+		}
+	}()
+
+	d, err = u.webhookRepo.GetWebhookDelivery(ctx, p)
+	if err != nil {
+		return
+	}
+
+	return u.webhookRepo.ReplayWebhookDelivery(ctx, d.ID)
+}
+
+func (u *webhookUseCase) DeliverDueWebhooks(ctx context.Context) (err error) {
+	defer func() {
+		if err != nil {
+			var appErr *dto.AppError
+			if !errors.As(err, &appErr) {
+				err = fmt.Errorf("WebhookUseCase - DeliverDueWebhooks: %w", err)
+			}
+		}
+	}()
+
+	return deliverDue(ctx, webhookQueue, u.webhookRepo.ClaimDueWebhookDeliveries, u.deliver)
+}
+
+func (u *webhookUseCase) deliver(ctx context.Context, job entity.WebhookDeliveryJob) (err error) {
+	defer func() {
+		if err != nil {
+			err = fmt.Errorf("delivery %d: %w", job.DeliveryID, err)
+		}
+	}()
+
+	sendCtx, cancel := context.WithTimeout(ctx, webhookSendTimeout)
+	defer cancel()
+
+	now := time.Now()
+	status, body, sendErr := u.sender.Send(sendCtx, job, now)
+	if ctx.Err() != nil {
+		return nil
+	}
+
+	var (
+		responseStatus null.Int
+		responseBody   null.String
+		deliveryErr    null.String
+	)
+	if sendErr != nil {
+		deliveryErr = null.StringFrom(truncateRunes(sanitizeWebhookText(sendErr.Error()), webhookErrorRuneLimit))
+	} else {
+		responseStatus = null.IntFrom(int64(status))
+		responseBody = null.StringFrom(sanitizeWebhookText(body))
+	}
+
+	if sendErr == nil && status >= 200 && status < 300 {
+		return u.webhookRepo.MarkWebhookDeliveryAttempt(
+			ctx, job.DeliveryID, entity.WebhookDeliverySucceeded, responseStatus, responseBody, deliveryErr, null.Int{},
+		)
+	}
+
+	next, ok := webhookQueue.retryAt(now, job.Attempts)
+	if !ok {
+		return u.webhookRepo.MarkWebhookDeliveryAttempt(
+			ctx, job.DeliveryID, entity.WebhookDeliveryFailed, responseStatus, responseBody, deliveryErr, null.Int{},
+		)
+	}
+
+	return u.webhookRepo.MarkWebhookDeliveryAttempt(
+		ctx,
+		job.DeliveryID,
+		entity.WebhookDeliveryPending,
+		responseStatus,
+		responseBody,
+		deliveryErr,
+		null.IntFrom(next.Unix()),
+	)
+}
+
+func sanitizeWebhookText(s string) string {
+	return strings.ReplaceAll(strings.ToValidUTF8(s, ""), "\x00", "")
+}
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS muted_media;
+
+DROP TABLE IF EXISTS hidden_news;
\ No newline at end of file
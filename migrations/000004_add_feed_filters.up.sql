@@ -0,0 +1,14 @@
+CREATE TABLE hidden_news (
+    user_id BIGINT NOT NULL REFERENCES "user" (ID_user),
+    news_id BIGINT NOT NULL REFERENCES news (ID_news),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (user_id, news_id)
+);
+
+CREATE TABLE muted_media (
+    user_id BIGINT NOT NULL REFERENCES "user" (ID_user),
+    media_id BIGINT NOT NULL REFERENCES media (ID_editor),
+    until TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (user_id, media_id)
+);
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS feed_id_user_idx;
+
+ALTER TABLE feed
+    DROP COLUMN IF EXISTS read_at;
\ No newline at end of file
@@ -0,0 +1,4 @@
+ALTER TABLE feed
+    ADD COLUMN read_at TIMESTAMPTZ;
+
+CREATE INDEX feed_id_user_idx ON feed (id_user);
\ No newline at end of file
@@ -0,0 +1,15 @@
+CREATE TABLE digest_preference (
+    user_id BIGINT PRIMARY KEY REFERENCES "user" (ID_user),
+    frequency VARCHAR(8) NOT NULL DEFAULT 'off',
+    send_hour SMALLINT NOT NULL DEFAULT 8,
+    send_weekday SMALLINT NOT NULL DEFAULT 1,
+    timezone VARCHAR(64) NOT NULL DEFAULT 'UTC',
+    unsubscribe_token VARCHAR(64) NOT NULL UNIQUE,
+    last_sent_at TIMESTAMPTZ,
+    next_send_at TIMESTAMPTZ,
+    CHECK (frequency IN ('off', 'daily', 'weekly')),
+    CHECK (send_hour BETWEEN 0 AND 23),
+    CHECK (send_weekday BETWEEN 0 AND 6)
+);
+
+CREATE INDEX digest_preference_next_send_at_idx ON digest_preference (next_send_at) WHERE frequency <> 'off';
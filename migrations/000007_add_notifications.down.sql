@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS notification_preference;
+
+DROP TABLE IF EXISTS notification;
+
+ALTER TABLE news
+    DROP COLUMN IF EXISTS is_breaking;
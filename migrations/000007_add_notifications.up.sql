@@ -0,0 +1,25 @@
+ALTER TABLE news
+    ADD COLUMN is_breaking BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE TABLE notification (
+    id BIGSERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL REFERENCES "user" (ID_user),
+    type VARCHAR(32) NOT NULL,
+    news_id BIGINT REFERENCES news (ID_news),
+    media_id BIGINT REFERENCES media (ID_editor),
+    title VARCHAR(255) NOT NULL,
+    body VARCHAR(1024) NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    read_at TIMESTAMPTZ
+);
+
+CREATE INDEX notification_user_id_created_at_idx ON notification (user_id, created_at DESC);
+
+CREATE INDEX notification_unread_idx ON notification (user_id) WHERE read_at IS NULL;
+
+CREATE TABLE notification_preference (
+    user_id BIGINT NOT NULL REFERENCES "user" (ID_user),
+    type VARCHAR(32) NOT NULL,
+    enabled BOOLEAN NOT NULL,
+    PRIMARY KEY (user_id, type)
+);
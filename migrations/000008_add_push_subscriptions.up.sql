@@ -0,0 +1,10 @@
+CREATE TABLE push_subscription (
+    id BIGSERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL REFERENCES "user" (ID_user),
+    endpoint VARCHAR(2048) NOT NULL UNIQUE,
+    p256dh VARCHAR(128) NOT NULL,
+    auth VARCHAR(64) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX push_subscription_user_id_idx ON push_subscription (user_id);
@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS news_num_reg_media_news_release_idx;
+
+DROP INDEX IF EXISTS media_corp_name_trgm_idx;
+
+ALTER TABLE media
+    DROP COLUMN IF EXISTS created_at;
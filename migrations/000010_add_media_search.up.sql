@@ -0,0 +1,8 @@
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+ALTER TABLE media
+    ADD COLUMN created_at TIMESTAMPTZ NOT NULL DEFAULT NOW();
+
+CREATE INDEX media_corp_name_trgm_idx ON media USING GIN (Corp_name gin_trgm_ops);
+
+CREATE INDEX news_num_reg_media_news_release_idx ON news (Num_reg_media_news, release);
@@ -0,0 +1,6 @@
+ALTER TABLE media
+    DROP COLUMN IF EXISTS cover_updated_at,
+    DROP COLUMN IF EXISTS logo_updated_at,
+    DROP COLUMN IF EXISTS social_links,
+    DROP COLUMN IF EXISTS website,
+    DROP COLUMN IF EXISTS description;
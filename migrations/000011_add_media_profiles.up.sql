@@ -0,0 +1,6 @@
+ALTER TABLE media
+    ADD COLUMN description VARCHAR(2000) NOT NULL DEFAULT '',
+    ADD COLUMN website VARCHAR(512),
+    ADD COLUMN social_links JSONB NOT NULL DEFAULT '[]',
+    ADD COLUMN logo_updated_at TIMESTAMPTZ,
+    ADD COLUMN cover_updated_at TIMESTAMPTZ;
@@ -0,0 +1,24 @@
+DO $$
+BEGIN
+    IF EXISTS(
+        SELECT 1
+        FROM media
+        WHERE CASE WHEN Num_reg_media_r ~ '^\d{1,10}$' THEN Num_reg_media_r::BIGINT > 2147483647 ELSE TRUE END
+    ) THEN
+        RAISE EXCEPTION 'media registration numbers other than plain integers cannot be converted back to INT';
+    END IF;
+END
+$$;
+
+ALTER TABLE news
+    DROP CONSTRAINT IF EXISTS news_num_reg_media_news_fkey;
+
+ALTER TABLE news
+    ALTER COLUMN Num_reg_media_news TYPE INT USING Num_reg_media_news::INT;
+
+ALTER TABLE media
+    ALTER COLUMN Num_reg_media_r TYPE INT USING Num_reg_media_r::INT;
+
+ALTER TABLE news
+    ADD CONSTRAINT news_num_reg_media_news_fkey
+        FOREIGN KEY (Num_reg_media_news) REFERENCES media (Num_reg_media_r);
@@ -0,0 +1,12 @@
+ALTER TABLE news
+    DROP CONSTRAINT IF EXISTS news_num_reg_media_news_fkey;
+
+ALTER TABLE media
+    ALTER COLUMN Num_reg_media_r TYPE VARCHAR(32) USING Num_reg_media_r::TEXT;
+
+ALTER TABLE news
+    ALTER COLUMN Num_reg_media_news TYPE VARCHAR(32) USING Num_reg_media_news::TEXT;
+
+ALTER TABLE news
+    ADD CONSTRAINT news_num_reg_media_news_fkey
+        FOREIGN KEY (Num_reg_media_news) REFERENCES media (Num_reg_media_r) ON UPDATE CASCADE;
@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS subscription_history;
+
+DROP INDEX IF EXISTS subscription_media_id_created_at_idx;
+
+ALTER TABLE subscription
+    DROP COLUMN IF EXISTS created_at;
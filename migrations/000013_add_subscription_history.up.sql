@@ -0,0 +1,19 @@
+ALTER TABLE subscription
+    ADD COLUMN created_at TIMESTAMPTZ;
+
+ALTER TABLE subscription
+    ALTER COLUMN created_at SET DEFAULT NOW();
+
+CREATE INDEX subscription_media_id_created_at_idx ON subscription (media_id, created_at DESC);
+
+CREATE TABLE subscription_history (
+    id BIGSERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL REFERENCES "user" (ID_user),
+    media_id BIGINT NOT NULL REFERENCES media (ID_editor),
+    subscribed_at TIMESTAMPTZ,
+    unsubscribed_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX subscription_history_media_id_unsubscribed_at_idx ON subscription_history (media_id, unsubscribed_at);
+
+CREATE INDEX subscription_history_media_id_subscribed_at_idx ON subscription_history (media_id, subscribed_at);
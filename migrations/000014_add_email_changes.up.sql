@@ -0,0 +1,7 @@
+CREATE TABLE email_change (
+    user_id BIGINT PRIMARY KEY REFERENCES "user" (ID_user),
+    new_email VARCHAR(16) NOT NULL,
+    token VARCHAR(64) NOT NULL UNIQUE,
+    expires_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
@@ -0,0 +1,62 @@
+ALTER TABLE feed
+    DROP CONSTRAINT IF EXISTS feed_id_user_fkey,
+    ADD CONSTRAINT feed_id_user_fkey
+        FOREIGN KEY (ID_user) REFERENCES "user" (ID_user) ON DELETE CASCADE;
+
+ALTER TABLE subscription
+    DROP CONSTRAINT IF EXISTS subscription_user_id_fkey,
+    ADD CONSTRAINT subscription_user_id_fkey
+        FOREIGN KEY (user_id) REFERENCES "user" (ID_user) ON DELETE CASCADE;
+
+ALTER TABLE favorite
+    DROP CONSTRAINT IF EXISTS favorite_user_id_fkey,
+    ADD CONSTRAINT favorite_user_id_fkey
+        FOREIGN KEY (user_id) REFERENCES "user" (ID_user) ON DELETE CASCADE;
+
+ALTER TABLE hidden_news
+    DROP CONSTRAINT IF EXISTS hidden_news_user_id_fkey,
+    ADD CONSTRAINT hidden_news_user_id_fkey
+        FOREIGN KEY (user_id) REFERENCES "user" (ID_user) ON DELETE CASCADE;
+
+ALTER TABLE muted_media
+    DROP CONSTRAINT IF EXISTS muted_media_user_id_fkey,
+    ADD CONSTRAINT muted_media_user_id_fkey
+        FOREIGN KEY (user_id) REFERENCES "user" (ID_user) ON DELETE CASCADE;
+
+ALTER TABLE digest_preference
+    DROP CONSTRAINT IF EXISTS digest_preference_user_id_fkey,
+    ADD CONSTRAINT digest_preference_user_id_fkey
+        FOREIGN KEY (user_id) REFERENCES "user" (ID_user) ON DELETE CASCADE;
+
+ALTER TABLE notification
+    DROP CONSTRAINT IF EXISTS notification_user_id_fkey,
+    ADD CONSTRAINT notification_user_id_fkey
+        FOREIGN KEY (user_id) REFERENCES "user" (ID_user) ON DELETE CASCADE;
+
+ALTER TABLE notification_preference
+    DROP CONSTRAINT IF EXISTS notification_preference_user_id_fkey,
+    ADD CONSTRAINT notification_preference_user_id_fkey
+        FOREIGN KEY (user_id) REFERENCES "user" (ID_user) ON DELETE CASCADE;
+
+ALTER TABLE push_subscription
+    DROP CONSTRAINT IF EXISTS push_subscription_user_id_fkey,
+    ADD CONSTRAINT push_subscription_user_id_fkey
+        FOREIGN KEY (user_id) REFERENCES "user" (ID_user) ON DELETE CASCADE;
+
+ALTER TABLE subscription_history
+    DROP CONSTRAINT IF EXISTS subscription_history_user_id_fkey,
+    ADD CONSTRAINT subscription_history_user_id_fkey
+        FOREIGN KEY (user_id) REFERENCES "user" (ID_user) ON DELETE CASCADE;
+
+ALTER TABLE email_change
+    DROP CONSTRAINT IF EXISTS email_change_user_id_fkey,
+    ADD CONSTRAINT email_change_user_id_fkey
+        FOREIGN KEY (user_id) REFERENCES "user" (ID_user) ON DELETE CASCADE;
+
+CREATE TABLE account_deletion (
+    user_id BIGINT PRIMARY KEY REFERENCES "user" (ID_user) ON DELETE CASCADE,
+    requested_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    scheduled_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX account_deletion_scheduled_at_idx ON account_deletion (scheduled_at);
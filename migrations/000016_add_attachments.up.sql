@@ -0,0 +1,12 @@
+CREATE TABLE attachment (
+    id BIGSERIAL PRIMARY KEY,
+    news_id BIGINT NOT NULL REFERENCES news (ID_news) ON DELETE CASCADE,
+    kind VARCHAR(8) NOT NULL,
+    content_type VARCHAR(64) NOT NULL,
+    size BIGINT NOT NULL,
+    storage_key VARCHAR(255) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (news_id, kind),
+    CHECK (kind IN ('audio', 'image', 'video'))
+);
@@ -0,0 +1,9 @@
+CREATE TABLE attachment_variant (
+    attachment_id BIGINT NOT NULL REFERENCES attachment (id) ON DELETE CASCADE,
+    width INT NOT NULL,
+    height INT NOT NULL,
+    content_type VARCHAR(64) NOT NULL,
+    size BIGINT NOT NULL,
+    storage_key VARCHAR(255) NOT NULL,
+    PRIMARY KEY (attachment_id, width, content_type)
+);
@@ -0,0 +1,2 @@
+ALTER TABLE attachment_variant DROP COLUMN IF EXISTS content_hash;
+ALTER TABLE attachment DROP COLUMN IF EXISTS content_hash;
@@ -0,0 +1,2 @@
+ALTER TABLE attachment ADD COLUMN content_hash VARCHAR(64);
+ALTER TABLE attachment_variant ADD COLUMN content_hash VARCHAR(64);
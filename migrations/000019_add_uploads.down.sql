@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS upload_chunk;
+DROP TABLE IF EXISTS upload;
@@ -0,0 +1,22 @@
+CREATE TABLE upload (
+    id VARCHAR(64) PRIMARY KEY,
+    media_id BIGINT NOT NULL REFERENCES media (id_editor) ON DELETE CASCADE,
+    news_id BIGINT NOT NULL REFERENCES news (ID_news) ON DELETE CASCADE,
+    kind VARCHAR(8) NOT NULL,
+    length BIGINT NOT NULL,
+    upload_offset BIGINT NOT NULL DEFAULT 0,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    expires_at TIMESTAMPTZ NOT NULL,
+    CHECK (kind IN ('audio', 'image', 'video')),
+    CHECK (upload_offset <= length)
+);
+
+CREATE INDEX upload_expires_at_idx ON upload (expires_at);
+
+CREATE TABLE upload_chunk (
+    upload_id VARCHAR(64) NOT NULL REFERENCES upload (id) ON DELETE CASCADE,
+    upload_offset BIGINT NOT NULL,
+    size BIGINT NOT NULL,
+    storage_key VARCHAR(255) NOT NULL,
+    PRIMARY KEY (upload_id, upload_offset)
+);
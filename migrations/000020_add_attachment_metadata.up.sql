@@ -0,0 +1 @@
+ALTER TABLE attachment ADD COLUMN metadata JSONB NOT NULL DEFAULT '{}';
@@ -0,0 +1 @@
+ALTER TABLE attachment DROP COLUMN IF EXISTS waveform;
@@ -0,0 +1 @@
+ALTER TABLE attachment ADD COLUMN waveform JSONB;
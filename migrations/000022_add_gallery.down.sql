@@ -0,0 +1,8 @@
+DROP TABLE IF EXISTS gallery_image;
+DELETE FROM attachment WHERE kind = 'gallery';
+
+DROP INDEX IF EXISTS attachment_news_id_kind_key;
+ALTER TABLE attachment ADD CONSTRAINT attachment_news_id_kind_key UNIQUE (news_id, kind);
+
+ALTER TABLE attachment DROP CONSTRAINT attachment_kind_check;
+ALTER TABLE attachment ADD CONSTRAINT attachment_kind_check CHECK (kind IN ('audio', 'image', 'video'));
@@ -0,0 +1,16 @@
+ALTER TABLE attachment DROP CONSTRAINT attachment_kind_check;
+ALTER TABLE attachment ADD CONSTRAINT attachment_kind_check CHECK (kind IN ('audio', 'image', 'video', 'gallery'));
+
+ALTER TABLE attachment DROP CONSTRAINT attachment_news_id_kind_key;
+CREATE UNIQUE INDEX attachment_news_id_kind_key ON attachment (news_id, kind) WHERE kind <> 'gallery';
+
+CREATE TABLE gallery_image (
+    attachment_id BIGINT PRIMARY KEY REFERENCES attachment (id) ON DELETE CASCADE,
+    news_id BIGINT NOT NULL REFERENCES news (ID_news) ON DELETE CASCADE,
+    caption TEXT NOT NULL DEFAULT '',
+    alt_text TEXT NOT NULL DEFAULT '',
+    credit VARCHAR(255) NOT NULL DEFAULT '',
+    position INT NOT NULL
+);
+
+CREATE INDEX gallery_image_news_id_position_idx ON gallery_image (news_id, position);
@@ -0,0 +1,2 @@
+ALTER TABLE news DROP COLUMN IF EXISTS search_vector;
+DROP TABLE IF EXISTS text_track;
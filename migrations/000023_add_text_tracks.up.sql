@@ -0,0 +1,29 @@
+CREATE TABLE text_track (
+    id BIGSERIAL PRIMARY KEY,
+    news_id BIGINT NOT NULL REFERENCES news (ID_news) ON DELETE CASCADE,
+    target VARCHAR(8) NOT NULL,
+    kind VARCHAR(16) NOT NULL,
+    language VARCHAR(35) NOT NULL,
+    label VARCHAR(64) NOT NULL DEFAULT '',
+    content_type VARCHAR(64) NOT NULL,
+    size BIGINT NOT NULL,
+    content_hash VARCHAR(64) NOT NULL,
+    storage_key VARCHAR(255) NOT NULL,
+    search_config REGCONFIG,
+    search_vector TSVECTOR,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (news_id, target, kind, language),
+    CHECK (target IN ('audio', 'video')),
+    CHECK (kind IN ('subtitles', 'transcript'))
+);
+
+CREATE INDEX text_track_search_vector_idx ON text_track USING GIN (search_vector);
+
+ALTER TABLE news
+    ADD COLUMN search_vector TSVECTOR GENERATED ALWAYS AS (
+        setweight(to_tsvector('russian', COALESCE(Title, '')), 'A') ||
+        setweight(to_tsvector('russian', COALESCE(Text_content, '')), 'B')
+    ) STORED;
+
+CREATE INDEX news_search_vector_idx ON news USING GIN (search_vector);
@@ -0,0 +1,3 @@
+ALTER TABLE attachment_variant DROP COLUMN IF EXISTS blob_hash;
+ALTER TABLE attachment DROP COLUMN IF EXISTS blob_hash;
+DROP TABLE IF EXISTS blob;
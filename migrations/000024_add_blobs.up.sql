@@ -0,0 +1,14 @@
+CREATE TABLE blob (
+    content_hash VARCHAR(64) PRIMARY KEY,
+    storage_key VARCHAR(255) NOT NULL UNIQUE,
+    size BIGINT NOT NULL,
+    ref_count BIGINT NOT NULL DEFAULT 0,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CHECK (ref_count >= 0)
+);
+
+ALTER TABLE attachment ADD COLUMN blob_hash VARCHAR(64) REFERENCES blob (content_hash);
+ALTER TABLE attachment_variant ADD COLUMN blob_hash VARCHAR(64) REFERENCES blob (content_hash);
+
+CREATE INDEX attachment_blob_hash_idx ON attachment (blob_hash);
+CREATE INDEX attachment_variant_blob_hash_idx ON attachment_variant (blob_hash);
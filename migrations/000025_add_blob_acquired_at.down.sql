@@ -0,0 +1 @@
+ALTER TABLE blob DROP COLUMN IF EXISTS acquired_at;
@@ -0,0 +1 @@
+ALTER TABLE blob ADD COLUMN acquired_at TIMESTAMPTZ NOT NULL DEFAULT NOW();
@@ -0,0 +1,10 @@
+CREATE TABLE push_delivery (
+    id BIGSERIAL PRIMARY KEY,
+    subscription_id BIGINT NOT NULL REFERENCES push_subscription (id) ON DELETE CASCADE,
+    payload TEXT NOT NULL,
+    attempts INT NOT NULL DEFAULT 0,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX push_delivery_next_attempt_at_idx ON push_delivery (next_attempt_at);
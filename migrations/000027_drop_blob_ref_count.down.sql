@@ -0,0 +1,10 @@
+ALTER TABLE blob ADD COLUMN ref_count BIGINT NOT NULL DEFAULT 0 CHECK (ref_count >= 0);
+
+UPDATE blob
+SET ref_count = refs.count
+FROM (SELECT blob_hash, COUNT(*) AS count
+      FROM (SELECT blob_hash FROM attachment WHERE blob_hash IS NOT NULL
+            UNION ALL
+            SELECT blob_hash FROM attachment_variant WHERE blob_hash IS NOT NULL) r
+      GROUP BY blob_hash) refs
+WHERE blob.content_hash = refs.blob_hash;
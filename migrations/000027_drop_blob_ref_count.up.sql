@@ -0,0 +1 @@
+ALTER TABLE blob DROP COLUMN ref_count;
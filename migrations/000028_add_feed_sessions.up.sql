@@ -0,0 +1,10 @@
+CREATE TABLE feed_session (
+    token VARCHAR(64) PRIMARY KEY,
+    user_id BIGINT NOT NULL REFERENCES "user" (ID_user) ON DELETE CASCADE,
+    news_ids BIGINT[] NOT NULL,
+    cutoff_release TIMESTAMPTZ,
+    cutoff_news_id BIGINT,
+    expires_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX feed_session_expires_at_idx ON feed_session (expires_at);
@@ -0,0 +1,7 @@
+DELETE FROM subscription_history WHERE user_id IS NULL;
+
+ALTER TABLE subscription_history
+    ALTER COLUMN user_id SET NOT NULL,
+    DROP CONSTRAINT IF EXISTS subscription_history_user_id_fkey,
+    ADD CONSTRAINT subscription_history_user_id_fkey
+        FOREIGN KEY (user_id) REFERENCES "user" (ID_user) ON DELETE CASCADE;
@@ -0,0 +1,5 @@
+ALTER TABLE subscription_history
+    ALTER COLUMN user_id DROP NOT NULL,
+    DROP CONSTRAINT IF EXISTS subscription_history_user_id_fkey,
+    ADD CONSTRAINT subscription_history_user_id_fkey
+        FOREIGN KEY (user_id) REFERENCES "user" (ID_user) ON DELETE SET NULL;
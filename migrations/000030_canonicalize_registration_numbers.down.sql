@@ -0,0 +1,2 @@
+ALTER TABLE media
+    DROP CONSTRAINT IF EXISTS media_num_reg_media_r_format_check;
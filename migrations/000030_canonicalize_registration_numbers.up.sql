@@ -0,0 +1,33 @@
+WITH parsed AS (
+    SELECT Num_reg_media_r AS original,
+           REGEXP_MATCH(
+               REGEXP_REPLACE(
+                   TRIM(TRANSLATE(
+                       UPPER(Num_reg_media_r),
+                       'ПРИЭЕЛАФСCТУY№#NНOО-–—._/:',
+                       'PPIEELAFSSTUU             '
+                   )),
+                   '\s+', ' ', 'g'
+               ),
+               '^(PI|EL|IA) ?(FS|TU)? ?(\d{2}) ?(\d{3,6})$'
+           ) AS m
+    FROM media
+)
+UPDATE media
+SET Num_reg_media_r = CASE parsed.m[1] WHEN 'PI' THEN 'ПИ' WHEN 'EL' THEN 'ЭЛ' ELSE 'ИА' END
+    || ' № '
+    || CASE parsed.m[2] WHEN 'FS' THEN 'ФС ' WHEN 'TU' THEN 'ТУ ' ELSE '' END
+    || parsed.m[3] || '-' || parsed.m[4]
+FROM parsed
+WHERE media.Num_reg_media_r = parsed.original
+  AND parsed.m IS NOT NULL;
+
+UPDATE media
+SET Num_reg_media_r = COALESCE(NULLIF(LTRIM(TRIM(Num_reg_media_r), '0'), ''), '0')
+WHERE TRIM(Num_reg_media_r) ~ '^\d{1,10}$';
+
+ALTER TABLE media
+    ADD CONSTRAINT media_num_reg_media_r_format_check CHECK (
+        Num_reg_media_r ~ '^(ПИ|ЭЛ|ИА) № (ФС |ТУ )?\d{2}-\d{3,6}$'
+        OR Num_reg_media_r ~ '^\d{1,10}$'
+    );
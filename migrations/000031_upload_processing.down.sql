@@ -0,0 +1,17 @@
+DELETE
+FROM upload
+WHERE media_id IS NULL
+   OR news_id IS NULL;
+
+DROP INDEX upload_process_after_idx;
+
+ALTER TABLE upload
+    DROP COLUMN process_after,
+    DROP COLUMN error,
+    DROP COLUMN status,
+    DROP CONSTRAINT upload_media_id_fkey,
+    DROP CONSTRAINT upload_news_id_fkey,
+    ADD CONSTRAINT upload_media_id_fkey FOREIGN KEY (media_id) REFERENCES media (id_editor) ON DELETE CASCADE,
+    ADD CONSTRAINT upload_news_id_fkey FOREIGN KEY (news_id) REFERENCES news (ID_news) ON DELETE CASCADE,
+    ALTER COLUMN media_id SET NOT NULL,
+    ALTER COLUMN news_id SET NOT NULL;
@@ -0,0 +1,18 @@
+ALTER TABLE upload
+    ALTER COLUMN media_id DROP NOT NULL,
+    ALTER COLUMN news_id DROP NOT NULL,
+    DROP CONSTRAINT upload_media_id_fkey,
+    DROP CONSTRAINT upload_news_id_fkey,
+    ADD CONSTRAINT upload_media_id_fkey FOREIGN KEY (media_id) REFERENCES media (id_editor) ON DELETE SET NULL,
+    ADD CONSTRAINT upload_news_id_fkey FOREIGN KEY (news_id) REFERENCES news (ID_news) ON DELETE SET NULL,
+    ADD COLUMN status VARCHAR(16) NOT NULL DEFAULT 'uploading',
+    ADD COLUMN error VARCHAR(255),
+    ADD COLUMN process_after TIMESTAMPTZ,
+    ADD CHECK (status IN ('uploading', 'processing', 'completed', 'failed'));
+
+UPDATE upload
+SET status        = 'processing',
+    process_after = NOW()
+WHERE upload_offset = length;
+
+CREATE INDEX upload_process_after_idx ON upload (process_after) WHERE status = 'processing';
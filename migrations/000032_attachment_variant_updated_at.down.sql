@@ -0,0 +1 @@
+ALTER TABLE attachment_variant DROP COLUMN IF EXISTS updated_at;
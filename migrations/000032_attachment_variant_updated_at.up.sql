@@ -0,0 +1 @@
+ALTER TABLE attachment_variant ADD COLUMN updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW();
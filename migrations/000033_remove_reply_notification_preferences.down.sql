@@ -0,0 +1 @@
+-- Removed reply preferences are not restored.
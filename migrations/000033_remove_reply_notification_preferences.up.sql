@@ -0,0 +1 @@
+DELETE FROM notification_preference WHERE type = 'reply';